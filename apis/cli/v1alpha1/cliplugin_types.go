@@ -49,6 +49,7 @@ type CLIPluginSpec struct {
 }
 
 //+kubebuilder:object:root=true
+//+kubebuilder:storageversion
 
 // CLIPlugin denotes a Tanzu cli plugin.
 type CLIPlugin struct {
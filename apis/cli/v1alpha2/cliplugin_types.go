@@ -0,0 +1,77 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+)
+
+// PluginGroupReference refers to a plugin group published to the plugin inventory.
+type PluginGroupReference struct {
+	// Name of the plugin group in the format vendor-publisher/name. E.g., vmware-tkg/default
+	Name string `json:"name"`
+	// Version of the plugin group. E.g., v2.1.0
+	// If not specified, the latest version of the plugin group is used.
+	Version string `json:"version,omitempty"`
+}
+
+// CLIPluginSpec defines the desired state of CLIPlugin.
+type CLIPluginSpec struct {
+	// Description is the plugin's description.
+	Description string `json:"description"`
+	// Recommended version that Tanzu CLI should use if available.
+	// The value should be a valid semantic version as defined in
+	// https://semver.org/. E.g., 2.0.1
+	// If not specified, the version of the plugin in the referenced
+	// plugin group is used.
+	RecommendedVersion string `json:"recommendedVersion,omitempty"`
+	// MinVersion is the oldest version of the plugin that supports
+	// the cluster. E.g., v2.0.0
+	MinVersion string `json:"minVersion,omitempty"`
+	// MaxVersion is the most recent version of the plugin that supports
+	// the cluster. E.g., v2.3.0
+	MaxVersion string `json:"maxVersion,omitempty"`
+	// PluginGroup refers to the plugin group of the plugin inventory
+	// providing the plugin.
+	PluginGroup *PluginGroupReference `json:"pluginGroup,omitempty"`
+	// Optional specifies whether the plugin is mandatory or optional
+	// If optional, the plugin will not get auto-downloaded as part of
+	// `tanzu login` or `tanzu plugin sync` command
+	// To view the list of plugin, user can use `tanzu plugin list` and
+	// to download a specific plugin run, `tanzu plugin install <plugin-name>`
+	Optional bool `json:"optional,omitempty"`
+	// Target specifies the target of the plugin. Only needed for standalone plugins
+	Target configtypes.Target `json:"target,omitempty"`
+	// Deprecated specifies whether the plugin should no longer be used with the cluster.
+	Deprecated bool `json:"deprecated,omitempty"`
+	// ReplacedBy is the name of the plugin replacing a deprecated plugin.
+	ReplacedBy string `json:"replacedBy,omitempty"`
+	// RequiredContextTypes specifies the types of context the plugin is
+	// recommended for. If empty, the plugin is recommended for any context.
+	RequiredContextTypes []configtypes.ContextType `json:"requiredContextTypes,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CLIPlugin denotes a Tanzu cli plugin.
+type CLIPlugin struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              CLIPluginSpec `json:"spec"`
+}
+
+//+kubebuilder:object:root=true
+
+// CLIPluginList contains a list of CLIPlugin
+type CLIPluginList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CLIPlugin `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CLIPlugin{}, &CLIPluginList{})
+}
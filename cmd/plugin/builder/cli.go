@@ -14,6 +14,7 @@ import (
 var (
 	dryRun      bool
 	description string
+	fromOpenAPI string
 )
 
 // NewCLICmd creates the CLI builder commands.
@@ -37,6 +38,10 @@ func newAddPluginCmd() *cobra.Command {
 			var err error
 
 			name := args[0]
+			if fromOpenAPI != "" {
+				// The description defaults to the one of the API
+				return command.AddPluginFromOpenAPI(name, description, fromOpenAPI, dryRun)
+			}
 			if description == "" {
 				description, err = askDescription()
 				if err != nil {
@@ -50,6 +55,7 @@ func newAddPluginCmd() *cobra.Command {
 
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print generated files to stdout")
 	cmd.Flags().StringVar(&description, "description", "", "Required plugin description")
+	cmd.Flags().StringVar(&fromOpenAPI, "from-openapi", "", "Path to an OpenAPI 3 specification (YAML or JSON) to generate the commands of the plugin from")
 
 	return cmd
 }
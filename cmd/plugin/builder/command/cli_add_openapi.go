@@ -0,0 +1,95 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/template"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
+)
+
+// openAPIPluginData is the data of the templates of a plugin generated from an OpenAPI specification
+type openAPIPluginData struct {
+	PluginName  string
+	Description string
+	// APIBasePath is the base path of the API, which the paths of the operations are relative to
+	APIBasePath string
+	// EnvPrefix is the prefix of the environment variables used by the plugin
+	EnvPrefix string
+	Resources []*openAPIResource
+	// Resource is the resource of the PluginOpenAPIResource target
+	Resource *openAPIResource
+}
+
+// AddPluginFromOpenAPI generates a new plugin from an OpenAPI 3 specification.
+// The plugin has a command for each resource of the API, with a sub-command for
+// each operation of the resource, which calls the API using the active tanzu context.
+func AddPluginFromOpenAPI(name, description, specFile string, dryRun bool) error {
+	// Try to ensure we are in the root of the repo.
+	if err := looksLikeARepo(); err != nil {
+		return err
+	}
+
+	spec, err := readOpenAPISpec(specFile)
+	if err != nil {
+		return err
+	}
+	if description == "" {
+		description = firstLine(spec.Info.Description)
+	}
+	if description == "" {
+		description = spec.Info.Title
+	}
+	if description == "" {
+		return errors.New("plugin description is required")
+	}
+
+	data, err := getOpenAPIPluginData(name, description, spec)
+	if err != nil {
+		return err
+	}
+	for _, target := range template.OpenAPIPluginTargets {
+		if err := target.Run("", data, dryRun); err != nil {
+			return err
+		}
+	}
+	for _, resource := range data.Resources {
+		data.Resource = resource
+		if err := template.PluginOpenAPIResource.Run("", data, dryRun); err != nil {
+			return errors.Wrapf(err, "unable to generate the commands of the %q resource", resource.Name)
+		}
+	}
+	log.Successf("successfully created plugin with %d resource commands", len(data.Resources))
+
+	return nil
+}
+
+// getOpenAPIPluginData returns the data of the templates of the plugin for the OpenAPI specification
+func getOpenAPIPluginData(name, description string, spec *openAPISpec) (*openAPIPluginData, error) {
+	resources, err := spec.resources()
+	if err != nil {
+		return nil, err
+	}
+	if len(resources) == 0 {
+		return nil, errors.New("the OpenAPI specification does not define any operation")
+	}
+	for _, resource := range resources {
+		// The main, client and output files of the plugin cannot be overwritten
+		switch resource.FileName {
+		case "main", "client", "output":
+			resource.FileName += "_cmd"
+		}
+	}
+
+	return &openAPIPluginData{
+		PluginName:  name,
+		Description: description,
+		APIBasePath: spec.basePath(),
+		EnvPrefix:   strings.ToUpper(strings.ReplaceAll(toKebabCase(name), "-", "_")),
+		Resources:   resources,
+	}, nil
+}
@@ -0,0 +1,245 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tj/assert"
+)
+
+const testOpenAPISpec = `openapi: 3.0.3
+info:
+  title: Cluster API
+  description: Manage clusters
+  version: 1.0.0
+servers:
+  - url: https://api.example.com/api/
+paths:
+  /v1/clusters:
+    get:
+      summary: List the clusters
+      parameters:
+        - name: pageSize
+          in: query
+          schema: {type: integer}
+        - $ref: '#/components/parameters/Labels'
+      responses:
+        "200":
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  clusters:
+                    type: array
+                    items: {$ref: '#/components/schemas/Cluster'}
+    post:
+      summary: Create a cluster
+      requestBody:
+        required: true
+      responses:
+        "201": {}
+  /v1/clusters/{clusterName}:
+    parameters:
+      - name: clusterName
+        in: path
+        required: true
+    get:
+      summary: Get a cluster
+      parameters:
+        - name: output
+          in: query
+          schema: {type: boolean}
+      responses:
+        "200":
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Cluster'}
+    delete:
+      responses:
+        "204": {}
+  /v1/clusters/{clusterName}:upgrade:
+    post:
+      responses:
+        "202": {}
+  /v1/nodePolicies:
+    get:
+      operationId: listNodePolicies
+      tags: [NodePolicies]
+      responses:
+        "200": {}
+components:
+  parameters:
+    Labels:
+      name: labels
+      in: query
+      required: true
+      schema: {type: array, items: {type: string}}
+  schemas:
+    Cluster:
+      type: object
+      properties:
+        version: {type: string}
+        name: {type: string}
+        spec: {type: object}
+`
+
+func writeTestOpenAPISpec(t *testing.T, dir, content string) string {
+	specFile := filepath.Join(dir, "spec.yaml")
+	assert.Nil(t, os.WriteFile(specFile, []byte(content), 0644))
+	return specFile
+}
+
+func TestOpenAPINames(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("cluster-groups", toKebabCase("clusterGroups"))
+	assert.Equal("cluster-groups", toKebabCase("Cluster Groups"))
+	assert.Equal("cluster-id", toKebabCase("clusterID"))
+	assert.Equal("http-proxy", toKebabCase("HTTPProxy"))
+	assert.Equal("x-org-id", toKebabCase("X-Org-ID"))
+
+	assert.Equal("ClusterGroup", toUpperCamelCase("cluster-group"))
+	assert.Equal("X2fa", toUpperCamelCase("2fa"))
+
+	assert.Equal("policy", singularize("policies"))
+	assert.Equal("cluster", singularize("clusters"))
+	assert.Equal("class", singularize("classes"))
+	assert.Equal("access", singularize("access"))
+
+	assert.Equal("list", getVerb("GET", "/v1/clusters"))
+	assert.Equal("get", getVerb("GET", "/v1/clusters/{name}"))
+	assert.Equal("create", getVerb("POST", "/v1/clusters"))
+	assert.Equal("update", getVerb("PATCH", "/v1/clusters/{name}"))
+	assert.Equal("delete", getVerb("DELETE", "/v1/clusters/{name}"))
+	assert.Equal("upgrade", getVerb("POST", "/v1/clusters/{name}:upgrade"))
+
+	assert.Equal("cluster", getResourceName("/v1/clusters/{name}", &openAPIOperation{}))
+	assert.Equal("node-policy", getResourceName("/v1/foo", &openAPIOperation{Tags: []string{"NodePolicies"}}))
+}
+
+func TestOpenAPIResources(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "openapi")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	spec, err := readOpenAPISpec(writeTestOpenAPISpec(t, dir, testOpenAPISpec))
+	assert.Nil(err)
+	assert.Equal("/api", spec.basePath())
+
+	resources, err := spec.resources()
+	assert.Nil(err)
+	assert.Equal(2, len(resources))
+
+	cluster := resources[0]
+	assert.Equal("cluster", cluster.Name)
+	assert.Equal("newClusterCmd", cluster.FuncName)
+	commands := map[string]*openAPICommand{}
+	for _, c := range cluster.Commands {
+		commands[c.Verb] = c
+	}
+	assert.Equal(5, len(commands))
+
+	list := commands["list"]
+	assert.Equal("list", list.Use)
+	assert.Nil(list.Arg)
+	assert.Equal([]string{"name", "version"}, list.Columns)
+	assert.Equal(2, len(list.Flags))
+	assert.Equal("page-size", list.Flags[0].Name)
+	assert.Equal("flagPageSize", list.Flags[0].VarName)
+	assert.Equal("int", list.Flags[0].GoType)
+	assert.False(list.Flags[0].Required)
+	assert.Equal("labels", list.Flags[1].Name)
+	assert.Equal("[]string", list.Flags[1].GoType)
+	assert.Equal("StringSliceVar", list.Flags[1].FlagFunc)
+	assert.True(list.Flags[1].Required)
+
+	assert.True(commands["create"].HasBody)
+	assert.True(commands["create"].BodyRequired)
+	assert.Nil(commands["create"].Arg)
+
+	get := commands["get"]
+	assert.Equal("get CLUSTER_NAME", get.Use)
+	assert.Equal("clusterName", get.Arg.ParamName)
+	assert.Equal([]string{"name", "version"}, get.Columns)
+	// The parameter is renamed as it conflicts with the output flag
+	assert.Equal(1, len(get.Flags))
+	assert.Equal("output-query", get.Flags[0].Name)
+	assert.Equal("bool", get.Flags[0].GoType)
+
+	assert.Equal("delete CLUSTER_NAME", commands["delete"].Use)
+	assert.Equal("Delete cluster", commands["delete"].Short)
+	assert.Equal("upgrade CLUSTER_NAME", commands["upgrade"].Use)
+
+	nodePolicy := resources[1]
+	assert.Equal("node-policy", nodePolicy.Name)
+	assert.Equal("node_policy", nodePolicy.FileName)
+	assert.Equal(1, len(nodePolicy.Commands))
+	assert.Equal("newNodePolicyListCmd", nodePolicy.Commands[0].FuncName)
+}
+
+func TestReadOpenAPISpecErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "openapi")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	_, err = readOpenAPISpec(filepath.Join(dir, "missing.yaml"))
+	assert.NotNil(err)
+
+	_, err = readOpenAPISpec(writeTestOpenAPISpec(t, dir, `{"swagger": "2.0", "paths": {"/foo": {}}}`))
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "only OpenAPI 3 specifications are supported")
+
+	_, err = readOpenAPISpec(writeTestOpenAPISpec(t, dir, `{"openapi": "3.1.0", "paths": {}}`))
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "does not define any path")
+}
+
+func TestAddPluginFromOpenAPI(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "openapi")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	specFile := writeTestOpenAPISpec(t, dir, testOpenAPISpec)
+
+	repoDir := filepath.Join(dir, "repo")
+	assert.Nil(os.MkdirAll(repoDir, 0755))
+	assert.Nil(os.WriteFile(filepath.Join(repoDir, "go.mod"), []byte("module example.com/repo\n"), 0644))
+	assert.Nil(os.WriteFile(filepath.Join(repoDir, "Makefile"), []byte(""), 0644))
+
+	wd, err := os.Getwd()
+	assert.Nil(err)
+	defer func() { _ = os.Chdir(wd) }()
+	assert.Nil(os.Chdir(repoDir))
+
+	err = AddPluginFromOpenAPI("clusters", "", specFile, false)
+	assert.Nil(err)
+
+	pluginDir := filepath.Join(repoDir, "cmd", "plugin", "clusters")
+	for _, file := range []string{"main.go", "client.go", "output.go", "cluster.go", "node_policy.go", "test/main.go"} {
+		_, err := parser.ParseFile(token.NewFileSet(), filepath.Join(pluginDir, file), nil, parser.AllErrors)
+		assert.Nil(err, file)
+	}
+	assert.FileExists(filepath.Join(pluginDir, "README.md"))
+
+	main, err := os.ReadFile(filepath.Join(pluginDir, "main.go"))
+	assert.Nil(err)
+	assert.Contains(string(main), `Description: "manage clusters"`)
+	assert.Contains(string(main), "newNodePolicyCmd(),")
+
+	client, err := os.ReadFile(filepath.Join(pluginDir, "client.go"))
+	assert.Nil(err)
+	assert.Contains(string(client), `const apiBasePath = "/api"`)
+	assert.Contains(string(client), `apiEndpointEnvVar = "CLUSTERS_API_ENDPOINT"`)
+}
@@ -0,0 +1,468 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	rtplugin "github.com/vmware-tanzu/tanzu-plugin-runtime/plugin"
+
+	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/helpers"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/carvelhelpers"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/provenance"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/utils"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
+)
+
+const (
+	cacheEntriesDir      = "entries"
+	cacheIndexDir        = "index"
+	cacheArtifactsDir    = "artifacts"
+	cacheMetadataFile    = "metadata.json"
+	cacheReasonReused    = "reused from cache"
+	cacheReasonNotCached = "not built before"
+)
+
+// buildCache is a content-addressed cache of the plugin binaries, keyed on the
+// inputs of the build of each plugin for each os/arch. The cache entries are stored
+// in a local directory, and optionally published to an OCI image repository to
+// share them between machines.
+type buildCache struct {
+	dir             string
+	image           string
+	imageOperations carvelhelpers.ImageOperationsImpl
+
+	goVersionOnce sync.Once
+	goVersion     string
+	goVersionErr  error
+
+	resultsMutex sync.Mutex
+	// results are the cache status of the plugins, by plugin path
+	results map[string]string
+}
+
+// buildInputs are the inputs of the build of a plugin for an os/arch
+type buildInputs struct {
+	SourceHash string `json:"sourceHash"`
+	GoSumHash  string `json:"goSumHash"`
+	GoVersion  string `json:"goVersion"`
+	Version    string `json:"version"`
+	LDFlags    string `json:"ldflags"`
+	GoFlags    string `json:"goflags"`
+	Tags       string `json:"tags"`
+	Env        string `json:"env,omitempty"`
+	Arch       string `json:"arch"`
+}
+
+// cacheEntryMetadata is the metadata of a cache entry
+type cacheEntryMetadata struct {
+	Descriptor rtplugin.PluginDescriptor `json:"descriptor"`
+	Target     string                    `json:"target"`
+	Inputs     buildInputs               `json:"inputs"`
+}
+
+// newBuildCache returns a build cache storing its entries in dir, and in the
+// image repository if not empty
+func newBuildCache(dir, image string) (*buildCache, error) {
+	if dir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(userCacheDir, "tanzu-builder", "plugin-build")
+	}
+	for _, d := range []string{cacheEntriesDir, cacheIndexDir} {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0755); err != nil {
+			return nil, err
+		}
+	}
+	return &buildCache{
+		dir:             dir,
+		image:           strings.TrimSuffix(image, "/"),
+		imageOperations: carvelhelpers.NewImageOperationsImpl(),
+		results:         map[string]string{},
+	}, nil
+}
+
+// key returns the cache key of the build inputs
+func (i *buildInputs) key() string {
+	b, _ := json.Marshal(i)
+	return fmt.Sprintf("%x", sha256.Sum256(b))
+}
+
+// changes returns the inputs that differ from the previous build inputs
+func (i *buildInputs) changes(previous *buildInputs) []string {
+	var changes []string
+	for _, c := range []struct {
+		name             string
+		current, earlier string
+	}{
+		{"sources", i.SourceHash, previous.SourceHash},
+		{"go.sum", i.GoSumHash, previous.GoSumHash},
+		{"go version", i.GoVersion, previous.GoVersion},
+		{"version", i.Version, previous.Version},
+		{"ldflags", i.LDFlags, previous.LDFlags},
+		{"goflags", i.GoFlags, previous.GoFlags},
+		{"tags", i.Tags, previous.Tags},
+		{"env", i.Env, previous.Env},
+	} {
+		if c.current != c.earlier {
+			changes = append(changes, c.name)
+		}
+	}
+	return changes
+}
+
+// lookup returns the build inputs of the plugin at path for each os/arch to build,
+// and the reason why the plugin needs to be rebuilt, which is empty if the plugin
+// binaries for all the os/arch are cached
+func (c *buildCache) lookup(path string, p *plugin) (map[cli.Arch]*buildInputs, string, error) {
+	goVersion, err := c.getGoVersion()
+	if err != nil {
+		return nil, "", err
+	}
+
+	allInputs := map[cli.Arch]*buildInputs{}
+	reason := ""
+	for arch, tgt := range getTargets() {
+		// The packages of the plugin depend on the os/arch it is built for
+		env := append(tgt("", "").env, p.env...)
+		sourceHash, goSumHash, err := hashPluginSources(p.path, p.modPath, p.tags, env)
+		if err != nil {
+			return nil, "", err
+		}
+		inputs := &buildInputs{
+			SourceHash: sourceHash,
+			GoSumHash:  goSumHash,
+			GoVersion:  goVersion,
+			Version:    version,
+			LDFlags:    ldflags,
+			GoFlags:    goflags,
+			Tags:       p.tags,
+			Env:        strings.Join(p.env, " "),
+			Arch:       string(arch),
+		}
+		allInputs[arch] = inputs
+		if reason == "" && !c.hasEntry(inputs.key()) {
+			reason = c.rebuildReason(path, inputs)
+		}
+	}
+	return allInputs, reason, nil
+}
+
+// rebuildReason returns why the plugin needs to be rebuilt, by comparing the
+// build inputs with the inputs of the last cached build of the plugin
+func (c *buildCache) rebuildReason(path string, inputs *buildInputs) string {
+	b, err := os.ReadFile(c.indexFile(path, cli.Arch(inputs.Arch)))
+	if err != nil {
+		return cacheReasonNotCached
+	}
+	previous := &buildInputs{}
+	if err := json.Unmarshal(b, previous); err != nil {
+		return cacheReasonNotCached
+	}
+	changes := inputs.changes(previous)
+	if len(changes) == 0 {
+		return "cache entry not found"
+	}
+	return fmt.Sprintf("%s changed", strings.Join(changes, ", "))
+}
+
+// hasEntry returns true if the cache entry exists in the local directory, or
+// can be fetched from the cache image repository
+func (c *buildCache) hasEntry(key string) bool {
+	if utils.PathExists(filepath.Join(c.entryDir(key), cacheMetadataFile)) {
+		return true
+	}
+	if c.image == "" {
+		return false
+	}
+	tempDir, err := os.MkdirTemp(c.dir, "fetch-")
+	if err != nil {
+		return false
+	}
+	defer os.RemoveAll(tempDir)
+	if err := c.imageOperations.DownloadImageAndSaveFilesToDir(c.entryImage(key), tempDir); err != nil {
+		return false
+	}
+	if !utils.PathExists(filepath.Join(tempDir, cacheMetadataFile)) {
+		return false
+	}
+	// Another build may have fetched the same entry in the meantime
+	return os.Rename(tempDir, c.entryDir(key)) == nil || utils.PathExists(filepath.Join(c.entryDir(key), cacheMetadataFile))
+}
+
+// restore copies the cached plugin binaries to the artifacts directory and
+// returns the plugin they were built for
+func (c *buildCache) restore(allInputs map[cli.Arch]*buildInputs, absArtifactsDir, id string) (plugin, error) {
+	var p plugin
+	for arch, inputs := range allInputs {
+		entryDir := c.entryDir(inputs.key())
+		b, err := os.ReadFile(filepath.Join(entryDir, cacheMetadataFile))
+		if err != nil {
+			return plugin{}, err
+		}
+		metadata := &cacheEntryMetadata{}
+		if err := json.Unmarshal(b, metadata); err != nil {
+			return plugin{}, err
+		}
+		p = plugin{PluginDescriptor: metadata.Descriptor, target: metadata.Target, buildID: id}
+
+		outputDir := getPluginOutputDir(absArtifactsDir, p.Name, p.target, arch)
+		if err := copyDir(filepath.Join(entryDir, cacheArtifactsDir), outputDir); err != nil {
+			return plugin{}, err
+		}
+	}
+	return p, p.saveDescriptor(absArtifactsDir)
+}
+
+// store adds the plugin binaries built for each os/arch to the cache
+func (c *buildCache) store(p *plugin, path string, allInputs map[cli.Arch]*buildInputs, absArtifactsDir string) error {
+	for arch, inputs := range allInputs {
+		if err := c.storeEntry(p, arch, inputs, absArtifactsDir); err != nil {
+			return err
+		}
+		b, err := json.Marshal(inputs)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(c.indexFile(path, arch), b, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// storeEntry adds the plugin binaries built for the os/arch to the cache
+func (c *buildCache) storeEntry(p *plugin, arch cli.Arch, inputs *buildInputs, absArtifactsDir string) error {
+	key := inputs.key()
+	outputDir := getPluginOutputDir(absArtifactsDir, p.Name, p.target, arch)
+	binaryName := cli.MakeArtifactName(p.Name, arch)
+	files := []string{binaryName, provenance.FileName(binaryName), filepath.Join("test", cli.MakeArtifactName(p.Name+"-test", arch))}
+
+	tempDir, err := os.MkdirTemp(c.dir, "store-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+	for _, f := range files {
+		if !utils.PathExists(filepath.Join(outputDir, f)) {
+			continue
+		}
+		if err := copyFile(filepath.Join(outputDir, f), filepath.Join(tempDir, cacheArtifactsDir, f)); err != nil {
+			return err
+		}
+	}
+	b, err := json.Marshal(&cacheEntryMetadata{Descriptor: p.PluginDescriptor, Target: p.target, Inputs: *inputs})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, cacheMetadataFile), b, 0644); err != nil {
+		return err
+	}
+
+	if c.image != "" {
+		if err := c.imageOperations.PushImage(c.entryImage(key), []string{tempDir}); err != nil {
+			return err
+		}
+	}
+	// Another build may have cached the same entry in the meantime
+	if err := os.Rename(tempDir, c.entryDir(key)); err != nil && !utils.PathExists(filepath.Join(c.entryDir(key), cacheMetadataFile)) {
+		return err
+	}
+	return nil
+}
+
+// record records the cache status of the plugin at path
+func (c *buildCache) record(path, status string) {
+	c.resultsMutex.Lock()
+	defer c.resultsMutex.Unlock()
+	c.results[path] = status
+}
+
+// report logs which plugins were rebuilt and why
+func (c *buildCache) report() {
+	paths := make([]string, 0, len(c.results))
+	for path := range c.results {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	log.Info("build cache:")
+	for _, path := range paths {
+		log.Infof("  %s: %s", path, c.results[path])
+	}
+}
+
+func (c *buildCache) getGoVersion() (string, error) {
+	c.goVersionOnce.Do(func() {
+		out, err := goCommand("env", "GOVERSION").Output()
+		c.goVersion, c.goVersionErr = strings.TrimSpace(string(out)), err
+	})
+	return c.goVersion, c.goVersionErr
+}
+
+func (c *buildCache) entryDir(key string) string {
+	return filepath.Join(c.dir, cacheEntriesDir, key)
+}
+
+func (c *buildCache) entryImage(key string) string {
+	return fmt.Sprintf("%s:%s", c.image, key)
+}
+
+func (c *buildCache) indexFile(path string, arch cli.Arch) string {
+	return filepath.Join(c.dir, cacheIndexDir, fmt.Sprintf("%s-%s.json", filepath.Base(path), arch))
+}
+
+// pluginSourcesTemplate lists the directories of the packages of the main modules and of
+// the modules replaced by a local directory, prefixed with "D", along with the files of
+// these packages outside of their directory (e.g., embedded files of subdirectories)
+// and the go.mod files of the replaced modules, prefixed with "F"
+const pluginSourcesTemplate = `{{if .Module}}{{if or .Module.Main (and .Module.Replace (not .Module.Replace.Version))}}D {{.Dir}}
+{{- range .EmbedFiles}}{{"\n"}}F {{$.Dir}}/{{.}}{{end}}
+{{- range .IgnoredGoFiles}}{{"\n"}}F {{$.Dir}}/{{.}}{{end}}
+{{- if not .Module.Main}}{{"\n"}}F {{.Module.GoMod}}{{end}}
+{{- end}}{{end}}`
+
+// hashPluginSources returns the hash of the sources of the plugin at path in the
+// module at modPath, which are the files of the packages of the main modules and of
+// the modules replaced by a local directory the plugin and its tests depend on when
+// built with the environment env, which sets the os/arch to build for, including
+// their embedded files, as well as go.mod and the go.work file of the go workspace
+// if any, and the hash of go.sum
+func hashPluginSources(path, modPath, tags string, env []string) (string, string, error) {
+	pattern := "./" + filepath.ToSlash(filepath.Join(path, "..."))
+	cmd := goCommand("list", "-deps", "-test", "-tags", tags, "-f", pluginSourcesTemplate, pattern)
+	cmd.Dir = modPath
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	cmd.Env = append(cmd.Env, env...)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", "", fmt.Errorf("unable to list the packages of the plugin at path %q: %v", path, err)
+	}
+	cmd = goCommand("env", "GOMOD")
+	cmd.Dir = modPath
+	gomod, err := cmd.Output()
+	if err != nil {
+		return "", "", err
+	}
+	moduleRoot := filepath.Dir(strings.TrimSpace(string(gomod)))
+
+	dirs := map[string]bool{}
+	sourceFiles := map[string]bool{}
+	for _, line := range strings.Split(string(out), "\n") {
+		kind, name, found := strings.Cut(strings.TrimSpace(line), " ")
+		switch {
+		case !found:
+		case kind == "D":
+			dirs[name] = true
+		case kind == "F":
+			sourceFiles[filepath.FromSlash(name)] = true
+		}
+	}
+	sortedDirs := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		sortedDirs = append(sortedDirs, dir)
+	}
+	sort.Strings(sortedDirs)
+
+	files := []string{filepath.Join(moduleRoot, "go.mod")}
+	goWork, err := getGoWorkspace(modPath)
+	if err != nil {
+		return "", "", err
+	}
+	if goWork != "" {
+		// The go.work file can replace the dependencies of all the modules of the workspace
+		files = append(files, goWork)
+		if _, err := os.Stat(goWork + ".sum"); err == nil {
+			files = append(files, goWork+".sum")
+		}
+	}
+	for _, dir := range sortedDirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return "", "", err
+		}
+		for _, e := range entries {
+			if e.Type().IsRegular() {
+				sourceFiles[filepath.Join(dir, e.Name())] = true
+			}
+		}
+	}
+	sortedFiles := make([]string, 0, len(sourceFiles))
+	for file := range sourceFiles {
+		sortedFiles = append(sortedFiles, file)
+	}
+	sort.Strings(sortedFiles)
+	files = append(files, sortedFiles...)
+
+	// The files are hashed with their path relative to the module root, for
+	// the cache entries to be shared between different checkouts
+	hash := sha256.New()
+	for _, file := range files {
+		fileHash, err := helpers.GetDigest(file)
+		if err != nil {
+			return "", "", err
+		}
+		rel, err := filepath.Rel(moduleRoot, file)
+		if err != nil {
+			return "", "", err
+		}
+		fmt.Fprintf(hash, "%s %s\n", filepath.ToSlash(rel), fileHash)
+	}
+
+	goSumHash, err := helpers.GetDigest(filepath.Join(moduleRoot, "go.sum"))
+	if err != nil && !os.IsNotExist(err) {
+		return "", "", err
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), goSumHash, nil
+}
+
+// copyFile copies the file, preserving its permissions
+func copyFile(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	b, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, b, info.Mode().Perm())
+}
+
+// copyDir copies the files of the src directory to the dst directory. Plugin
+// binaries fetched from the cache image repository may have lost their
+// permissions, so all the copied files but the provenance statements are executable.
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if err := copyFile(path, filepath.Join(dst, rel)); err != nil {
+			return err
+		}
+		if strings.HasSuffix(path, provenance.FileSuffix) {
+			return nil
+		}
+		return os.Chmod(filepath.Join(dst, rel), 0755)
+	})
+}
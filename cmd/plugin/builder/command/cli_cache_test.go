@@ -0,0 +1,152 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tj/assert"
+
+	rtplugin "github.com/vmware-tanzu/tanzu-plugin-runtime/plugin"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+)
+
+func TestBuildInputsChanges(t *testing.T) {
+	previous := &buildInputs{SourceHash: "s1", GoSumHash: "g1", GoVersion: "go1.22", Version: "v1.0.0", LDFlags: "-w", Arch: "linux_amd64"}
+
+	current := *previous
+	assert.Empty(t, current.changes(previous))
+	assert.Equal(t, previous.key(), current.key())
+
+	current.SourceHash = "s2"
+	current.LDFlags = "-w -s"
+	assert.Equal(t, []string{"sources", "ldflags"}, current.changes(previous))
+	assert.NotEqual(t, previous.key(), current.key())
+}
+
+func TestBuildCacheStoreAndRestore(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "")
+	assert.Nil(t, err)
+	defer os.RemoveAll(tempDir)
+
+	version = "v1.0.0"
+	groupByOSArch = true
+	targetArch = []string{string(cli.LinuxAMD64)}
+	defer setGlobals(&PluginCompileArgs{})
+
+	cache, err := newBuildCache(filepath.Join(tempDir, "cache"), "")
+	assert.Nil(t, err)
+
+	// Simulate the build of the plugin
+	artifacts := filepath.Join(tempDir, "artifacts")
+	p := &plugin{PluginDescriptor: rtplugin.PluginDescriptor{Name: "foo", Version: "v1.0.0", Description: "foo plugin"}, target: "global"}
+	outputDir := getPluginOutputDir(artifacts, "foo", "global", cli.LinuxAMD64)
+	binary := filepath.Join(outputDir, cli.MakeArtifactName("foo", cli.LinuxAMD64))
+	assert.Nil(t, os.MkdirAll(filepath.Join(outputDir, "test"), 0755))
+	assert.Nil(t, os.WriteFile(binary, []byte("foo binary"), 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(outputDir, "test", cli.MakeArtifactName("foo-test", cli.LinuxAMD64)), []byte("foo test binary"), 0755))
+
+	inputs := map[cli.Arch]*buildInputs{cli.LinuxAMD64: {SourceHash: "s1", Version: "v1.0.0", Arch: string(cli.LinuxAMD64)}}
+	assert.False(t, cache.hasEntry(inputs[cli.LinuxAMD64].key()))
+	assert.Equal(t, cacheReasonNotCached, cache.rebuildReason("./cmd/plugin/foo", inputs[cli.LinuxAMD64]))
+	assert.Nil(t, cache.store(p, "./cmd/plugin/foo", inputs, artifacts))
+	assert.True(t, cache.hasEntry(inputs[cli.LinuxAMD64].key()))
+
+	// The cached binaries are restored in another artifacts directory
+	restoredArtifacts := filepath.Join(tempDir, "restored")
+	restored, err := cache.restore(inputs, restoredArtifacts, "id")
+	assert.Nil(t, err)
+	assert.Equal(t, "foo", restored.Name)
+	assert.Equal(t, "foo plugin", restored.Description)
+	assert.Equal(t, "global", restored.target)
+	restoredBinary := filepath.Join(getPluginOutputDir(restoredArtifacts, "foo", "global", cli.LinuxAMD64), cli.MakeArtifactName("foo", cli.LinuxAMD64))
+	b, err := os.ReadFile(restoredBinary)
+	assert.Nil(t, err)
+	assert.Equal(t, "foo binary", string(b))
+	info, err := os.Stat(restoredBinary)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+	assert.FileExists(t, filepath.Join(getPluginOutputDir(restoredArtifacts, "foo", "global", cli.LinuxAMD64), "test", cli.MakeArtifactName("foo-test", cli.LinuxAMD64)))
+
+	// The reason to rebuild the plugin is the change of its build inputs
+	changed := *inputs[cli.LinuxAMD64]
+	changed.SourceHash = "s2"
+	changed.GoFlags = "-trimpath"
+	assert.Equal(t, "sources, goflags changed", cache.rebuildReason("./cmd/plugin/foo", &changed))
+}
+
+func TestHashPluginSourcesPerTarget(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "")
+	assert.Nil(t, err)
+	defer os.RemoveAll(tempDir)
+
+	// The plugin depends on a different package for each os
+	files := map[string]string{
+		"go.mod":                       "module example.com/foo\n\ngo 1.21\n",
+		"cmd/plugin/foo/main.go":       "package main\n\nfunc main() { run() }\n",
+		"cmd/plugin/foo/run_linux.go":  "package main\n\nimport \"example.com/foo/pkg/linux\"\n\nfunc run() { linux.Run() }\n",
+		"cmd/plugin/foo/run_darwin.go": "package main\n\nimport \"example.com/foo/pkg/darwin\"\n\nfunc run() { darwin.Run() }\n",
+		"pkg/linux/run.go":             "package linux\n\n// Run runs\nfunc Run() {}\n",
+		"pkg/darwin/run.go":            "package darwin\n\n// Run runs\nfunc Run() {}\n",
+	}
+	for name, content := range files {
+		assert.Nil(t, os.MkdirAll(filepath.Dir(filepath.Join(tempDir, name)), 0755))
+		assert.Nil(t, os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644))
+	}
+	linuxEnv := []string{"GOOS=linux", "GOARCH=amd64"}
+	darwinEnv := []string{"GOOS=darwin", "GOARCH=arm64"}
+
+	linuxHash, _, err := hashPluginSources("cmd/plugin/foo", tempDir, "", linuxEnv)
+	assert.Nil(t, err)
+	darwinHash, _, err := hashPluginSources("cmd/plugin/foo", tempDir, "", darwinEnv)
+	assert.Nil(t, err)
+
+	// Only the hash of the sources for darwin changes when the darwin package changes
+	assert.Nil(t, os.WriteFile(filepath.Join(tempDir, "pkg/darwin/run.go"), []byte("package darwin\n\n// Run runs on darwin\nfunc Run() {}\n"), 0644))
+	hash, _, err := hashPluginSources("cmd/plugin/foo", tempDir, "", linuxEnv)
+	assert.Nil(t, err)
+	assert.Equal(t, linuxHash, hash)
+	hash, _, err = hashPluginSources("cmd/plugin/foo", tempDir, "", darwinEnv)
+	assert.Nil(t, err)
+	assert.NotEqual(t, darwinHash, hash)
+}
+
+func TestHashPluginSourcesWithReplacedModulesAndEmbeddedFiles(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "")
+	assert.Nil(t, err)
+	defer os.RemoveAll(tempDir)
+
+	// The plugin embeds the files of a subdirectory and depends on a module replaced by a local directory
+	files := map[string]string{
+		"foo/go.mod":                          "module example.com/foo\n\ngo 1.21\n\nrequire example.com/lib v0.0.0\n\nreplace example.com/lib => ../lib\n",
+		"foo/cmd/plugin/foo/main.go":          "package main\n\nimport (\n\t\"embed\"\n\n\t\"example.com/lib\"\n)\n\n//go:embed templates\nvar templates embed.FS\n\nfunc main() { lib.Run(templates) }\n",
+		"foo/cmd/plugin/foo/templates/a.tmpl": "a\n",
+		"lib/go.mod":                          "module example.com/lib\n\ngo 1.21\n",
+		"lib/run.go":                          "package lib\n\n// Run runs\nfunc Run(_ interface{}) {}\n",
+	}
+	for name, content := range files {
+		assert.Nil(t, os.MkdirAll(filepath.Dir(filepath.Join(tempDir, name)), 0755))
+		assert.Nil(t, os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644))
+	}
+	modPath := filepath.Join(tempDir, "foo")
+	env := []string{"GOOS=linux", "GOARCH=amd64", "GOFLAGS=-mod=mod"}
+
+	initialHash, _, err := hashPluginSources("cmd/plugin/foo", modPath, "", env)
+	assert.Nil(t, err)
+
+	// Changing an embedded file of a subdirectory changes the hash
+	assert.Nil(t, os.WriteFile(filepath.Join(modPath, "cmd/plugin/foo/templates/a.tmpl"), []byte("b\n"), 0644))
+	hash, _, err := hashPluginSources("cmd/plugin/foo", modPath, "", env)
+	assert.Nil(t, err)
+	assert.NotEqual(t, initialHash, hash)
+
+	// Changing the sources of the replaced module changes the hash
+	assert.Nil(t, os.WriteFile(filepath.Join(tempDir, "lib/run.go"), []byte("package lib\n\n// Run runs the lib\nfunc Run(_ interface{}) {}\n"), 0644))
+	newHash, _, err := hashPluginSources("cmd/plugin/foo", modPath, "", env)
+	assert.Nil(t, err)
+	assert.NotEqual(t, hash, newHash)
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/helpers"
 	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/types"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/provenance"
 	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
 )
 
@@ -43,6 +44,15 @@ var (
 	goflags                        string
 	targetArch                     []string
 	groupByOSArch                  bool
+	reproducible                   bool
+	// buildTime is the time recorded in the manifests and the provenance
+	// statements, set from SOURCE_DATE_EPOCH for reproducible builds
+	buildTime time.Time
+	// cache is the build cache of the plugin binaries, nil if disabled
+	cache *buildCache
+	// gowork is the go.work file of the go workspace to build the plugins in, or
+	// 'off' to disable the workspace mode, empty to let the go command find it
+	gowork string
 )
 
 type plugin struct {
@@ -53,6 +63,12 @@ type plugin struct {
 	modPath  string
 	buildID  string
 	target   string
+	// tags are the build tags of the plugin, including the build tags of all the plugins
+	tags string
+	// env are the environment variables of the build of the plugin from its build configuration
+	env []string
+	// releaseNotes are the release notes of the version of the plugin from its build configuration
+	releaseNotes string
 }
 
 // PluginCompileArgs contains the values to use for compiling plugins.
@@ -70,6 +86,18 @@ type PluginCompileArgs struct {
 	TargetArch                 []string
 	GroupByOSArch              bool
 	DebugSymbols               bool
+	// Reproducible builds the plugins with -trimpath, without build id and with
+	// a read-only module graph, and records SOURCE_DATE_EPOCH as the build time
+	Reproducible bool
+	// CacheDir is the local directory of the build cache. Up-to-date plugins
+	// are not rebuilt if either CacheDir or CacheImage is set.
+	CacheDir string
+	// CacheImage is the OCI image repository sharing the build cache entries
+	CacheImage string
+	// GoWork is the go.work file of the go workspace to build the plugins in, or
+	// 'off' to disable the workspace mode. By default, the go.work file found by
+	// the go command in the current directory or its parents is used, if any.
+	GoWork string
 }
 
 const local = "local"
@@ -101,6 +129,14 @@ func setGlobals(compileArgs *PluginCompileArgs) {
 	targetArch = compileArgs.TargetArch
 	groupByOSArch = compileArgs.GroupByOSArch
 	goflags = compileArgs.GoFlags
+	reproducible = compileArgs.Reproducible
+	gowork = compileArgs.GoWork
+	if gowork != "" && gowork != goWorkOff {
+		// The go commands are run in the directories of the modules of the plugins
+		if absGoWork, err := filepath.Abs(gowork); err == nil {
+			gowork = absGoWork
+		}
+	}
 
 	// Append version specific ldflag by default so that user doesn't need to pass this ldflag always.
 	ldflags = fmt.Sprintf("%s -X 'github.com/vmware-tanzu/tanzu-plugin-runtime/plugin/buildinfo.Version=%s'", ldflags, version)
@@ -111,12 +147,18 @@ func setGlobals(compileArgs *PluginCompileArgs) {
 	}
 
 	// Disable function inlining to reduce binary size
-	disableInlining := "-gcflags=all=-l"
+	defaultGoflags := "-gcflags=all=-l"
+	if reproducible {
+		// Remove the build id and the local file system paths from the binaries, and
+		// fail the build instead of updating go.mod when the module graph is not pinned
+		ldflags = fmt.Sprintf("%s -buildid=", ldflags)
+		defaultGoflags = fmt.Sprintf("%s -trimpath -mod=readonly", defaultGoflags)
+	}
 	if goflags != "" {
 		// Append the user-defined goflags so they can override the default if needed
-		goflags = fmt.Sprintf("%s %s", disableInlining, goflags)
+		goflags = fmt.Sprintf("%s %s", defaultGoflags, goflags)
 	} else {
-		goflags = disableInlining
+		goflags = defaultGoflags
 	}
 }
 
@@ -126,8 +168,28 @@ func Compile(compileArgs *PluginCompileArgs) error {
 
 	log.Infof("building local repository at %s, %v, %v", compileArgs.ArtifactsDir, compileArgs.Version, compileArgs.TargetArch)
 
+	buildTime = time.Now()
+	if reproducible {
+		var err error
+		buildTime, err = getSourceDateEpoch(compileArgs.SourcePath)
+		if err != nil {
+			return err
+		}
+		log.Infof("building reproducible plugins with SOURCE_DATE_EPOCH=%d", buildTime.Unix())
+	}
+
+	cache = nil
+	if compileArgs.CacheDir != "" || compileArgs.CacheImage != "" {
+		var err error
+		cache, err = newBuildCache(compileArgs.CacheDir, compileArgs.CacheImage)
+		if err != nil {
+			return err
+		}
+		log.Infof("using build cache at %s", cache.dir)
+	}
+
 	manifest := cli.Manifest{
-		CreatedTime: time.Now(),
+		CreatedTime: buildTime,
 		Plugins:     []cli.Plugin{},
 	}
 
@@ -136,6 +198,19 @@ func Compile(compileArgs *PluginCompileArgs) error {
 		return err
 	}
 
+	g := glob.MustCompile(compileArgs.Match)
+	var pluginPaths []string
+	for _, f := range files {
+		if f.IsDir() && g.Match(f.Name()) {
+			pluginPaths = append(pluginPaths, filepath.Join(compileArgs.SourcePath, f.Name()))
+		}
+	}
+
+	// Download the dependencies of the modules of the plugins once, instead of for each plugin
+	if err := downloadGoDeps(pluginPaths); err != nil {
+		return err
+	}
+
 	// Limit the number of concurrent operations we perform so we don't overwhelm the system.
 	maxConcurrent := helpers.GetMaxParallelism()
 	guard := make(chan struct{}, maxConcurrent)
@@ -145,30 +220,28 @@ func Compile(compileArgs *PluginCompileArgs) error {
 	var wg sync.WaitGroup
 	plugins := make(chan cli.Plugin, len(files))
 	fatalErrors := make(chan helpers.ErrInfo, len(files))
-	g := glob.MustCompile(compileArgs.Match)
-	for i, f := range files {
-		if f.IsDir() {
-			if g.Match(f.Name()) {
-				wg.Add(1)
-				guard <- struct{}{}
-				go func(fullPath, id string) {
-					defer wg.Done()
-					p, err := buildPlugin(fullPath, id)
-					if err != nil {
-						fatalErrors <- helpers.ErrInfo{Err: err, Path: fullPath, ID: id}
-					} else {
-						plug := cli.Plugin{
-							Name:        p.Name,
-							Description: p.Description,
-							Target:      p.target,
-							Versions:    []string{p.Version},
-						}
-						plugins <- plug
-					}
-					<-guard
-				}(filepath.Join(compileArgs.SourcePath, f.Name()), helpers.GetID(i+randSkew))
+	for i, pluginPath := range pluginPaths {
+		wg.Add(1)
+		guard <- struct{}{}
+		go func(fullPath, id string) {
+			defer wg.Done()
+			p, err := buildPlugin(fullPath, id)
+			if err != nil {
+				fatalErrors <- helpers.ErrInfo{Err: err, Path: fullPath, ID: id}
+			} else {
+				plug := cli.Plugin{
+					Name:        p.Name,
+					Description: p.Description,
+					Target:      p.target,
+					Versions:    []string{p.Version},
+				}
+				if p.releaseNotes != "" {
+					plug.ReleaseNotes = map[string]string{p.Version: p.releaseNotes}
+				}
+				plugins <- plug
 			}
-		}
+			<-guard
+		}(pluginPath, helpers.GetID(i+randSkew))
 	}
 
 	wg.Wait()
@@ -189,6 +262,10 @@ func Compile(compileArgs *PluginCompileArgs) error {
 		}
 	}
 
+	if cache != nil {
+		cache.report()
+	}
+
 	if hasFailed {
 		os.Exit(1)
 	}
@@ -214,23 +291,70 @@ func Compile(compileArgs *PluginCompileArgs) error {
 func buildPlugin(path, id string) (plugin, error) {
 	log.Infof("%s - building plugin at path %q", id, path)
 
-	var modPath string
+	// The plugin is either in the module of the current directory, or in another module
+	// of the repository, e.g., with its own go.mod file or in a module of a go workspace
+	modPath, relPath, err := getPluginModule(path)
+	if err != nil {
+		return plugin{}, err
+	}
+	if modPath != "" {
+		log.Infof("%s - plugin at path %q is in the module at path %q", id, path, modPath)
+	}
+
+	buildConfig, err := getPluginBuildConfig(path)
+	if err != nil {
+		log.Errorf("%s - error: %v", id, err)
+		return plugin{}, err
+	}
+	p := plugin{
+		path:    relPath,
+		modPath: modPath,
+		buildID: id,
+		tags:    getPluginBuildTags(buildConfig),
+		env:     getPluginBuildEnv(buildConfig),
+
+		releaseNotes: strings.TrimSpace(buildConfig.ReleaseNotes),
+	}
 
-	cmd := goCommand("run", "-ldflags", ldflags, "-tags", tags)
+	cmd := goCommand("run", "-ldflags", ldflags, "-tags", p.tags)
+	cmd.Dir = modPath
+	if len(p.env) > 0 {
+		if cmd.Env == nil {
+			cmd.Env = os.Environ()
+		}
+		cmd.Env = append(cmd.Env, p.env...)
+	}
+	cmd.Args = append(cmd.Args, fmt.Sprintf("./%s", filepath.ToSlash(relPath)))
 
-	if isLocalGoModFileExists(path) {
-		modPath = path
-		cmd.Dir = modPath
-		cmd.Args = append(cmd.Args, "./.")
-		log.Infof("%s - running godep path %q", id, path)
-		err := runDownloadGoDep(path, id)
+	if reproducible {
+		if err := verifyModuleGraph(modPath, id); err != nil {
+			log.Errorf("%s - the module graph of the plugin at path %q is not pinned - error: %v", id, path, err)
+			return plugin{}, err
+		}
+	}
+
+	var cacheInputs map[cli.Arch]*buildInputs
+	if cache != nil {
+		var reason string
+		var err error
+		cacheInputs, reason, err = cache.lookup(path, &p)
 		if err != nil {
-			log.Errorf("%s - cannot download go dependencies in path: %s - error: %v", id, path, err)
+			log.Errorf("%s - unable to compute the build cache key of the plugin at path %q - error: %v", id, path, err)
 			return plugin{}, err
 		}
-	} else {
-		modPath = ""
-		cmd.Args = append(cmd.Args, fmt.Sprintf("./%s", path))
+		if reason == "" {
+			cached, err := cache.restore(cacheInputs, artifactsDir, id)
+			if err == nil {
+				log.Infof("%s - plugin %q is up to date, reusing the cached binaries", id, cached.Name)
+				cache.record(path, cacheReasonReused)
+				cached.releaseNotes = p.releaseNotes
+				return cached, nil
+			}
+			log.Warningf("%s - unable to reuse the cached binaries of the plugin at path %q: %v", id, path, err)
+			reason = "cache entry unusable"
+		}
+		log.Infof("%s - rebuilding plugin at path %q: %s", id, path, reason)
+		cache.record(path, "rebuilt, "+reason)
 	}
 
 	cmd.Args = append(cmd.Args, "info")
@@ -271,25 +395,11 @@ func buildPlugin(path, id string) (plugin, error) {
 		return plugin{}, err
 	}
 
-	p := plugin{
-		PluginDescriptor: desc,
-		docPath:          docPath,
-		buildID:          id,
-		target:           target,
-	}
-
-	if modPath != "" {
-		p.path = "."
-		if testPath == "" {
-			p.testPath = ""
-		} else {
-			p.testPath = "test"
-		}
-		p.modPath = modPath
-	} else {
-		p.path = path
-		p.testPath = testPath
-		p.modPath = ""
+	p.PluginDescriptor = desc
+	p.docPath = docPath
+	p.target = target
+	if testPath != "" {
+		p.testPath = filepath.Join(relPath, "test")
 	}
 
 	log.V(4).Infof("plugin %v", p)
@@ -300,6 +410,12 @@ func buildPlugin(path, id string) (plugin, error) {
 		return plugin{}, err
 	}
 
+	if cache != nil {
+		if err := cache.store(&p, path, cacheInputs, artifactsDir); err != nil {
+			log.Warningf("%s - unable to cache the binaries of plugin %s: %v", id, desc.Name, err)
+		}
+	}
+
 	return p, nil
 }
 
@@ -308,7 +424,7 @@ type target struct {
 	args []string
 }
 
-func (t target) build(targetPath, prefix, modPath, ldflags, tags, goflags string) error {
+func (t target) build(targetPath, prefix, modPath, ldflags, tags, goflags string, env []string) error {
 	cmd := goCommand("build")
 
 	var commonArgs = []string{
@@ -322,16 +438,28 @@ func (t target) build(targetPath, prefix, modPath, ldflags, tags, goflags string
 	cmd.Args = append(cmd.Args, t.args...)
 	cmd.Args = append(cmd.Args, commonArgs...)
 
-	cmd.Env = append(cmd.Env, os.Environ()...)
+	// Keep the environment set by goCommand, if any
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
 	cmd.Env = append(cmd.Env, t.env...)
+	// The environment of the plugin overrides the environment of the os/arch
+	cmd.Env = append(cmd.Env, env...)
+	if reproducible {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("SOURCE_DATE_EPOCH=%d", buildTime.Unix()))
+	}
 
 	if modPath != "" {
 		cmd.Dir = modPath
 	}
 
-	cmd.Args = append(cmd.Args, fmt.Sprintf("./%s", targetPath))
+	cmd.Args = append(cmd.Args, fmt.Sprintf("./%s", filepath.ToSlash(targetPath)))
 
-	log.Infof("%s$ %s", prefix, cmd.String())
+	if len(env) > 0 {
+		log.Infof("%s$ %s %s", prefix, strings.Join(env, " "), cmd.String())
+	} else {
+		log.Infof("%s$ %s", prefix, cmd.String())
+	}
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		log.Errorf("%serror: %v", prefix, err)
@@ -446,18 +574,24 @@ func (p *plugin) compile() error {
 		return err
 	}
 
-	err = buildTargets(p.path, absArtifactsDir, p.Name, p.target, p.buildID, p.modPath, false)
+	err = p.buildTargets(p.path, absArtifactsDir, false)
 	if err != nil {
 		return err
 	}
 
 	if p.testPath != "" {
-		err = buildTargets(p.testPath, absArtifactsDir, p.Name, p.target, p.buildID, p.modPath, true)
+		err = p.buildTargets(p.testPath, absArtifactsDir, true)
 		if err != nil {
 			return err
 		}
 	}
 
+	return p.saveDescriptor(absArtifactsDir)
+}
+
+// saveDescriptor saves the plugin descriptor in the artifacts directory
+// when the artifacts are not grouped by os/arch
+func (p *plugin) saveDescriptor(absArtifactsDir string) error {
 	if !groupByOSArch {
 		b, err := yaml.Marshal(p.PluginDescriptor)
 		if err != nil {
@@ -473,11 +607,8 @@ func (p *plugin) compile() error {
 	return nil
 }
 
-func buildTargets(targetPath, artifactsDir, pluginName, target, id, modPath string, isTest bool) error {
-	if id != "" {
-		id = fmt.Sprintf("%s - ", id)
-	}
-
+// getTargets returns the target builders of the os/arch to build the plugins for
+func getTargets() map[cli.Arch]targetBuilder {
 	targets := map[cli.Arch]targetBuilder{}
 	for _, buildArch := range targetArch {
 		if buildArch == string(AllTargets) {
@@ -494,29 +625,87 @@ func buildTargets(targetPath, artifactsDir, pluginName, target, id, modPath stri
 			}
 		}
 	}
+	return targets
+}
+
+// getPluginOutputDir returns the directory of the plugin binary built for the os/arch
+func getPluginOutputDir(artifactsDir, pluginName, target string, arch cli.Arch) string {
+	outputDir := artifactsDir
+	if groupByOSArch {
+		outputDir = filepath.Join(outputDir, arch.OS(), arch.Arch(), target)
+	}
+	return filepath.Join(outputDir, pluginName, version)
+}
 
-	for arch, targetBuilder := range targets {
-		pn := pluginName
+// buildTargets builds the plugin, or its test plugin, at the target path for the os/arch to build the plugins for
+func (p *plugin) buildTargets(targetPath, artifactsDir string, isTest bool) error {
+	id := ""
+	if p.buildID != "" {
+		id = fmt.Sprintf("%s - ", p.buildID)
+	}
 
-		outputDir := artifactsDir
-		if groupByOSArch {
-			outputDir = filepath.Join(outputDir, arch.OS(), arch.Arch(), target)
-		}
-		outputDir = filepath.Join(outputDir, pn, version)
+	for arch, targetBuilder := range getTargets() {
+		pn := p.Name
+
+		outputDir := getPluginOutputDir(artifactsDir, pn, p.target, arch)
 		if isTest {
 			outputDir = filepath.Join(outputDir, "test")
 			pn = fmt.Sprintf("%s-test", pn)
 		}
 
 		tgt := targetBuilder(pn, outputDir)
-		err := tgt.build(targetPath, id, modPath, ldflags, tags, goflags)
+		err := tgt.build(targetPath, id, p.modPath, ldflags, p.tags, goflags, p.env)
 		if err != nil {
 			return err
 		}
+
+		if !isTest {
+			err = saveProvenance(filepath.Join(outputDir, cli.MakeArtifactName(pn, arch)), targetPath, p.modPath, pn, p.target, p.tags, arch, id)
+			if err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
 
+// saveProvenance saves the provenance statement of the plugin binary next to it
+func saveProvenance(binaryPath, targetPath, modPath, pluginName, target, tags string, arch cli.Arch, prefix string) error {
+	sourceDir := targetPath
+	if modPath != "" {
+		sourceDir = modPath
+	}
+	finishedOn := buildTime
+	if !reproducible {
+		finishedOn = time.Now()
+	}
+	params := &provenance.BuildParameters{
+		Plugin:       pluginName,
+		Target:       target,
+		Version:      version,
+		OS:           arch.OS(),
+		Arch:         arch.Arch(),
+		LDFlags:      ldflags,
+		Tags:         tags,
+		GoFlags:      goflags,
+		Reproducible: reproducible,
+	}
+	statement, err := provenance.NewStatement(binaryPath, sourceDir, params, finishedOn)
+	if err != nil {
+		return err
+	}
+	settings := statement.Predicate.BuildDefinition.InternalParameters.Settings
+	if reproducible && settings["vcs.modified"] == "true" {
+		log.Warningf("%splugin %q is built from uncommitted changes, the build cannot be reproduced from commit %s", prefix, pluginName, settings["vcs.revision"])
+	}
+
+	b, err := statement.Marshal()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(provenance.FileName(binaryPath), b, 0644)
+}
+
 func runDownloadGoDep(targetPath, prefix string) error {
 	cmdgomoddownload := goCommand("mod", "download")
 	cmdgomoddownload.Dir = targetPath
@@ -531,6 +720,45 @@ func runDownloadGoDep(targetPath, prefix string) error {
 	return nil
 }
 
+// verifyModuleGraph verifies that the dependencies of the module in the specified
+// directory have not been modified since they were downloaded, and that go.mod and
+// go.sum pin the complete module graph, so that the build does not depend on the
+// state of the module cache or of the module proxy
+func verifyModuleGraph(modPath, prefix string) error {
+	for _, args := range [][]string{{"mod", "verify"}, {"list", "-mod=readonly", "-m", "all"}} {
+		cmd := goCommand(args...)
+		cmd.Dir = modPath
+
+		log.Infof("%s - $ %s", prefix, cmd.String())
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			log.Errorf("%s - output: %v", prefix, string(output))
+			return err
+		}
+	}
+	return nil
+}
+
+// getSourceDateEpoch returns the time set by the SOURCE_DATE_EPOCH environment
+// variable, or the time of the last commit of the sources if not set
+func getSourceDateEpoch(sourcePath string) (time.Time, error) {
+	epoch := os.Getenv("SOURCE_DATE_EPOCH")
+	if epoch == "" {
+		cmd := exec.Command("git", "log", "-1", "--format=%ct")
+		cmd.Dir = sourcePath
+		out, err := cmd.Output()
+		if err != nil {
+			return time.Time{}, fmt.Errorf("SOURCE_DATE_EPOCH is not set and the time of the last commit of %q cannot be found: %v", sourcePath, err)
+		}
+		epoch = strings.TrimSpace(string(out))
+	}
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid SOURCE_DATE_EPOCH %q: %v", epoch, err)
+	}
+	return time.Unix(seconds, 0).UTC(), nil
+}
+
 func isLocalGoModFileExists(path string) bool {
 	_, err := os.Stat(filepath.Join(path, "go.mod"))
 	return err == nil
@@ -538,10 +766,15 @@ func isLocalGoModFileExists(path string) bool {
 
 func goCommand(arg ...string) *exec.Cmd {
 	cmd := exec.Command("go", arg...)
-	if goprivate != "" {
+	if goprivate != "" || gowork != "" {
 		cmd.Env = os.Environ()
+	}
+	if goprivate != "" {
 		cmd.Env = append(cmd.Env, fmt.Sprintf("GOPRIVATE=%s", goprivate))
 	}
+	if gowork != "" {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("GOWORK=%s", gowork))
+	}
 	return cmd
 }
 
@@ -664,7 +897,7 @@ func savePluginGroupManifest(plugins []cli.Plugin, artifactsDir, pluginScopeAsso
 	}
 
 	pgManifest := cli.PluginGroupManifest{
-		CreatedTime: time.Now(),
+		CreatedTime: buildTime,
 		Plugins:     []cli.PluginNameTargetScopeVersion{},
 	}
 
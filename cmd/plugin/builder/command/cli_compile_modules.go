@@ -0,0 +1,164 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/types"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
+)
+
+// pluginBuildConfigFileName is the name of the optional file specifying the
+// build configuration of a plugin, in the directory of the plugin
+const pluginBuildConfigFileName = "plugin.yaml"
+
+// goWorkOff disables the workspace mode of the go command
+const goWorkOff = "off"
+
+// getPluginModule returns the directory of the go module the plugin at path belongs to,
+// which is the closest directory with a go.mod file, and the path of the plugin relative
+// to that directory. The directory of the module is empty if the plugin belongs to the
+// module of the current directory, in which case the path of the plugin is unchanged.
+func getPluginModule(path string) (modPath, relPath string, err error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", "", err
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", "", err
+	}
+
+	for dir := absPath; dir != wd; dir = filepath.Dir(dir) {
+		if isLocalGoModFileExists(dir) {
+			relPath, err := filepath.Rel(dir, absPath)
+			if err != nil {
+				return "", "", err
+			}
+			if filepath.IsAbs(path) {
+				return dir, relPath, nil
+			}
+			modPath, err := filepath.Rel(wd, dir)
+			return modPath, relPath, err
+		}
+		if filepath.Dir(dir) == dir {
+			// The plugin is not in the current directory, use the module of the current directory
+			break
+		}
+	}
+	return "", path, nil
+}
+
+// getPluginBuildConfig returns the build configuration of the plugin at path,
+// which is empty if the plugin does not have a build configuration file
+func getPluginBuildConfig(path string) (*types.PluginBuildConfig, error) {
+	config := &types.PluginBuildConfig{}
+	b, err := os.ReadFile(filepath.Join(path, pluginBuildConfigFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return nil, err
+	}
+	if err := yaml.Unmarshal(b, config); err != nil {
+		return nil, fmt.Errorf("unable to parse the build configuration %q of the plugin: %v", filepath.Join(path, pluginBuildConfigFileName), err)
+	}
+	for _, env := range config.Build.Env {
+		if !strings.Contains(env, "=") {
+			return nil, fmt.Errorf("invalid environment variable %q in the build configuration of the plugin at path %q, must be of the form NAME=VALUE", env, path)
+		}
+	}
+	return config, nil
+}
+
+// getPluginBuildTags returns the build tags of all the plugins merged with the
+// build tags of the plugin, as a comma-separated list
+func getPluginBuildTags(config *types.PluginBuildConfig) string {
+	seen := map[string]bool{}
+	var merged []string
+	for _, tag := range append(strings.FieldsFunc(tags, func(r rune) bool { return r == ',' || r == ' ' }), config.Build.Tags...) {
+		if tag = strings.TrimSpace(tag); tag != "" && !seen[tag] {
+			seen[tag] = true
+			merged = append(merged, tag)
+		}
+	}
+	return strings.Join(merged, ",")
+}
+
+// getPluginBuildEnv returns the environment variables of the build of the plugin,
+// which override the environment variables of the os/arch
+func getPluginBuildEnv(config *types.PluginBuildConfig) []string {
+	var env []string
+	if config.Build.CGOEnabled != nil {
+		if *config.Build.CGOEnabled {
+			env = append(env, "CGO_ENABLED=1")
+		} else {
+			env = append(env, "CGO_ENABLED=0")
+		}
+	}
+	return append(env, config.Build.Env...)
+}
+
+// getGoWorkspace returns the go.work file used by the go command when run in
+// the directory, which is empty if the go command is not in workspace mode
+func getGoWorkspace(dir string) (string, error) {
+	cmd := goCommand("env", "GOWORK")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("unable to get the go workspace: %v", err)
+	}
+	gowork := strings.TrimSpace(string(out))
+	if gowork == goWorkOff {
+		return "", nil
+	}
+	return gowork, nil
+}
+
+// downloadGoDeps downloads the dependencies of the go modules of the plugins once
+// before building them. In workspace mode, the dependencies of all the modules
+// of the workspace are downloaded at once, otherwise the dependencies of each
+// module other than the module of the current directory are downloaded.
+func downloadGoDeps(pluginPaths []string) error {
+	gowork, err := getGoWorkspace("")
+	if err != nil {
+		return err
+	}
+	if gowork != "" {
+		log.Infof("building plugins in the go workspace %q", gowork)
+		return runDownloadGoDep("", "")
+	}
+
+	modPaths := map[string]bool{}
+	for _, path := range pluginPaths {
+		modPath, _, err := getPluginModule(path)
+		if err != nil {
+			return err
+		}
+		if modPath != "" {
+			modPaths[modPath] = true
+		}
+	}
+	sortedModPaths := make([]string, 0, len(modPaths))
+	for modPath := range modPaths {
+		sortedModPaths = append(sortedModPaths, modPath)
+	}
+	sort.Strings(sortedModPaths)
+
+	for _, modPath := range sortedModPaths {
+		log.Infof("downloading the go dependencies of the module at path %q", modPath)
+		if err := runDownloadGoDep(modPath, ""); err != nil {
+			log.Errorf("cannot download go dependencies in path: %s - error: %v", modPath, err)
+			return err
+		}
+	}
+	return nil
+}
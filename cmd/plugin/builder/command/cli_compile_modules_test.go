@@ -0,0 +1,110 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tj/assert"
+)
+
+func TestGetPluginModule(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "modules")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	dir, err = filepath.EvalSymlinks(dir)
+	assert.Nil(err)
+
+	// A repository with plugins in the main module, with their own go.mod
+	// and in a module with multiple plugins
+	for _, d := range []string{"cmd/plugin/foo", "cmd/plugin/bar", "plugins/cmd/baz"} {
+		assert.Nil(os.MkdirAll(filepath.Join(dir, d), 0755))
+	}
+	for _, f := range []string{"go.mod", "cmd/plugin/bar/go.mod", "plugins/go.mod"} {
+		assert.Nil(os.WriteFile(filepath.Join(dir, f), []byte("module example.com/test\n"), 0644))
+	}
+
+	wd, err := os.Getwd()
+	assert.Nil(err)
+	defer func() { _ = os.Chdir(wd) }()
+	assert.Nil(os.Chdir(dir))
+
+	modPath, relPath, err := getPluginModule(filepath.Join("cmd", "plugin", "foo"))
+	assert.Nil(err)
+	assert.Equal("", modPath)
+	assert.Equal(filepath.Join("cmd", "plugin", "foo"), relPath)
+
+	modPath, relPath, err = getPluginModule(filepath.Join("cmd", "plugin", "bar"))
+	assert.Nil(err)
+	assert.Equal(filepath.Join("cmd", "plugin", "bar"), modPath)
+	assert.Equal(".", relPath)
+
+	modPath, relPath, err = getPluginModule(filepath.Join("plugins", "cmd", "baz"))
+	assert.Nil(err)
+	assert.Equal("plugins", modPath)
+	assert.Equal(filepath.Join("cmd", "baz"), relPath)
+
+	modPath, relPath, err = getPluginModule(filepath.Join(dir, "plugins", "cmd", "baz"))
+	assert.Nil(err)
+	assert.Equal(filepath.Join(dir, "plugins"), modPath)
+	assert.Equal(filepath.Join("cmd", "baz"), relPath)
+}
+
+func TestPluginBuildConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "plugin")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	defer setGlobals(&PluginCompileArgs{})
+	setGlobals(&PluginCompileArgs{Tags: "netgo, osusergo"})
+
+	// Without build configuration, the build tags of all the plugins are used
+	config, err := getPluginBuildConfig(dir)
+	assert.Nil(err)
+	assert.Equal("netgo,osusergo", getPluginBuildTags(config))
+	assert.Empty(getPluginBuildEnv(config))
+
+	assert.Nil(os.WriteFile(filepath.Join(dir, pluginBuildConfigFileName), []byte(`build:
+  tags: [sqlite, netgo]
+  cgoEnabled: true
+  env:
+    - CC=musl-gcc
+releaseNotes: |
+  Add the list command
+`), 0644))
+	config, err = getPluginBuildConfig(dir)
+	assert.Nil(err)
+	assert.Equal("netgo,osusergo,sqlite", getPluginBuildTags(config))
+	assert.Equal([]string{"CGO_ENABLED=1", "CC=musl-gcc"}, getPluginBuildEnv(config))
+	assert.Equal("Add the list command\n", config.ReleaseNotes)
+
+	assert.Nil(os.WriteFile(filepath.Join(dir, pluginBuildConfigFileName), []byte("build:\n  env: [CC]\n"), 0644))
+	_, err = getPluginBuildConfig(dir)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "must be of the form NAME=VALUE")
+
+	assert.Nil(os.WriteFile(filepath.Join(dir, pluginBuildConfigFileName), []byte("build: [\n"), 0644))
+	_, err = getPluginBuildConfig(dir)
+	assert.NotNil(err)
+}
+
+func TestSetGlobalsGoWork(t *testing.T) {
+	defer setGlobals(&PluginCompileArgs{})
+
+	setGlobals(&PluginCompileArgs{GoWork: goWorkOff})
+	assert.Equal(t, goWorkOff, gowork)
+	assert.Contains(t, goCommand("env").Env, "GOWORK=off")
+
+	setGlobals(&PluginCompileArgs{GoWork: "go.work"})
+	assert.True(t, filepath.IsAbs(gowork))
+
+	setGlobals(&PluginCompileArgs{})
+	assert.Nil(t, goCommand("env").Env)
+}
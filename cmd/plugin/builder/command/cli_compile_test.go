@@ -4,7 +4,10 @@
 package command
 
 import (
+	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/tj/assert"
 
@@ -196,3 +199,29 @@ func TestMergePluginGroupManifest(t *testing.T) {
 		assert.Equal(foundPlugin.Version, plugin.Version)
 	}
 }
+
+func TestSetGlobalsReproducible(t *testing.T) {
+	defer setGlobals(&PluginCompileArgs{})
+
+	setGlobals(&PluginCompileArgs{Version: "v1.0.0", GoFlags: "-v"})
+	assert.False(t, strings.Contains(goflags, "-trimpath"))
+	assert.False(t, strings.Contains(ldflags, "-buildid="))
+
+	setGlobals(&PluginCompileArgs{Version: "v1.0.0", GoFlags: "-v", Reproducible: true})
+	assert.Equal(t, "-gcflags=all=-l -trimpath -mod=readonly -v", goflags)
+	assert.True(t, strings.HasSuffix(ldflags, " -w -s -buildid="))
+}
+
+func TestGetSourceDateEpoch(t *testing.T) {
+	os.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	defer os.Unsetenv("SOURCE_DATE_EPOCH")
+
+	buildTime, err := getSourceDateEpoch(".")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Unix(1700000000, 0).UTC(), buildTime)
+
+	os.Setenv("SOURCE_DATE_EPOCH", "yesterday")
+	_, err = getSourceDateEpoch(".")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `invalid SOURCE_DATE_EPOCH "yesterday"`)
+}
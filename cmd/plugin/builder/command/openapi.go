@@ -0,0 +1,568 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// openAPISpec is the subset of an OpenAPI 3 specification used to generate the
+// commands of a plugin. As JSON is a subset of YAML, it is parsed from both formats.
+type openAPISpec struct {
+	OpenAPI string `yaml:"openapi"`
+	Swagger string `yaml:"swagger"`
+	Info    struct {
+		Title       string `yaml:"title"`
+		Description string `yaml:"description"`
+	} `yaml:"info"`
+	Servers []struct {
+		URL string `yaml:"url"`
+	} `yaml:"servers"`
+	Paths      map[string]*openAPIPathItem `yaml:"paths"`
+	Components struct {
+		Schemas    map[string]*openAPISchema    `yaml:"schemas"`
+		Parameters map[string]*openAPIParameter `yaml:"parameters"`
+	} `yaml:"components"`
+}
+
+type openAPIPathItem struct {
+	Get        *openAPIOperation   `yaml:"get"`
+	Post       *openAPIOperation   `yaml:"post"`
+	Put        *openAPIOperation   `yaml:"put"`
+	Patch      *openAPIOperation   `yaml:"patch"`
+	Delete     *openAPIOperation   `yaml:"delete"`
+	Parameters []*openAPIParameter `yaml:"parameters"`
+}
+
+type openAPIOperation struct {
+	OperationID string              `yaml:"operationId"`
+	Summary     string              `yaml:"summary"`
+	Description string              `yaml:"description"`
+	Tags        []string            `yaml:"tags"`
+	Parameters  []*openAPIParameter `yaml:"parameters"`
+	RequestBody *struct {
+		Required bool `yaml:"required"`
+	} `yaml:"requestBody"`
+	Responses map[string]struct {
+		Content map[string]struct {
+			Schema *openAPISchema `yaml:"schema"`
+		} `yaml:"content"`
+	} `yaml:"responses"`
+}
+
+type openAPIParameter struct {
+	Ref         string         `yaml:"$ref"`
+	Name        string         `yaml:"name"`
+	In          string         `yaml:"in"`
+	Description string         `yaml:"description"`
+	Required    bool           `yaml:"required"`
+	Schema      *openAPISchema `yaml:"schema"`
+}
+
+type openAPISchema struct {
+	Ref        string                    `yaml:"$ref"`
+	Type       string                    `yaml:"type"`
+	Items      *openAPISchema            `yaml:"items"`
+	Properties map[string]*openAPISchema `yaml:"properties"`
+}
+
+// openAPIResource is a resource of the API, for which a command is generated
+// with a sub-command per operation of the resource
+type openAPIResource struct {
+	// Name is the noun used as command name
+	Name string
+	// FuncName is the name of the function creating the command
+	FuncName string
+	// FileName is the name of the go file of the command, without extension
+	FileName    string
+	Short       string
+	Description string
+	Commands    []*openAPICommand
+}
+
+// openAPICommand is the command generated for an operation of the API
+type openAPICommand struct {
+	// Verb is the verb used as command name
+	Verb string
+	// Use is the usage of the command
+	Use string
+	// FuncName is the name of the function creating the command
+	FuncName string
+	Short    string
+	Long     string
+	Method   string
+	Path     string
+	// Arg is the path parameter identifying the resource, provided as argument
+	// of the command rather than as a flag
+	Arg *openAPIFlag
+	// Flags are the other parameters of the operation
+	Flags []*openAPIFlag
+	// HasBody indicates that the operation requires a request body,
+	// read from the file specified with the '--file' flag
+	HasBody      bool
+	BodyRequired bool
+	// Columns are the properties of the returned objects shown in table output
+	Columns []string
+}
+
+// openAPIFlag is the flag or argument generated for a parameter of an operation
+type openAPIFlag struct {
+	// Name is the name of the flag, or the name of the argument for the usage of the command
+	Name string
+	// VarName is the name of the variable storing the value of the flag
+	VarName string
+	// ParamName is the name of the parameter in the API
+	ParamName string
+	// In is the location of the parameter: path, query or header
+	In string
+	// GoType is the type of the variable: string, int, float64, bool or []string
+	GoType string
+	// FlagFunc is the function of the flag set defining the flag for the type
+	FlagFunc string
+	// DefaultValue is the default value of the flag for the type
+	DefaultValue string
+	Required     bool
+	Description  string
+}
+
+var (
+	// nonAlphanumericRegexp matches the separators of words
+	nonAlphanumericRegexp = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+	// versionSegmentRegexp matches the version segments of paths, e.g., 'v1' or 'v1alpha1'
+	versionSegmentRegexp = regexp.MustCompile(`^v[0-9]+([a-z]+[0-9]*)?$`)
+	// pathParamRegexp matches the parameters of paths, e.g., '{name}'
+	pathParamRegexp = regexp.MustCompile(`{([^}/]+)}`)
+	// customMethodRegexp matches the paths of custom methods, e.g., '/clusters/{name}:upgrade'
+	customMethodRegexp = regexp.MustCompile(`{([^}/]+)}:([a-zA-Z][a-zA-Z0-9]*)$`)
+
+	// reservedFlagNames are the flags added to the generated commands which
+	// cannot be used for the parameters of the API
+	reservedFlagNames = map[string]bool{"output": true, "file": true, "help": true}
+)
+
+// readOpenAPISpec reads the OpenAPI 3 specification from the file, in YAML or JSON format
+func readOpenAPISpec(specFile string) (*openAPISpec, error) {
+	data, err := os.ReadFile(specFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read the OpenAPI specification %q", specFile)
+	}
+	spec := &openAPISpec{}
+	if err := yaml.Unmarshal(data, spec); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse the OpenAPI specification %q", specFile)
+	}
+	if spec.Swagger != "" || !strings.HasPrefix(spec.OpenAPI, "3.") {
+		return nil, errors.Errorf("%q is not an OpenAPI 3 specification, only OpenAPI 3 specifications are supported", specFile)
+	}
+	if len(spec.Paths) == 0 {
+		return nil, errors.Errorf("the OpenAPI specification %q does not define any path", specFile)
+	}
+	return spec, nil
+}
+
+// basePath returns the path of the URL of the first server of the specification,
+// which the paths of the operations are relative to
+func (spec *openAPISpec) basePath() string {
+	if len(spec.Servers) == 0 {
+		return ""
+	}
+	u, err := url.Parse(spec.Servers[0].URL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(u.Path, "/")
+}
+
+// resources returns the resources of the API with a command for each of their operations.
+// The operations are grouped by their first tag, or by the first segment of their path.
+func (spec *openAPISpec) resources() ([]*openAPIResource, error) {
+	resourcesByName := map[string]*openAPIResource{}
+
+	paths := make([]string, 0, len(spec.Paths))
+	for p := range spec.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		pathItem := spec.Paths[p]
+		if pathItem == nil {
+			continue
+		}
+		for _, mo := range []struct {
+			method    string
+			operation *openAPIOperation
+		}{
+			{"GET", pathItem.Get},
+			{"POST", pathItem.Post},
+			{"PUT", pathItem.Put},
+			{"PATCH", pathItem.Patch},
+			{"DELETE", pathItem.Delete},
+		} {
+			if mo.operation == nil {
+				continue
+			}
+			resourceName := getResourceName(p, mo.operation)
+			if resourceName == "" {
+				return nil, errors.Errorf("unable to determine the resource of the operation %s %s", mo.method, p)
+			}
+			resource, exists := resourcesByName[resourceName]
+			if !exists {
+				resource = &openAPIResource{
+					Name:     resourceName,
+					FuncName: "new" + toUpperCamelCase(resourceName) + "Cmd",
+					FileName: strings.ReplaceAll(resourceName, "-", "_"),
+					Short:    fmt.Sprintf("Manage %s resources", resourceName),
+				}
+				resourcesByName[resourceName] = resource
+			}
+			command, err := spec.getCommand(resource, mo.method, p, pathItem, mo.operation)
+			if err != nil {
+				return nil, err
+			}
+			resource.Commands = append(resource.Commands, command)
+		}
+	}
+
+	resources := make([]*openAPIResource, 0, len(resourcesByName))
+	for _, r := range resourcesByName {
+		resources = append(resources, r)
+	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i].Name < resources[j].Name })
+	return resources, nil
+}
+
+// getCommand returns the command of the resource for the operation
+func (spec *openAPISpec) getCommand(resource *openAPIResource, method, p string, pathItem *openAPIPathItem, operation *openAPIOperation) (*openAPICommand, error) {
+	verb := getVerb(method, p)
+	for _, c := range resource.Commands {
+		if c.Verb == verb {
+			// Use the operation id for the other operations with the same verb
+			verb = toKebabCase(operation.OperationID)
+			if verb == "" {
+				verb = strings.ToLower(method) + "-" + toKebabCase(p)
+			}
+			break
+		}
+	}
+
+	command := &openAPICommand{
+		Verb:     verb,
+		FuncName: "new" + toUpperCamelCase(resource.Name) + toUpperCamelCase(verb) + "Cmd",
+		Short:    firstLine(operation.Summary),
+		Long:     strings.TrimSpace(operation.Description),
+		Method:   method,
+		Path:     p,
+		HasBody:  operation.RequestBody != nil,
+		Columns:  spec.getColumns(operation),
+	}
+	if command.Short == "" {
+		command.Short = firstLine(operation.Description)
+	}
+	if command.Short == "" {
+		command.Short = fmt.Sprintf("%s %s", toUpperFirst(verb), resource.Name)
+	}
+	if command.Long == command.Short {
+		command.Long = ""
+	}
+	if operation.RequestBody != nil {
+		command.BodyRequired = operation.RequestBody.Required
+	}
+
+	// The parameters of the operation override the parameters of the path
+	params := map[string]*openAPIParameter{}
+	var paramKeys []string
+	for _, param := range append(append([]*openAPIParameter{}, pathItem.Parameters...), operation.Parameters...) {
+		param, err := spec.resolveParameter(param)
+		if err != nil {
+			return nil, err
+		}
+		if param.In == "cookie" || strings.EqualFold(param.Name, "Authorization") {
+			continue
+		}
+		key := param.In + "/" + param.Name
+		if _, exists := params[key]; !exists {
+			paramKeys = append(paramKeys, key)
+		}
+		params[key] = param
+	}
+	// Path parameters are sometimes missing from the specification
+	for _, match := range pathParamRegexp.FindAllStringSubmatch(p, -1) {
+		key := "path/" + match[1]
+		if _, exists := params[key]; !exists {
+			paramKeys = append(paramKeys, key)
+			params[key] = &openAPIParameter{Name: match[1], In: "path", Required: true}
+		}
+	}
+
+	// The last path parameter identifies the resource for the 'get', 'update' and 'delete' operations,
+	// as well as for the custom methods, e.g., 'POST /clusters/{name}:upgrade'
+	argParam := ""
+	if match := customMethodRegexp.FindStringSubmatch(p); match != nil {
+		argParam = match[1]
+	} else if strings.HasSuffix(p, "}") && method != "POST" {
+		argParam = p[strings.LastIndex(p, "{")+1 : len(p)-1]
+	}
+
+	flagNames := map[string]bool{}
+	for _, key := range paramKeys {
+		param := params[key]
+		flag := &openAPIFlag{
+			Name:        toKebabCase(param.Name),
+			ParamName:   param.Name,
+			In:          param.In,
+			GoType:      getGoType(spec.resolveSchema(param.Schema)),
+			Required:    param.Required || param.In == "path",
+			Description: firstLine(param.Description),
+		}
+		if flag.Description == "" {
+			flag.Description = fmt.Sprintf("%s parameter %q", param.In, param.Name)
+		}
+		if param.In == "path" && param.Name == argParam {
+			flag.Name = strings.ToUpper(strings.ReplaceAll(toKebabCase(param.Name), "-", "_"))
+			flag.GoType = "string"
+			command.Arg = flag
+			continue
+		}
+		if reservedFlagNames[flag.Name] || flagNames[flag.Name] {
+			flag.Name = flag.Name + "-" + param.In
+		}
+		flagNames[flag.Name] = true
+		flag.VarName = "flag" + toUpperCamelCase(flag.Name)
+		flag.FlagFunc, flag.DefaultValue = getFlagFunc(flag.GoType)
+		command.Flags = append(command.Flags, flag)
+	}
+
+	command.Use = command.Verb
+	if command.Arg != nil {
+		command.Use += " " + command.Arg.Name
+	}
+	return command, nil
+}
+
+// getColumns returns the scalar properties of the objects returned by the
+// operation, which are shown as columns of the table output
+func (spec *openAPISpec) getColumns(operation *openAPIOperation) []string {
+	var schema *openAPISchema
+	for _, code := range []string{"200", "201", "202"} {
+		if response, exists := operation.Responses[code]; exists {
+			if content, exists := response.Content["application/json"]; exists {
+				schema = spec.resolveSchema(content.Schema)
+				break
+			}
+		}
+	}
+	if schema == nil {
+		return nil
+	}
+	if schema.Type == "array" {
+		schema = spec.resolveSchema(schema.Items)
+	} else if len(schema.Properties) == 1 {
+		// List operations often return the objects in a single property of the response
+		for _, property := range schema.Properties {
+			if property = spec.resolveSchema(property); property != nil && property.Type == "array" {
+				schema = spec.resolveSchema(property.Items)
+			}
+		}
+	}
+	if schema == nil {
+		return nil
+	}
+
+	var columns []string
+	for name, property := range schema.Properties {
+		property = spec.resolveSchema(property)
+		if property != nil && property.Type != "object" && property.Type != "array" {
+			columns = append(columns, name)
+		}
+	}
+	// Show the identifying properties first
+	sort.Slice(columns, func(i, j int) bool {
+		ri, rj := columnRank(columns[i]), columnRank(columns[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return columns[i] < columns[j]
+	})
+	return columns
+}
+
+func columnRank(column string) int {
+	switch strings.ToLower(column) {
+	case "name":
+		return 0
+	case "id":
+		return 1
+	}
+	return 2
+}
+
+// resolveSchema returns the schema referenced by the schema, if any
+func (spec *openAPISpec) resolveSchema(schema *openAPISchema) *openAPISchema {
+	// Limit the number of references to follow to protect against cycles
+	for i := 0; schema != nil && schema.Ref != "" && i < 10; i++ {
+		schema = spec.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	}
+	return schema
+}
+
+// resolveParameter returns the parameter referenced by the parameter, if any
+func (spec *openAPISpec) resolveParameter(param *openAPIParameter) (*openAPIParameter, error) {
+	if param == nil {
+		return nil, errors.New("invalid empty parameter")
+	}
+	if param.Ref == "" {
+		return param, nil
+	}
+	resolved, exists := spec.Components.Parameters[strings.TrimPrefix(param.Ref, "#/components/parameters/")]
+	if !exists || resolved == nil {
+		return nil, errors.Errorf("unable to resolve the parameter %q", param.Ref)
+	}
+	return resolved, nil
+}
+
+// getResourceName returns the name of the resource of the operation, which is
+// its first tag or the first segment of its path, as a singular noun
+func getResourceName(p string, operation *openAPIOperation) string {
+	name := ""
+	if len(operation.Tags) > 0 {
+		name = operation.Tags[0]
+	} else {
+		for _, segment := range strings.Split(p, "/") {
+			if segment == "" || strings.HasPrefix(segment, "{") || versionSegmentRegexp.MatchString(segment) {
+				continue
+			}
+			name = segment
+			break
+		}
+	}
+	words := strings.Split(toKebabCase(name), "-")
+	words[len(words)-1] = singularize(words[len(words)-1])
+	return strings.Trim(strings.Join(words, "-"), "-")
+}
+
+// getVerb returns the verb of the taxonomy matching the method of the operation
+func getVerb(method, p string) string {
+	if match := customMethodRegexp.FindStringSubmatch(p); match != nil {
+		return toKebabCase(match[2])
+	}
+	switch method {
+	case "GET":
+		if strings.HasSuffix(p, "}") {
+			return "get"
+		}
+		return "list"
+	case "POST":
+		return "create"
+	case "PUT", "PATCH":
+		return "update"
+	case "DELETE":
+		return "delete"
+	}
+	return strings.ToLower(method)
+}
+
+// getGoType returns the type of the variable storing the value of a parameter with the schema
+func getGoType(schema *openAPISchema) string {
+	if schema == nil {
+		return "string"
+	}
+	switch schema.Type {
+	case "integer":
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]string"
+	}
+	return "string"
+}
+
+// getFlagFunc returns the function of the flag set defining a flag of the type, with its default value
+func getFlagFunc(goType string) (flagFunc, defaultValue string) {
+	switch goType {
+	case "int":
+		return "IntVar", "0"
+	case "float64":
+		return "Float64Var", "0"
+	case "bool":
+		return "BoolVar", "false"
+	case "[]string":
+		return "StringSliceVar", "nil"
+	}
+	return "StringVar", `""`
+}
+
+// toKebabCase converts the name to lowercase kebab-case, e.g., 'clusterGroups' or
+// 'Cluster Groups' to 'cluster-groups', as used by the names of the commands and flags
+func toKebabCase(name string) string {
+	var words []string
+	for _, part := range nonAlphanumericRegexp.Split(name, -1) {
+		runes := []rune(part)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			// Split camelCase words, keeping acronyms together, e.g., 'clusterID' or 'HTTPProxy'
+			if unicode.IsUpper(runes[i]) && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		if start < len(runes) {
+			words = append(words, string(runes[start:]))
+		}
+	}
+	return strings.ToLower(strings.Join(words, "-"))
+}
+
+// toUpperCamelCase converts the kebab-case name to UpperCamelCase, as used by go identifiers
+func toUpperCamelCase(name string) string {
+	var sb strings.Builder
+	for _, word := range strings.Split(toKebabCase(name), "-") {
+		sb.WriteString(toUpperFirst(word))
+	}
+	identifier := sb.String()
+	if identifier != "" && unicode.IsDigit(rune(identifier[0])) {
+		identifier = "X" + identifier
+	}
+	return identifier
+}
+
+func toUpperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// singularize returns the singular form of a plural noun, as the command names
+// of the taxonomy use singular nouns, e.g., 'tanzu cluster list'
+func singularize(word string) string {
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 3:
+		return strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "sses"), strings.HasSuffix(word, "xes"), strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"):
+		return strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && len(word) > 1:
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, "\n"); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return s
+}
@@ -5,4 +5,9 @@ package helpers
 
 const (
 	PluginInventoryDBImageName = "plugin-inventory"
+
+	// PluginSignatureSuffix is the suffix added to the tag of a plugin image to get the
+	// image publishing the signature of the plugin binary, and to the name of the plugin
+	// binary to get the name of the signature file in that image
+	PluginSignatureSuffix = ".sig"
 )
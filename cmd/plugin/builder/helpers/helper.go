@@ -5,12 +5,16 @@
 package helpers
 
 import (
+	"archive/tar"
 	"crypto/sha256"
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	"github.com/pkg/errors"
 
 	"gopkg.in/yaml.v3"
@@ -56,6 +60,69 @@ func GetPluginArchiveRelativePath(plugin cli.Plugin, osArch cli.Arch, version st
 	return filepath.Join(osArch.OS(), osArch.Arch(), plugin.Target, plugin.Name, version, pluginTarFileName)
 }
 
+// GetPluginSignatureImage returns the image publishing the signature of the plugin binary of the plugin image
+func GetPluginSignatureImage(pluginImage string) string {
+	return pluginImage + PluginSignatureSuffix
+}
+
+// GetPluginSignatureFileName returns the name of the file storing the signature of the plugin binary
+func GetPluginSignatureFileName(pluginBinaryFileName string) string {
+	return pluginBinaryFileName + PluginSignatureSuffix
+}
+
+// ImagePusher publishes images, as carvelhelpers.ImageOperationsImpl does.
+// It avoids an import cycle with the carvelhelpers package.
+type ImagePusher interface {
+	PushImage(imageWithTag string, filePaths []string) error
+}
+
+// PublishPluginSignature publishes the signature of the plugin binary as an image next to
+// the plugin image, for the signature to be added to the plugin inventory database by
+// 'inventory plugin add --with-signatures'
+func PublishPluginSignature(imageOperations ImagePusher, pluginImage, pluginBinaryFileName string, signature []byte) error {
+	dir, err := os.MkdirTemp("", "")
+	if err != nil {
+		return errors.Wrap(err, "unable to create temporary directory")
+	}
+	defer os.RemoveAll(dir)
+	signatureFile := filepath.Join(dir, GetPluginSignatureFileName(pluginBinaryFileName))
+	if err := os.WriteFile(signatureFile, signature, 0644); err != nil {
+		return errors.Wrap(err, "unable to save the plugin signature")
+	}
+
+	signatureImage := GetPluginSignatureImage(pluginImage)
+	if err := imageOperations.PushImage(signatureImage, []string{signatureFile}); err != nil {
+		return errors.Wrapf(err, "unable to publish the plugin signature %q", signatureImage)
+	}
+	return nil
+}
+
+// ReadFileFromPackage returns the content of the specified file of the plugin package,
+// which is an OCI image saved as a tar file
+func ReadFileFromPackage(pluginTarFilePath, fileName string) ([]byte, error) {
+	img, err := tarball.ImageFromPath(pluginTarFilePath, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read the plugin package %q", pluginTarFilePath)
+	}
+	rc := mutate.Extract(img)
+	defer rc.Close()
+
+	tr := tar.NewReader(rc)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read the plugin package %q", pluginTarFilePath)
+		}
+		if hdr.Typeflag == tar.TypeReg && path.Base(hdr.Name) == fileName {
+			return io.ReadAll(tr)
+		}
+	}
+	return nil, errors.Errorf("file %q not found in the plugin package %q", fileName, pluginTarFilePath)
+}
+
 // GetDigest computes the sha256 digest of the specified file
 func GetDigest(filePath string) (string, error) {
 	f, err := os.Open(filePath)
@@ -5,8 +5,10 @@ package helpers
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/google/go-containerregistry/pkg/crane"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -59,3 +61,17 @@ func TestEmptyValidatePluginBinary(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, false, valid)
 }
+
+func TestReadFileFromPackage(t *testing.T) {
+	img, err := crane.Image(map[string][]byte{"tanzu-foo-linux_amd64": []byte("foo binary")})
+	assert.Nil(t, err)
+	pluginTarFilePath := filepath.Join(t.TempDir(), "foo-linux_amd64.tar")
+	assert.Nil(t, crane.Save(img, "example.com/foo:v1.0.0", pluginTarFilePath))
+
+	content, err := ReadFileFromPackage(pluginTarFilePath, "tanzu-foo-linux_amd64")
+	assert.Nil(t, err)
+	assert.Equal(t, "foo binary", string(content))
+
+	_, err = ReadFileFromPackage(pluginTarFilePath, "tanzu-bar-linux_amd64")
+	assert.ErrorContains(t, err, `file "tanzu-bar-linux_amd64" not found in the plugin package`)
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package helpers
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// JUnitTestSuites is the root element of a JUnit XML report
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     float64          `xml:"time,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite is a group of test cases of a JUnit XML report
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      float64         `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	TestCases []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase is a test case of a JUnit XML report
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	Skipped   *JUnitSkipped `xml:"skipped,omitempty"`
+}
+
+// JUnitFailure describes the failure of a test case
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Details string `xml:",chardata"`
+}
+
+// JUnitSkipped describes why a test case was skipped
+type JUnitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// AddTestCase adds the test case to the test suite and updates the counters of the test suite
+func (s *JUnitTestSuite) AddTestCase(tc JUnitTestCase) {
+	s.TestCases = append(s.TestCases, tc)
+	s.Tests++
+	if tc.Failure != nil {
+		s.Failures++
+	}
+	if tc.Skipped != nil {
+		s.Skipped++
+	}
+	s.Time += tc.Time
+}
+
+// AddTestSuite adds the test suite to the report and updates the counters of the report
+func (r *JUnitTestSuites) AddTestSuite(s JUnitTestSuite) {
+	r.Suites = append(r.Suites, s)
+	r.Tests += s.Tests
+	r.Failures += s.Failures
+	r.Skipped += s.Skipped
+	r.Time += s.Time
+}
+
+// JUnitDuration returns the duration in seconds, as used for the time attributes of JUnit XML reports
+func JUnitDuration(d time.Duration) float64 {
+	return float64(d.Milliseconds()) / 1000
+}
+
+// WriteJUnitReport saves the JUnit XML report to the specified file
+func WriteJUnitReport(report *JUnitTestSuites, reportFile string) error {
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal the JUnit report")
+	}
+	if err := os.MkdirAll(filepath.Dir(reportFile), 0755); err != nil {
+		return errors.Wrap(err, "unable to create the directory of the JUnit report")
+	}
+	data = append([]byte(xml.Header), data...)
+	if err := os.WriteFile(reportFile, append(data, '\n'), 0644); err != nil {
+		return errors.Wrapf(err, "unable to write the JUnit report to %q", reportFile)
+	}
+	return nil
+}
@@ -21,6 +21,7 @@ func newInventoryCmd() *cobra.Command {
 		newInventoryInitCmd(),
 		newInventoryPluginCmd(),
 		newInventoryPluginGroupCmd(),
+		newInventoryPromoteCmd(),
 	)
 
 	return inventoryCmd
@@ -30,20 +31,27 @@ type inventoryInitFlags struct {
 	Repository        string
 	InventoryImageTag string
 	Override          bool
+	LocalRepository   string
 }
 
 func newInventoryInitCmd() *cobra.Command {
 	var piiFlags = &inventoryInitFlags{}
 
 	var pluginInventoryInitCmd = &cobra.Command{
-		Use:     "init",
-		Short:   "Initialize empty plugin inventory database and publish it to the remote repository",
-		Example: ``,
+		Use:   "init",
+		Short: "Initialize empty plugin inventory database and publish it to the remote repository",
+		Example: `
+    # Initialize the plugin inventory database on the remote repository
+    tanzu builder inventory init --repository registry.example.com/tanzu-cli/plugins
+
+    # Initialize a local plugin repository which can be served by any web server
+    tanzu builder inventory init --local-repository ./artifacts/repository`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			iiOptions := inventory.InventoryInitOptions{
 				Repository:          piiFlags.Repository,
 				InventoryImageTag:   piiFlags.InventoryImageTag,
 				Override:            piiFlags.Override,
+				LocalRepository:     piiFlags.LocalRepository,
 				ImageOperationsImpl: carvelhelpers.NewImageOperationsImpl(),
 			}
 			return iiOptions.InitializeInventory()
@@ -53,7 +61,62 @@ func newInventoryInitCmd() *cobra.Command {
 	pluginInventoryInitCmd.Flags().StringVarP(&piiFlags.Repository, "repository", "", "", "repository to publish plugin inventory image")
 	pluginInventoryInitCmd.Flags().StringVarP(&piiFlags.InventoryImageTag, "plugin-inventory-image-tag", "", "latest", "tag to which plugin inventory image needs to be published")
 	pluginInventoryInitCmd.Flags().BoolVarP(&piiFlags.Override, "override", "", false, "override the inventory database image if already exists")
-	_ = pluginInventoryInitCmd.MarkFlagRequired("repository")
+	pluginInventoryInitCmd.Flags().StringVarP(&piiFlags.LocalRepository, "local-repository", "", "", "directory of a local plugin repository to initialize instead of the remote repository")
+	pluginInventoryInitCmd.MarkFlagsOneRequired("repository", "local-repository")
+	pluginInventoryInitCmd.MarkFlagsMutuallyExclusive("repository", "local-repository")
 
 	return pluginInventoryInitCmd
 }
+
+type inventoryPromoteFlags struct {
+	FromImage    string
+	ToImage      string
+	Plugins      []string
+	PluginGroups []string
+	SigningKey   string
+	Force        bool
+}
+
+func newInventoryPromoteCmd() *cobra.Command {
+	var ipFlags = &inventoryPromoteFlags{}
+
+	var inventoryPromoteCmd = &cobra.Command{
+		Use:          "promote",
+		Short:        "Promote plugin and plugin-group versions, along with their plugin binaries, from one plugin inventory to another",
+		SilenceUsage: true,
+		Example: `
+    # Promote a plugin-group version and the plugin versions it references from staging to production
+    tanzu builder inventory promote --from staging.example.com/tanzu-cli/plugins/plugin-inventory:latest \
+        --to registry.example.com/tanzu-cli/plugins/plugin-inventory:latest --group vmware-tkg/default:v2.1.0
+
+    # Promote plugin versions and re-sign their plugin binaries with the production key
+    tanzu builder inventory promote --from staging.example.com/tanzu-cli/plugins/plugin-inventory:latest \
+        --to registry.example.com/tanzu-cli/plugins/plugin-inventory:latest \
+        --plugin cluster@kubernetes:v1.2.0 --plugin login@global:v1.0.0 --signing-key ./cosign.key`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ipOptions := inventory.InventoryPromoteOptions{
+				FromImage:           ipFlags.FromImage,
+				ToImage:             ipFlags.ToImage,
+				Plugins:             ipFlags.Plugins,
+				PluginGroups:        ipFlags.PluginGroups,
+				SigningKey:          ipFlags.SigningKey,
+				Force:               ipFlags.Force,
+				ImageOperationsImpl: carvelhelpers.NewImageOperationsImpl(),
+			}
+			return ipOptions.Promote()
+		},
+	}
+
+	inventoryPromoteCmd.Flags().StringVarP(&ipFlags.FromImage, "from", "", "", "plugin inventory image to promote the versions from")
+	inventoryPromoteCmd.Flags().StringVarP(&ipFlags.ToImage, "to", "", "", "plugin inventory image to promote the versions to")
+	inventoryPromoteCmd.Flags().StringArrayVarP(&ipFlags.Plugins, "plugin", "", nil, "plugin version to promote as name@target:version, can be specified multiple times")
+	inventoryPromoteCmd.Flags().StringArrayVarP(&ipFlags.PluginGroups, "group", "", nil, "plugin-group version to promote with its plugins as vendor-publisher/name:version, can be specified multiple times")
+	inventoryPromoteCmd.Flags().StringVarP(&ipFlags.SigningKey, "signing-key", "", "", "cosign private key reference used to re-sign the promoted plugin binaries")
+	inventoryPromoteCmd.Flags().BoolVarP(&ipFlags.Force, "force", "", false, "promote deactivated plugin and plugin-group versions, which remain deactivated")
+
+	_ = inventoryPromoteCmd.MarkFlagRequired("from")
+	_ = inventoryPromoteCmd.MarkFlagRequired("to")
+	inventoryPromoteCmd.MarkFlagsOneRequired("plugin", "group")
+
+	return inventoryPromoteCmd
+}
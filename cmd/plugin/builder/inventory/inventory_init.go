@@ -22,12 +22,19 @@ type InventoryInitOptions struct {
 	Repository        string
 	InventoryImageTag string
 	Override          bool
+	// LocalRepository is the directory of the local plugin repository to
+	// initialize instead of the Repository
+	LocalRepository string
 
 	ImageOperationsImpl carvelhelpers.ImageOperationsImpl
 }
 
 // InitializeInventory initializes the repository with the empty inventory database
 func (iio *InventoryInitOptions) InitializeInventory() error {
+	if iio.LocalRepository != "" {
+		return initializeLocalRepository(iio.LocalRepository, iio.Override)
+	}
+
 	// create plugin inventory database image path
 	pluginInventoryDBImage := fmt.Sprintf("%s/%s:%s", iio.Repository, helpers.PluginInventoryDBImageName, iio.InventoryImageTag)
 
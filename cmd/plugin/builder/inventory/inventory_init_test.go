@@ -6,6 +6,7 @@ package inventory
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/helpers"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/fakes"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/plugininventory"
 )
 
 func TestInventorySuite(t *testing.T) {
@@ -60,4 +62,24 @@ var _ = Describe("Unit tests for inventory init", func() {
 			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("%s/%s:%s", iip.Repository, helpers.PluginInventoryDBImageName, iip.InventoryImageTag)))
 		})
 	})
+
+	var _ = Context("tests for the inventory init function with a local plugin repository", func() {
+		var _ = It("when the local plugin repository is initialized", func() {
+			localRepository := filepath.Join(GinkgoT().TempDir(), "repository")
+			iio := InventoryInitOptions{LocalRepository: localRepository}
+
+			err := iio.InitializeInventory()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filepath.Join(localRepository, plugininventory.SQliteDBFileName)).To(BeARegularFile())
+
+			// The local plugin repository cannot be initialized again without override
+			err = iio.InitializeInventory()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("already exists. Use `--override` flag to override the content"))
+
+			iio.Override = true
+			err = iio.InitializeInventory()
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
 })
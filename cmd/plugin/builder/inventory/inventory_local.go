@@ -0,0 +1,136 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
+
+	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/helpers"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/plugininventory"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/provenance"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/utils"
+)
+
+// A local plugin repository is a directory containing the plugin inventory database
+// and the plugin binaries, which can be served as is by any web server or used
+// directly from the filesystem. The plugin binaries are stored at
+// <vendor>/<publisher>/<os>/<arch>/<target>/<plugin>/<version>/tanzu-<plugin>-<os>_<arch>
+// relative to the database, which stores these relative paths as the plugin URIs.
+
+// getLocalRepositoryDBFile returns the inventory database file of the local plugin repository
+func getLocalRepositoryDBFile(localRepository string) string {
+	return filepath.Join(localRepository, plugininventory.SQliteDBFileName)
+}
+
+// getLocalPluginBinaryRelativePath returns the path of the plugin binary relative to
+// the local plugin repository, using '/' as separator as the path is also a relative URL
+func getLocalPluginBinaryRelativePath(vendor, publisher string, plugin cli.Plugin, osArch cli.Arch, version string) string {
+	return path.Join(vendor, publisher, osArch.OS(), osArch.Arch(), plugin.Target, plugin.Name, version, cli.MakeArtifactName(plugin.Name, osArch))
+}
+
+// initializeLocalRepository creates the local plugin repository with an empty inventory database
+func initializeLocalRepository(localRepository string, override bool) error {
+	dbFile := getLocalRepositoryDBFile(localRepository)
+	if utils.PathExists(dbFile) {
+		if !override {
+			return errors.Errorf("%q already exists. Use `--override` flag to override the content", dbFile)
+		}
+		if err := os.Remove(dbFile); err != nil {
+			return errors.Wrapf(err, "unable to remove %q", dbFile)
+		}
+	}
+	if err := os.MkdirAll(localRepository, 0755); err != nil {
+		return errors.Wrapf(err, "unable to create the local plugin repository %q", localRepository)
+	}
+	if err := plugininventory.NewSQLiteInventory(dbFile, "").CreateSchema(); err != nil {
+		return errors.Wrap(err, "error while creating database")
+	}
+	log.Infof("successfully created plugin inventory database at: %q", dbFile)
+	return nil
+}
+
+// getLocalRepositoryDBFileForUpdate returns the inventory database file of the local
+// plugin repository, which must have been initialized
+func getLocalRepositoryDBFileForUpdate(localRepository string) (string, error) {
+	dbFile := getLocalRepositoryDBFile(localRepository)
+	if !utils.PathExists(dbFile) {
+		return "", errors.Errorf("plugin inventory database %q not found, use 'tanzu builder inventory init --local-repository %s' to create it", dbFile, localRepository)
+	}
+	log.Infof("using local plugin repository: %q", localRepository)
+	return dbFile, nil
+}
+
+// copyPluginBinariesToLocalRepository copies the plugin binaries of the plugin manifest,
+// along with their test plugin binaries and provenance statements, from the binary
+// artifacts directory generated by 'tanzu builder plugin build' to the local plugin repository
+func (ipuo *InventoryPluginUpdateOptions) copyPluginBinariesToLocalRepository(pluginManifest *cli.Manifest) error {
+	binaryArtifactDir := ipuo.BinaryArtifactDir
+	if binaryArtifactDir == "" {
+		binaryArtifactDir = filepath.Dir(ipuo.ManifestFile)
+	}
+
+	for i := range pluginManifest.Plugins {
+		p := pluginManifest.Plugins[i]
+		for _, osArch := range cli.AllOSArch {
+			for _, version := range p.Versions {
+				srcDir := filepath.Join(binaryArtifactDir, osArch.OS(), osArch.Arch(), p.Target, p.Name, version)
+				binaryFileName := cli.MakeArtifactName(p.Name, osArch)
+				if !utils.PathExists(filepath.Join(srcDir, binaryFileName)) {
+					// Only the required OSArch combinations must be available
+					if helpers.IsRequiredOSArch(osArch) {
+						return errors.Errorf("plugin binary %q not found", filepath.Join(srcDir, binaryFileName))
+					}
+					continue
+				}
+
+				dstDir := filepath.Join(ipuo.LocalRepository, filepath.FromSlash(path.Dir(getLocalPluginBinaryRelativePath(ipuo.Vendor, ipuo.Publisher, p, osArch, version))))
+				if err := os.MkdirAll(dstDir, 0755); err != nil {
+					return errors.Wrapf(err, "unable to create directory %q", dstDir)
+				}
+				for _, fileName := range []string{binaryFileName, provenance.FileName(binaryFileName)} {
+					if !utils.PathExists(filepath.Join(srcDir, fileName)) {
+						continue
+					}
+					if err := utils.CopyFile(filepath.Join(srcDir, fileName), filepath.Join(dstDir, fileName)); err != nil {
+						return errors.Wrapf(err, "unable to copy %q to the local plugin repository", filepath.Join(srcDir, fileName))
+					}
+				}
+				if err := copyTestPluginBinary(filepath.Join(srcDir, "test"), filepath.Join(dstDir, "test")); err != nil {
+					return err
+				}
+				log.Infof("copied plugin '%s_%s' version %q for %s to the local plugin repository", p.Name, p.Target, version, osArch)
+			}
+		}
+	}
+	return nil
+}
+
+// copyTestPluginBinary copies the test plugin binary, if any, from the srcDir
+// to the dstDir where it is expected when installing the test plugin
+func copyTestPluginBinary(srcDir, dstDir string) error {
+	entries, err := os.ReadDir(srcDir)
+	if err != nil {
+		// The test plugin binary is optional
+		return nil
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if err := os.MkdirAll(dstDir, 0755); err != nil {
+			return errors.Wrapf(err, "unable to create directory %q", dstDir)
+		}
+		if err := utils.CopyFile(filepath.Join(srcDir, entry.Name()), filepath.Join(dstDir, entry.Name())); err != nil {
+			return errors.Wrapf(err, "unable to copy the test plugin binary %q to the local plugin repository", filepath.Join(srcDir, entry.Name()))
+		}
+	}
+	return nil
+}
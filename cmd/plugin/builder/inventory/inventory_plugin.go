@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/pkg/errors"
@@ -34,6 +35,15 @@ type InventoryPluginUpdateOptions struct {
 	InventoryDBFile   string
 	DeactivatePlugins bool
 	ValidateOnly      bool
+	// WithSignatures adds the signatures of the plugin binaries, published with
+	// 'plugin publish-package --signing-key', to the inventory database
+	WithSignatures bool
+	// LocalRepository is the directory of a local plugin repository storing the
+	// inventory database and the plugin binaries, used instead of the Repository
+	LocalRepository string
+	// BinaryArtifactDir is the directory of the plugin binaries to add to the local
+	// plugin repository. It defaults to the directory of the ManifestFile.
+	BinaryArtifactDir string
 
 	ImageOperationsImpl carvelhelpers.ImageOperationsImpl
 }
@@ -49,6 +59,18 @@ func (ipuo *InventoryPluginUpdateOptions) PluginAdd() error {
 		}
 		return nil
 	}
+	if ipuo.LocalRepository != "" && !ipuo.ValidateOnly {
+		if ipuo.WithSignatures {
+			return errors.New("the signatures of the plugin binaries cannot be added to a local plugin repository")
+		}
+		pluginManifest, err := helpers.ReadPluginManifest(ipuo.ManifestFile)
+		if err != nil {
+			return err
+		}
+		if err := ipuo.copyPluginBinariesToLocalRepository(pluginManifest); err != nil {
+			return errors.Wrap(err, "error while adding the plugin binaries to the local plugin repository")
+		}
+	}
 	return ipuo.genericInventoryUpdater(pluginAddFunc)
 }
 
@@ -99,8 +121,9 @@ func (ipuo *InventoryPluginUpdateOptions) preparePluginInventoryEntriesFromManif
 	var pluginInventoryEntries []*plugininventory.PluginInventoryEntry
 
 	pluginBinaryDigestMap := map[string]string{}
+	pluginBinarySignatureMap := map[string]string{}
 	if !ipuo.ValidateOnly {
-		pluginBinaryDigestMap, err = ipuo.fetchPluginBinaryDigest(pluginManifest)
+		pluginBinaryDigestMap, pluginBinarySignatureMap, err = ipuo.fetchPluginBinaryDigest(pluginManifest)
 		if err != nil {
 			return nil, err
 		}
@@ -111,7 +134,7 @@ func (ipuo *InventoryPluginUpdateOptions) preparePluginInventoryEntriesFromManif
 
 		for _, osArch := range cli.AllOSArch {
 			for _, version := range pluginManifest.Plugins[i].Versions {
-				pluginInventoryEntry, err = ipuo.updatePluginInventoryEntry(pluginInventoryEntry, pluginManifest.Plugins[i], osArch, version, pluginBinaryDigestMap)
+				pluginInventoryEntry, err = ipuo.updatePluginInventoryEntry(pluginInventoryEntry, pluginManifest.Plugins[i], osArch, version, pluginBinaryDigestMap, pluginBinarySignatureMap)
 				if err != nil {
 					return nil, err
 				}
@@ -124,8 +147,11 @@ func (ipuo *InventoryPluginUpdateOptions) preparePluginInventoryEntriesFromManif
 	return pluginInventoryEntries, nil
 }
 
-func (ipuo *InventoryPluginUpdateOptions) fetchPluginBinaryDigest(pluginManifest *cli.Manifest) (map[string]string, error) {
+// fetchPluginBinaryDigest returns the digests of the plugin binaries and, if requested,
+// their signatures, both indexed by plugin image
+func (ipuo *InventoryPluginUpdateOptions) fetchPluginBinaryDigest(pluginManifest *cli.Manifest) (map[string]string, map[string]string, error) {
 	pluginBinaryDigestMap := map[string]string{}
+	pluginBinarySignatureMap := map[string]string{}
 
 	// Limit the number of concurrent operations we perform so we don't overwhelm the system.
 	maxConcurrent := helpers.GetMaxParallelism()
@@ -140,9 +166,19 @@ func (ipuo *InventoryPluginUpdateOptions) fetchPluginBinaryDigest(pluginManifest
 			wg.Done()
 		}()
 
-		log.Infof("%s getting plugin digest from image: '%s'", threadID, pluginImage)
-
-		digest, err := ipuo.ImageOperationsImpl.GetFileDigestFromImage(pluginImage, filename)
+		var digest string
+		var err error
+		if ipuo.LocalRepository != "" {
+			log.Infof("%s getting plugin digest from file: '%s'", threadID, pluginImage)
+			if !utils.PathExists(pluginImage) {
+				err = errors.Errorf("plugin binary %q not found", pluginImage)
+			} else {
+				digest, err = helpers.GetDigest(pluginImage)
+			}
+		} else {
+			log.Infof("%s getting plugin digest from image: '%s'", threadID, pluginImage)
+			digest, err = ipuo.ImageOperationsImpl.GetFileDigestFromImage(pluginImage, filename)
+		}
 		if err != nil {
 			// Return an error only for require OSArch combinations.
 			// For optional OSArch combinations, we can just ignore the missing plugin binary
@@ -158,11 +194,24 @@ func (ipuo *InventoryPluginUpdateOptions) fetchPluginBinaryDigest(pluginManifest
 			} else {
 				log.Infof("%s ignoring unavailable plugin for optional os/arch: %s", threadID, osArch.String())
 			}
-		} else {
-			mutex.Lock()
-			pluginBinaryDigestMap[pluginImage] = digest
-			mutex.Unlock()
+			return
+		}
+
+		var signature string
+		if ipuo.WithSignatures {
+			signature, err = ipuo.fetchPluginBinarySignature(pluginImage, filename)
+			if err != nil {
+				fatalErrors <- helpers.ErrInfo{Err: err, ID: threadID, Path: pluginImage}
+				return
+			}
+		}
+
+		mutex.Lock()
+		pluginBinaryDigestMap[pluginImage] = digest
+		if signature != "" {
+			pluginBinarySignatureMap[pluginImage] = signature
 		}
+		mutex.Unlock()
 	}
 
 	if !ipuo.ValidateOnly {
@@ -172,8 +221,7 @@ func (ipuo *InventoryPluginUpdateOptions) fetchPluginBinaryDigest(pluginManifest
 				for _, version := range pluginManifest.Plugins[i].Versions {
 					wg.Add(1)
 					guard <- struct{}{}
-					pluginImageBasePath := fmt.Sprintf("%s/%s/%s/%s/%s/%s:%s", ipuo.Vendor, ipuo.Publisher, osArch.OS(), osArch.Arch(), pluginManifest.Plugins[i].Target, pluginManifest.Plugins[i].Name, version)
-					pluginImage := fmt.Sprintf("%s/%s", ipuo.Repository, pluginImageBasePath)
+					pluginImage := ipuo.getPluginArtifactLocation(ipuo.getPluginArtifactURI(pluginManifest.Plugins[i], osArch, version))
 					go fetchPluginBinaryDigestFromImage(helpers.GetID(id), pluginImage, cli.MakeArtifactName(pluginManifest.Plugins[i].Name, osArch), osArch)
 					id++
 				}
@@ -188,23 +236,40 @@ func (ipuo *InventoryPluginUpdateOptions) fetchPluginBinaryDigest(pluginManifest
 			errList = append(errList, err.Err)
 		}
 		if len(errList) > 0 {
-			return pluginBinaryDigestMap, kerrors.NewAggregate(errList)
+			return pluginBinaryDigestMap, pluginBinarySignatureMap, kerrors.NewAggregate(errList)
 		}
 	}
-	return pluginBinaryDigestMap, nil
+	return pluginBinaryDigestMap, pluginBinarySignatureMap, nil
+}
+
+// fetchPluginBinarySignature returns the signature of the plugin binary from the
+// image published next to the plugin image by 'plugin publish-package --signing-key'
+func (ipuo *InventoryPluginUpdateOptions) fetchPluginBinarySignature(pluginImage, filename string) (string, error) {
+	signatureImage := helpers.GetPluginSignatureImage(pluginImage)
+	files, err := ipuo.ImageOperationsImpl.GetFilesMapFromImage(signatureImage)
+	if err != nil {
+		return "", errors.Wrapf(err, "error while getting plugin binary signature from the image %q", signatureImage)
+	}
+	signatureFileName := helpers.GetPluginSignatureFileName(filename)
+	for path, content := range files {
+		if filepath.Base(path) == signatureFileName {
+			return strings.TrimSpace(string(content)), nil
+		}
+	}
+	return "", errors.Errorf("plugin binary signature %q not found in the image %q", signatureFileName, signatureImage)
 }
 
 // Take the image download logic to get the digest out of the updatePluginInventoryEntry and run it in parallel
 // Pass the digest map to this function to update the plugin inventory entry in sync operation
-func (ipuo *InventoryPluginUpdateOptions) updatePluginInventoryEntry(pluginInventoryEntry *plugininventory.PluginInventoryEntry, plugin cli.Plugin, osArch cli.Arch, version string, pluginBinaryDigestMap map[string]string) (*plugininventory.PluginInventoryEntry, error) {
-	var digest string
+func (ipuo *InventoryPluginUpdateOptions) updatePluginInventoryEntry(pluginInventoryEntry *plugininventory.PluginInventoryEntry, plugin cli.Plugin, osArch cli.Arch, version string, pluginBinaryDigestMap, pluginBinarySignatureMap map[string]string) (*plugininventory.PluginInventoryEntry, error) {
+	var digest, signature string
 	var exists bool
 
-	pluginImageBasePath := fmt.Sprintf("%s/%s/%s/%s/%s/%s:%s", ipuo.Vendor, ipuo.Publisher, osArch.OS(), osArch.Arch(), plugin.Target, plugin.Name, version)
+	pluginImageBasePath := ipuo.getPluginArtifactURI(plugin, osArch, version)
 	if !ipuo.ValidateOnly {
 		// If we are only validating the plugin's existence, we don't need to waste
 		// resources downloading the image to get the digest which won't actually be used.
-		pluginImage := fmt.Sprintf("%s/%s", ipuo.Repository, pluginImageBasePath)
+		pluginImage := ipuo.getPluginArtifactLocation(pluginImageBasePath)
 		digest, exists = pluginBinaryDigestMap[pluginImage]
 		if !exists {
 			// For optional OSArch combinations, we can just ignore the missing plugin binary
@@ -214,6 +279,7 @@ func (ipuo *InventoryPluginUpdateOptions) updatePluginInventoryEntry(pluginInven
 		if digest == "" {
 			return nil, errors.Errorf("plugin binary digest cannot be empty for image %q", pluginImage)
 		}
+		signature = pluginBinarySignatureMap[pluginImage]
 	}
 
 	if pluginInventoryEntry == nil {
@@ -233,34 +299,62 @@ func (ipuo *InventoryPluginUpdateOptions) updatePluginInventoryEntry(pluginInven
 	}
 
 	artifact := distribution.Artifact{
-		OS:     osArch.OS(),
-		Arch:   osArch.Arch(),
-		Digest: digest,
-		Image:  pluginImageBasePath,
+		OS:        osArch.OS(),
+		Arch:      osArch.Arch(),
+		Digest:    digest,
+		Signature: signature,
+		Image:     pluginImageBasePath,
 	}
 	pluginInventoryEntry.Artifacts[version] = append(pluginInventoryEntry.Artifacts[version], artifact)
 	return pluginInventoryEntry, nil
 }
 
+// getPluginArtifactURI returns the URI of the plugin binary stored in the inventory database,
+// which is relative to the location of the inventory database. It is the plugin image for
+// an OCI repository and the path of the plugin binary for a local plugin repository.
+func (ipuo *InventoryPluginUpdateOptions) getPluginArtifactURI(plugin cli.Plugin, osArch cli.Arch, version string) string {
+	if ipuo.LocalRepository != "" {
+		return getLocalPluginBinaryRelativePath(ipuo.Vendor, ipuo.Publisher, plugin, osArch, version)
+	}
+	return fmt.Sprintf("%s/%s/%s/%s/%s/%s:%s", ipuo.Vendor, ipuo.Publisher, osArch.OS(), osArch.Arch(), plugin.Target, plugin.Name, version)
+}
+
+// getPluginArtifactLocation returns the location of the plugin binary from its URI
+// relative to the location of the inventory database
+func (ipuo *InventoryPluginUpdateOptions) getPluginArtifactLocation(artifactURI string) string {
+	if ipuo.LocalRepository != "" {
+		return filepath.Join(ipuo.LocalRepository, filepath.FromSlash(artifactURI))
+	}
+	return fmt.Sprintf("%s/%s", ipuo.Repository, artifactURI)
+}
+
 func (ipuo *InventoryPluginUpdateOptions) getPluginInventoryDBImagePath() string {
 	return fmt.Sprintf("%s/%s:%s", ipuo.Repository, helpers.PluginInventoryDBImageName, ipuo.InventoryImageTag)
 }
 
 func (ipuo *InventoryPluginUpdateOptions) getInventoryDBFile() (string, error) {
-	if ipuo.InventoryDBFile != "" {
-		log.Infof("using local plugin inventory database file: %q", ipuo.InventoryDBFile)
+	dbFile := ipuo.InventoryDBFile
+	if ipuo.LocalRepository != "" {
+		var err error
+		if dbFile, err = getLocalRepositoryDBFileForUpdate(ipuo.LocalRepository); err != nil {
+			return "", err
+		}
+	} else if dbFile != "" {
+		log.Infof("using local plugin inventory database file: %q", dbFile)
+	}
+	if dbFile != "" {
 		if ipuo.ValidateOnly {
 			tempFile, err := os.CreateTemp("", "*.db")
 			if err != nil {
 				return "", err
 			}
-			err = utils.CopyFile(ipuo.InventoryDBFile, tempFile.Name())
+			err = utils.CopyFile(dbFile, tempFile.Name())
 			if err != nil {
 				return "", err
 			}
 			return tempFile.Name(), nil
 		}
-		return ipuo.InventoryDBFile, nil
+		return dbFile, nil
 	}
 
 	// get plugin inventory database image path
@@ -289,6 +383,10 @@ func (ipuo *InventoryPluginUpdateOptions) putInventoryDBFile(dbFile string) erro
 	}
 
 	// If local inventory database file was provided nothing to publish just return
+	if ipuo.LocalRepository != "" {
+		log.Infof("successfully updated local plugin repository at: %q", ipuo.LocalRepository)
+		return nil
+	}
 	if ipuo.InventoryDBFile != "" {
 		log.Infof("successfully updated plugin inventory database file at: %q", ipuo.InventoryDBFile)
 		return nil
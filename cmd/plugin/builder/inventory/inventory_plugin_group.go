@@ -31,6 +31,9 @@ type InventoryPluginGroupUpdateOptions struct {
 	InventoryDBFile         string
 	DeactivatePluginGroup   bool
 	Override                bool
+	// LocalRepository is the directory of a local plugin repository storing the
+	// inventory database, used instead of the Repository
+	LocalRepository string
 
 	ImageOperationsImpl carvelhelpers.ImageOperationsImpl
 }
@@ -132,6 +135,9 @@ func (ipuo *InventoryPluginGroupUpdateOptions) getPluginInventoryDBImagePath() s
 }
 
 func (ipuo *InventoryPluginGroupUpdateOptions) getInventoryDBFile() (string, error) {
+	if ipuo.LocalRepository != "" {
+		return getLocalRepositoryDBFileForUpdate(ipuo.LocalRepository)
+	}
 	if ipuo.InventoryDBFile != "" {
 		log.Infof("using local plugin inventory database file: %q", ipuo.InventoryDBFile)
 		return ipuo.InventoryDBFile, nil
@@ -158,6 +164,10 @@ func (ipuo *InventoryPluginGroupUpdateOptions) putInventoryDBFile(dbFile string)
 	pluginInventoryDBImage := ipuo.getPluginInventoryDBImagePath()
 
 	// If local inventory database file was provided nothing to publish just return
+	if ipuo.LocalRepository != "" {
+		log.Infof("successfully updated local plugin repository at: %q", ipuo.LocalRepository)
+		return nil
+	}
 	if ipuo.InventoryDBFile != "" {
 		log.Infof("successfully updated plugin inventory database file at: %q", ipuo.InventoryDBFile)
 		return nil
@@ -0,0 +1,339 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
+
+	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/helpers"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/carvelhelpers"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/plugininventory"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/utils"
+)
+
+const (
+	// DiffOutputMarkdown is the markdown output format of the plugin-group diff
+	DiffOutputMarkdown = "markdown"
+	// DiffOutputJSON is the JSON output format of the plugin-group diff
+	DiffOutputJSON = "json"
+)
+
+// InventoryPluginGroupDiffOptions defines options for comparing two versions
+// of a plugin-group of the inventory database
+type InventoryPluginGroupDiffOptions struct {
+	Repository        string
+	InventoryImageTag string
+	InventoryDBFile   string
+	// LocalRepository is the directory of a local plugin repository storing the
+	// inventory database, used instead of the Repository
+	LocalRepository string
+
+	Vendor    string
+	Publisher string
+	GroupName string
+	// FromVersion and ToVersion are the versions of the plugin-group to compare
+	FromVersion string
+	ToVersion   string
+	// PluginManifestFiles are the plugin manifests providing the release notes of the plugin versions
+	PluginManifestFiles []string
+	// OutputFormat is either markdown or json
+	OutputFormat string
+	// Output is where the diff is written, stdout by default
+	Output io.Writer
+
+	ImageOperationsImpl carvelhelpers.ImageOperationsImpl
+}
+
+// PluginGroupDiff are the changes of the plugins between two versions of a plugin-group
+type PluginGroupDiff struct {
+	Group       string `json:"group"`
+	FromVersion string `json:"fromVersion"`
+	ToVersion   string `json:"toVersion"`
+
+	Added            []PluginChange `json:"added"`
+	Removed          []PluginChange `json:"removed"`
+	Upgraded         []PluginChange `json:"upgraded"`
+	Downgraded       []PluginChange `json:"downgraded"`
+	MandatoryChanged []PluginChange `json:"mandatoryChanged"`
+}
+
+// PluginChange is the change of a plugin between two versions of a plugin-group
+type PluginChange struct {
+	Name   string `json:"name"`
+	Target string `json:"target"`
+	// FromVersion is the version of the plugin in the older plugin-group version, empty if the plugin was added
+	FromVersion string `json:"fromVersion,omitempty"`
+	// ToVersion is the version of the plugin in the newer plugin-group version, empty if the plugin was removed
+	ToVersion     string `json:"toVersion,omitempty"`
+	FromMandatory bool   `json:"fromMandatory"`
+	ToMandatory   bool   `json:"toMandatory"`
+	// ReleaseNotes are the release notes of the plugin versions included by the change
+	ReleaseNotes []PluginReleaseNotes `json:"releaseNotes,omitempty"`
+}
+
+// PluginReleaseNotes are the release notes of a version of a plugin
+type PluginReleaseNotes struct {
+	Version string `json:"version"`
+	Notes   string `json:"notes"`
+}
+
+// PluginGroupDiff writes the plugins added, removed, upgraded and downgraded between
+// two versions of a plugin-group, as well as the plugins whose mandatory flag changed,
+// with the release notes of the plugin versions provided by the plugin manifests
+func (ipdo *InventoryPluginGroupDiffOptions) PluginGroupDiff() error {
+	if ipdo.OutputFormat != DiffOutputMarkdown && ipdo.OutputFormat != DiffOutputJSON {
+		return errors.Errorf("invalid output format %q, must be one of: %s, %s", ipdo.OutputFormat, DiffOutputMarkdown, DiffOutputJSON)
+	}
+	releaseNotes, err := ipdo.getReleaseNotes()
+	if err != nil {
+		return err
+	}
+
+	dbFile, err := ipdo.getInventoryDBFile()
+	if err != nil {
+		return err
+	}
+	db := plugininventory.NewSQLiteInventory(dbFile, "")
+	groups, err := db.GetPluginGroups(plugininventory.PluginGroupFilter{
+		Vendor:        ipdo.Vendor,
+		Publisher:     ipdo.Publisher,
+		Name:          ipdo.GroupName,
+		IncludeHidden: true,
+	})
+	if err != nil {
+		return errors.Wrap(err, "error while reading the plugin groups of the plugin inventory database")
+	}
+	groupID := fmt.Sprintf("%s-%s/%s", ipdo.Vendor, ipdo.Publisher, ipdo.GroupName)
+	if len(groups) == 0 {
+		return errors.Errorf("plugin-group %q not found in the plugin inventory database", groupID)
+	}
+	pg := groups[0]
+	for _, version := range []string{ipdo.FromVersion, ipdo.ToVersion} {
+		if _, exists := pg.Versions[version]; !exists {
+			return errors.Errorf("version %q of plugin-group %q not found in the plugin inventory database", version, groupID)
+		}
+	}
+
+	diff := diffPluginGroupVersions(pg.Versions[ipdo.FromVersion], pg.Versions[ipdo.ToVersion], releaseNotes)
+	diff.Group = plugininventory.PluginGroupToID(pg)
+	diff.FromVersion = ipdo.FromVersion
+	diff.ToVersion = ipdo.ToVersion
+
+	out := ipdo.Output
+	if out == nil {
+		out = os.Stdout
+	}
+	if ipdo.OutputFormat == DiffOutputJSON {
+		b, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(b))
+		return err
+	}
+	_, err = io.WriteString(out, diff.Markdown())
+	return err
+}
+
+// getReleaseNotes returns the release notes of the plugins in the plugin manifests,
+// by plugin name, target and version
+func (ipdo *InventoryPluginGroupDiffOptions) getReleaseNotes() (map[string]string, error) {
+	releaseNotes := map[string]string{}
+	for _, manifestFile := range ipdo.PluginManifestFiles {
+		manifest, err := helpers.ReadPluginManifest(manifestFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error while reading the plugin manifest %q", manifestFile)
+		}
+		for _, p := range manifest.Plugins {
+			for version, notes := range p.ReleaseNotes {
+				if notes = strings.TrimSpace(notes); notes != "" {
+					releaseNotes[pluginVersionKey(p.Name, p.Target, version)] = notes
+				}
+			}
+		}
+	}
+	return releaseNotes, nil
+}
+
+// diffPluginGroupVersions returns the changes of the plugins between the plugins of two versions of a plugin-group
+func diffPluginGroupVersions(fromPlugins, toPlugins []*plugininventory.PluginGroupPluginEntry, releaseNotes map[string]string) *PluginGroupDiff {
+	diff := &PluginGroupDiff{
+		Added:            []PluginChange{},
+		Removed:          []PluginChange{},
+		Upgraded:         []PluginChange{},
+		Downgraded:       []PluginChange{},
+		MandatoryChanged: []PluginChange{},
+	}
+
+	from := map[string]*plugininventory.PluginGroupPluginEntry{}
+	for _, p := range fromPlugins {
+		from[p.Name+"/"+string(p.Target)] = p
+	}
+	to := map[string]*plugininventory.PluginGroupPluginEntry{}
+	for _, p := range toPlugins {
+		to[p.Name+"/"+string(p.Target)] = p
+	}
+
+	for key, t := range to {
+		change := PluginChange{
+			Name:        t.Name,
+			Target:      string(t.Target),
+			ToVersion:   t.Version,
+			ToMandatory: t.Mandatory,
+		}
+		f, exists := from[key]
+		if !exists {
+			change.ReleaseNotes = getPluginReleaseNotes(releaseNotes, t.Name, string(t.Target), "", t.Version)
+			diff.Added = append(diff.Added, change)
+			continue
+		}
+		change.FromVersion = f.Version
+		change.FromMandatory = f.Mandatory
+		if f.Version != t.Version {
+			if utils.IsNewVersion(t.Version, f.Version) {
+				change.ReleaseNotes = getPluginReleaseNotes(releaseNotes, t.Name, string(t.Target), f.Version, t.Version)
+				diff.Upgraded = append(diff.Upgraded, change)
+			} else {
+				diff.Downgraded = append(diff.Downgraded, change)
+			}
+		}
+		if f.Mandatory != t.Mandatory {
+			diff.MandatoryChanged = append(diff.MandatoryChanged, PluginChange{
+				Name:          change.Name,
+				Target:        change.Target,
+				FromVersion:   change.FromVersion,
+				ToVersion:     change.ToVersion,
+				FromMandatory: change.FromMandatory,
+				ToMandatory:   change.ToMandatory,
+			})
+		}
+	}
+	for key, f := range from {
+		if _, exists := to[key]; !exists {
+			diff.Removed = append(diff.Removed, PluginChange{
+				Name:          f.Name,
+				Target:        string(f.Target),
+				FromVersion:   f.Version,
+				FromMandatory: f.Mandatory,
+			})
+		}
+	}
+
+	for _, changes := range [][]PluginChange{diff.Added, diff.Removed, diff.Upgraded, diff.Downgraded, diff.MandatoryChanged} {
+		sort.Slice(changes, func(i, j int) bool {
+			if changes[i].Name != changes[j].Name {
+				return changes[i].Name < changes[j].Name
+			}
+			return changes[i].Target < changes[j].Target
+		})
+	}
+	return diff
+}
+
+// getPluginReleaseNotes returns the release notes of the versions of the plugin newer than
+// fromVersion, if not empty, up to toVersion, from the most recent version
+func getPluginReleaseNotes(releaseNotes map[string]string, name, target, fromVersion, toVersion string) []PluginReleaseNotes {
+	prefix := pluginVersionKey(name, target, "")
+	var versions []string
+	for key := range releaseNotes {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		version := strings.TrimPrefix(key, prefix)
+		if version == toVersion || (fromVersion != "" && utils.IsNewVersion(version, fromVersion) && utils.IsNewVersion(toVersion, version)) {
+			versions = append(versions, version)
+		}
+	}
+	_ = utils.SortVersions(versions)
+
+	var notes []PluginReleaseNotes
+	for i := len(versions) - 1; i >= 0; i-- {
+		notes = append(notes, PluginReleaseNotes{Version: versions[i], Notes: releaseNotes[prefix+versions[i]]})
+	}
+	return notes
+}
+
+// Markdown returns the changes of the plugin-group as markdown, e.g., for a release page
+func (d *PluginGroupDiff) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## %s: %s to %s\n\n", d.Group, d.FromVersion, d.ToVersion)
+
+	if len(d.Added)+len(d.Removed)+len(d.Upgraded)+len(d.Downgraded)+len(d.MandatoryChanged) == 0 {
+		sb.WriteString("No plugin changes.\n")
+		return sb.String()
+	}
+
+	writeTable := func(title string, headers []string, changes []PluginChange, row func(c PluginChange) []string) {
+		if len(changes) == 0 {
+			return
+		}
+		fmt.Fprintf(&sb, "### %s\n\n", title)
+		fmt.Fprintf(&sb, "| %s |\n", strings.Join(headers, " | "))
+		fmt.Fprintf(&sb, "|%s\n", strings.Repeat(" --- |", len(headers)))
+		for _, c := range changes {
+			fmt.Fprintf(&sb, "| %s |\n", strings.Join(row(c), " | "))
+		}
+		sb.WriteString("\n")
+	}
+	writeTable("Added plugins", []string{"Plugin", "Target", "Version", "Mandatory"}, d.Added, func(c PluginChange) []string {
+		return []string{c.Name, c.Target, c.ToVersion, fmt.Sprint(c.ToMandatory)}
+	})
+	writeTable("Removed plugins", []string{"Plugin", "Target", "Version"}, d.Removed, func(c PluginChange) []string {
+		return []string{c.Name, c.Target, c.FromVersion}
+	})
+	writeTable("Upgraded plugins", []string{"Plugin", "Target", "From", "To"}, d.Upgraded, func(c PluginChange) []string {
+		return []string{c.Name, c.Target, c.FromVersion, c.ToVersion}
+	})
+	writeTable("Downgraded plugins", []string{"Plugin", "Target", "From", "To"}, d.Downgraded, func(c PluginChange) []string {
+		return []string{c.Name, c.Target, c.FromVersion, c.ToVersion}
+	})
+	writeTable("Mandatory changes", []string{"Plugin", "Target", "Version", "Mandatory"}, d.MandatoryChanged, func(c PluginChange) []string {
+		return []string{c.Name, c.Target, c.ToVersion, fmt.Sprintf("%v to %v", c.FromMandatory, c.ToMandatory)}
+	})
+
+	hasReleaseNotes := false
+	for _, c := range append(append([]PluginChange{}, d.Added...), d.Upgraded...) {
+		if len(c.ReleaseNotes) > 0 {
+			hasReleaseNotes = true
+			break
+		}
+	}
+	if hasReleaseNotes {
+		sb.WriteString("### Release notes\n\n")
+		for _, c := range append(append([]PluginChange{}, d.Added...), d.Upgraded...) {
+			for _, rn := range c.ReleaseNotes {
+				fmt.Fprintf(&sb, "#### %s (%s) %s\n\n%s\n\n", c.Name, c.Target, rn.Version, rn.Notes)
+			}
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
+
+func (ipdo *InventoryPluginGroupDiffOptions) getInventoryDBFile() (string, error) {
+	if ipdo.LocalRepository != "" {
+		return getLocalRepositoryDBFileForUpdate(ipdo.LocalRepository)
+	}
+	if ipdo.InventoryDBFile != "" {
+		log.Infof("using local plugin inventory database file: %q", ipdo.InventoryDBFile)
+		return ipdo.InventoryDBFile, nil
+	}
+
+	pluginInventoryDBImage := fmt.Sprintf("%s/%s:%s", ipdo.Repository, helpers.PluginInventoryDBImageName, ipdo.InventoryImageTag)
+	tempDir, err := os.MkdirTemp("", "")
+	if err != nil {
+		return "", errors.Wrap(err, "unable to create temporary directory")
+	}
+
+	log.Infof("pulling plugin inventory database from: %q", pluginInventoryDBImage)
+	return inventoryDBDownload(ipdo.ImageOperationsImpl, pluginInventoryDBImage, tempDir)
+}
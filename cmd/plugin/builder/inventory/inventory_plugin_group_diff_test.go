@@ -0,0 +1,174 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/distribution"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/plugininventory"
+)
+
+var pluginManifestWithReleaseNotes = `created: "2024-01-01T00:00:00Z"
+plugins:
+- name: foo
+  target: global
+  description: foo plugin
+  versions:
+  - v1.1.0
+  - v1.2.0
+  releaseNotes:
+    v1.1.0: Add the foo list command
+    v1.2.0: Fix the output of foo get
+- name: baz
+  target: kubernetes
+  description: baz plugin
+  versions:
+  - v0.2.0
+  releaseNotes:
+    v0.2.0: First release of baz
+`
+
+var _ = Describe("Unit tests for inventory plugin-group diff", func() {
+	var (
+		tmpDir string
+		dbFile string
+		out    *bytes.Buffer
+		ipdo   *InventoryPluginGroupDiffOptions
+	)
+
+	entry := func(name string, target types.Target, version string, mandatory bool) *plugininventory.PluginGroupPluginEntry {
+		return &plugininventory.PluginGroupPluginEntry{
+			PluginIdentifier: plugininventory.PluginIdentifier{Name: name, Target: target, Version: version},
+			Mandatory:        mandatory,
+		}
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "")
+		Expect(err).ToNot(HaveOccurred())
+		dbFile = filepath.Join(tmpDir, plugininventory.SQliteDBFileName)
+		db := plugininventory.NewSQLiteInventory(dbFile, "")
+		Expect(db.CreateSchema()).To(Succeed())
+
+		for name, target := range map[string]types.Target{"foo": types.TargetGlobal, "bar": types.TargetK8s, "baz": types.TargetK8s, "qux": types.TargetTMC} {
+			artifacts := distribution.Artifacts{}
+			for _, version := range []string{"v0.1.0", "v0.2.0", "v1.0.0", "v1.1.0", "v1.2.0"} {
+				artifacts[version] = []distribution.Artifact{{OS: "linux", Arch: "amd64", Digest: "digest", Image: "fakevendor/fakepublisher/linux/amd64/" + string(target) + "/" + name + ":" + version}}
+			}
+			err = db.InsertPlugin(&plugininventory.PluginInventoryEntry{
+				Name:        name,
+				Target:      target,
+				Description: name + " plugin",
+				Publisher:   "fakepublisher",
+				Vendor:      "fakevendor",
+				Artifacts:   artifacts,
+			})
+			Expect(err).ToNot(HaveOccurred())
+		}
+		err = db.InsertPluginGroup(&plugininventory.PluginGroup{
+			Vendor:      "fakevendor",
+			Publisher:   "fakepublisher",
+			Name:        "default",
+			Description: "Default group",
+			Versions: map[string][]*plugininventory.PluginGroupPluginEntry{
+				"v1.0.0": {
+					entry("foo", types.TargetGlobal, "v1.0.0", true),
+					entry("bar", types.TargetK8s, "v0.2.0", true),
+					entry("qux", types.TargetTMC, "v0.1.0", false),
+				},
+				"v2.0.0": {
+					entry("foo", types.TargetGlobal, "v1.2.0", true),
+					entry("bar", types.TargetK8s, "v0.1.0", false),
+					entry("baz", types.TargetK8s, "v0.2.0", true),
+				},
+			},
+		}, false)
+		Expect(err).ToNot(HaveOccurred())
+
+		manifestFile := filepath.Join(tmpDir, "plugin_manifest.yaml")
+		Expect(os.WriteFile(manifestFile, []byte(pluginManifestWithReleaseNotes), 0644)).To(Succeed())
+
+		out = &bytes.Buffer{}
+		ipdo = &InventoryPluginGroupDiffOptions{
+			InventoryDBFile:     dbFile,
+			Vendor:              "fakevendor",
+			Publisher:           "fakepublisher",
+			GroupName:           "default",
+			FromVersion:         "v1.0.0",
+			ToVersion:           "v2.0.0",
+			PluginManifestFiles: []string{manifestFile},
+			OutputFormat:        DiffOutputJSON,
+			Output:              out,
+		}
+	})
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	var _ = Context("tests for the inventory plugin-group diff function", func() {
+		var _ = It("should list the plugin changes with their release notes as JSON", func() {
+			Expect(ipdo.PluginGroupDiff()).To(Succeed())
+			diff := &PluginGroupDiff{}
+			Expect(json.Unmarshal(out.Bytes(), diff)).To(Succeed())
+
+			Expect(diff.Group).To(Equal("fakevendor-fakepublisher/default"))
+			Expect(diff.Added).To(Equal([]PluginChange{
+				{Name: "baz", Target: "kubernetes", ToVersion: "v0.2.0", ToMandatory: true, ReleaseNotes: []PluginReleaseNotes{{Version: "v0.2.0", Notes: "First release of baz"}}},
+			}))
+			Expect(diff.Removed).To(Equal([]PluginChange{
+				{Name: "qux", Target: "mission-control", FromVersion: "v0.1.0"},
+			}))
+			Expect(diff.Upgraded).To(Equal([]PluginChange{
+				{Name: "foo", Target: "global", FromVersion: "v1.0.0", ToVersion: "v1.2.0", FromMandatory: true, ToMandatory: true, ReleaseNotes: []PluginReleaseNotes{
+					{Version: "v1.2.0", Notes: "Fix the output of foo get"},
+					{Version: "v1.1.0", Notes: "Add the foo list command"},
+				}},
+			}))
+			Expect(diff.Downgraded).To(Equal([]PluginChange{
+				{Name: "bar", Target: "kubernetes", FromVersion: "v0.2.0", ToVersion: "v0.1.0", FromMandatory: true},
+			}))
+			Expect(diff.MandatoryChanged).To(Equal([]PluginChange{
+				{Name: "bar", Target: "kubernetes", FromVersion: "v0.2.0", ToVersion: "v0.1.0", FromMandatory: true},
+			}))
+		})
+		var _ = It("should output the plugin changes as markdown", func() {
+			ipdo.OutputFormat = DiffOutputMarkdown
+			Expect(ipdo.PluginGroupDiff()).To(Succeed())
+
+			Expect(out.String()).To(ContainSubstring("## fakevendor-fakepublisher/default: v1.0.0 to v2.0.0"))
+			Expect(out.String()).To(ContainSubstring("### Added plugins\n\n| Plugin | Target | Version | Mandatory |\n| --- | --- | --- | --- |\n| baz | kubernetes | v0.2.0 | true |"))
+			Expect(out.String()).To(ContainSubstring("### Removed plugins\n\n| Plugin | Target | Version |\n| --- | --- | --- |\n| qux | mission-control | v0.1.0 |"))
+			Expect(out.String()).To(ContainSubstring("| foo | global | v1.0.0 | v1.2.0 |"))
+			Expect(out.String()).To(ContainSubstring("### Downgraded plugins"))
+			Expect(out.String()).To(ContainSubstring("| bar | kubernetes | v0.1.0 | true to false |"))
+			Expect(out.String()).To(ContainSubstring("#### foo (global) v1.2.0\n\nFix the output of foo get"))
+		})
+		var _ = It("should report no changes between the same versions", func() {
+			ipdo.OutputFormat = DiffOutputMarkdown
+			ipdo.FromVersion = "v2.0.0"
+			Expect(ipdo.PluginGroupDiff()).To(Succeed())
+			Expect(out.String()).To(Equal("## fakevendor-fakepublisher/default: v2.0.0 to v2.0.0\n\nNo plugin changes.\n"))
+		})
+		var _ = It("should fail when a version of the plugin-group does not exist", func() {
+			ipdo.ToVersion = "v3.0.0"
+			err := ipdo.PluginGroupDiff()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`version "v3.0.0" of plugin-group "fakevendor-fakepublisher/default" not found`))
+		})
+		var _ = It("should fail with an invalid output format", func() {
+			ipdo.OutputFormat = "yaml"
+			Expect(ipdo.PluginGroupDiff()).To(MatchError(ContainSubstring(`invalid output format "yaml"`)))
+		})
+	})
+})
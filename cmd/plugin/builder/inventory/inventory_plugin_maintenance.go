@@ -0,0 +1,424 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
+
+	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/helpers"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/carvelhelpers"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/distribution"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/plugininventory"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/utils"
+)
+
+// InventoryPluginMaintenanceOptions defines options for removing, pruning, rewriting and
+// validating the plugin entries of the inventory database
+type InventoryPluginMaintenanceOptions struct {
+	Repository        string
+	InventoryImageTag string
+	InventoryDBFile   string
+	// LocalRepository is the directory of a local plugin repository storing the
+	// inventory database and the plugin binaries, used instead of the Repository
+	LocalRepository string
+
+	// PluginName and Target specify the plugin to remove or prune
+	PluginName string
+	Target     string
+	// Version is the version of the plugin to remove
+	Version string
+	// Keep is the number of most recent versions of each plugin to keep when pruning
+	Keep int
+	// DryRun only reports the plugin versions that would be pruned
+	DryRun bool
+	// FromPrefix and ToPrefix are the old and new prefixes of the plugin binary URIs
+	FromPrefix string
+	ToPrefix   string
+
+	ImageOperationsImpl carvelhelpers.ImageOperationsImpl
+}
+
+// PluginRemove removes a version of a plugin from the inventory database, along with its
+// plugin binaries for a local plugin repository. A plugin version referenced by a plugin
+// group cannot be removed.
+func (ipmo *InventoryPluginMaintenanceOptions) PluginRemove() error {
+	dbFile, err := ipmo.getInventoryDBFile()
+	if err != nil {
+		return err
+	}
+	db := plugininventory.NewSQLiteInventory(dbFile, "")
+
+	plugins, err := db.GetPlugins(&plugininventory.PluginInventoryFilter{
+		Name:          ipmo.PluginName,
+		Target:        configtypes.Target(ipmo.Target),
+		Version:       ipmo.Version,
+		IncludeHidden: true,
+	})
+	if err != nil {
+		return errors.Wrap(err, "error while reading the plugin inventory database")
+	}
+	// The version filter also matches the versions with the specified version as prefix
+	if len(plugins) == 0 || len(plugins[0].Artifacts[ipmo.Version]) == 0 {
+		return errors.Errorf("plugin '%s_%s' version %q not found in the plugin inventory database", ipmo.PluginName, ipmo.Target, ipmo.Version)
+	}
+
+	referencedVersions, err := getPluginVersionsReferencedByGroups(db)
+	if err != nil {
+		return err
+	}
+	if groupID, exists := referencedVersions[pluginVersionKey(ipmo.PluginName, ipmo.Target, ipmo.Version)]; exists {
+		return errors.Errorf("plugin '%s_%s' version %q cannot be removed as it is referenced by the plugin group %q", ipmo.PluginName, ipmo.Target, ipmo.Version, groupID)
+	}
+
+	entry := &plugininventory.PluginInventoryEntry{
+		Name:      ipmo.PluginName,
+		Target:    configtypes.Target(ipmo.Target),
+		Artifacts: distribution.Artifacts{ipmo.Version: plugins[0].Artifacts[ipmo.Version]},
+	}
+	if err := ipmo.deletePlugin(db, entry); err != nil {
+		return err
+	}
+	log.Infof("removed plugin '%s_%s' version %q", ipmo.PluginName, ipmo.Target, ipmo.Version)
+
+	return ipmo.putInventoryDBFile(dbFile)
+}
+
+// PluginPrune removes the oldest versions of the plugins from the inventory database,
+// keeping the specified number of most recent versions of each plugin as well as the
+// versions referenced by plugin groups
+func (ipmo *InventoryPluginMaintenanceOptions) PluginPrune() error {
+	if ipmo.Keep < 1 {
+		return errors.New("at least one version of each plugin must be kept")
+	}
+
+	dbFile, err := ipmo.getInventoryDBFile()
+	if err != nil {
+		return err
+	}
+	db := plugininventory.NewSQLiteInventory(dbFile, "")
+
+	plugins, err := db.GetPlugins(&plugininventory.PluginInventoryFilter{
+		Name:          ipmo.PluginName,
+		Target:        configtypes.Target(ipmo.Target),
+		IncludeHidden: true,
+	})
+	if err != nil {
+		return errors.Wrap(err, "error while reading the plugin inventory database")
+	}
+	referencedVersions, err := getPluginVersionsReferencedByGroups(db)
+	if err != nil {
+		return err
+	}
+
+	var prunedEntries []*plugininventory.PluginInventoryEntry
+	for _, p := range plugins {
+		versions := make([]string, 0, len(p.Artifacts))
+		for version := range p.Artifacts {
+			versions = append(versions, version)
+		}
+		if len(versions) <= ipmo.Keep {
+			continue
+		}
+		if err := utils.SortVersions(versions); err != nil {
+			return errors.Wrapf(err, "unable to sort the versions of plugin '%s_%s'", p.Name, p.Target)
+		}
+
+		entry := &plugininventory.PluginInventoryEntry{
+			Name:      p.Name,
+			Target:    p.Target,
+			Artifacts: distribution.Artifacts{},
+		}
+		for _, version := range versions[:len(versions)-ipmo.Keep] {
+			if groupID, exists := referencedVersions[pluginVersionKey(p.Name, string(p.Target), version)]; exists {
+				log.Infof("keeping plugin '%s_%s' version %q referenced by the plugin group %q", p.Name, p.Target, version, groupID)
+				continue
+			}
+			entry.Artifacts[version] = p.Artifacts[version]
+		}
+		if len(entry.Artifacts) > 0 {
+			prunedEntries = append(prunedEntries, entry)
+		}
+	}
+
+	if len(prunedEntries) == 0 {
+		log.Info("no plugin version to prune")
+		return nil
+	}
+	for _, entry := range prunedEntries {
+		versions := sortedVersions(entry.Artifacts)
+		if ipmo.DryRun {
+			log.Infof("plugin '%s_%s' versions %v would be pruned", entry.Name, entry.Target, versions)
+			continue
+		}
+		if err := ipmo.deletePlugin(db, entry); err != nil {
+			return err
+		}
+		log.Infof("pruned plugin '%s_%s' versions %v", entry.Name, entry.Target, versions)
+	}
+	if ipmo.DryRun {
+		return nil
+	}
+
+	return ipmo.putInventoryDBFile(dbFile)
+}
+
+// RewriteURI replaces the prefix of the URIs of the plugin binaries in the inventory
+// database, e.g., after the plugin images have been migrated to another registry
+func (ipmo *InventoryPluginMaintenanceOptions) RewriteURI() error {
+	dbFile, err := ipmo.getInventoryDBFile()
+	if err != nil {
+		return err
+	}
+	db := plugininventory.NewSQLiteInventory(dbFile, "")
+
+	count, err := db.UpdatePluginURIPrefix(ipmo.FromPrefix, ipmo.ToPrefix)
+	if err != nil {
+		return errors.Wrap(err, "error while rewriting the URIs of the plugin binaries")
+	}
+	if count == 0 {
+		log.Infof("no plugin binary URI starts with %q", ipmo.FromPrefix)
+		return nil
+	}
+	log.Infof("rewrote the URI of %d plugin binaries from prefix %q to prefix %q", count, ipmo.FromPrefix, ipmo.ToPrefix)
+
+	return ipmo.putInventoryDBFile(dbFile)
+}
+
+// Validate verifies that the plugin binary of every plugin of the inventory database
+// exists and matches its digest, and that every plugin referenced by a plugin group
+// exists in the inventory database
+func (ipmo *InventoryPluginMaintenanceOptions) Validate() error {
+	dbFile, err := ipmo.getInventoryDBFile()
+	if err != nil {
+		return err
+	}
+	// The plugin binary URIs are relative to the location of the inventory database
+	prefix := ipmo.Repository
+	if ipmo.LocalRepository != "" {
+		prefix = filepath.ToSlash(ipmo.LocalRepository)
+	}
+	db := plugininventory.NewSQLiteInventory(dbFile, prefix)
+
+	plugins, err := db.GetPlugins(&plugininventory.PluginInventoryFilter{IncludeHidden: true})
+	if err != nil {
+		return errors.Wrap(err, "error while reading the plugins of the plugin inventory database")
+	}
+	groups, err := db.GetPluginGroups(plugininventory.PluginGroupFilter{IncludeHidden: true})
+	if err != nil {
+		return errors.Wrap(err, "error while reading the plugin groups of the plugin inventory database")
+	}
+
+	errList := ipmo.validatePluginBinaries(plugins)
+	errList = append(errList, validatePluginGroups(plugins, groups)...)
+	if len(errList) > 0 {
+		for _, err := range errList {
+			log.Errorf("%v", err)
+		}
+		return errors.Wrapf(kerrors.NewAggregate(errList), "%d errors found in the plugin inventory database", len(errList))
+	}
+	log.Info("validation successful")
+	return nil
+}
+
+// validatePluginBinaries verifies in parallel that every plugin binary exists and matches its digest
+func (ipmo *InventoryPluginMaintenanceOptions) validatePluginBinaries(plugins []*plugininventory.PluginInventoryEntry) []error {
+	// Limit the number of concurrent operations we perform so we don't overwhelm the system.
+	guard := make(chan struct{}, helpers.GetMaxParallelism())
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	var errList []error
+
+	for _, p := range plugins {
+		for version, artifacts := range p.Artifacts {
+			for _, a := range artifacts {
+				wg.Add(1)
+				guard <- struct{}{}
+				go func(p *plugininventory.PluginInventoryEntry, version string, a distribution.Artifact) {
+					defer func() {
+						<-guard
+						wg.Done()
+					}()
+					if err := ipmo.validatePluginBinary(p.Name, a); err != nil {
+						mutex.Lock()
+						errList = append(errList, errors.Wrapf(err, "plugin '%s_%s' version %q for %s_%s", p.Name, p.Target, version, a.OS, a.Arch))
+						mutex.Unlock()
+					}
+				}(p, version, a)
+			}
+		}
+	}
+	wg.Wait()
+
+	// Sort the errors to report them in a stable order
+	sort.Slice(errList, func(i, j int) bool { return errList[i].Error() < errList[j].Error() })
+	return errList
+}
+
+// validatePluginBinary verifies that the plugin binary of the artifact exists and matches its digest
+func (ipmo *InventoryPluginMaintenanceOptions) validatePluginBinary(pluginName string, a distribution.Artifact) error {
+	var digest string
+	var err error
+	if ipmo.LocalRepository != "" {
+		pluginBinary := filepath.FromSlash(a.Image)
+		if !utils.PathExists(pluginBinary) {
+			return errors.Errorf("plugin binary %q not found", pluginBinary)
+		}
+		digest, err = helpers.GetDigest(pluginBinary)
+	} else {
+		digest, err = ipmo.ImageOperationsImpl.GetFileDigestFromImage(a.Image, cli.MakeArtifactName(pluginName, cli.Arch(a.OS+"_"+a.Arch)))
+	}
+	if err != nil {
+		return errors.Wrapf(err, "unable to get the digest of the plugin binary %q", a.Image)
+	}
+	if digest != a.Digest {
+		return errors.Errorf("the digest %q of the plugin binary %q does not match the digest %q of the inventory database", digest, a.Image, a.Digest)
+	}
+	return nil
+}
+
+// validatePluginGroups verifies that every plugin referenced by the plugin groups exists
+func validatePluginGroups(plugins []*plugininventory.PluginInventoryEntry, groups []*plugininventory.PluginGroup) []error {
+	pluginVersions := map[string]bool{}
+	for _, p := range plugins {
+		for version := range p.Artifacts {
+			pluginVersions[pluginVersionKey(p.Name, string(p.Target), version)] = true
+		}
+	}
+
+	var errList []error
+	for _, pg := range groups {
+		for _, groupVersion := range sortedGroupVersions(pg) {
+			for _, pi := range pg.Versions[groupVersion] {
+				if !pluginVersions[pluginVersionKey(pi.Name, string(pi.Target), pi.Version)] {
+					errList = append(errList, errors.Errorf("plugin group '%s:%s' references plugin '%s_%s' version %q which is not in the plugin inventory database", plugininventory.PluginGroupToID(pg), groupVersion, pi.Name, pi.Target, pi.Version))
+				}
+			}
+		}
+	}
+	return errList
+}
+
+// getPluginVersionsReferencedByGroups returns the plugin versions referenced by the
+// plugin groups, including the deactivated ones, along with a referencing plugin group
+func getPluginVersionsReferencedByGroups(db plugininventory.PluginInventory) (map[string]string, error) {
+	groups, err := db.GetPluginGroups(plugininventory.PluginGroupFilter{IncludeHidden: true})
+	if err != nil {
+		return nil, errors.Wrap(err, "error while reading the plugin groups of the plugin inventory database")
+	}
+	referencedVersions := map[string]string{}
+	for _, pg := range groups {
+		for groupVersion, plugins := range pg.Versions {
+			for _, pi := range plugins {
+				referencedVersions[pluginVersionKey(pi.Name, string(pi.Target), pi.Version)] = fmt.Sprintf("%s:%s", plugininventory.PluginGroupToID(pg), groupVersion)
+			}
+		}
+	}
+	return referencedVersions, nil
+}
+
+// deletePlugin deletes the plugin versions from the inventory database and,
+// for a local plugin repository, their plugin binaries
+func (ipmo *InventoryPluginMaintenanceOptions) deletePlugin(db plugininventory.PluginInventory, entry *plugininventory.PluginInventoryEntry) error {
+	if err := db.DeletePlugin(entry); err != nil {
+		return errors.Wrapf(err, "error while removing plugin '%s_%s'", entry.Name, entry.Target)
+	}
+	if ipmo.LocalRepository == "" {
+		return nil
+	}
+	for _, artifacts := range entry.Artifacts {
+		for _, a := range artifacts {
+			// The URI is relative to the local plugin repository, as the inventory database is
+			// read without URI prefix, and the directory of the plugin binary also contains
+			// its provenance statement and test plugin binary
+			binaryDir := filepath.Join(ipmo.LocalRepository, filepath.FromSlash(path.Dir(strings.TrimPrefix(a.Image, "/"))))
+			if err := os.RemoveAll(binaryDir); err != nil {
+				return errors.Wrapf(err, "unable to remove the plugin binary directory %q", binaryDir)
+			}
+		}
+	}
+	return nil
+}
+
+func pluginVersionKey(name, target, version string) string {
+	return fmt.Sprintf("%s_%s:%s", name, target, version)
+}
+
+func sortedVersions(artifacts distribution.Artifacts) []string {
+	versions := make([]string, 0, len(artifacts))
+	for version := range artifacts {
+		versions = append(versions, version)
+	}
+	if err := utils.SortVersions(versions); err != nil {
+		sort.Strings(versions)
+	}
+	return versions
+}
+
+func sortedGroupVersions(pg *plugininventory.PluginGroup) []string {
+	versions := make([]string, 0, len(pg.Versions))
+	for version := range pg.Versions {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+func (ipmo *InventoryPluginMaintenanceOptions) getPluginInventoryDBImagePath() string {
+	return fmt.Sprintf("%s/%s:%s", ipmo.Repository, helpers.PluginInventoryDBImageName, ipmo.InventoryImageTag)
+}
+
+func (ipmo *InventoryPluginMaintenanceOptions) getInventoryDBFile() (string, error) {
+	if ipmo.LocalRepository != "" {
+		return getLocalRepositoryDBFileForUpdate(ipmo.LocalRepository)
+	}
+	if ipmo.InventoryDBFile != "" {
+		log.Infof("using local plugin inventory database file: %q", ipmo.InventoryDBFile)
+		return ipmo.InventoryDBFile, nil
+	}
+
+	// get plugin inventory database image path
+	pluginInventoryDBImage := ipmo.getPluginInventoryDBImagePath()
+
+	tempDir, err := os.MkdirTemp("", "")
+	if err != nil {
+		return "", errors.Wrap(err, "unable to create temporary directory")
+	}
+
+	log.Infof("pulling plugin inventory database from: %q", pluginInventoryDBImage)
+	return inventoryDBDownload(ipmo.ImageOperationsImpl, pluginInventoryDBImage, tempDir)
+}
+
+func (ipmo *InventoryPluginMaintenanceOptions) putInventoryDBFile(dbFile string) error {
+	// If local inventory database file was provided nothing to publish just return
+	if ipmo.LocalRepository != "" {
+		log.Infof("successfully updated local plugin repository at: %q", ipmo.LocalRepository)
+		return nil
+	}
+	if ipmo.InventoryDBFile != "" {
+		log.Infof("successfully updated plugin inventory database file at: %q", ipmo.InventoryDBFile)
+		return nil
+	}
+
+	// Publish the database to the remote repository
+	pluginInventoryDBImage := ipmo.getPluginInventoryDBImagePath()
+	log.Info("publishing plugin inventory database")
+	if err := inventoryDBUpload(ipmo.ImageOperationsImpl, pluginInventoryDBImage, dbFile); err != nil {
+		return err
+	}
+	log.Infof("successfully published plugin inventory database at: %q", pluginInventoryDBImage)
+	return nil
+}
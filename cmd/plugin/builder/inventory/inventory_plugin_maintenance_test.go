@@ -0,0 +1,306 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+
+	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/helpers"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/distribution"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/fakes"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/plugininventory"
+)
+
+var _ = Describe("Unit tests for inventory plugin maintenance", func() {
+	var (
+		tmpDir            string
+		dbFile            string
+		fakeImgpkgWrapper *fakes.ImageOperationsImpl
+		ipmo              *InventoryPluginMaintenanceOptions
+	)
+
+	// insertPlugin inserts the versions of the plugin to the database
+	insertPlugin := func(name string, target types.Target, versions ...string) {
+		artifacts := distribution.Artifacts{}
+		for _, version := range versions {
+			artifacts[version] = []distribution.Artifact{
+				{
+					OS:     "linux",
+					Arch:   "amd64",
+					Digest: "digest-" + name + "-" + version,
+					Image:  "fakevendor/fakepublisher/linux/amd64/" + string(target) + "/" + name + ":" + version,
+				},
+			}
+		}
+		db := plugininventory.NewSQLiteInventory(dbFile, "")
+		err := db.InsertPlugin(&plugininventory.PluginInventoryEntry{
+			Name:        name,
+			Target:      target,
+			Description: name + " plugin",
+			Publisher:   "fakepublisher",
+			Vendor:      "fakevendor",
+			Artifacts:   artifacts,
+		})
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	// insertPluginGroup inserts a plugin group referencing the plugin version
+	insertPluginGroup := func(name string, target types.Target, version string) {
+		db := plugininventory.NewSQLiteInventory(dbFile, "")
+		err := db.InsertPluginGroup(&plugininventory.PluginGroup{
+			Vendor:      "fakevendor",
+			Publisher:   "fakepublisher",
+			Name:        "default",
+			Description: "Default group",
+			Versions: map[string][]*plugininventory.PluginGroupPluginEntry{
+				"v1.0.0": {
+					{PluginIdentifier: plugininventory.PluginIdentifier{Name: name, Target: target, Version: version}, Mandatory: true},
+				},
+			},
+		}, false)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	getVersions := func(name string, target types.Target) []string {
+		db := plugininventory.NewSQLiteInventory(dbFile, "")
+		plugins, err := db.GetPlugins(&plugininventory.PluginInventoryFilter{Name: name, Target: target, IncludeHidden: true})
+		Expect(err).ToNot(HaveOccurred())
+		var versions []string
+		for _, p := range plugins {
+			versions = append(versions, sortedVersions(p.Artifacts)...)
+		}
+		return versions
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "")
+		Expect(err).ToNot(HaveOccurred())
+		dbFile = filepath.Join(tmpDir, plugininventory.SQliteDBFileName)
+		err = plugininventory.NewSQLiteInventory(dbFile, "").CreateSchema()
+		Expect(err).ToNot(HaveOccurred())
+
+		insertPlugin("foo", types.TargetGlobal, "v1.0.0", "v1.1.0", "v1.2.0", "v1.10.0")
+		insertPlugin("bar", types.TargetK8s, "v0.1.0")
+
+		fakeImgpkgWrapper = &fakes.ImageOperationsImpl{}
+		ipmo = &InventoryPluginMaintenanceOptions{
+			Repository:          "test-repo.com",
+			InventoryImageTag:   "latest",
+			InventoryDBFile:     dbFile,
+			ImageOperationsImpl: fakeImgpkgWrapper,
+		}
+	})
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	var _ = Context("tests for the inventory plugin remove function", func() {
+		var _ = It("should remove the plugin version", func() {
+			ipmo.PluginName = "foo"
+			ipmo.Target = "global"
+			ipmo.Version = "v1.1.0"
+			err := ipmo.PluginRemove()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(getVersions("foo", types.TargetGlobal)).To(Equal([]string{"v1.0.0", "v1.2.0", "v1.10.0"}))
+			Expect(getVersions("bar", types.TargetK8s)).To(Equal([]string{"v0.1.0"}))
+		})
+
+		var _ = It("should fail when the plugin version does not exist", func() {
+			ipmo.PluginName = "foo"
+			ipmo.Target = "global"
+			ipmo.Version = "v1"
+			err := ipmo.PluginRemove()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`plugin 'foo_global' version "v1" not found in the plugin inventory database`))
+			Expect(getVersions("foo", types.TargetGlobal)).To(HaveLen(4))
+		})
+
+		var _ = It("should fail when the plugin version is referenced by a plugin group", func() {
+			insertPluginGroup("foo", types.TargetGlobal, "v1.1.0")
+			ipmo.PluginName = "foo"
+			ipmo.Target = "global"
+			ipmo.Version = "v1.1.0"
+			err := ipmo.PluginRemove()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`cannot be removed as it is referenced by the plugin group "fakevendor-fakepublisher/default:v1.0.0"`))
+			Expect(getVersions("foo", types.TargetGlobal)).To(HaveLen(4))
+		})
+
+		var _ = It("should publish the updated inventory database to the repository", func() {
+			ipmo.InventoryDBFile = ""
+			fakeImgpkgWrapper.DownloadImageAndSaveFilesToDirCalls(func(_, dir string) error {
+				data, err := os.ReadFile(dbFile)
+				Expect(err).ToNot(HaveOccurred())
+				return os.WriteFile(filepath.Join(dir, plugininventory.SQliteDBFileName), data, 0644)
+			})
+			fakeImgpkgWrapper.PushImageReturns(nil)
+
+			ipmo.PluginName = "bar"
+			ipmo.Target = "kubernetes"
+			ipmo.Version = "v0.1.0"
+			err := ipmo.PluginRemove()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeImgpkgWrapper.PushImageCallCount()).To(Equal(1))
+			image, files := fakeImgpkgWrapper.PushImageArgsForCall(0)
+			Expect(image).To(Equal("test-repo.com/plugin-inventory:latest"))
+			Expect(files).To(HaveLen(1))
+
+			db := plugininventory.NewSQLiteInventory(files[0], "")
+			plugins, err := db.GetPlugins(&plugininventory.PluginInventoryFilter{Name: "bar", IncludeHidden: true})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plugins).To(BeEmpty())
+		})
+	})
+
+	var _ = Context("tests for the inventory plugin prune function", func() {
+		var _ = It("should keep the most recent versions of each plugin", func() {
+			ipmo.Keep = 2
+			err := ipmo.PluginPrune()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(getVersions("foo", types.TargetGlobal)).To(Equal([]string{"v1.2.0", "v1.10.0"}))
+			Expect(getVersions("bar", types.TargetK8s)).To(Equal([]string{"v0.1.0"}))
+		})
+
+		var _ = It("should keep the versions referenced by plugin groups", func() {
+			insertPluginGroup("foo", types.TargetGlobal, "v1.0.0")
+			ipmo.Keep = 1
+			err := ipmo.PluginPrune()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(getVersions("foo", types.TargetGlobal)).To(Equal([]string{"v1.0.0", "v1.10.0"}))
+		})
+
+		var _ = It("should not remove any version with the dry-run option", func() {
+			ipmo.Keep = 1
+			ipmo.DryRun = true
+			err := ipmo.PluginPrune()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(getVersions("foo", types.TargetGlobal)).To(HaveLen(4))
+		})
+
+		var _ = It("should fail when no version is kept", func() {
+			ipmo.Keep = 0
+			err := ipmo.PluginPrune()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("at least one version of each plugin must be kept"))
+		})
+	})
+
+	var _ = Context("tests for the inventory plugin rewrite-uri function", func() {
+		var _ = It("should replace the prefix of the plugin binary URIs", func() {
+			ipmo.FromPrefix = "fakevendor/fakepublisher/linux/amd64/kubernetes/"
+			ipmo.ToPrefix = "newvendor/newpublisher/linux/amd64/kubernetes/"
+			err := ipmo.RewriteURI()
+			Expect(err).ToNot(HaveOccurred())
+
+			db := plugininventory.NewSQLiteInventory(dbFile, "test-repo.com")
+			plugins, err := db.GetPlugins(&plugininventory.PluginInventoryFilter{Name: "bar"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plugins).To(HaveLen(1))
+			Expect(plugins[0].Artifacts["v0.1.0"][0].Image).To(Equal("test-repo.com/newvendor/newpublisher/linux/amd64/kubernetes/bar:v0.1.0"))
+			plugins, err = db.GetPlugins(&plugininventory.PluginInventoryFilter{Name: "foo", Version: "v1.0.0"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plugins[0].Artifacts["v1.0.0"][0].Image).To(Equal("test-repo.com/fakevendor/fakepublisher/linux/amd64/global/foo:v1.0.0"))
+		})
+	})
+
+	var _ = Context("tests for the inventory plugin validate function", func() {
+		var _ = It("should succeed when all the plugin binaries match their digest", func() {
+			insertPluginGroup("bar", types.TargetK8s, "v0.1.0")
+			fakeImgpkgWrapper.GetFileDigestFromImageCalls(func(image, _ string) (string, error) {
+				db := plugininventory.NewSQLiteInventory(dbFile, "test-repo.com")
+				plugins, err := db.GetAllPlugins()
+				Expect(err).ToNot(HaveOccurred())
+				for _, p := range plugins {
+					for _, artifacts := range p.Artifacts {
+						if artifacts[0].Image == image {
+							return artifacts[0].Digest, nil
+						}
+					}
+				}
+				return "", errors.New("image not found")
+			})
+			err := ipmo.Validate()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeImgpkgWrapper.GetFileDigestFromImageCallCount()).To(Equal(5))
+		})
+
+		var _ = It("should report the invalid plugin binaries and plugin group entries", func() {
+			insertPluginGroup("bar", types.TargetK8s, "v0.1.0")
+			// Remove the plugin referenced by the group without the verification
+			db := plugininventory.NewSQLiteInventory(dbFile, "")
+			err := db.DeletePlugin(&plugininventory.PluginInventoryEntry{Name: "bar", Target: types.TargetK8s, Artifacts: distribution.Artifacts{"v0.1.0": nil}})
+			Expect(err).ToNot(HaveOccurred())
+
+			fakeImgpkgWrapper.GetFileDigestFromImageCalls(func(image, _ string) (string, error) {
+				if image == "test-repo.com/fakevendor/fakepublisher/linux/amd64/global/foo:v1.0.0" {
+					return "", errors.New("image not found")
+				}
+				return "wrong-digest", nil
+			})
+			err = ipmo.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("5 errors found in the plugin inventory database"))
+			Expect(err.Error()).To(ContainSubstring(`plugin 'foo_global' version "v1.0.0" for linux_amd64: unable to get the digest of the plugin binary`))
+			Expect(err.Error()).To(ContainSubstring(`plugin 'foo_global' version "v1.1.0" for linux_amd64: the digest "wrong-digest" of the plugin binary`))
+			Expect(err.Error()).To(ContainSubstring(`plugin group 'fakevendor-fakepublisher/default:v1.0.0' references plugin 'bar_kubernetes' version "v0.1.0" which is not in the plugin inventory database`))
+		})
+
+		var _ = It("should validate the plugin binaries of a local plugin repository", func() {
+			localRepository := filepath.Join(tmpDir, "repository")
+			err := initializeLocalRepository(localRepository, false)
+			Expect(err).ToNot(HaveOccurred())
+			dbFile = getLocalRepositoryDBFile(localRepository)
+
+			pluginBinary := filepath.Join(localRepository, "fakevendor", "fakepublisher", "linux", "amd64", "global", "foo", "v1.0.0", "tanzu-foo-linux_amd64")
+			Expect(os.MkdirAll(filepath.Dir(pluginBinary), 0755)).To(Succeed())
+			Expect(os.WriteFile(pluginBinary, []byte("foo binary"), 0755)).To(Succeed())
+			digest, err := helpers.GetDigest(pluginBinary)
+			Expect(err).ToNot(HaveOccurred())
+
+			db := plugininventory.NewSQLiteInventory(dbFile, "")
+			err = db.InsertPlugin(&plugininventory.PluginInventoryEntry{
+				Name:        "foo",
+				Target:      types.TargetGlobal,
+				Description: "foo plugin",
+				Publisher:   "fakepublisher",
+				Vendor:      "fakevendor",
+				Artifacts: distribution.Artifacts{
+					"v1.0.0": {{OS: "linux", Arch: "amd64", Digest: digest, Image: "fakevendor/fakepublisher/linux/amd64/global/foo/v1.0.0/tanzu-foo-linux_amd64"}},
+					"v2.0.0": {{OS: "linux", Arch: "amd64", Digest: digest, Image: "fakevendor/fakepublisher/linux/amd64/global/foo/v2.0.0/tanzu-foo-linux_amd64"}},
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			ipmo.Repository = ""
+			ipmo.InventoryDBFile = ""
+			ipmo.LocalRepository = localRepository
+			err = ipmo.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("1 errors found in the plugin inventory database"))
+			Expect(err.Error()).To(ContainSubstring(`plugin 'foo_global' version "v2.0.0" for linux_amd64: plugin binary`))
+
+			// Removing the missing plugin version also removes the plugin binaries of the version
+			ipmo.PluginName = "foo"
+			ipmo.Target = "global"
+			ipmo.Version = "v1.0.0"
+			err = ipmo.PluginRemove()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(filepath.Dir(pluginBinary)).ToNot(BeADirectory())
+
+			ipmo.Version = "v2.0.0"
+			err = ipmo.PluginRemove()
+			Expect(err).ToNot(HaveOccurred())
+			err = ipmo.Validate()
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+})
@@ -13,6 +13,8 @@ import (
 
 	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
 
+	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/helpers"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/distribution"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/fakes"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/plugininventory"
@@ -170,6 +172,55 @@ var _ = Describe("Unit tests for inventory plugin add", func() {
 		})
 	})
 
+	var _ = Context("tests for the inventory plugin add function with signatures", func() {
+		AfterEach(func() {
+			iip.WithSignatures = false
+		})
+
+		var _ = It("when the signatures of the plugin binaries are published", func() {
+			fakeImgpkgWrapper.ResolveImageReturns(nil)
+			fakeImgpkgWrapper.PushImageReturns(nil)
+			fakeImgpkgWrapper.DownloadImageAndSaveFilesToDirCalls(pullDBImageStub)
+			fakeImgpkgWrapper.GetFileDigestFromImageReturns("fake-digest", nil)
+			signatureFiles := map[string][]byte{}
+			for _, osArch := range cli.AllOSArch {
+				signatureFiles[cli.MakeArtifactName("foo", osArch)+".sig"] = []byte("signature-" + osArch.String() + "\n")
+			}
+			fakeImgpkgWrapper.GetFilesMapFromImageReturns(signatureFiles, nil)
+
+			iip.DeactivatePlugins = false
+			iip.WithSignatures = true
+			err := iip.PluginAdd()
+			Expect(err).NotTo(HaveOccurred())
+
+			image := fakeImgpkgWrapper.GetFilesMapFromImageArgsForCall(0)
+			Expect(image).To(HaveSuffix(":v0.0.2.sig"))
+
+			db := plugininventory.NewSQLiteInventory(referencedDBFile, "")
+			pluginInventoryEntries, err := db.GetAllPlugins()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(pluginInventoryEntries)).To(Equal(1))
+			artifacts := pluginInventoryEntries[0].Artifacts["v0.0.2"]
+			Expect(artifacts).NotTo(BeEmpty())
+			for _, a := range artifacts {
+				Expect(a.Signature).To(Equal("signature-" + a.OS + "_" + a.Arch))
+			}
+		})
+
+		var _ = It("when the signatures of the plugin binaries are not published", func() {
+			fakeImgpkgWrapper.ResolveImageReturns(nil)
+			fakeImgpkgWrapper.PushImageReturns(nil)
+			fakeImgpkgWrapper.DownloadImageAndSaveFilesToDirCalls(pullDBImageStub)
+			fakeImgpkgWrapper.GetFileDigestFromImageReturns("fake-digest", nil)
+			fakeImgpkgWrapper.GetFilesMapFromImageReturns(nil, errors.New("image not found"))
+
+			iip.WithSignatures = true
+			err := iip.PluginAdd()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("error while getting plugin binary signature from the image"))
+		})
+	})
+
 	var _ = Context("tests for the inventory plugin UpdatePluginActivationState function", func() {
 
 		var _ = It("when specified pluginInventoryEntry doesn't exist in database", func() {
@@ -230,6 +281,106 @@ var _ = Describe("Unit tests for inventory plugin add", func() {
 			Expect(pluginInventoryEntries[0].Artifacts["v0.0.2"]).NotTo(BeNil())
 		})
 	})
+
+	var _ = Context("tests for the inventory plugin add function with a local plugin repository", func() {
+		var binaryArtifactDir, localRepository string
+		var localManifestFile string
+
+		BeforeEach(func() {
+			binaryArtifactDir, err = os.MkdirTemp("", "binary-artifacts")
+			Expect(err).ToNot(HaveOccurred())
+			localRepository, err = os.MkdirTemp("", "local-repository")
+			Expect(err).ToNot(HaveOccurred())
+
+			manifest, err := os.ReadFile(manifestFile)
+			Expect(err).ToNot(HaveOccurred())
+			localManifestFile = filepath.Join(binaryArtifactDir, cli.PluginManifestFileName)
+			Expect(os.WriteFile(localManifestFile, manifest, 0644)).To(Succeed())
+
+			for _, osArch := range cli.MinOSArch {
+				pluginDir := filepath.Join(binaryArtifactDir, osArch.OS(), osArch.Arch(), "global", "foo", "v0.0.2")
+				Expect(os.MkdirAll(filepath.Join(pluginDir, "test"), 0755)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(pluginDir, cli.MakeArtifactName("foo", osArch)), []byte("foo-"+osArch.String()), 0755)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(pluginDir, "test", cli.MakeArtifactName("foo-test", osArch)), []byte("foo-test"), 0755)).To(Succeed())
+			}
+
+			iio := InventoryInitOptions{LocalRepository: localRepository}
+			Expect(iio.InitializeInventory()).To(Succeed())
+		})
+		AfterEach(func() {
+			os.RemoveAll(binaryArtifactDir)
+			os.RemoveAll(localRepository)
+		})
+
+		var _ = It("when the plugin binaries are available, the binaries are copied and the database is updated", func() {
+			iipLocal := InventoryPluginUpdateOptions{
+				LocalRepository: localRepository,
+				Vendor:          "fakevendor",
+				Publisher:       "fakepublisher",
+				ManifestFile:    localManifestFile,
+			}
+			err := iipLocal.PluginAdd()
+			Expect(err).NotTo(HaveOccurred())
+
+			db := plugininventory.NewSQLiteInventory(filepath.Join(localRepository, plugininventory.SQliteDBFileName), "")
+			pluginInventoryEntries, err := db.GetAllPlugins()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(pluginInventoryEntries)).To(Equal(1))
+			Expect(pluginInventoryEntries[0].Name).To(Equal("foo"))
+			Expect(len(pluginInventoryEntries[0].Artifacts["v0.0.2"])).To(Equal(len(cli.MinOSArch)))
+
+			for _, a := range pluginInventoryEntries[0].Artifacts["v0.0.2"] {
+				osArch := cli.Arch(a.OS + "_" + a.Arch)
+				relativePath := "fakevendor/fakepublisher/" + a.OS + "/" + a.Arch + "/global/foo/v0.0.2/" + cli.MakeArtifactName("foo", osArch)
+				// The inventory adds the URI prefix, which is empty here, to the stored path
+				Expect(a.Image).To(Equal("/" + relativePath))
+
+				binaryPath := filepath.Join(localRepository, filepath.FromSlash(relativePath))
+				Expect(binaryPath).To(BeARegularFile())
+				Expect(filepath.Join(filepath.Dir(binaryPath), "test", cli.MakeArtifactName("foo-test", osArch))).To(BeARegularFile())
+				digest, err := helpers.GetDigest(binaryPath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(a.Digest).To(Equal(digest))
+			}
+
+			// The plugins can then be deactivated in the local plugin repository
+			iipLocal.DeactivatePlugins = true
+			err = iipLocal.UpdatePluginActivationState()
+			Expect(err).NotTo(HaveOccurred())
+			pluginInventoryEntries, err = db.GetPlugins(&plugininventory.PluginInventoryFilter{IncludeHidden: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(pluginInventoryEntries)).To(Equal(1))
+			Expect(pluginInventoryEntries[0].Hidden).To(Equal(true))
+		})
+
+		var _ = It("when a plugin binary of a required os-arch is missing", func() {
+			osArch := cli.MinOSArch[0]
+			Expect(os.Remove(filepath.Join(binaryArtifactDir, osArch.OS(), osArch.Arch(), "global", "foo", "v0.0.2", cli.MakeArtifactName("foo", osArch)))).To(Succeed())
+
+			iipLocal := InventoryPluginUpdateOptions{
+				LocalRepository:   localRepository,
+				BinaryArtifactDir: binaryArtifactDir,
+				Vendor:            "fakevendor",
+				Publisher:         "fakepublisher",
+				ManifestFile:      localManifestFile,
+			}
+			err := iipLocal.PluginAdd()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("error while adding the plugin binaries to the local plugin repository"))
+		})
+
+		var _ = It("when the local plugin repository is not initialized", func() {
+			iipLocal := InventoryPluginUpdateOptions{
+				LocalRepository: filepath.Join(localRepository, "missing"),
+				Vendor:          "fakevendor",
+				Publisher:       "fakepublisher",
+				ManifestFile:    localManifestFile,
+			}
+			err := iipLocal.PluginAdd()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("tanzu builder inventory init --local-repository"))
+		})
+	})
 })
 
 func createTestManifestFile() (string, error) {
@@ -0,0 +1,402 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
+
+	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/helpers"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/carvelhelpers"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cosignhelper"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/distribution"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/plugininventory"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/utils"
+)
+
+// InventoryPromoteOptions defines options for promoting plugin and plugin-group
+// versions from one plugin inventory to another, e.g., from staging to production
+type InventoryPromoteOptions struct {
+	// FromImage is the plugin inventory image to promote the versions from
+	FromImage string
+	// ToImage is the plugin inventory image to promote the versions to
+	ToImage string
+	// Plugins are the plugin versions to promote, as name@target:version
+	Plugins []string
+	// PluginGroups are the plugin-group versions to promote along with the
+	// plugin versions they reference, as vendor-publisher/name:version
+	PluginGroups []string
+	// SigningKey is the cosign private key reference used to re-sign the plugin
+	// binaries. The signatures of the source inventory are kept if empty.
+	SigningKey string
+	// Force promotes deactivated versions, which remain deactivated
+	Force bool
+
+	ImageOperationsImpl carvelhelpers.ImageOperationsImpl
+
+	signBlob func(blob []byte) ([]byte, error)
+}
+
+// Promote copies the selected plugin versions, their plugin binary images and the selected
+// plugin-group versions from the source plugin inventory to the destination plugin inventory.
+// The digests of the plugin binaries are verified before publishing them, and the promotions
+// are recorded in the destination inventory database before publishing it.
+func (ipo *InventoryPromoteOptions) Promote() error {
+	if ipo.FromImage == "" || ipo.ToImage == "" {
+		return errors.New("both the source and the destination plugin inventory images are required")
+	}
+	if ipo.FromImage == ipo.ToImage {
+		return errors.New("the source and the destination plugin inventory images must be different")
+	}
+	if len(ipo.Plugins) == 0 && len(ipo.PluginGroups) == 0 {
+		return errors.New("at least one plugin or plugin-group version to promote is required")
+	}
+	if ipo.SigningKey != "" {
+		var err error
+		if ipo.signBlob, err = cosignhelper.NewBlobSigner(context.Background(), ipo.SigningKey); err != nil {
+			return err
+		}
+	}
+
+	tempDir, err := os.MkdirTemp("", "")
+	if err != nil {
+		return errors.Wrap(err, "unable to create temporary directory")
+	}
+	defer os.RemoveAll(tempDir)
+
+	log.Infof("pulling source plugin inventory database from: %q", ipo.FromImage)
+	srcDBFile, err := inventoryDBDownload(ipo.ImageOperationsImpl, ipo.FromImage, filepath.Join(tempDir, "from"))
+	if err != nil {
+		return err
+	}
+	log.Infof("pulling destination plugin inventory database from: %q", ipo.ToImage)
+	dstDBFile, err := inventoryDBDownload(ipo.ImageOperationsImpl, ipo.ToImage, filepath.Join(tempDir, "to"))
+	if err != nil {
+		return err
+	}
+	// The URIs of the plugin binaries are relative to the repository of the inventory image
+	src := plugininventory.NewSQLiteInventory(srcDBFile, path.Dir(ipo.FromImage))
+	dst := plugininventory.NewSQLiteInventory(dstDBFile, "")
+
+	groups, plugins, err := ipo.getPromotedVersions(src)
+	if err != nil {
+		return err
+	}
+	if groups, err = skipPromotedPluginGroups(dst, groups); err != nil {
+		return err
+	}
+	if plugins, err = skipPromotedPlugins(dst, plugins); err != nil {
+		return err
+	}
+	if len(groups) == 0 && len(plugins) == 0 {
+		log.Infof("all the versions are already promoted to %q", ipo.ToImage)
+		return nil
+	}
+
+	for _, p := range plugins {
+		if err := ipo.copyPluginBinaries(p, tempDir); err != nil {
+			return err
+		}
+	}
+	if err := ipo.updateInventoryDB(dst, groups, plugins); err != nil {
+		return err
+	}
+
+	log.Info("publishing destination plugin inventory database")
+	if err := inventoryDBUpload(ipo.ImageOperationsImpl, ipo.ToImage, dstDBFile); err != nil {
+		return err
+	}
+	log.Successf("successfully promoted %d plugin versions and %d plugin-group versions to %q", len(plugins), len(groups), ipo.ToImage)
+	return nil
+}
+
+// getPromotedVersions returns the plugin-group versions and the plugin versions to promote
+// from the source inventory, each with a single version, including the plugin versions
+// referenced by the plugin-group versions
+func (ipo *InventoryPromoteOptions) getPromotedVersions(src plugininventory.PluginInventory) ([]*plugininventory.PluginGroup, []*plugininventory.PluginInventoryEntry, error) {
+	var groups []*plugininventory.PluginGroup
+	plugins := map[string]*plugininventory.PluginInventoryEntry{}
+	var errList []error
+
+	addPlugin := func(name string, target configtypes.Target, version string) {
+		key := pluginVersionKey(name, string(target), version)
+		if _, exists := plugins[key]; exists {
+			return
+		}
+		p, err := ipo.getPromotedPlugin(src, name, target, version)
+		if err != nil {
+			errList = append(errList, err)
+			return
+		}
+		plugins[key] = p
+	}
+
+	for _, groupID := range ipo.PluginGroups {
+		pg, err := ipo.getPromotedPluginGroup(src, groupID)
+		if err != nil {
+			errList = append(errList, err)
+			continue
+		}
+		groups = append(groups, pg)
+		for _, entries := range pg.Versions {
+			for _, pi := range entries {
+				addPlugin(pi.Name, pi.Target, pi.Version)
+			}
+		}
+	}
+	for _, pluginID := range ipo.Plugins {
+		name, target, version := utils.ParsePluginID(pluginID)
+		if name == "" || version == "" {
+			errList = append(errList, errors.Errorf("invalid plugin %q, must be of the form name@target:version", pluginID))
+			continue
+		}
+		addPlugin(name, configtypes.Target(target), version)
+	}
+	if len(errList) > 0 {
+		return nil, nil, kerrors.NewAggregate(errList)
+	}
+
+	keys := make([]string, 0, len(plugins))
+	for key := range plugins {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	sortedPlugins := make([]*plugininventory.PluginInventoryEntry, 0, len(keys))
+	for _, key := range keys {
+		sortedPlugins = append(sortedPlugins, plugins[key])
+	}
+	return groups, sortedPlugins, nil
+}
+
+// getPromotedPlugin returns the plugin entry of the source inventory with only the version to promote
+func (ipo *InventoryPromoteOptions) getPromotedPlugin(src plugininventory.PluginInventory, name string, target configtypes.Target, version string) (*plugininventory.PluginInventoryEntry, error) {
+	pluginID := fmt.Sprintf("%s@%s:%s", name, target, version)
+	entries, err := src.GetPlugins(&plugininventory.PluginInventoryFilter{Name: name, Target: target, Version: version, IncludeHidden: true})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error while reading plugin %q from the source plugin inventory database", pluginID)
+	}
+	if len(entries) == 0 {
+		return nil, errors.Errorf("plugin %q not found in the source plugin inventory database", pluginID)
+	}
+	if len(entries) > 1 {
+		return nil, errors.Errorf("plugin %q is available for several targets, the target must be specified", pluginID)
+	}
+	p := entries[0]
+	if p.Hidden && !ipo.Force {
+		return nil, errors.Errorf("plugin %q is deactivated, use --force to promote it", plugininventory.PluginToID(p)+":"+version)
+	}
+	artifacts := p.Artifacts[version]
+	for i := range artifacts {
+		artifacts[i].Image = strings.TrimPrefix(artifacts[i].Image, path.Dir(ipo.FromImage)+"/")
+	}
+	p.Artifacts = distribution.Artifacts{version: artifacts}
+	return p, nil
+}
+
+// getPromotedPluginGroup returns the plugin-group of the source inventory with only the version to promote
+func (ipo *InventoryPromoteOptions) getPromotedPluginGroup(src plugininventory.PluginInventory, groupID string) (*plugininventory.PluginGroup, error) {
+	pgi := plugininventory.PluginGroupIdentifierFromID(groupID)
+	if pgi == nil || pgi.Version == "" {
+		return nil, errors.Errorf("invalid plugin-group %q, must be of the form vendor-publisher/name:version", groupID)
+	}
+	groups, err := src.GetPluginGroups(plugininventory.PluginGroupFilter{
+		Vendor:        pgi.Vendor,
+		Publisher:     pgi.Publisher,
+		Name:          pgi.Name,
+		Version:       pgi.Version,
+		IncludeHidden: true,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error while reading plugin-group %q from the source plugin inventory database", groupID)
+	}
+	if len(groups) == 0 || len(groups[0].Versions[pgi.Version]) == 0 {
+		return nil, errors.Errorf("plugin-group %q not found in the source plugin inventory database", groupID)
+	}
+	pg := groups[0]
+	if pg.Hidden && !ipo.Force {
+		return nil, errors.Errorf("plugin-group %q is deactivated, use --force to promote it", groupID)
+	}
+	pg.Versions = map[string][]*plugininventory.PluginGroupPluginEntry{pgi.Version: pg.Versions[pgi.Version]}
+	return pg, nil
+}
+
+// skipPromotedPluginGroups returns the plugin-group versions which are not in the destination
+// inventory yet. It fails if a version exists in the destination inventory with other plugins.
+func skipPromotedPluginGroups(dst plugininventory.PluginInventory, groups []*plugininventory.PluginGroup) ([]*plugininventory.PluginGroup, error) {
+	var promoted []*plugininventory.PluginGroup
+	for _, pg := range groups {
+		for version, plugins := range pg.Versions {
+			groupID := fmt.Sprintf("%s:%s", plugininventory.PluginGroupToID(pg), version)
+			existing, err := dst.GetPluginGroups(plugininventory.PluginGroupFilter{Vendor: pg.Vendor, Publisher: pg.Publisher, Name: pg.Name, Version: version, IncludeHidden: true})
+			if err != nil {
+				return nil, errors.Wrapf(err, "error while reading plugin-group %q from the destination plugin inventory database", groupID)
+			}
+			if len(existing) == 0 || len(existing[0].Versions[version]) == 0 {
+				promoted = append(promoted, pg)
+				continue
+			}
+			if !samePluginGroupPlugins(existing[0].Versions[version], plugins) {
+				return nil, errors.Errorf("plugin-group %q already exists in the destination plugin inventory database with different plugins", groupID)
+			}
+			log.Infof("plugin-group %q is already promoted", groupID)
+		}
+	}
+	return promoted, nil
+}
+
+// skipPromotedPlugins returns the plugin versions which are not in the destination inventory yet.
+// It fails if a version exists in the destination inventory with other plugin binaries.
+func skipPromotedPlugins(dst plugininventory.PluginInventory, plugins []*plugininventory.PluginInventoryEntry) ([]*plugininventory.PluginInventoryEntry, error) {
+	var promoted []*plugininventory.PluginInventoryEntry
+	for _, p := range plugins {
+		for version, artifacts := range p.Artifacts {
+			pluginID := plugininventory.PluginToID(p) + ":" + version
+			existing, err := dst.GetPlugins(&plugininventory.PluginInventoryFilter{Name: p.Name, Target: p.Target, Version: version, IncludeHidden: true})
+			if err != nil {
+				return nil, errors.Wrapf(err, "error while reading plugin %q from the destination plugin inventory database", pluginID)
+			}
+			if len(existing) == 0 {
+				promoted = append(promoted, p)
+				continue
+			}
+			if !reflect.DeepEqual(artifactDigests(existing[0].Artifacts[version]), artifactDigests(artifacts)) {
+				return nil, errors.Errorf("plugin %q already exists in the destination plugin inventory database with different plugin binaries", pluginID)
+			}
+			log.Infof("plugin %q is already promoted", pluginID)
+		}
+	}
+	return promoted, nil
+}
+
+// samePluginGroupPlugins returns true if both lists reference the same plugin versions
+func samePluginGroupPlugins(a, b []*plugininventory.PluginGroupPluginEntry) bool {
+	entries := func(plugins []*plugininventory.PluginGroupPluginEntry) map[string]bool {
+		m := map[string]bool{}
+		for _, pi := range plugins {
+			m[pluginVersionKey(pi.Name, string(pi.Target), pi.Version)] = pi.Mandatory
+		}
+		return m
+	}
+	return reflect.DeepEqual(entries(a), entries(b))
+}
+
+// artifactDigests returns the digests of the plugin binaries by os/arch
+func artifactDigests(artifacts distribution.ArtifactList) map[string]string {
+	digests := map[string]string{}
+	for _, a := range artifacts {
+		digests[a.OS+"_"+a.Arch] = a.Digest
+	}
+	return digests
+}
+
+// copyPluginBinaries copies the plugin binary images of the plugin version from the
+// repository of the source inventory to the repository of the destination inventory.
+// The digest of each plugin binary is verified against the source inventory before
+// publishing it, and the plugin binary is re-signed if a signing key is specified.
+// The signatures of the plugin binaries are published next to the plugin images.
+func (ipo *InventoryPromoteOptions) copyPluginBinaries(p *plugininventory.PluginInventoryEntry, tempDir string) error {
+	for version, artifacts := range p.Artifacts {
+		for i := range artifacts {
+			a := &artifacts[i]
+			srcImage := fmt.Sprintf("%s/%s", path.Dir(ipo.FromImage), a.Image)
+			dstImage := fmt.Sprintf("%s/%s", path.Dir(ipo.ToImage), a.Image)
+			osArch := cli.Arch(fmt.Sprintf("%s_%s", a.OS, a.Arch))
+
+			log.Infof("copying plugin binary image %q to %q", srcImage, dstImage)
+			files, err := ipo.ImageOperationsImpl.GetFilesMapFromImage(srcImage)
+			if err != nil {
+				return errors.Wrapf(err, "error while downloading plugin binary image %q", srcImage)
+			}
+			binary, exists := files[cli.MakeArtifactName(p.Name, osArch)]
+			if !exists {
+				return errors.Errorf("plugin binary %q not found in plugin binary image %q", cli.MakeArtifactName(p.Name, osArch), srcImage)
+			}
+			if digest := fmt.Sprintf("%x", sha256.Sum256(binary)); digest != a.Digest {
+				return errors.Errorf("digest %q of plugin binary image %q does not match the digest %q of the source plugin inventory database", digest, srcImage, a.Digest)
+			}
+
+			if ipo.signBlob != nil {
+				sig, err := ipo.signBlob(binary)
+				if err != nil {
+					return errors.Wrapf(err, "unable to sign plugin binary image %q", srcImage)
+				}
+				a.Signature = strings.TrimSpace(string(sig))
+			}
+			tarFile := filepath.Join(tempDir, fmt.Sprintf("%s-%s-%s-%s.tar", p.Name, p.Target, version, osArch))
+			if err := ipo.ImageOperationsImpl.CopyImageToTar(srcImage, tarFile); err != nil {
+				return errors.Wrapf(err, "error while downloading plugin binary image %q", srcImage)
+			}
+			if err := ipo.ImageOperationsImpl.CopyImageFromTar(tarFile, dstImage); err != nil {
+				return errors.Wrapf(err, "error while publishing plugin binary image %q", dstImage)
+			}
+			if a.Signature != "" {
+				if err := helpers.PublishPluginSignature(ipo.ImageOperationsImpl, dstImage, cli.MakeArtifactName(p.Name, osArch), []byte(a.Signature)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// updateInventoryDB inserts the promoted plugin and plugin-group versions to the destination
+// inventory database and records their promotion
+func (ipo *InventoryPromoteOptions) updateInventoryDB(dst plugininventory.PluginInventory, groups []*plugininventory.PluginGroup, plugins []*plugininventory.PluginInventoryEntry) error {
+	now := time.Now()
+	var promotions []*plugininventory.PromotionEntry
+
+	// Deactivated plugins are inserted as activated and deactivated once the plugin-groups
+	// are inserted, as plugin-groups can only reference activated plugins
+	var deactivated []*plugininventory.PluginInventoryEntry
+	for _, p := range plugins {
+		entry := *p
+		entry.Hidden = false
+		if err := dst.InsertPlugin(&entry); err != nil {
+			return errors.Wrapf(err, "error while inserting plugin %q", plugininventory.PluginToID(p))
+		}
+		if p.Hidden {
+			deactivated = append(deactivated, p)
+		}
+		for version := range p.Artifacts {
+			promotions = append(promotions, &plugininventory.PromotionEntry{Kind: plugininventory.PromotionKindPlugin, ID: plugininventory.PluginToID(p), Version: version})
+		}
+	}
+	for _, pg := range groups {
+		if err := dst.InsertPluginGroup(pg, false); err != nil {
+			return errors.Wrapf(err, "error while inserting plugin-group %q", plugininventory.PluginGroupToID(pg))
+		}
+		for version := range pg.Versions {
+			promotions = append(promotions, &plugininventory.PromotionEntry{Kind: plugininventory.PromotionKindPluginGroup, ID: plugininventory.PluginGroupToID(pg), Version: version})
+		}
+	}
+	for _, p := range deactivated {
+		if err := dst.UpdatePluginActivationState(p); err != nil {
+			return errors.Wrapf(err, "error while deactivating plugin %q", plugininventory.PluginToID(p))
+		}
+	}
+
+	for _, promotion := range promotions {
+		promotion.Source = ipo.FromImage
+		promotion.PromotedAt = now
+		if err := dst.InsertPromotion(promotion); err != nil {
+			return err
+		}
+	}
+	return nil
+}
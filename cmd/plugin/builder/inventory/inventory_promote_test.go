@@ -0,0 +1,273 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cosignhelper"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/distribution"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/fakes"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/plugininventory"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/utils"
+)
+
+const (
+	stagingInventoryImage    = "staging.example.com/plugins/plugin-inventory:latest"
+	productionInventoryImage = "registry.example.com/plugins/plugin-inventory:latest"
+)
+
+var _ = Describe("Unit tests for inventory promote", func() {
+	var (
+		tmpDir            string
+		srcDBFile         string
+		dstDBFile         string
+		publishedDBFile   string
+		binaries          map[string][]byte
+		copiedImages      map[string]string
+		fakeImgpkgWrapper *fakes.ImageOperationsImpl
+		ipo               *InventoryPromoteOptions
+	)
+
+	pluginImage := func(name string, target types.Target, version string) string {
+		return fmt.Sprintf("fakevendor/fakepublisher/linux/amd64/%s/%s:%s", target, name, version)
+	}
+
+	// insertPlugin inserts the plugin version to the source database and stores its plugin binary
+	insertPlugin := func(name string, target types.Target, version string) {
+		binary := []byte(name + " " + version + " binary")
+		binaries[path.Dir(stagingInventoryImage)+"/"+pluginImage(name, target, version)] = binary
+		err := plugininventory.NewSQLiteInventory(srcDBFile, "").InsertPlugin(&plugininventory.PluginInventoryEntry{
+			Name:        name,
+			Target:      target,
+			Description: name + " plugin",
+			Publisher:   "fakepublisher",
+			Vendor:      "fakevendor",
+			Artifacts: distribution.Artifacts{
+				version: []distribution.Artifact{{OS: "linux", Arch: "amd64", Digest: fmt.Sprintf("%x", sha256.Sum256(binary)), Image: pluginImage(name, target, version)}},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "")
+		Expect(err).ToNot(HaveOccurred())
+		srcDBFile = filepath.Join(tmpDir, "staging.db")
+		dstDBFile = filepath.Join(tmpDir, "production.db")
+		publishedDBFile = filepath.Join(tmpDir, "published.db")
+		Expect(plugininventory.NewSQLiteInventory(srcDBFile, "").CreateSchema()).To(Succeed())
+		Expect(plugininventory.NewSQLiteInventory(dstDBFile, "").CreateSchema()).To(Succeed())
+
+		binaries = map[string][]byte{}
+		copiedImages = map[string]string{}
+		insertPlugin("foo", types.TargetGlobal, "v1.0.0")
+		insertPlugin("bar", types.TargetK8s, "v0.1.0")
+
+		src := plugininventory.NewSQLiteInventory(srcDBFile, "")
+		err = src.InsertPluginGroup(&plugininventory.PluginGroup{
+			Vendor:      "fakevendor",
+			Publisher:   "fakepublisher",
+			Name:        "default",
+			Description: "Default group",
+			Versions: map[string][]*plugininventory.PluginGroupPluginEntry{
+				"v1.0.0": {
+					{PluginIdentifier: plugininventory.PluginIdentifier{Name: "foo", Target: types.TargetGlobal, Version: "v1.0.0"}, Mandatory: true},
+					{PluginIdentifier: plugininventory.PluginIdentifier{Name: "bar", Target: types.TargetK8s, Version: "v0.1.0"}, Mandatory: false},
+				},
+			},
+		}, false)
+		Expect(err).ToNot(HaveOccurred())
+		// Deactivate bar once referenced by the plugin-group
+		err = src.UpdatePluginActivationState(&plugininventory.PluginInventoryEntry{Name: "bar", Target: types.TargetK8s, Hidden: true, Artifacts: distribution.Artifacts{"v0.1.0": nil}})
+		Expect(err).ToNot(HaveOccurred())
+
+		fakeImgpkgWrapper = &fakes.ImageOperationsImpl{}
+		fakeImgpkgWrapper.DownloadImageAndSaveFilesToDirCalls(func(image, dir string) error {
+			dbFile := map[string]string{stagingInventoryImage: srcDBFile, productionInventoryImage: dstDBFile}[image]
+			Expect(dbFile).ToNot(BeEmpty())
+			Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+			return utils.CopyFile(dbFile, filepath.Join(dir, plugininventory.SQliteDBFileName))
+		})
+		fakeImgpkgWrapper.GetFilesMapFromImageCalls(func(image string) (map[string][]byte, error) {
+			return map[string][]byte{"tanzu-" + strings.Split(path.Base(image), ":")[0] + "-linux_amd64": binaries[image]}, nil
+		})
+		// imgpkg saves the image with its own tar layout, which is only read back by imgpkg
+		fakeImgpkgWrapper.CopyImageToTarCalls(func(image, tarFile string) error {
+			return os.WriteFile(tarFile, []byte("imgpkg tarball of "+image), 0644)
+		})
+		fakeImgpkgWrapper.CopyImageFromTarCalls(func(tarFile, image string) error {
+			copiedImages[image] = tarFile
+			return nil
+		})
+		fakeImgpkgWrapper.PushImageCalls(func(image string, files []string) error {
+			if image == productionInventoryImage {
+				return utils.CopyFile(files[0], publishedDBFile)
+			}
+			return nil
+		})
+
+		ipo = &InventoryPromoteOptions{
+			FromImage:           stagingInventoryImage,
+			ToImage:             productionInventoryImage,
+			ImageOperationsImpl: fakeImgpkgWrapper,
+		}
+	})
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	var _ = Context("tests for the inventory promote function", func() {
+		var _ = It("should promote the plugin version and its plugin binaries", func() {
+			ipo.Plugins = []string{"foo@global:v1.0.0"}
+			Expect(ipo.Promote()).To(Succeed())
+
+			Expect(copiedImages).To(HaveKey("registry.example.com/plugins/" + pluginImage("foo", types.TargetGlobal, "v1.0.0")))
+			Expect(copiedImages).To(HaveLen(1))
+
+			dst := plugininventory.NewSQLiteInventory(publishedDBFile, "")
+			plugins, err := dst.GetPlugins(&plugininventory.PluginInventoryFilter{Name: "foo", Target: types.TargetGlobal})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plugins).To(HaveLen(1))
+			Expect(plugins[0].Artifacts["v1.0.0"][0].Image).To(Equal("/" + pluginImage("foo", types.TargetGlobal, "v1.0.0")))
+
+			promotions, err := dst.GetPromotions()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(promotions).To(HaveLen(1))
+			Expect(promotions[0].Source).To(Equal(stagingInventoryImage))
+			Expect(promotions[0].Kind).To(Equal(plugininventory.PromotionKindPlugin))
+			Expect(promotions[0].ID).To(Equal("foo@global"))
+			Expect(promotions[0].Version).To(Equal("v1.0.0"))
+		})
+		var _ = It("should refuse to promote a plugin-group referencing a deactivated plugin", func() {
+			ipo.PluginGroups = []string{"fakevendor-fakepublisher/default:v1.0.0"}
+			err := ipo.Promote()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`plugin "bar@kubernetes:v0.1.0" is deactivated, use --force to promote it`))
+			Expect(fakeImgpkgWrapper.PushImageCallCount()).To(Equal(0))
+		})
+		var _ = It("should promote the plugin-group and its deactivated plugins with the force option", func() {
+			ipo.PluginGroups = []string{"fakevendor-fakepublisher/default:v1.0.0"}
+			ipo.Force = true
+			Expect(ipo.Promote()).To(Succeed())
+			Expect(copiedImages).To(HaveLen(2))
+
+			dst := plugininventory.NewSQLiteInventory(publishedDBFile, "")
+			groups, err := dst.GetPluginGroups(plugininventory.PluginGroupFilter{Vendor: "fakevendor", Publisher: "fakepublisher", Name: "default", IncludeHidden: true})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(groups).To(HaveLen(1))
+			Expect(groups[0].Versions["v1.0.0"]).To(HaveLen(2))
+
+			// The deactivated plugin remains deactivated
+			plugins, err := dst.GetPlugins(&plugininventory.PluginInventoryFilter{Name: "bar", Target: types.TargetK8s})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plugins).To(BeEmpty())
+			plugins, err = dst.GetPlugins(&plugininventory.PluginInventoryFilter{Name: "bar", Target: types.TargetK8s, IncludeHidden: true})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plugins).To(HaveLen(1))
+
+			promotions, err := dst.GetPromotions()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(promotions).To(HaveLen(3))
+		})
+		var _ = It("should fail when the digest of a plugin binary does not match the source inventory", func() {
+			binaries["staging.example.com/plugins/"+pluginImage("foo", types.TargetGlobal, "v1.0.0")] = []byte("tampered binary")
+			ipo.Plugins = []string{"foo@global:v1.0.0"}
+			err := ipo.Promote()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("does not match the digest"))
+			Expect(copiedImages).To(BeEmpty())
+			Expect(fakeImgpkgWrapper.PushImageCallCount()).To(Equal(0))
+		})
+		var _ = It("should not publish anything when the versions are already promoted", func() {
+			ipo.Plugins = []string{"foo@global:v1.0.0"}
+			Expect(ipo.Promote()).To(Succeed())
+			Expect(utils.CopyFile(publishedDBFile, dstDBFile)).To(Succeed())
+			Expect(fakeImgpkgWrapper.PushImageCallCount()).To(Equal(1))
+
+			Expect(ipo.Promote()).To(Succeed())
+			Expect(fakeImgpkgWrapper.PushImageCallCount()).To(Equal(1))
+		})
+		var _ = It("should fail when the plugin version does not exist", func() {
+			ipo.Plugins = []string{"foo@global:v9.9.9", "foo"}
+			err := ipo.Promote()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`plugin "foo@global:v9.9.9" not found in the source plugin inventory database`))
+			Expect(err.Error()).To(ContainSubstring(`invalid plugin "foo", must be of the form name@target:version`))
+		})
+		var _ = It("should keep and publish the signatures of the source inventory without signing key", func() {
+			binary := []byte("foo v1.1.0 binary")
+			binaries["staging.example.com/plugins/"+pluginImage("foo", types.TargetGlobal, "v1.1.0")] = binary
+			err := plugininventory.NewSQLiteInventory(srcDBFile, "").InsertPlugin(&plugininventory.PluginInventoryEntry{
+				Name:      "foo",
+				Target:    types.TargetGlobal,
+				Publisher: "fakepublisher",
+				Vendor:    "fakevendor",
+				Artifacts: distribution.Artifacts{
+					"v1.1.0": []distribution.Artifact{{OS: "linux", Arch: "amd64", Digest: fmt.Sprintf("%x", sha256.Sum256(binary)), Image: pluginImage("foo", types.TargetGlobal, "v1.1.0"), Signature: "foo-signature"}},
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			var signatures []string
+			fakeImgpkgWrapper.PushImageCalls(func(image string, files []string) error {
+				if image == productionInventoryImage {
+					return utils.CopyFile(files[0], publishedDBFile)
+				}
+				content, err := os.ReadFile(files[0])
+				Expect(err).ToNot(HaveOccurred())
+				signatures = append(signatures, string(content))
+				Expect(image).To(Equal("registry.example.com/plugins/" + pluginImage("foo", types.TargetGlobal, "v1.1.0") + ".sig"))
+				return nil
+			})
+			ipo.Plugins = []string{"foo@global:v1.1.0"}
+			Expect(ipo.Promote()).To(Succeed())
+			Expect(signatures).To(Equal([]string{"foo-signature"}))
+
+			dst := plugininventory.NewSQLiteInventory(publishedDBFile, "")
+			plugins, err := dst.GetPlugins(&plugininventory.PluginInventoryFilter{Name: "foo", Target: types.TargetGlobal, Version: "v1.1.0"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plugins[0].Artifacts["v1.1.0"][0].Signature).To(Equal("foo-signature"))
+		})
+		var _ = It("should re-sign the promoted plugin binaries with the signing key", func() {
+			keys, err := cosign.GenerateKeyPair(func(bool) ([]byte, error) { return []byte{}, nil })
+			Expect(err).NotTo(HaveOccurred())
+			privateKey := filepath.Join(tmpDir, "cosign.key")
+			publicKey := filepath.Join(tmpDir, "cosign.pub")
+			Expect(os.WriteFile(privateKey, keys.PrivateBytes, 0600)).To(Succeed())
+			Expect(os.WriteFile(publicKey, keys.PublicBytes, 0644)).To(Succeed())
+
+			os.Setenv("COSIGN_PASSWORD", "")
+			defer os.Unsetenv("COSIGN_PASSWORD")
+			ipo.Plugins = []string{"foo@global:v1.0.0"}
+			ipo.SigningKey = privateKey
+			Expect(ipo.Promote()).To(Succeed())
+
+			dst := plugininventory.NewSQLiteInventory(publishedDBFile, "")
+			plugins, err := dst.GetPlugins(&plugininventory.PluginInventoryFilter{Name: "foo", Target: types.TargetGlobal})
+			Expect(err).ToNot(HaveOccurred())
+			signature := plugins[0].Artifacts["v1.0.0"][0].Signature
+			Expect(signature).ToNot(BeEmpty())
+			binary := binaries["staging.example.com/plugins/"+pluginImage("foo", types.TargetGlobal, "v1.0.0")]
+			Expect(cosignhelper.VerifyBlobSignature(context.Background(), publicKey, binary, []byte(signature))).To(Succeed())
+
+			// The signature is published next to the plugin image
+			image, _ := fakeImgpkgWrapper.PushImageArgsForCall(0)
+			Expect(image).To(Equal("registry.example.com/plugins/" + pluginImage("foo", types.TargetGlobal, "v1.0.0") + ".sig"))
+		})
+	})
+})
@@ -21,6 +21,10 @@ func newInventoryPluginCmd() *cobra.Command {
 		newInventoryPluginAddCmd(),
 		newInventoryPluginActivateCmd(),
 		newInventoryPluginDeactivateCmd(),
+		newInventoryPluginRemoveCmd(),
+		newInventoryPluginPruneCmd(),
+		newInventoryPluginRewriteURICmd(),
+		newInventoryPluginValidateCmd(),
 	)
 
 	return inventoryPluginCmd
@@ -35,6 +39,9 @@ type inventoryPluginAddFlags struct {
 	InventoryDBFile   string
 	DeactivatePlugins bool
 	ValidateOnly      bool
+	WithSignatures    bool
+	LocalRepository   string
+	BinaryArtifactDir string
 }
 
 func newInventoryPluginAddCmd() *cobra.Command {
@@ -44,7 +51,12 @@ func newInventoryPluginAddCmd() *cobra.Command {
 		Use:          "add",
 		Short:        "Add the plugin to the inventory database available on the remote repository",
 		SilenceUsage: true,
-		Example:      ``,
+		Example: `
+    # Add the plugins published with 'tanzu builder plugin publish-package' to the inventory database
+    tanzu builder inventory plugin add --repository registry.example.com/tanzu-cli/plugins --vendor vmware --publisher tkg --manifest ./artifacts/packages/plugin_manifest.yaml
+
+    # Add the plugins built with 'tanzu builder plugin build' to a local plugin repository, along with their binaries
+    tanzu builder inventory plugin add --local-repository ./artifacts/repository --vendor vmware --publisher tkg --manifest ./artifacts/plugins/plugin_manifest.yaml`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			paOptions := inventory.InventoryPluginUpdateOptions{
 				Repository:          ipaFlags.Repository,
@@ -55,6 +67,9 @@ func newInventoryPluginAddCmd() *cobra.Command {
 				DeactivatePlugins:   ipaFlags.DeactivatePlugins,
 				InventoryDBFile:     ipaFlags.InventoryDBFile,
 				ValidateOnly:        ipaFlags.ValidateOnly,
+				WithSignatures:      ipaFlags.WithSignatures,
+				LocalRepository:     ipaFlags.LocalRepository,
+				BinaryArtifactDir:   ipaFlags.BinaryArtifactDir,
 				ImageOperationsImpl: carvelhelpers.NewImageOperationsImpl(),
 			}
 			return paOptions.PluginAdd()
@@ -69,8 +84,12 @@ func newInventoryPluginAddCmd() *cobra.Command {
 	pluginAddCmd.Flags().StringVarP(&ipaFlags.InventoryDBFile, "plugin-inventory-db-file", "", "", "local file for the inventory database")
 	pluginAddCmd.Flags().BoolVarP(&ipaFlags.DeactivatePlugins, "deactivate", "", false, "mark plugins as deactivated")
 	pluginAddCmd.Flags().BoolVarP(&ipaFlags.ValidateOnly, "validate", "", false, "validate whether plugins already exists in the plugin inventory or not")
+	pluginAddCmd.Flags().BoolVarP(&ipaFlags.WithSignatures, "with-signatures", "", false, "add the signatures of the plugin binaries published with 'plugin publish-package --signing-key'")
+	pluginAddCmd.Flags().StringVarP(&ipaFlags.LocalRepository, "local-repository", "", "", "directory of a local plugin repository to add the plugins and their binaries to instead of the remote repository")
+	pluginAddCmd.Flags().StringVarP(&ipaFlags.BinaryArtifactDir, "binary-artifacts", "", "", "plugin binary artifact directory to copy the plugin binaries from when using a local plugin repository (default to the directory of the manifest)")
 
-	_ = pluginAddCmd.MarkFlagRequired("repository")
+	pluginAddCmd.MarkFlagsOneRequired("repository", "local-repository")
+	pluginAddCmd.MarkFlagsMutuallyExclusive("repository", "local-repository")
 	_ = pluginAddCmd.MarkFlagRequired("vendor")
 	_ = pluginAddCmd.MarkFlagRequired("publisher")
 	_ = pluginAddCmd.MarkFlagRequired("manifest")
@@ -85,6 +104,7 @@ type inventoryPluginActivateDeactivateFlags struct {
 	Publisher         string
 	Vendor            string
 	InventoryDBFile   string
+	LocalRepository   string
 }
 
 func newInventoryPluginActivateCmd() *cobra.Command { //nolint:dupl
@@ -100,6 +120,7 @@ func newInventoryPluginActivateCmd() *cobra.Command { //nolint:dupl
 			Vendor:              flags.Vendor,
 			Publisher:           flags.Publisher,
 			InventoryDBFile:     flags.InventoryDBFile,
+			LocalRepository:     flags.LocalRepository,
 			DeactivatePlugins:   false,
 			ImageOperationsImpl: carvelhelpers.NewImageOperationsImpl(),
 		}
@@ -121,6 +142,7 @@ func newInventoryPluginDeactivateCmd() *cobra.Command { //nolint:dupl
 			Vendor:              flags.Vendor,
 			Publisher:           flags.Publisher,
 			InventoryDBFile:     flags.InventoryDBFile,
+			LocalRepository:     flags.LocalRepository,
 			DeactivatePlugins:   true,
 			ImageOperationsImpl: carvelhelpers.NewImageOperationsImpl(),
 		}
@@ -141,6 +163,7 @@ func getActivateDeactivateBaseCmd() (*cobra.Command, *inventoryPluginActivateDea
 	activateDeactivateCmd.Flags().StringVarP(&flags.Vendor, "vendor", "", "", "name of the vendor")
 	activateDeactivateCmd.Flags().StringVarP(&flags.Publisher, "publisher", "", "", "name of the publisher")
 	activateDeactivateCmd.Flags().StringVarP(&flags.InventoryDBFile, "plugin-inventory-db-file", "", "", "local file for the inventory database")
+	activateDeactivateCmd.Flags().StringVarP(&flags.LocalRepository, "local-repository", "", "", "directory of a local plugin repository to update instead of the remote repository")
 
 	_ = activateDeactivateCmd.MarkFlagRequired("vendor")
 	_ = activateDeactivateCmd.MarkFlagRequired("publisher")
@@ -148,3 +171,137 @@ func getActivateDeactivateBaseCmd() (*cobra.Command, *inventoryPluginActivateDea
 
 	return activateDeactivateCmd, flags
 }
+
+type inventoryPluginMaintenanceFlags struct {
+	Repository        string
+	InventoryImageTag string
+	InventoryDBFile   string
+	LocalRepository   string
+	PluginName        string
+	Target            string
+	Version           string
+	Keep              int
+	DryRun            bool
+	FromPrefix        string
+	ToPrefix          string
+}
+
+func newInventoryPluginRemoveCmd() *cobra.Command {
+	pluginRemoveCmd, flags := getMaintenanceBaseCmd()
+	pluginRemoveCmd.Use = "remove"
+	pluginRemoveCmd.Short = "Remove a version of a plugin from the inventory database available on the remote repository"
+	pluginRemoveCmd.Example = `
+    # Remove a plugin version which is not referenced by any plugin group
+    tanzu builder inventory plugin remove --repository registry.example.com/tanzu-cli/plugins --name foo --target global --version v1.0.0`
+	pluginRemoveCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		options := newInventoryPluginMaintenanceOptions(flags)
+		return options.PluginRemove()
+	}
+
+	pluginRemoveCmd.Flags().StringVarP(&flags.PluginName, "name", "", "", "name of the plugin")
+	pluginRemoveCmd.Flags().StringVarP(&flags.Target, "target", "", "", "target of the plugin")
+	pluginRemoveCmd.Flags().StringVarP(&flags.Version, "version", "", "", "version of the plugin to remove")
+	_ = pluginRemoveCmd.MarkFlagRequired("name")
+	_ = pluginRemoveCmd.MarkFlagRequired("target")
+	_ = pluginRemoveCmd.MarkFlagRequired("version")
+
+	return pluginRemoveCmd
+}
+
+func newInventoryPluginPruneCmd() *cobra.Command {
+	pluginPruneCmd, flags := getMaintenanceBaseCmd()
+	pluginPruneCmd.Use = "prune"
+	pluginPruneCmd.Short = "Remove the oldest versions of the plugins from the inventory database available on the remote repository"
+	pluginPruneCmd.Long = "Remove the oldest versions of the plugins from the inventory database available on the remote repository, keeping the most recent versions of each plugin as well as the versions referenced by plugin groups"
+	pluginPruneCmd.Example = `
+    # Keep the 5 most recent versions of every plugin
+    tanzu builder inventory plugin prune --repository registry.example.com/tanzu-cli/plugins --keep 5
+
+    # Show the versions of a plugin which would be pruned
+    tanzu builder inventory plugin prune --repository registry.example.com/tanzu-cli/plugins --keep 5 --name foo --target global --dry-run`
+	pluginPruneCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		options := newInventoryPluginMaintenanceOptions(flags)
+		return options.PluginPrune()
+	}
+
+	pluginPruneCmd.Flags().IntVarP(&flags.Keep, "keep", "", 0, "number of most recent versions of each plugin to keep")
+	pluginPruneCmd.Flags().StringVarP(&flags.PluginName, "name", "", "", "only prune the versions of the plugins with this name")
+	pluginPruneCmd.Flags().StringVarP(&flags.Target, "target", "", "", "only prune the versions of the plugins with this target")
+	pluginPruneCmd.Flags().BoolVarP(&flags.DryRun, "dry-run", "", false, "only show the plugin versions which would be pruned")
+	_ = pluginPruneCmd.MarkFlagRequired("keep")
+
+	return pluginPruneCmd
+}
+
+func newInventoryPluginRewriteURICmd() *cobra.Command {
+	rewriteURICmd, flags := getMaintenanceBaseCmd()
+	rewriteURICmd.Use = "rewrite-uri"
+	rewriteURICmd.Short = "Replace the prefix of the plugin binary URIs of the inventory database available on the remote repository"
+	rewriteURICmd.Example = `
+    # Update the URIs of the plugin binaries after the plugin images have been migrated to another registry
+    tanzu builder inventory plugin rewrite-uri --repository registry.example.com/tanzu-cli/plugins --from-prefix old-registry.example.com/plugins/ --to-prefix new-registry.example.com/plugins/`
+	rewriteURICmd.RunE = func(cmd *cobra.Command, args []string) error {
+		options := newInventoryPluginMaintenanceOptions(flags)
+		return options.RewriteURI()
+	}
+
+	rewriteURICmd.Flags().StringVarP(&flags.FromPrefix, "from-prefix", "", "", "prefix of the plugin binary URIs to replace")
+	rewriteURICmd.Flags().StringVarP(&flags.ToPrefix, "to-prefix", "", "", "new prefix of the plugin binary URIs")
+	_ = rewriteURICmd.MarkFlagRequired("from-prefix")
+	_ = rewriteURICmd.MarkFlagRequired("to-prefix")
+
+	return rewriteURICmd
+}
+
+func newInventoryPluginValidateCmd() *cobra.Command {
+	validateCmd, flags := getMaintenanceBaseCmd()
+	validateCmd.Use = "validate"
+	validateCmd.Short = "Validate the plugin binaries and plugin groups of the inventory database available on the remote repository"
+	validateCmd.Long = "Validate that the plugin binary of every plugin of the inventory database exists and matches its digest, and that every plugin of the plugin groups exists in the inventory database"
+	validateCmd.Example = `
+    # Validate the inventory database of the remote repository
+    tanzu builder inventory plugin validate --repository registry.example.com/tanzu-cli/plugins
+
+    # Validate a local plugin repository
+    tanzu builder inventory plugin validate --local-repository ./artifacts/repository`
+	validateCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		options := newInventoryPluginMaintenanceOptions(flags)
+		return options.Validate()
+	}
+
+	return validateCmd
+}
+
+func getMaintenanceBaseCmd() (*cobra.Command, *inventoryPluginMaintenanceFlags) {
+	var flags = &inventoryPluginMaintenanceFlags{}
+
+	var maintenanceCmd = &cobra.Command{}
+	maintenanceCmd.SilenceUsage = true
+
+	maintenanceCmd.Flags().StringVarP(&flags.Repository, "repository", "", "", "repository to publish plugin inventory image")
+	maintenanceCmd.Flags().StringVarP(&flags.InventoryImageTag, "plugin-inventory-image-tag", "", "latest", "tag to which plugin inventory image needs to be published")
+	maintenanceCmd.Flags().StringVarP(&flags.InventoryDBFile, "plugin-inventory-db-file", "", "", "local file for the inventory database")
+	maintenanceCmd.Flags().StringVarP(&flags.LocalRepository, "local-repository", "", "", "directory of a local plugin repository to update instead of the remote repository")
+
+	maintenanceCmd.MarkFlagsOneRequired("repository", "local-repository")
+	maintenanceCmd.MarkFlagsMutuallyExclusive("repository", "local-repository")
+
+	return maintenanceCmd, flags
+}
+
+func newInventoryPluginMaintenanceOptions(flags *inventoryPluginMaintenanceFlags) *inventory.InventoryPluginMaintenanceOptions {
+	return &inventory.InventoryPluginMaintenanceOptions{
+		Repository:          flags.Repository,
+		InventoryImageTag:   flags.InventoryImageTag,
+		InventoryDBFile:     flags.InventoryDBFile,
+		LocalRepository:     flags.LocalRepository,
+		PluginName:          flags.PluginName,
+		Target:              flags.Target,
+		Version:             flags.Version,
+		Keep:                flags.Keep,
+		DryRun:              flags.DryRun,
+		FromPrefix:          flags.FromPrefix,
+		ToPrefix:            flags.ToPrefix,
+		ImageOperationsImpl: carvelhelpers.NewImageOperationsImpl(),
+	}
+}
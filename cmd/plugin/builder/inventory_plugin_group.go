@@ -21,6 +21,7 @@ func newInventoryPluginGroupCmd() *cobra.Command {
 		newInventoryPluginGroupAddCmd(),
 		newInventoryPluginGroupActivateCmd(),
 		newInventoryPluginGroupDeactivateCmd(),
+		newInventoryPluginGroupDiffCmd(),
 	)
 
 	return inventoryPluginCmd
@@ -38,6 +39,7 @@ type inventoryPluginGroupAddFlags struct {
 	InventoryDBFile       string
 	DeactivatePluginGroup bool
 	Override              bool
+	LocalRepository       string
 }
 
 func newInventoryPluginGroupAddCmd() *cobra.Command {
@@ -61,6 +63,7 @@ func newInventoryPluginGroupAddCmd() *cobra.Command {
 				InventoryDBFile:         ipgaFlags.InventoryDBFile,
 				DeactivatePluginGroup:   ipgaFlags.DeactivatePluginGroup,
 				Override:                ipgaFlags.Override,
+				LocalRepository:         ipgaFlags.LocalRepository,
 				ImageOperationsImpl:     carvelhelpers.NewImageOperationsImpl(),
 			}
 			return pgaOptions.PluginGroupAdd()
@@ -78,6 +81,7 @@ func newInventoryPluginGroupAddCmd() *cobra.Command {
 	pluginGroupAddCmd.Flags().StringVarP(&ipgaFlags.InventoryDBFile, "plugin-inventory-db-file", "", "", "local file for the inventory database")
 	pluginGroupAddCmd.Flags().BoolVarP(&ipgaFlags.DeactivatePluginGroup, "deactivate", "", false, "mark plugin-group as deactivated")
 	pluginGroupAddCmd.Flags().BoolVarP(&ipgaFlags.Override, "override", "", false, "overwrite the plugin-group version if it already exists")
+	pluginGroupAddCmd.Flags().StringVarP(&ipgaFlags.LocalRepository, "local-repository", "", "", "directory of a local plugin repository to update instead of the remote repository")
 
 	_ = pluginGroupAddCmd.MarkFlagRequired("name")
 	_ = pluginGroupAddCmd.MarkFlagRequired("version")
@@ -97,6 +101,7 @@ type inventoryPluginGroupActivateDeactivateFlags struct {
 	Publisher         string
 	Vendor            string
 	InventoryDBFile   string
+	LocalRepository   string
 }
 
 func newInventoryPluginGroupActivateCmd() *cobra.Command { //nolint:dupl
@@ -113,6 +118,7 @@ func newInventoryPluginGroupActivateCmd() *cobra.Command { //nolint:dupl
 			Vendor:                flags.Vendor,
 			Publisher:             flags.Publisher,
 			InventoryDBFile:       flags.InventoryDBFile,
+			LocalRepository:       flags.LocalRepository,
 			DeactivatePluginGroup: false,
 			ImageOperationsImpl:   carvelhelpers.NewImageOperationsImpl(),
 		}
@@ -135,6 +141,7 @@ func newInventoryPluginGroupDeactivateCmd() *cobra.Command { //nolint:dupl
 			Vendor:                flags.Vendor,
 			Publisher:             flags.Publisher,
 			InventoryDBFile:       flags.InventoryDBFile,
+			LocalRepository:       flags.LocalRepository,
 			DeactivatePluginGroup: true,
 			ImageOperationsImpl:   carvelhelpers.NewImageOperationsImpl(),
 		}
@@ -156,6 +163,7 @@ func getPluginGroupActivateDeactivateBaseCmd() (*cobra.Command, *inventoryPlugin
 	activateDeactivateCmd.Flags().StringVarP(&flags.Vendor, "vendor", "", "", "name of the vendor")
 	activateDeactivateCmd.Flags().StringVarP(&flags.Publisher, "publisher", "", "", "name of the publisher")
 	activateDeactivateCmd.Flags().StringVarP(&flags.InventoryDBFile, "plugin-inventory-db-file", "", "", "local file for the inventory database")
+	activateDeactivateCmd.Flags().StringVarP(&flags.LocalRepository, "local-repository", "", "", "directory of a local plugin repository to update instead of the remote repository")
 
 	_ = activateDeactivateCmd.MarkFlagRequired("name")
 	_ = activateDeactivateCmd.MarkFlagRequired("version")
@@ -164,3 +172,73 @@ func getPluginGroupActivateDeactivateBaseCmd() (*cobra.Command, *inventoryPlugin
 
 	return activateDeactivateCmd, flags
 }
+
+type inventoryPluginGroupDiffFlags struct {
+	GroupName           string
+	FromVersion         string
+	ToVersion           string
+	Repository          string
+	InventoryImageTag   string
+	Publisher           string
+	Vendor              string
+	InventoryDBFile     string
+	LocalRepository     string
+	PluginManifestFiles []string
+	OutputFormat        string
+}
+
+func newInventoryPluginGroupDiffCmd() *cobra.Command {
+	var flags = &inventoryPluginGroupDiffFlags{}
+
+	var pluginGroupDiffCmd = &cobra.Command{
+		Use:          "diff",
+		Short:        "Show the plugin changes between two versions of a plugin-group of the inventory database",
+		SilenceUsage: true,
+		Example: `  # Generate the markdown release notes of version v1.1.0 of a plugin-group
+  tanzu builder inventory plugin-group diff --name default --vendor vmware --publisher tkg \
+      --from v1.0.0 --to v1.1.0 --repository localhost:5002/test/v1/tanzu-cli/plugins \
+      --plugin-manifest ./artifacts/plugins/plugin_manifest.yaml
+
+  # Output the plugin changes between two versions of a plugin-group as JSON
+  tanzu builder inventory plugin-group diff --name default --vendor vmware --publisher tkg \
+      --from v1.0.0 --to v1.1.0 --plugin-inventory-db-file ./plugin_inventory.db --output json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pgdOptions := inventory.InventoryPluginGroupDiffOptions{
+				GroupName:           flags.GroupName,
+				FromVersion:         flags.FromVersion,
+				ToVersion:           flags.ToVersion,
+				Repository:          flags.Repository,
+				InventoryImageTag:   flags.InventoryImageTag,
+				Vendor:              flags.Vendor,
+				Publisher:           flags.Publisher,
+				InventoryDBFile:     flags.InventoryDBFile,
+				LocalRepository:     flags.LocalRepository,
+				PluginManifestFiles: flags.PluginManifestFiles,
+				OutputFormat:        flags.OutputFormat,
+				Output:              cmd.OutOrStdout(),
+				ImageOperationsImpl: carvelhelpers.NewImageOperationsImpl(),
+			}
+			return pgdOptions.PluginGroupDiff()
+		},
+	}
+
+	pluginGroupDiffCmd.Flags().StringVarP(&flags.GroupName, "name", "", "", "name of the plugin-group")
+	pluginGroupDiffCmd.Flags().StringVarP(&flags.FromVersion, "from", "", "", "version of the plugin-group to compare from")
+	pluginGroupDiffCmd.Flags().StringVarP(&flags.ToVersion, "to", "", "", "version of the plugin-group to compare to")
+	pluginGroupDiffCmd.Flags().StringVarP(&flags.Repository, "repository", "", "", "repository of the plugin inventory image")
+	pluginGroupDiffCmd.Flags().StringVarP(&flags.InventoryImageTag, "plugin-inventory-image-tag", "", "latest", "tag of the plugin inventory image")
+	pluginGroupDiffCmd.Flags().StringVarP(&flags.Vendor, "vendor", "", "", "name of the vendor")
+	pluginGroupDiffCmd.Flags().StringVarP(&flags.Publisher, "publisher", "", "", "name of the publisher")
+	pluginGroupDiffCmd.Flags().StringVarP(&flags.InventoryDBFile, "plugin-inventory-db-file", "", "", "local file for the inventory database")
+	pluginGroupDiffCmd.Flags().StringVarP(&flags.LocalRepository, "local-repository", "", "", "directory of a local plugin repository to use instead of the remote repository")
+	pluginGroupDiffCmd.Flags().StringArrayVarP(&flags.PluginManifestFiles, "plugin-manifest", "", nil, "plugin manifest file providing the release notes of the plugins, can be specified multiple times")
+	pluginGroupDiffCmd.Flags().StringVarP(&flags.OutputFormat, "output", "o", inventory.DiffOutputMarkdown, "output format (markdown|json)")
+
+	_ = pluginGroupDiffCmd.MarkFlagRequired("name")
+	_ = pluginGroupDiffCmd.MarkFlagRequired("from")
+	_ = pluginGroupDiffCmd.MarkFlagRequired("to")
+	_ = pluginGroupDiffCmd.MarkFlagRequired("vendor")
+	_ = pluginGroupDiffCmd.MarkFlagRequired("publisher")
+
+	return pluginGroupDiffCmd
+}
@@ -5,6 +5,7 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -12,6 +13,7 @@ import (
 	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/command"
 	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/crane"
 	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/plugin"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/carvelhelpers"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/registry"
 )
 
@@ -26,6 +28,7 @@ func NewPluginCmd() *cobra.Command {
 		newPluginBuildCmd(),
 		newPluginBuildPackageCmd(),
 		newPluginPublishPackageCmd(),
+		newPluginVerifyCmd(),
 	)
 	return pluginCmd
 }
@@ -40,6 +43,10 @@ type pluginBuildFlags struct {
 	PluginScopeAssociationFile string
 	GoFlags                    string
 	DebugSymbols               bool
+	Reproducible               bool
+	CacheDir                   string
+	CacheImage                 string
+	GoWork                     string
 }
 
 type pluginBuildPackageFlags struct {
@@ -48,11 +55,19 @@ type pluginBuildPackageFlags struct {
 	localOCIRepository string
 }
 
+type pluginVerifyFlags struct {
+	BinaryArtifactDir string
+	Match             string
+	JUnitReport       string
+	Timeout           time.Duration
+}
+
 type pluginPublishPackageFlags struct {
 	PackageArtifactDir string
 	Repository         string
 	Publisher          string
 	Vendor             string
+	SigningKey         string
 	DryRun             bool
 }
 
@@ -71,7 +86,16 @@ func newPluginBuildCmd() *cobra.Command {
     tanzu builder plugin build --path ./cmd/plugin --version v0.0.2 --os-arch darwin_amd64 --os-arch linux_amd64 --os-arch windows_amd64
 
     # Build only foo plugin under 'cmd/plugin' directory for all supported os-arch
-    tanzu builder plugin build --path ./cmd/plugin --version v0.0.2 --os-arch all --match foo`,
+    tanzu builder plugin build --path ./cmd/plugin --version v0.0.2 --os-arch all --match foo
+
+    # Build all plugins reproducibly, using the time of the last commit as the build time
+    tanzu builder plugin build --path ./cmd/plugin --version v0.0.2 --reproducible
+
+    # Build all plugins, only rebuilding the plugins whose build inputs changed since they were cached
+    tanzu builder plugin build --path ./cmd/plugin --version v0.0.2 --cache-dir ~/.cache/plugin-build
+
+    # Build all plugins of the modules of a go workspace other than the one of the current directory
+    tanzu builder plugin build --path ./cmd/plugin --version v0.0.2 --go-work ./build/go.work`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			compileArgs := &command.PluginCompileArgs{
 				Match:                      pbFlags.Match,
@@ -84,6 +108,10 @@ func newPluginBuildCmd() *cobra.Command {
 				GroupByOSArch:              true,
 				GoFlags:                    pbFlags.GoFlags,
 				DebugSymbols:               pbFlags.DebugSymbols,
+				Reproducible:               pbFlags.Reproducible,
+				CacheDir:                   pbFlags.CacheDir,
+				CacheImage:                 pbFlags.CacheImage,
+				GoWork:                     pbFlags.GoWork,
 			}
 
 			return command.Compile(compileArgs)
@@ -99,6 +127,10 @@ func newPluginBuildCmd() *cobra.Command {
 	pluginBuildCmd.Flags().StringVarP(&pbFlags.PluginScopeAssociationFile, "plugin-scope-association-file", "", "", "file specifying plugin scope association")
 	pluginBuildCmd.Flags().StringVarP(&pbFlags.GoFlags, "goflags", "", "", "goflags to set on build")
 	pluginBuildCmd.Flags().BoolVarP(&pbFlags.DebugSymbols, "debug-symbols", "", false, "include debug symbols in the build")
+	pluginBuildCmd.Flags().StringVarP(&pbFlags.CacheDir, "cache-dir", "", "", "local directory of the build cache, to only rebuild the plugins whose sources, go.sum or build flags changed")
+	pluginBuildCmd.Flags().StringVarP(&pbFlags.CacheImage, "cache-image", "", "", "OCI image repository to share the build cache between machines, in addition to the local cache directory")
+	pluginBuildCmd.Flags().StringVarP(&pbFlags.GoWork, "go-work", "", "", "go.work file of the go workspace to build the plugins in, or 'off' to disable the workspace mode (default: the go.work file found by the go command)")
+	pluginBuildCmd.Flags().BoolVarP(&pbFlags.Reproducible, "reproducible", "", false, "build reproducible binaries with -trimpath, a pinned module graph and SOURCE_DATE_EPOCH as the build time")
 
 	_ = pluginBuildCmd.MarkFlagRequired("version")
 
@@ -154,8 +186,10 @@ func newPluginPublishPackageCmd() *cobra.Command {
 				Publisher:          pppFlags.Publisher,
 				Vendor:             pppFlags.Vendor,
 				Repository:         pppFlags.Repository,
+				SigningKey:         pppFlags.SigningKey,
 				DryRun:             pppFlags.DryRun,
 				CraneOptions:       crane.NewCraneWrapper(),
+				ImageOperations:    carvelhelpers.NewImageOperationsImpl(),
 			}
 			return bppArgs.PublishPluginPackages()
 		},
@@ -165,6 +199,7 @@ func newPluginPublishPackageCmd() *cobra.Command {
 	pluginBuildPackageCmd.Flags().StringVarP(&pppFlags.Repository, "repository", "", "", "repository to publish plugins")
 	pluginBuildPackageCmd.Flags().StringVarP(&pppFlags.Vendor, "vendor", "", "", "name of the vendor")
 	pluginBuildPackageCmd.Flags().StringVarP(&pppFlags.Publisher, "publisher", "", "", "name of the publisher")
+	pluginBuildPackageCmd.Flags().StringVarP(&pppFlags.SigningKey, "signing-key", "", "", "cosign private key (file, KMS or PKCS11 URI) to sign the plugin binaries with (optional)")
 	pluginBuildPackageCmd.Flags().BoolVarP(&pppFlags.DryRun, "dry-run", "", false, "show commands without publishing plugin packages")
 
 	_ = pluginBuildPackageCmd.MarkFlagRequired("repository")
@@ -173,3 +208,41 @@ func newPluginPublishPackageCmd() *cobra.Command {
 
 	return pluginBuildPackageCmd
 }
+
+func newPluginVerifyCmd() *cobra.Command {
+	var pvFlags = &pluginVerifyFlags{}
+
+	var pluginVerifyCmd = &cobra.Command{
+		Use:   "verify",
+		Short: "Verify plugins against the plugin contract",
+		Long: `Verify that the plugin binaries built for the host os-arch satisfy the plugin contract
+and follow the command naming rules of the style guide.
+
+Each plugin binary is run to check the 'info', 'version', 'generate-docs', '__complete'
+and 'post-install' commands, its help output and its exit codes. The results can be saved
+as a JUnit report for CI pipelines to gate the publishing of the plugins.`,
+		SilenceUsage: true,
+		Example: `
+    # Verify all plugins built under './artifacts/plugins'
+    tanzu builder plugin verify --binary-artifacts ./artifacts/plugins
+
+    # Verify the foo plugin and save the results as a JUnit report
+    tanzu builder plugin verify --binary-artifacts ./artifacts/plugins --match foo --junit-report ./artifacts/verify-report.xml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vpArgs := &plugin.VerifyPluginOptions{
+				BinaryArtifactDir: pvFlags.BinaryArtifactDir,
+				Match:             pvFlags.Match,
+				JUnitReport:       pvFlags.JUnitReport,
+				Timeout:           pvFlags.Timeout,
+			}
+			return vpArgs.VerifyPlugins()
+		},
+	}
+
+	pluginVerifyCmd.Flags().StringVarP(&pvFlags.BinaryArtifactDir, "binary-artifacts", "", "./artifacts/plugins", "plugin binary artifact directory")
+	pluginVerifyCmd.Flags().StringVarP(&pvFlags.Match, "match", "", "*", "match a plugin name to verify, supports globbing")
+	pluginVerifyCmd.Flags().StringVarP(&pvFlags.JUnitReport, "junit-report", "", "", "file to save the results of the verification as a JUnit report (optional)")
+	pluginVerifyCmd.Flags().DurationVarP(&pvFlags.Timeout, "timeout", "", plugin.DefaultVerifyTimeout, "time allowed for each plugin command to complete")
+
+	return pluginVerifyCmd
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/helpers"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/carvelhelpers"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/provenance"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/utils"
 )
 
@@ -138,5 +139,16 @@ func (bpo *BuildPluginPackageOptions) generatePluginPackage(pluginBinaryFilePath
 	}
 
 	log.Infof("%s Generated plugin package at %q", threadID, pluginTarFilePath)
+
+	// Keep the provenance statement of the plugin binary, if any, next to the
+	// plugin package for it to be published along with the plugin package
+	provenanceFileName := provenance.FileName(filepath.Base(pluginBinaryFilePath))
+	provenanceFilePath := filepath.Join(filepath.Dir(pluginBinaryFilePath), provenanceFileName)
+	if utils.PathExists(provenanceFilePath) {
+		err = utils.CopyFile(provenanceFilePath, filepath.Join(filepath.Dir(pluginTarFilePath), provenanceFileName))
+		if err != nil {
+			return errors.Wrapf(err, "unable to copy the provenance of plugin: %s, target: %s, os: %s, arch: %s, version: %s", p.Name, p.Target, osArch.OS(), osArch.Arch(), version)
+		}
+	}
 	return nil
 }
@@ -4,6 +4,7 @@
 package plugin
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -15,7 +16,10 @@ import (
 
 	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/crane"
 	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/helpers"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/carvelhelpers"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cosignhelper"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/provenance"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/utils"
 )
 
@@ -25,9 +29,14 @@ type PublishPluginPackageOptions struct {
 	Vendor             string
 	Repository         string
 	DryRun             bool
-	CraneOptions       crane.CraneWrapper
+	// SigningKey is the cosign private key reference used to sign the plugin
+	// binaries. The plugin binaries are not signed if empty.
+	SigningKey      string
+	CraneOptions    crane.CraneWrapper
+	ImageOperations carvelhelpers.ImageOperationsImpl
 
 	pluginManifestFile string
+	signBlob           func(blob []byte) ([]byte, error)
 }
 
 func (ppo *PublishPluginPackageOptions) PublishPluginPackages() error {
@@ -45,6 +54,14 @@ func (ppo *PublishPluginPackageOptions) PublishPluginPackages() error {
 
 	log.Infof("using plugin package artifacts from %q", ppo.PackageArtifactDir)
 
+	// Load the signing key once, before publishing any plugin package
+	if ppo.SigningKey != "" && !ppo.DryRun {
+		ppo.signBlob, err = cosignhelper.NewBlobSigner(context.Background(), ppo.SigningKey)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Limit the number of concurrent operations we perform so we don't overwhelm the system.
 	maxConcurrent := helpers.GetMaxParallelism()
 	guard := make(chan struct{}, maxConcurrent)
@@ -110,5 +127,66 @@ func (ppo *PublishPluginPackageOptions) publishPluginPackage(pluginTarFilePath s
 		}
 		log.Infof("%s published plugin at '%s'", threadID, imageToPush)
 	}
+
+	if err := ppo.publishPluginProvenance(pluginTarFilePath, imageToPush, p, osArch, threadID); err != nil {
+		return err
+	}
+	if ppo.SigningKey != "" {
+		return ppo.publishPluginSignature(pluginTarFilePath, imageToPush, p, osArch, threadID)
+	}
+	return nil
+}
+
+// publishPluginProvenance publishes the provenance statement of the plugin binary,
+// saved next to the plugin package by 'plugin build-package', as an OCI referrer
+// of the plugin image
+func (ppo *PublishPluginPackageOptions) publishPluginProvenance(pluginTarFilePath, pluginImage string, p cli.Plugin, osArch cli.Arch, threadID string) error {
+	provenanceFilePath := filepath.Join(filepath.Dir(pluginTarFilePath), provenance.FileName(cli.MakeArtifactName(p.Name, osArch)))
+	if !utils.PathExists(provenanceFilePath) {
+		return nil
+	}
+
+	if ppo.DryRun {
+		log.Infof("%s command: 'oras attach --artifact-type %s %s %s'", threadID, provenance.ArtifactType, pluginImage, provenanceFilePath)
+		return nil
+	}
+
+	content, err := os.ReadFile(provenanceFilePath)
+	if err != nil {
+		return errors.Wrap(err, "unable to read the plugin provenance")
+	}
+	err = ppo.ImageOperations.PushReferrer(pluginImage, provenance.ArtifactType, content)
+	if err != nil {
+		return errors.Wrapf(err, "unable to publish the provenance of plugin (name:%s, target:%s, os:%s, arch:%s)", p.Name, p.Target, osArch.OS(), osArch.Arch())
+	}
+	log.Infof("%s published plugin provenance as a referrer of '%s'", threadID, pluginImage)
+	return nil
+}
+
+// publishPluginSignature signs the plugin binary of the plugin package and publishes
+// the signature as an image next to the plugin image
+func (ppo *PublishPluginPackageOptions) publishPluginSignature(pluginTarFilePath, pluginImage string, p cli.Plugin, osArch cli.Arch, threadID string) error {
+	pluginBinaryFileName := cli.MakeArtifactName(p.Name, osArch)
+	signatureImage := helpers.GetPluginSignatureImage(pluginImage)
+
+	if ppo.DryRun {
+		log.Infof("%s command: 'cosign sign-blob --key %s %s' and publish the signature at '%s'", threadID, ppo.SigningKey, pluginBinaryFileName, signatureImage)
+		return nil
+	}
+
+	binary, err := helpers.ReadFileFromPackage(pluginTarFilePath, pluginBinaryFileName)
+	if err != nil {
+		return err
+	}
+	sig, err := ppo.signBlob(binary)
+	if err != nil {
+		return errors.Wrapf(err, "unable to sign plugin (name:%s, target:%s, os:%s, arch:%s)", p.Name, p.Target, osArch.OS(), osArch.Arch())
+	}
+
+	err = helpers.PublishPluginSignature(ppo.ImageOperations, pluginImage, pluginBinaryFileName, sig)
+	if err != nil {
+		return errors.Wrapf(err, "unable to publish the signature of plugin (name:%s, target:%s, os:%s, arch:%s)", p.Name, p.Target, osArch.OS(), osArch.Arch())
+	}
+	log.Infof("%s published plugin signature at '%s'", threadID, signatureImage)
 	return nil
 }
@@ -0,0 +1,499 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
+
+	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/helpers"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/utils"
+)
+
+const (
+	// DefaultVerifyTimeout is the default time allowed for each command of
+	// the plugin contract to complete
+	DefaultVerifyTimeout = 30 * time.Second
+
+	// maxCommandDepth is the maximum number of commands under 'tanzu' in a command
+	// path, as in 'tanzu plugin-name noun sub-noun verb'
+	maxCommandDepth = 4
+
+	unknownCommandName = "tanzu-verify-unknown-command"
+)
+
+// kebabCaseRegexp matches the lowercase kebab-case names expected for commands and flags
+var kebabCaseRegexp = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// completionDirectiveRegexp matches the last line of the output of the '__complete' command
+var completionDirectiveRegexp = regexp.MustCompile(`^:[0-9]+$`)
+
+// flagRegexp matches the long flag names in the 'Options' section of the generated docs
+var flagRegexp = regexp.MustCompile(`^\s+(?:-[^-\s], )?--([^\s=]+)`)
+
+// VerifyPluginOptions defines options for verifying that the plugin binaries
+// satisfy the plugin contract and follow the command naming rules of the style guide
+type VerifyPluginOptions struct {
+	BinaryArtifactDir string
+	Match             string
+	JUnitReport       string
+	Timeout           time.Duration
+
+	pluginManifestFile string
+}
+
+// errCheckSkipped is returned by the conformance checks that cannot run
+type errCheckSkipped struct {
+	reason string
+}
+
+func (e *errCheckSkipped) Error() string {
+	return e.reason
+}
+
+// pluginVerifier runs the conformance checks against one plugin binary
+type pluginVerifier struct {
+	plugin     cli.Plugin
+	version    string
+	osArch     cli.Arch
+	binaryPath string
+	timeout    time.Duration
+	threadID   string
+
+	// homeDir isolates the plugin from the configuration of the user running the checks
+	homeDir string
+	docsDir string
+}
+
+// pluginInfo is the output of the 'info' command of the plugin, which includes the
+// fields added by the plugin runtime to the plugin descriptor
+type pluginInfo struct {
+	cli.PluginInfo
+	PluginRuntimeVersion string `json:"pluginRuntimeVersion"`
+	BinaryArch           string `json:"binaryArch"`
+}
+
+// conformanceCheck is a check of the plugin contract, reported as a JUnit test case
+type conformanceCheck struct {
+	name string
+	run  func() error
+}
+
+// VerifyPlugins runs the conformance checks against the plugin binaries built for
+// the host os-arch and optionally saves the results as a JUnit report
+func (vpo *VerifyPluginOptions) VerifyPlugins() error {
+	if vpo.pluginManifestFile == "" {
+		vpo.pluginManifestFile = filepath.Join(vpo.BinaryArtifactDir, cli.PluginManifestFileName)
+	}
+	if vpo.Match == "" {
+		vpo.Match = "*"
+	}
+	if vpo.Timeout <= 0 {
+		vpo.Timeout = DefaultVerifyTimeout
+	}
+
+	pluginManifest, err := helpers.ReadPluginManifest(vpo.pluginManifestFile)
+	if err != nil {
+		return err
+	}
+
+	osArch := cli.BuildArch()
+	var verifiers []*pluginVerifier
+	for i := range pluginManifest.Plugins {
+		p := pluginManifest.Plugins[i]
+		match, err := filepath.Match(vpo.Match, p.Name)
+		if err != nil {
+			return errors.Wrapf(err, "invalid plugin name pattern %q", vpo.Match)
+		}
+		if !match {
+			continue
+		}
+		for _, version := range p.Versions {
+			verifiers = append(verifiers, &pluginVerifier{
+				plugin:  p,
+				version: version,
+				osArch:  osArch,
+				binaryPath: filepath.Join(vpo.BinaryArtifactDir, osArch.OS(), osArch.Arch(),
+					p.Target, p.Name, version, cli.MakeArtifactName(p.Name, osArch)),
+				timeout:  vpo.Timeout,
+				threadID: helpers.GetID(len(verifiers)),
+			})
+		}
+	}
+	if len(verifiers) == 0 {
+		return errors.Errorf("no plugin matching %q found in %q", vpo.Match, vpo.pluginManifestFile)
+	}
+
+	log.Infof("Verifying plugin binaries from %q for %s", vpo.BinaryArtifactDir, osArch)
+
+	// Limit the number of concurrent operations we perform so we don't overwhelm the system.
+	guard := make(chan struct{}, helpers.GetMaxParallelism())
+	var wg sync.WaitGroup
+	suites := make([]helpers.JUnitTestSuite, len(verifiers))
+	for i := range verifiers {
+		wg.Add(1)
+		guard <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-guard
+				wg.Done()
+			}()
+			suites[i] = verifiers[i].verify()
+		}(i)
+	}
+	wg.Wait()
+
+	report := &helpers.JUnitTestSuites{Name: "tanzu plugin conformance"}
+	for i := range suites {
+		report.AddTestSuite(suites[i])
+	}
+	if vpo.JUnitReport != "" {
+		if err := helpers.WriteJUnitReport(report, vpo.JUnitReport); err != nil {
+			return err
+		}
+		log.Infof("Saved JUnit report at %q", vpo.JUnitReport)
+	}
+
+	if report.Failures > 0 {
+		return errors.Errorf("%d of %d plugin conformance checks failed", report.Failures, report.Tests)
+	}
+	log.Successf("all %d plugin conformance checks passed, %d skipped", report.Tests, report.Skipped)
+	return nil
+}
+
+// verify runs all the conformance checks against the plugin binary and returns their results
+func (v *pluginVerifier) verify() helpers.JUnitTestSuite {
+	suite := helpers.JUnitTestSuite{
+		Name:      fmt.Sprintf("%s/%s@%s (%s)", v.plugin.Target, v.plugin.Name, v.version, v.osArch),
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+	checks := []conformanceCheck{
+		{name: "info", run: v.checkInfo},
+		{name: "version", run: v.checkVersion},
+		{name: "help", run: v.checkHelp},
+		{name: "exit-code", run: v.checkExitCode},
+		{name: "generate-docs", run: v.checkGenerateDocs},
+		{name: "completion", run: v.checkCompletion},
+		{name: "post-install", run: v.checkPostInstall},
+		{name: "command-naming", run: v.checkCommandNaming},
+	}
+
+	setupErr := v.setup()
+	defer v.cleanup()
+
+	log.Infof("%s Verifying plugin %q at %q", v.threadID, v.plugin.Name, v.binaryPath)
+	for _, check := range checks {
+		start := time.Now()
+		err := setupErr
+		if err == nil {
+			err = check.run()
+		}
+		tc := helpers.JUnitTestCase{
+			Name:      check.name,
+			ClassName: fmt.Sprintf("%s.%s", v.plugin.Target, v.plugin.Name),
+			Time:      helpers.JUnitDuration(time.Since(start)),
+		}
+		var skipped *errCheckSkipped
+		switch {
+		case errors.As(err, &skipped):
+			tc.Skipped = &helpers.JUnitSkipped{Message: skipped.reason}
+			log.Infof("%s   %s: skipped, %s", v.threadID, check.name, skipped.reason)
+		case err != nil:
+			tc.Failure = &helpers.JUnitFailure{Message: firstLine(err.Error()), Details: err.Error()}
+			log.Errorf("%s   %s: failed - %v", v.threadID, check.name, err)
+		default:
+			log.Infof("%s   %s: passed", v.threadID, check.name)
+		}
+		suite.AddTestCase(tc)
+	}
+	return suite
+}
+
+func (v *pluginVerifier) setup() error {
+	if !utils.PathExists(v.binaryPath) {
+		return &errCheckSkipped{reason: fmt.Sprintf("plugin binary not built for %s", v.osArch)}
+	}
+	var err error
+	if v.homeDir, err = os.MkdirTemp("", "plugin-verify-home"); err != nil {
+		return errors.Wrap(err, "unable to create a temporary home directory")
+	}
+	if v.docsDir, err = os.MkdirTemp("", "plugin-verify-docs"); err != nil {
+		return errors.Wrap(err, "unable to create a temporary docs directory")
+	}
+	return nil
+}
+
+func (v *pluginVerifier) cleanup() {
+	for _, dir := range []string{v.homeDir, v.docsDir} {
+		if dir != "" {
+			_ = os.RemoveAll(dir)
+		}
+	}
+}
+
+// run executes the plugin binary with the specified arguments and returns its
+// output and exit code. An error is only returned if the binary cannot be run
+// or does not complete in time.
+func (v *pluginVerifier) run(args ...string) (stdout, stderr string, exitCode int, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), v.timeout)
+	defer cancel()
+
+	var outBuf, errBuf bytes.Buffer
+	cmd := exec.CommandContext(ctx, v.binaryPath, args...)
+	cmd.Env = append(os.Environ(), "HOME="+v.homeDir, "USERPROFILE="+v.homeDir)
+	cmd.Dir = v.homeDir
+	cmd.Stdout = &outBuf
+	cmd.Stderr = &errBuf
+
+	err = cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return outBuf.String(), errBuf.String(), -1, errors.Errorf("'%s' did not complete within %v", commandLine(args), v.timeout)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return outBuf.String(), errBuf.String(), exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return "", "", -1, errors.Wrapf(err, "unable to run '%s'", commandLine(args))
+	}
+	return outBuf.String(), errBuf.String(), 0, nil
+}
+
+// runSuccessfully executes the plugin binary and returns an error if it exits with a non-zero exit code
+func (v *pluginVerifier) runSuccessfully(args ...string) (string, error) {
+	stdout, stderr, exitCode, err := v.run(args...)
+	if err != nil {
+		return "", err
+	}
+	if exitCode != 0 {
+		return "", errors.Errorf("'%s' exited with code %d:\n%s", commandLine(args), exitCode, stderr)
+	}
+	return stdout, nil
+}
+
+// checkInfo verifies that the 'info' command describes the plugin as published in the plugin manifest
+func (v *pluginVerifier) checkInfo() error {
+	stdout, err := v.runSuccessfully("info")
+	if err != nil {
+		return err
+	}
+	var info pluginInfo
+	if err := json.Unmarshal([]byte(stdout), &info); err != nil {
+		return errors.Wrapf(err, "the output of 'info' is not a valid plugin info JSON:\n%s", stdout)
+	}
+
+	var errList []error
+	if info.Name != v.plugin.Name {
+		errList = append(errList, errors.Errorf("the plugin name is %q instead of %q", info.Name, v.plugin.Name))
+	}
+	if configtypes.StringToTarget(string(info.Target)) != configtypes.StringToTarget(v.plugin.Target) {
+		errList = append(errList, errors.Errorf("the plugin target is %q instead of %q", info.Target, v.plugin.Target))
+	}
+	if info.Version != v.version {
+		errList = append(errList, errors.Errorf("the plugin version is %q instead of %q", info.Version, v.version))
+	} else if _, err := semver.NewVersion(info.Version); err != nil {
+		errList = append(errList, errors.Errorf("the plugin version %q is not a valid semantic version", info.Version))
+	}
+	if strings.TrimSpace(info.Description) == "" {
+		errList = append(errList, errors.New("the plugin description is empty"))
+	}
+	if info.PluginRuntimeVersion == "" {
+		errList = append(errList, errors.New("the plugin runtime version is missing, the plugin must be built with the tanzu-plugin-runtime library"))
+	}
+	if info.BinaryArch != "" && info.BinaryArch != v.osArch.Arch() {
+		errList = append(errList, errors.Errorf("the plugin binary architecture is %q instead of %q", info.BinaryArch, v.osArch.Arch()))
+	}
+	return kerrors.NewAggregate(errList)
+}
+
+// checkVersion verifies that the 'version' command prints the version of the plugin
+func (v *pluginVerifier) checkVersion() error {
+	stdout, err := v.runSuccessfully("version")
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(stdout, v.version) {
+		return errors.Errorf("the output of 'version' does not include the plugin version %q:\n%s", v.version, stdout)
+	}
+	return nil
+}
+
+// checkHelp verifies that the plugin prints its usage with the '--help' flag
+func (v *pluginVerifier) checkHelp() error {
+	stdout, err := v.runSuccessfully("--help")
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(stdout, "Usage:") {
+		return errors.Errorf("the output of '--help' does not include the usage of the plugin:\n%s", stdout)
+	}
+	return nil
+}
+
+// checkExitCode verifies that the plugin exits with a non-zero exit code on errors
+func (v *pluginVerifier) checkExitCode() error {
+	_, _, exitCode, err := v.run(unknownCommandName)
+	if err != nil {
+		return err
+	}
+	if exitCode == 0 {
+		return errors.Errorf("'%s' exited with code 0 instead of a non-zero exit code", unknownCommandName)
+	}
+	return nil
+}
+
+// checkGenerateDocs verifies that the 'generate-docs' command generates the
+// documentation of the commands of the plugin
+func (v *pluginVerifier) checkGenerateDocs() error {
+	if _, err := v.runSuccessfully("generate-docs", "--docs-dir", v.docsDir); err != nil {
+		return err
+	}
+	docs, err := filepath.Glob(filepath.Join(v.docsDir, "*.md"))
+	if err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return errors.New("'generate-docs' did not generate any documentation file")
+	}
+	return nil
+}
+
+// checkCompletion verifies that the '__complete' command used for shell
+// completion returns the completions followed by the completion directive
+func (v *pluginVerifier) checkCompletion() error {
+	stdout, err := v.runSuccessfully("__complete", "")
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); !completionDirectiveRegexp.MatchString(last) {
+		return errors.Errorf("the output of '__complete' does not end with a completion directive:\n%s", stdout)
+	}
+	return nil
+}
+
+// checkPostInstall verifies that the 'post-install' command succeeds
+func (v *pluginVerifier) checkPostInstall() error {
+	_, err := v.runSuccessfully("post-install")
+	return err
+}
+
+// checkCommandNaming verifies the command naming rules of the style guide
+// against the documentation generated by the plugin
+func (v *pluginVerifier) checkCommandNaming() error {
+	docs, _ := filepath.Glob(filepath.Join(v.docsDir, "*.md"))
+	if len(docs) == 0 {
+		return &errCheckSkipped{reason: "no documentation generated to read the commands from"}
+	}
+	return CheckCommandNaming(v.docsDir)
+}
+
+// CheckCommandNaming verifies the command naming rules of the style guide against
+// the markdown documentation of the commands in docsDir, as generated by the
+// 'generate-docs' command of the plugins:
+// - command and flag names are in lowercase kebab-case
+// - commands are not nested more than 'tanzu plugin-name noun sub-noun verb'
+// - commands have a short description
+func CheckCommandNaming(docsDir string) error {
+	docs, err := filepath.Glob(filepath.Join(docsDir, "*.md"))
+	if err != nil {
+		return err
+	}
+
+	var errList []error
+	for _, doc := range docs {
+		cmdPath, short, flags, err := readCommandDoc(doc)
+		if err != nil {
+			return err
+		}
+		names := strings.Fields(cmdPath)
+		if len(names) < 2 {
+			// 'tanzu' itself
+			continue
+		}
+		for _, name := range names[1:] {
+			if !kebabCaseRegexp.MatchString(name) {
+				errList = append(errList, errors.Errorf("'%s': the command name %q is not in lowercase kebab-case", cmdPath, name))
+			}
+		}
+		if len(names)-1 > maxCommandDepth {
+			errList = append(errList, errors.Errorf("'%s': the command is nested more than 'tanzu plugin-name noun sub-noun verb'", cmdPath))
+		}
+		if strings.TrimSpace(short) == "" {
+			errList = append(errList, errors.Errorf("'%s': the command has no short description", cmdPath))
+		}
+		for _, flag := range flags {
+			if !kebabCaseRegexp.MatchString(flag) {
+				errList = append(errList, errors.Errorf("'%s': the flag '--%s' is not in lowercase kebab-case", cmdPath, flag))
+			}
+		}
+	}
+	return kerrors.NewAggregate(errList)
+}
+
+// readCommandDoc returns the command path, the short description and the names of the
+// flags of the command from its markdown documentation generated by cobra
+func readCommandDoc(docFile string) (cmdPath, short string, flags []string, err error) {
+	f, err := os.Open(docFile)
+	if err != nil {
+		return "", "", nil, errors.Wrapf(err, "unable to read %q", docFile)
+	}
+	defer f.Close()
+
+	var section string
+	inCodeBlock := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "```"):
+			inCodeBlock = !inCodeBlock
+		case inCodeBlock:
+			if section == "Options" {
+				if m := flagRegexp.FindStringSubmatch(line); m != nil {
+					flags = append(flags, m[1])
+				}
+			}
+		case strings.HasPrefix(line, "### "):
+			section = strings.TrimPrefix(line, "### ")
+		case strings.HasPrefix(line, "## ") && cmdPath == "":
+			cmdPath = strings.TrimPrefix(line, "## ")
+		case cmdPath != "" && section == "" && short == "" && strings.TrimSpace(line) != "":
+			short = line
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", "", nil, errors.Wrapf(err, "unable to read %q", docFile)
+	}
+	return cmdPath, short, flags, nil
+}
+
+func commandLine(args []string) string {
+	return strings.TrimSpace("<plugin> " + strings.Join(args, " "))
+}
+
+func firstLine(s string) string {
+	if i := strings.Index(s, "\n"); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
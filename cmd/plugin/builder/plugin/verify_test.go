@@ -0,0 +1,135 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/helpers"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+)
+
+const fakePluginScript = `#!/bin/sh
+case "$1" in
+info)
+  echo '{"name":"%s","description":"Foo plugin","target":"global","version":"v1.0.0","pluginRuntimeVersion":"v1.4.7"}' ;;
+version)
+  echo "v1.0.0" ;;
+--help)
+  echo "Usage:"; echo "  tanzu foo [command]" ;;
+generate-docs)
+  printf '## tanzu foo\n\nFoo plugin\n' > "$3/tanzu_foo.md"
+  printf '## tanzu foo get_all\n\n\n### Options\n\n` + "```" + `\n      --Output string   output format\n` + "```" + `\n' > "$3/tanzu_foo_get_all.md" ;;
+__complete)
+  echo "get"; echo ":4" ;;
+post-install)
+  ;;
+*)
+  echo "unknown command" >&2; exit 1 ;;
+esac
+`
+
+func writeCommandDoc(t *testing.T, dir, name, content string) {
+	err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+	assert.Nil(t, err)
+}
+
+func TestCheckCommandNaming(t *testing.T) {
+	dir := t.TempDir()
+	writeCommandDoc(t, dir, "tanzu.md", "## tanzu\n\nThe main Tanzu CLI\n")
+	writeCommandDoc(t, dir, "tanzu_foo.md", "## tanzu foo\n\nFoo plugin\n\n### Options\n\n```\n  -h, --help   help for foo\n```\n")
+	writeCommandDoc(t, dir, "tanzu_foo_bar-baz_get.md", "## tanzu foo bar-baz get\n\nGet bar-baz\n\n```\ntanzu foo bar-baz get NAME [flags]\n```\n\n### Options\n\n```\n      --dry-run         show the result\n  -o, --output string   output format\n```\n")
+	assert.Nil(t, CheckCommandNaming(dir))
+
+	writeCommandDoc(t, dir, "tanzu_foo_Bar.md", "## tanzu foo Bar\n\n### Options\n\n```\n      --dryRun   show the result\n```\n\n### Options inherited from parent commands\n\n```\n      --Inherited   not checked\n```\n")
+	writeCommandDoc(t, dir, "tanzu_foo_a_b_c_d.md", "## tanzu foo a b c d\n\nToo deep\n")
+	err := CheckCommandNaming(dir)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `'tanzu foo Bar': the command name "Bar" is not in lowercase kebab-case`)
+	assert.Contains(t, err.Error(), `'tanzu foo Bar': the command has no short description`)
+	assert.Contains(t, err.Error(), `'tanzu foo Bar': the flag '--dryRun' is not in lowercase kebab-case`)
+	assert.Contains(t, err.Error(), `'tanzu foo a b c d': the command is nested more than`)
+	assert.NotContains(t, err.Error(), "Inherited")
+}
+
+func TestVerifyPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake plugin is a shell script")
+	}
+
+	tests := []struct {
+		name             string
+		pluginName       string
+		expectedFailures []string
+	}{
+		{
+			name:       "plugin with command naming violations",
+			pluginName: "foo",
+			expectedFailures: []string{
+				"command-naming",
+			},
+		},
+		{
+			name:       "plugin with a wrong name in its info",
+			pluginName: "bar",
+			expectedFailures: []string{
+				"info",
+				"command-naming",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			manifest := "plugins:\n- name: foo\n  target: global\n  description: Foo plugin\n  versions:\n  - v1.0.0\n- name: other\n  target: global\n  versions:\n  - v1.0.0\n"
+			err := os.WriteFile(filepath.Join(dir, cli.PluginManifestFileName), []byte(manifest), 0644)
+			assert.Nil(t, err)
+
+			osArch := cli.BuildArch()
+			binaryDir := filepath.Join(dir, osArch.OS(), osArch.Arch(), "global", "foo", "v1.0.0")
+			err = os.MkdirAll(binaryDir, 0755)
+			assert.Nil(t, err)
+			err = os.WriteFile(filepath.Join(binaryDir, cli.MakeArtifactName("foo", osArch)), []byte(fmt.Sprintf(fakePluginScript, tt.pluginName)), 0755)
+			assert.Nil(t, err)
+
+			reportFile := filepath.Join(dir, "report", "verify.xml")
+			vpo := &VerifyPluginOptions{
+				BinaryArtifactDir: dir,
+				JUnitReport:       reportFile,
+			}
+			err = vpo.VerifyPlugins()
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), fmt.Sprintf("%d of 16 plugin conformance checks failed", len(tt.expectedFailures)))
+
+			data, err := os.ReadFile(reportFile)
+			assert.Nil(t, err)
+			report := &helpers.JUnitTestSuites{}
+			err = xml.Unmarshal(data, report)
+			assert.Nil(t, err)
+			assert.Equal(t, 16, report.Tests)
+			assert.Equal(t, len(tt.expectedFailures), report.Failures)
+			assert.Equal(t, 8, report.Skipped)
+			assert.Equal(t, 2, len(report.Suites))
+
+			var failures []string
+			for _, tc := range report.Suites[0].TestCases {
+				if tc.Failure != nil {
+					failures = append(failures, tc.Name)
+				}
+			}
+			assert.Equal(t, tt.expectedFailures, failures)
+
+			// the plugin without a binary for the host os-arch is skipped
+			assert.Equal(t, 8, report.Suites[1].Skipped)
+		})
+	}
+}
@@ -23,3 +23,27 @@ var PluginTest = Target{
 	Filepath: "cmd/plugin/{{ .PluginName }}/test/main.go",
 	Template: plugintemplates.MainTestGo,
 }
+
+// PluginOpenAPIMain target
+var PluginOpenAPIMain = Target{
+	Filepath: "cmd/plugin/{{ .PluginName | ToLower }}/main.go",
+	Template: plugintemplates.OpenAPIMainGo,
+}
+
+// PluginOpenAPIClient target
+var PluginOpenAPIClient = Target{
+	Filepath: "cmd/plugin/{{ .PluginName | ToLower }}/client.go",
+	Template: plugintemplates.OpenAPIClientGo,
+}
+
+// PluginOpenAPIOutput target
+var PluginOpenAPIOutput = Target{
+	Filepath: "cmd/plugin/{{ .PluginName | ToLower }}/output.go",
+	Template: plugintemplates.OpenAPIOutputGo,
+}
+
+// PluginOpenAPIResource target, run for each resource of the API
+var PluginOpenAPIResource = Target{
+	Filepath: "cmd/plugin/{{ .PluginName | ToLower }}/{{ .Resource.FileName }}.go",
+	Template: plugintemplates.OpenAPIResourceGo,
+}
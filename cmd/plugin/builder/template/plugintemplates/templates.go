@@ -59,3 +59,23 @@ var GithubWorkflowBuild string
 //
 //go:embed gitlab-ci.yml.tmpl
 var GitlabCI string
+
+// OpenAPIMainGo contains the main.go template of plugins generated from an OpenAPI specification
+//
+//go:embed openapi_main.go.tmpl
+var OpenAPIMainGo string
+
+// OpenAPIClientGo contains the template of the API client of plugins generated from an OpenAPI specification
+//
+//go:embed openapi_client.go.tmpl
+var OpenAPIClientGo string
+
+// OpenAPIOutputGo contains the template of the output formatting of plugins generated from an OpenAPI specification
+//
+//go:embed openapi_output.go.tmpl
+var OpenAPIOutputGo string
+
+// OpenAPIResourceGo contains the template of the commands of a resource of plugins generated from an OpenAPI specification
+//
+//go:embed openapi_resource.go.tmpl
+var OpenAPIResourceGo string
@@ -6,6 +6,7 @@ package template
 import (
 	"bytes"
 	"fmt"
+	"go/format"
 	"os"
 	"path/filepath"
 	"strings"
@@ -40,6 +41,14 @@ func (t Target) Run(rootDir string, data interface{}, dryRun bool) error {
 	if err := tmpl.Execute(buf, data); err != nil {
 		return err
 	}
+	// Format the generated go files, as their templates cannot always produce formatted code
+	if filepath.Ext(fp) == ".go" {
+		formatted, err := format.Source(buf.Bytes())
+		if err != nil {
+			return fmt.Errorf("generated file %s is not valid go code: %w", bufFp.String(), err)
+		}
+		buf = bytes.NewBuffer(formatted)
+	}
 	if dryRun {
 		fmt.Printf("-- file: %s --\n\n%s", t.Filepath, buf.String())
 		return nil
@@ -73,3 +82,13 @@ var DefaultPluginTargets = []Target{
 	PluginMain,
 	PluginTest,
 }
+
+// OpenAPIPluginTargets are the plugin targets of plugins generated from an OpenAPI
+// specification. The PluginOpenAPIResource target is run for each resource of the API.
+var OpenAPIPluginTargets = []Target{
+	PluginReadMe,
+	PluginOpenAPIMain,
+	PluginOpenAPIClient,
+	PluginOpenAPIOutput,
+	PluginTest,
+}
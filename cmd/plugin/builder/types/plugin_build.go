@@ -0,0 +1,24 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// PluginBuildConfig specifies the build configuration of a plugin, read from
+// the optional plugin.yaml file in the directory of the plugin
+type PluginBuildConfig struct {
+	Build PluginBuildSettings `json:"build" yaml:"build"`
+	// ReleaseNotes are the release notes of the version of the plugin being
+	// built, recorded in the plugin manifest
+	ReleaseNotes string `json:"releaseNotes,omitempty" yaml:"releaseNotes,omitempty"`
+}
+
+// PluginBuildSettings specifies the settings used to build a plugin in
+// addition to the ones specified for all the plugins
+type PluginBuildSettings struct {
+	// Tags are the build tags of the plugin, added to the build tags of all the plugins
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// CGOEnabled enables or disables cgo for all the os/arch the plugin is built for
+	CGOEnabled *bool `json:"cgoEnabled,omitempty" yaml:"cgoEnabled,omitempty"`
+	// Env are the environment variables of the build of the plugin, e.g., 'CC=musl-gcc'
+	Env []string `json:"env,omitempty" yaml:"env,omitempty"`
+}
@@ -9,6 +9,8 @@ import (
 
 	dockerparser "github.com/novln/docker-parser"
 	"github.com/pkg/errors"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/registry"
 )
 
 // GetPluginInventoryMetadataImage returns the plugin inventory metadata
@@ -16,6 +18,14 @@ import (
 // E.g. if plugin inventory image is `fake.repo.com/plugin/plugin-inventory:latest`
 // it returns metadata image as `fake.repo.com/plugin/plugin-inventory-metadata:latest`
 func GetPluginInventoryMetadataImage(pluginInventoryImage string) (string, error) {
+	if registry.IsLocalImage(pluginInventoryImage) {
+		// A local image is not a valid docker reference, only split its tag
+		repository, tag := pluginInventoryImage, "latest"
+		if idx := strings.LastIndex(pluginInventoryImage, ":"); idx > strings.LastIndex(pluginInventoryImage, "/") {
+			repository, tag = pluginInventoryImage[:idx], pluginInventoryImage[idx+1:]
+		}
+		return fmt.Sprintf("%s-metadata:%s", repository, tag), nil
+	}
 	ref, err := dockerparser.Parse(pluginInventoryImage)
 	if err != nil {
 		return "", errors.Wrapf(err, "invalid image %q", pluginInventoryImage)
@@ -22,6 +22,16 @@ func Test_GetPluginInventoryMetadataImage(t *testing.T) {
 			expectedMetadataImage: "fake.repo.com/plugin/plugin-inventory-metadata:latest",
 			errString:             "",
 		},
+		{
+			pluginInventoryImage:  "file:///opt/plugins/plugin-inventory:latest",
+			expectedMetadataImage: "file:///opt/plugins/plugin-inventory-metadata:latest",
+			errString:             "",
+		},
+		{
+			pluginInventoryImage:  "file:///opt/plugins/plugin-inventory",
+			expectedMetadataImage: "file:///opt/plugins/plugin-inventory-metadata:latest",
+			errString:             "",
+		},
 		{
 			pluginInventoryImage:  "fake.repo.com/plugin/airgapped:v1.0.0",
 			expectedMetadataImage: "fake.repo.com/plugin/airgapped-metadata:v1.0.0",
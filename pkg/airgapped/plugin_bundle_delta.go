@@ -0,0 +1,212 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package airgapped
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/verybluebot/tarinator-go"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/plugininventory"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/utils"
+)
+
+// existingContent is the set of plugins and plugin groups that already
+// exist in the repository a delta plugin bundle is uploaded to
+type existingContent struct {
+	plugins      map[string]bool
+	pluginGroups map[string]bool
+}
+
+func pluginIdentifierToID(pi *plugininventory.PluginIdentifier) string {
+	return fmt.Sprintf("%s@%s:%s", pi.Name, pi.Target, pi.Version)
+}
+
+func pluginGroupIdentifierToID(pgi *plugininventory.PluginGroupIdentifier) string {
+	return fmt.Sprintf("%s-%s/%s:%s", pgi.Vendor, pgi.Publisher, pgi.Name, pgi.Version)
+}
+
+// readExistingContent reads the plugins and plugin groups listed in the specified
+// plugin inventory metadata database, as well as the ones listed in the base of a
+// delta plugin bundle if specified
+func readExistingContent(metadataDBFile string, base *DeltaBaseInfo) (*existingContent, error) {
+	metadataDB := plugininventory.NewSQLiteInventoryMetadata(metadataDBFile)
+	pis, err := metadataDB.GetPluginIdentifiers()
+	if err != nil {
+		return nil, err
+	}
+	pgis, err := metadataDB.GetPluginGroupIdentifiers()
+	if err != nil {
+		return nil, err
+	}
+
+	existing := &existingContent{plugins: map[string]bool{}, pluginGroups: map[string]bool{}}
+	for _, pi := range pis {
+		existing.plugins[pluginIdentifierToID(pi)] = true
+	}
+	for _, pgi := range pgis {
+		existing.pluginGroups[pluginGroupIdentifierToID(pgi)] = true
+	}
+	if base != nil {
+		for _, id := range base.Plugins {
+			existing.plugins[id] = true
+		}
+		for _, id := range base.PluginGroups {
+			existing.pluginGroups[id] = true
+		}
+	}
+	return existing, nil
+}
+
+// getExistingContent returns the plugins and plugin groups to exclude from a delta
+// plugin bundle, or nil if a full plugin bundle is requested
+func (o *DownloadPluginBundleOptions) getExistingContent() (*existingContent, error) {
+	switch {
+	case o.SinceBundle != "":
+		return getExistingContentFromBundle(o.SinceBundle)
+	case o.ExcludeExistingIn != "":
+		return o.getExistingContentFromRepository(o.ExcludeExistingIn)
+	}
+	return nil, nil
+}
+
+// getExistingContentFromBundle returns the plugins and plugin groups provided by a
+// previous plugin bundle. The bundle can be specified either as the tar file produced
+// by download-bundle or as the plugin migration manifest of an extracted bundle.
+func getExistingContentFromBundle(bundle string) (*existingContent, error) {
+	manifestFile := bundle
+	if ext := filepath.Ext(bundle); ext != ".yaml" && ext != ".yml" {
+		tempDir, err := os.MkdirTemp("", "")
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to create temp directory")
+		}
+		defer os.RemoveAll(tempDir)
+
+		log.Infof("extracting %q for processing...", bundle)
+		if err := tarinator.UnTarinate(tempDir, bundle); err != nil {
+			return nil, errors.Wrapf(err, "unable to extract the previous plugin bundle %q", bundle)
+		}
+		manifestFile = filepath.Join(tempDir, PluginBundleDirName, PluginMigrationManifestFile)
+	}
+
+	manifest, err := readPluginMigrationManifest(manifestFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read the previous plugin bundle %q", bundle)
+	}
+	if manifest.InventoryMetadataImage == nil {
+		return nil, errors.Errorf("the previous plugin bundle %q does not list its plugin inventory metadata", bundle)
+	}
+	metadataDBFile := filepath.Join(filepath.Dir(manifestFile), manifest.InventoryMetadataImage.SourceFilePath)
+	if !utils.PathExists(metadataDBFile) {
+		return nil, errors.Errorf("the plugin inventory metadata %q of the previous plugin bundle %q does not exist", metadataDBFile, bundle)
+	}
+	return readExistingContent(metadataDBFile, manifest.DeltaBase)
+}
+
+// getExistingContentFromRepository returns the plugins and plugin groups already
+// published to the specified repository, as listed by its plugin inventory metadata image
+func (o *DownloadPluginBundleOptions) getExistingContentFromRepository(repo string) (*existingContent, error) {
+	pluginInventoryMetadataImage, err := GetPluginInventoryMetadataImage(o.PluginInventoryImage)
+	if err != nil {
+		return nil, err
+	}
+	pluginInventoryMetadataImageWithTag, err := utils.JoinURL(repo, GetImageRelativePath(pluginInventoryMetadataImage, path.Dir(o.PluginInventoryImage), true))
+	if err != nil {
+		return nil, errors.Wrap(err, "error while constructing the plugin inventory metadata image with tag")
+	}
+
+	tempDir, err := os.MkdirTemp("", "")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create temp directory")
+	}
+	defer os.RemoveAll(tempDir)
+
+	log.Infof("getting the plugins already published to %q...", repo)
+	if err := o.ImageProcessor.DownloadImageAndSaveFilesToDir(pluginInventoryMetadataImageWithTag, tempDir); err != nil {
+		return nil, errors.Wrapf(err, "failed to download plugin inventory metadata image '%s'", pluginInventoryMetadataImageWithTag)
+	}
+	return readExistingContent(filepath.Join(tempDir, plugininventory.SQliteInventoryMetadataDBFileName), nil)
+}
+
+// excludeExistingContent removes the plugin versions and plugin group versions that
+// already exist from the selected ones, and returns the excluded ones as the base
+// of the delta plugin bundle
+func excludeExistingContent(pes []*plugininventory.PluginInventoryEntry, pgs []*plugininventory.PluginGroup, existing *existingContent) ([]*plugininventory.PluginInventoryEntry, []*plugininventory.PluginGroup, *DeltaBaseInfo) {
+	base := &DeltaBaseInfo{}
+
+	deltaPluginEntries := []*plugininventory.PluginInventoryEntry{}
+	for _, pe := range pes {
+		for version := range pe.Artifacts {
+			id := pluginIdentifierToID(&plugininventory.PluginIdentifier{Name: pe.Name, Target: pe.Target, Version: version})
+			if existing.plugins[id] {
+				delete(pe.Artifacts, version)
+				base.Plugins = append(base.Plugins, id)
+			}
+		}
+		if len(pe.Artifacts) != 0 {
+			deltaPluginEntries = append(deltaPluginEntries, pe)
+		}
+	}
+
+	deltaPluginGroups := []*plugininventory.PluginGroup{}
+	for _, pg := range pgs {
+		for version := range pg.Versions {
+			id := pluginGroupIdentifierToID(&plugininventory.PluginGroupIdentifier{Vendor: pg.Vendor, Publisher: pg.Publisher, Name: pg.Name, Version: version})
+			if existing.pluginGroups[id] {
+				delete(pg.Versions, version)
+				base.PluginGroups = append(base.PluginGroups, id)
+			}
+		}
+		if len(pg.Versions) != 0 {
+			deltaPluginGroups = append(deltaPluginGroups, pg)
+		}
+	}
+
+	sort.Strings(base.Plugins)
+	sort.Strings(base.PluginGroups)
+	log.Infof("excluding %d plugin versions and %d plugin group versions that already exist", len(base.Plugins), len(base.PluginGroups))
+
+	return deltaPluginEntries, deltaPluginGroups, base
+}
+
+// validateDeltaBase verifies that the plugins and plugin groups a delta plugin
+// bundle builds upon exist in the destination repository, whose plugin inventory
+// metadata database is specified. An empty path means the destination repository
+// does not have any plugin inventory metadata.
+func (o *UploadPluginBundleOptions) validateDeltaBase(base *DeltaBaseInfo, existingMetadataDBFile string) error {
+	if len(base.Plugins) == 0 && len(base.PluginGroups) == 0 {
+		return nil
+	}
+	if existingMetadataDBFile == "" {
+		return errors.Errorf("the delta plugin bundle cannot be applied to %q as no plugins were published to it. Please upload the plugin bundle it is based on first", o.DestinationRepo)
+	}
+
+	existing, err := readExistingContent(existingMetadataDBFile, nil)
+	if err != nil {
+		return errors.Wrap(err, "error while reading the plugin inventory metadata of the destination repository")
+	}
+	var missing []string
+	for _, id := range base.Plugins {
+		if !existing.plugins[id] {
+			missing = append(missing, "plugin "+id)
+		}
+	}
+	for _, id := range base.PluginGroups {
+		if !existing.pluginGroups[id] {
+			missing = append(missing, "plugin group "+id)
+		}
+	}
+	if len(missing) != 0 {
+		return errors.Errorf("the delta plugin bundle cannot be applied to %q as the following content it builds upon is missing: %s", o.DestinationRepo, strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -8,11 +8,13 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/verybluebot/tarinator-go"
 	"gopkg.in/yaml.v3"
 
+	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/helpers"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/carvelhelpers"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/cosignhelper/sigverifier"
@@ -30,11 +32,37 @@ const fileExists = "the file '%s' already exists"
 type DownloadPluginBundleOptions struct {
 	PluginInventoryImage string
 	ToTar                string
-	Groups               []string
-	Plugins              []string
-	RefreshConfigOnly    bool
-	DryRun               bool
-	ImageProcessor       carvelhelpers.ImageOperationsImpl
+	// ToOCILayout is a directory the plugin bundle is saved to as an OCI image
+	// layout, which can be used as a discovery source without any registry
+	ToOCILayout       string
+	Groups            []string
+	Plugins           []string
+	RefreshConfigOnly bool
+	DryRun            bool
+	// SinceBundle is a previous plugin bundle, or its plugin migration manifest.
+	// When specified, only the plugins and plugin groups that are not part of
+	// the previous bundle are downloaded.
+	SinceBundle string
+	// ExcludeExistingIn is a repository the plugins were already uploaded to.
+	// When specified, only the plugins and plugin groups that are not yet
+	// published to the repository are downloaded.
+	ExcludeExistingIn string
+	// SigningKey is the cosign private key used to sign the plugin migration
+	// manifest, which lists the digests of all the files of the plugin bundle
+	SigningKey string
+	// SBOMFormat is the format of the software bill of materials of the
+	// bundled plugins to include in the plugin bundle, if any
+	SBOMFormat string
+	// OSArch restricts the plugin binaries included in the plugin bundle
+	// to the specified "os/arch" platforms, e.g. "linux/amd64"
+	OSArch []string
+	// LatestNVersions restricts the versions of each plugin included in the
+	// plugin bundle to the specified number of latest versions
+	LatestNVersions int
+	// SinceVersion restricts the versions of each plugin included in the
+	// plugin bundle to the versions newer than or equal to the specified one
+	SinceVersion   string
+	ImageProcessor carvelhelpers.ImageOperationsImpl
 }
 
 // DownloadPluginBundle download the plugin bundle based on provided plugin inventory image
@@ -64,6 +92,16 @@ func (o *DownloadPluginBundleOptions) DownloadPluginBundle() error {
 		return errors.Wrap(err, "error while getting selected plugin and plugin group information")
 	}
 
+	// Exclude the plugins and plugin groups that already exist when creating a delta plugin bundle
+	existing, err := o.getExistingContent()
+	if err != nil {
+		return errors.Wrap(err, "error while getting the existing plugin and plugin group information")
+	}
+	var deltaBase *DeltaBaseInfo
+	if existing != nil {
+		selectedPluginEntries, selectedPluginGroups, deltaBase = excludeExistingContent(selectedPluginEntries, selectedPluginGroups, existing)
+	}
+
 	if o.DryRun {
 		imageMetadata, err := o.getListOfImages(selectedPluginEntries)
 		if err != nil {
@@ -92,12 +130,39 @@ func (o *DownloadPluginBundleOptions) DownloadPluginBundle() error {
 		return errors.Wrap(err, "error while saving plugin inventory metadata")
 	}
 
+	manifest := &PluginMigrationManifest{
+		RelativeInventoryImagePathWithTag: relativeInventoryImagePathWithTag,
+		ImagesToCopy:                      imagesToCopy,
+		InventoryMetadataImage:            inventoryMetadataImageInfo,
+		DeltaBase:                         deltaBase,
+	}
+
+	// Save the software bill of materials of the bundled plugins
+	if o.SBOMFormat != "" {
+		manifest.SBOM, err = saveSBOM(o.SBOMFormat, selectedPluginEntries, tempPluginBundleDir)
+		if err != nil {
+			return errors.Wrap(err, "error while saving the software bill of materials")
+		}
+	}
+
 	// Save plugin migration manifest file to the plugin bundle directory
-	err = savePluginMigrationManifestFile(relativeInventoryImagePathWithTag, imagesToCopy, inventoryMetadataImageInfo, tempPluginBundleDir)
+	err = savePluginMigrationManifestFile(manifest, tempPluginBundleDir)
 	if err != nil {
 		return errors.Wrap(err, "error while saving plugin migration manifest")
 	}
 
+	// Sign the plugin migration manifest so that the plugin bundle can be verified before uploading it
+	if o.SigningKey != "" {
+		err = signPluginMigrationManifest(o.SigningKey, tempPluginBundleDir)
+		if err != nil {
+			return errors.Wrap(err, "error while signing plugin migration manifest")
+		}
+	}
+
+	if o.ToOCILayout != "" {
+		return o.saveAsOCILayout(tempBaseDir, tempPluginBundleDir)
+	}
+
 	// Save entire plugin bundle as a single tar file which can be used with upload-bundle
 	log.Infof("saving plugin bundle at: %s", o.ToTar)
 	err = tarinator.Tarinate([]string{tempPluginBundleDir}, o.ToTar)
@@ -145,10 +210,17 @@ func (o *DownloadPluginBundleOptions) getSelectedPluginInfo() ([]*plugininventor
 			if err != nil {
 				return nil, nil, errors.Wrap(err, "unable to read all plugin groups from database")
 			}
-			selectedPluginEntries, err = pi.GetPlugins(&plugininventory.PluginInventoryFilter{IncludeHidden: true}) // Include the hidden plugins during plugin migration
+			selectedPluginEntries, err = o.getPlugins(pi, &plugininventory.PluginInventoryFilter{IncludeHidden: true}) // Include the hidden plugins during plugin migration
 			if err != nil {
 				return nil, nil, errors.Wrap(err, "unable to read all plugins from database")
 			}
+			if err = o.filterPluginVersions(selectedPluginEntries); err != nil {
+				return nil, nil, err
+			}
+			selectedPluginEntries = removePluginsWithoutArtifacts(selectedPluginEntries)
+			if o.hasVersionFilter() {
+				selectedPluginGroups = removeGroupVersionsWithMissingPlugins(selectedPluginGroups, selectedPluginEntries)
+			}
 			if len(selectedPluginEntries) == 1 {
 				log.Infof("will be downloading the one plugin from: %s", o.PluginInventoryImage)
 			} else {
@@ -190,6 +262,7 @@ func (o *DownloadPluginBundleOptions) getSelectedPluginInfo() ([]*plugininventor
 
 	// Remove duplicate PluginInventoryEntries and PluginGroups from the selected list
 	selectedPluginEntries = plugininventory.RemoveDuplicatePluginInventoryEntries(selectedPluginEntries)
+	selectedPluginEntries = removePluginsWithoutArtifacts(selectedPluginEntries)
 	selectedPluginGroups = plugininventory.RemoveDuplicatePluginGroups(selectedPluginGroups)
 
 	return selectedPluginEntries, selectedPluginGroups, nil
@@ -197,11 +270,13 @@ func (o *DownloadPluginBundleOptions) getSelectedPluginInfo() ([]*plugininventor
 
 func (o *DownloadPluginBundleOptions) getPluginFromPluginID(pluginID string, pi plugininventory.PluginInventory) ([]*plugininventory.PluginInventoryEntry, error) {
 	pluginName, pluginTarget, pluginVersion := utils.ParsePluginID(pluginID)
-	if pluginVersion == "" {
+	// Only the latest version is downloaded when no version is specified, unless
+	// the versions to download are selected with the version filters
+	if pluginVersion == "" && !o.hasVersionFilter() {
 		pluginVersion = cli.VersionLatest
 	}
 
-	pluginEntries, err := pi.GetPlugins(&plugininventory.PluginInventoryFilter{
+	pluginEntries, err := o.getPlugins(pi, &plugininventory.PluginInventoryFilter{
 		Name:          pluginName,
 		Target:        configtypes.StringToTarget(pluginTarget),
 		Version:       pluginVersion,
@@ -220,6 +295,14 @@ func (o *DownloadPluginBundleOptions) getPluginFromPluginID(pluginID string, pi
 	if len(pluginEntries) > 1 {
 		return nil, errors.Errorf("more than one plugins found for pluginID '%s'. Please specify the uniquely identifiable pluginID in the form of 'name@target'", pluginID)
 	}
+	if pluginVersion == "" {
+		if err = o.filterPluginVersions(pluginEntries); err != nil {
+			return nil, err
+		}
+		if len(pluginEntries[0].Artifacts) == 0 {
+			return nil, errors.Errorf("no versions of plugin %q match the version filters", pluginID)
+		}
+	}
 
 	log.Infof("will be downloading the %q plugin individually", pluginID)
 
@@ -261,7 +344,7 @@ func (o *DownloadPluginBundleOptions) getAllPluginGroupsAndPluginEntriesFromPlug
 					Version:       p.Version,
 					IncludeHidden: true, // Include the hidden plugins during plugin migration
 				}
-				pluginEntries, err := pi.GetPlugins(pif)
+				pluginEntries, err := o.getPlugins(pi, pif)
 				if err != nil {
 					return nil, nil, errors.Wrapf(err, "unable to get plugins in plugin group %v", plugininventory.PluginGroupToID(pg))
 				}
@@ -304,19 +387,19 @@ func (o *DownloadPluginBundleOptions) downloadImagesAsTarFile(pluginEntries []*p
 	allImages := []*ImageCopyInfo{}
 
 	// Download plugin inventory database as tar file
-	pluginInventoryFileNameTar := "plugin-inventory-image.tar.gz"
 	log.Infof("downloading image %q", o.PluginInventoryImage)
-	err := o.ImageProcessor.CopyImageToTar(o.PluginInventoryImage, filepath.Join(downloadDir, pluginInventoryFileNameTar))
+	err := o.ImageProcessor.CopyImageToTar(o.PluginInventoryImage, filepath.Join(downloadDir, pluginInventoryImageTarFileName))
 	if err != nil {
 		return "", nil, err
 	}
 
 	relativeInventoryImagePathWithTag := GetImageRelativePath(o.PluginInventoryImage, path.Dir(o.PluginInventoryImage), true)
 
-	allImages = append(allImages, &ImageCopyInfo{
-		SourceTarFilePath: pluginInventoryFileNameTar,
-		RelativeImagePath: GetImageRelativePath(o.PluginInventoryImage, path.Dir(o.PluginInventoryImage), false),
-	})
+	imageCopyInfo, err := o.newImageCopyInfo(o.PluginInventoryImage, pluginInventoryImageTarFileName, downloadDir)
+	if err != nil {
+		return "", nil, err
+	}
+	allImages = append(allImages, imageCopyInfo)
 
 	// Process all plugin entries and download the oci image as tar file
 	for _, pe := range pluginEntries {
@@ -324,21 +407,51 @@ func (o *DownloadPluginBundleOptions) downloadImagesAsTarFile(pluginEntries []*p
 			for _, a := range artifacts {
 				log.Infof("---------------------------")
 				log.Infof("downloading image %q", a.Image)
-				tarfileName := fmt.Sprintf("%s-%s-%s_%s-%s.tar.gz", pe.Name, pe.Target, a.OS, a.Arch, version)
+				tarfileName := pluginImageTarFileName(pe.Name, string(pe.Target), a.OS, a.Arch, version)
 				err = o.ImageProcessor.CopyImageToTar(a.Image, filepath.Join(downloadDir, tarfileName))
 				if err != nil {
 					return "", nil, err
 				}
-				allImages = append(allImages, &ImageCopyInfo{
-					SourceTarFilePath: tarfileName,
-					RelativeImagePath: GetImageRelativePath(a.Image, path.Dir(o.PluginInventoryImage), false),
-				})
+				imageCopyInfo, err := o.newImageCopyInfo(a.Image, tarfileName, downloadDir)
+				if err != nil {
+					return "", nil, err
+				}
+				allImages = append(allImages, imageCopyInfo)
 			}
 		}
 	}
 	return relativeInventoryImagePathWithTag, allImages, nil
 }
 
+// pluginImageTarFileName returns the name of the tar file of the plugin bundle
+// the image of a plugin binary is saved to
+func pluginImageTarFileName(name, target, osName, arch, version string) string {
+	return fmt.Sprintf("%s-%s-%s_%s-%s.tar.gz", name, target, osName, arch, version)
+}
+
+// newImageCopyInfo returns the ImageCopyInfo of an image downloaded as the specified
+// tar file, including the digests used to verify and resume the upload of the image
+func (o *DownloadPluginBundleOptions) newImageCopyInfo(image, tarFileName, downloadDir string) (*ImageCopyInfo, error) {
+	tarFileDigest, err := helpers.GetDigest(filepath.Join(downloadDir, tarFileName))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to calculate the digest of %q", tarFileName)
+	}
+	hashAlgorithm, hashHexVal, err := o.ImageProcessor.GetImageDigest(image)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get the digest of image %q", image)
+	}
+	var imageDigest string
+	if hashHexVal != "" {
+		imageDigest = fmt.Sprintf("%s:%s", hashAlgorithm, hashHexVal)
+	}
+	return &ImageCopyInfo{
+		SourceTarFilePath:   tarFileName,
+		RelativeImagePath:   GetImageRelativePath(image, path.Dir(o.PluginInventoryImage), false),
+		SourceTarFileDigest: tarFileDigest,
+		ImageDigest:         imageDigest,
+	}, nil
+}
+
 // downloadImagesAsTarFile downloads plugin inventory image and all plugin images
 // as tar file to the specified directory
 //
@@ -364,7 +477,35 @@ func (o *DownloadPluginBundleOptions) getListOfImages(pluginEntries []*plugininv
 // validateOptions validates the provided options and returns
 // error if contains invalid option
 func (o *DownloadPluginBundleOptions) validateOptions() error {
-	if !o.DryRun {
+	if o.SinceBundle != "" && o.ExcludeExistingIn != "" {
+		return errors.New("only one of the previous plugin bundle and the repository to exclude existing plugins from can be specified")
+	}
+
+	if o.ToTar != "" && o.ToOCILayout != "" {
+		return errors.New("only one of the tar file and the OCI image layout directory to save the plugin bundle to can be specified")
+	}
+
+	if o.ToOCILayout != "" && (o.SigningKey != "" || o.SBOMFormat != "") {
+		return errors.New("signing the plugin bundle and including a software bill of materials are only supported when saving the plugin bundle to a tar file")
+	}
+
+	if o.SigningKey != "" && !strings.Contains(o.SigningKey, "://") {
+		if _, err := os.Stat(o.SigningKey); err != nil {
+			return errors.Wrapf(err, "invalid signing key %q", o.SigningKey)
+		}
+	}
+
+	if o.SBOMFormat != "" {
+		if _, ok := sbomFileNames[o.SBOMFormat]; !ok {
+			return errors.Errorf("unsupported SBOM format %q, supported formats are %v", o.SBOMFormat, SBOMFormats)
+		}
+	}
+
+	if err := o.validateFilterOptions(); err != nil {
+		return err
+	}
+
+	if !o.DryRun && o.ToOCILayout == "" {
 		// Verify tar file to be used to save plugin bundle
 		err := o.verifyTarFile()
 		if err != nil {
@@ -381,6 +522,29 @@ func (o *DownloadPluginBundleOptions) validateOptions() error {
 	return nil
 }
 
+// saveAsOCILayout saves the plugin bundle to an OCI image layout directory by
+// publishing it to the directory the same way upload-bundle publishes it to
+// a repository
+func (o *DownloadPluginBundleOptions) saveAsOCILayout(tempBaseDir, pluginBundleDir string) error {
+	layoutDir, err := filepath.Abs(o.ToOCILayout)
+	if err != nil {
+		return errors.Wrapf(err, "invalid path for %q", o.ToOCILayout)
+	}
+	tarFile := filepath.Join(tempBaseDir, "plugin_bundle.tar.gz")
+	err = tarinator.Tarinate([]string{pluginBundleDir}, tarFile)
+	if err != nil {
+		return errors.Wrap(err, "error while creating archive file")
+	}
+
+	log.Infof("saving plugin bundle as an OCI image layout at: %s", layoutDir)
+	uploadOptions := &UploadPluginBundleOptions{
+		Tar:             tarFile,
+		DestinationRepo: "file://" + filepath.ToSlash(layoutDir),
+		ImageProcessor:  o.ImageProcessor,
+	}
+	return uploadOptions.UploadPluginBundle()
+}
+
 func (o *DownloadPluginBundleOptions) verifyTarFile() error {
 	dir := filepath.Dir(o.ToTar)
 	_, err := os.Stat(dir)
@@ -402,14 +566,8 @@ func (o *DownloadPluginBundleOptions) verifyTarFile() error {
 
 // savePluginMigrationManifestFile save the plugin_migration_manifest.yaml file
 // to the provided pluginBundleDir
-func savePluginMigrationManifestFile(relativeInventoryImagePathWithTag string, imagesToCopy []*ImageCopyInfo, inventoryMetadataImageInfo *ImagePublishInfo, pluginBundleDir string) error {
-	// Save all downloaded images as part of manifest file
-	manifest := PluginMigrationManifest{
-		RelativeInventoryImagePathWithTag: relativeInventoryImagePathWithTag,
-		ImagesToCopy:                      imagesToCopy,
-		InventoryMetadataImage:            inventoryMetadataImageInfo,
-	}
-	bytes, err := yaml.Marshal(&manifest)
+func savePluginMigrationManifestFile(manifest *PluginMigrationManifest, pluginBundleDir string) error {
+	bytes, err := yaml.Marshal(manifest)
 	if err != nil {
 		return err
 	}
@@ -434,11 +592,24 @@ func (o *DownloadPluginBundleOptions) savePluginInventoryMetadata(pgs []*plugini
 	}
 
 	for _, pe := range pes {
-		for version := range pe.Artifacts {
-			err := inventoryMetadataDB.InsertPluginIdentifier(&plugininventory.PluginIdentifier{Name: pe.Name, Target: pe.Target, Version: version})
+		for version, artifacts := range pe.Artifacts {
+			pi := &plugininventory.PluginIdentifier{Name: pe.Name, Target: pe.Target, Version: version}
+			err := inventoryMetadataDB.InsertPluginIdentifier(pi)
 			if err != nil {
 				return nil, err
 			}
+			// Record the platforms of the bundled plugin binaries when only some of
+			// them were selected, so that the published plugin inventory does not
+			// refer to the plugin binaries that were not bundled
+			if len(o.OSArch) == 0 {
+				continue
+			}
+			for _, a := range artifacts {
+				err := inventoryMetadataDB.InsertPluginPlatform(pi, a.OS, a.Arch)
+				if err != nil {
+					return nil, err
+				}
+			}
 		}
 	}
 	for _, pg := range pgs {
@@ -455,9 +626,15 @@ func (o *DownloadPluginBundleOptions) savePluginInventoryMetadata(pgs []*plugini
 		return nil, err
 	}
 
+	inventoryMetadataDBFileDigest, err := helpers.GetDigest(inventoryMetadataDBFilePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to calculate the digest of %q", inventoryMetadataDBFileName)
+	}
+
 	imagePublishInfo := &ImagePublishInfo{
 		SourceFilePath:           inventoryMetadataDBFileName,
 		RelativeImagePathWithTag: GetImageRelativePath(pluginInventoryMetadataImage, path.Dir(o.PluginInventoryImage), true),
+		SourceFileDigest:         inventoryMetadataDBFileDigest,
 	}
 
 	return imagePublishInfo, nil
@@ -0,0 +1,204 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package airgapped
+
+import (
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/plugininventory"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/utils"
+)
+
+// validateFilterOptions validates the options restricting the plugin binaries
+// included in the plugin bundle
+func (o *DownloadPluginBundleOptions) validateFilterOptions() error {
+	for _, osArch := range o.OSArch {
+		if _, _, err := parseOSArch(osArch); err != nil {
+			return err
+		}
+	}
+	if o.LatestNVersions < 0 {
+		return errors.Errorf("invalid number of latest versions %d, it cannot be negative", o.LatestNVersions)
+	}
+	if o.SinceVersion != "" {
+		if _, err := semver.NewVersion(o.SinceVersion); err != nil {
+			return errors.Wrapf(err, "invalid version %q", o.SinceVersion)
+		}
+	}
+	return nil
+}
+
+// hasVersionFilter returns true if only some of the versions of the plugins
+// selected without a specific version are to be included in the plugin bundle
+func (o *DownloadPluginBundleOptions) hasVersionFilter() bool {
+	return o.LatestNVersions > 0 || o.SinceVersion != ""
+}
+
+// parseOSArch parses an "os/arch" platform
+func parseOSArch(osArch string) (string, string, error) {
+	parts := strings.Split(osArch, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.Errorf("invalid platform %q, it should be specified as 'os/arch', e.g. 'linux/amd64'", osArch)
+	}
+	return parts[0], parts[1], nil
+}
+
+// getPlugins returns the plugins matching the filter that have a binary for any
+// of the selected OS and architectures, with the artifacts of the selected OS and
+// architectures only. All the OS and architectures are considered if none was selected.
+func (o *DownloadPluginBundleOptions) getPlugins(pi plugininventory.PluginInventory, filter *plugininventory.PluginInventoryFilter) ([]*plugininventory.PluginInventoryEntry, error) {
+	if len(o.OSArch) == 0 {
+		return pi.GetPlugins(filter)
+	}
+
+	var allPluginEntries []*plugininventory.PluginInventoryEntry
+	pluginEntriesByID := make(map[string]*plugininventory.PluginInventoryEntry)
+	for _, osArch := range o.OSArch {
+		osName, arch, err := parseOSArch(osArch)
+		if err != nil {
+			return nil, err
+		}
+		platformFilter := *filter
+		platformFilter.OS = osName
+		platformFilter.Arch = arch
+		pluginEntries, err := pi.GetPlugins(&platformFilter)
+		if err != nil {
+			return nil, err
+		}
+
+		// Merge the artifacts of the plugins found for the different OS and architectures
+		for _, pe := range pluginEntries {
+			id := pe.Name + "@" + string(pe.Target)
+			existing, exists := pluginEntriesByID[id]
+			if !exists {
+				pluginEntriesByID[id] = pe
+				allPluginEntries = append(allPluginEntries, pe)
+				continue
+			}
+			for version, artifacts := range pe.Artifacts {
+				existing.Artifacts[version] = append(existing.Artifacts[version], artifacts...)
+			}
+			// The recommended version is computed from the artifacts found when
+			// it is not set in the database, so keep the highest one
+			if utils.IsNewVersion(pe.RecommendedVersion, existing.RecommendedVersion) {
+				existing.RecommendedVersion = pe.RecommendedVersion
+			}
+		}
+	}
+	return allPluginEntries, nil
+}
+
+// filterPluginVersions only keeps the versions of the plugins that are newer
+// than or equal to SinceVersion, and among these, the LatestNVersions latest ones.
+// The recommended version of a plugin is also kept unless it is older than
+// SinceVersion, as it is the version installed when no version is specified.
+func (o *DownloadPluginBundleOptions) filterPluginVersions(pluginEntries []*plugininventory.PluginInventoryEntry) error {
+	var sinceVersion *semver.Version
+	if o.SinceVersion != "" {
+		var err error
+		if sinceVersion, err = semver.NewVersion(o.SinceVersion); err != nil {
+			return errors.Wrapf(err, "invalid version %q", o.SinceVersion)
+		}
+	}
+
+	for _, pe := range pluginEntries {
+		var versions []string
+		for version := range pe.Artifacts {
+			versions = append(versions, version)
+		}
+		if err := utils.SortVersions(versions); err != nil {
+			return errors.Wrapf(err, "unable to sort the versions of plugin %s@%s", pe.Name, pe.Target)
+		}
+
+		kept := 0
+		for i := len(versions) - 1; i >= 0; i-- {
+			// The versions were successfully parsed when sorting them
+			v, _ := semver.NewVersion(versions[i])
+			if sinceVersion != nil && v.LessThan(sinceVersion) {
+				delete(pe.Artifacts, versions[i])
+				continue
+			}
+			if o.LatestNVersions > 0 && kept >= o.LatestNVersions && versions[i] != pe.RecommendedVersion {
+				delete(pe.Artifacts, versions[i])
+				continue
+			}
+			kept++
+		}
+	}
+	return nil
+}
+
+// removePluginsWithoutArtifacts removes the versions without any artifact and
+// the plugins without any version left once the plugin binaries are filtered
+func removePluginsWithoutArtifacts(pluginEntries []*plugininventory.PluginInventoryEntry) []*plugininventory.PluginInventoryEntry {
+	var result []*plugininventory.PluginInventoryEntry
+	for _, pe := range pluginEntries {
+		for version, artifacts := range pe.Artifacts {
+			if len(artifacts) == 0 {
+				delete(pe.Artifacts, version)
+			}
+		}
+		if len(pe.Artifacts) > 0 {
+			result = append(result, pe)
+		}
+	}
+	return result
+}
+
+// removeGroupVersionsWithMissingPlugins removes the versions of the plugin groups
+// that refer to a plugin version that is not part of the plugin entries, along
+// with the plugin groups without any version left. This prevents the inventory
+// metadata of the plugin bundle from listing plugin group versions that cannot be
+// installed once the plugin versions are filtered.
+func removeGroupVersionsWithMissingPlugins(pluginGroups []*plugininventory.PluginGroup, pluginEntries []*plugininventory.PluginInventoryEntry) []*plugininventory.PluginGroup {
+	bundledVersions := make(map[string][]string)
+	for _, pe := range pluginEntries {
+		id := pe.Name + "@" + string(pe.Target)
+		for version := range pe.Artifacts {
+			bundledVersions[id] = append(bundledVersions[id], version)
+		}
+	}
+	isBundled := func(p *plugininventory.PluginGroupPluginEntry) bool {
+		for _, version := range bundledVersions[p.Name+"@"+string(p.Target)] {
+			// The version of a plugin in a plugin group can be a shortened version
+			if version == p.Version || strings.HasPrefix(version, p.Version+".") {
+				return true
+			}
+		}
+		return false
+	}
+
+	var result []*plugininventory.PluginGroup
+	for _, pg := range pluginGroups {
+		for version, plugins := range pg.Versions {
+			for _, p := range plugins {
+				if !isBundled(p) {
+					log.Infof("skipping plugin group %s:%s as plugin %s@%s:%s is not part of the plugin bundle", plugininventory.PluginGroupToID(pg), version, p.Name, p.Target, p.Version)
+					delete(pg.Versions, version)
+					break
+				}
+			}
+		}
+		if len(pg.Versions) == 0 {
+			continue
+		}
+
+		if _, exists := pg.Versions[pg.RecommendedVersion]; !exists {
+			var versions []string
+			for version := range pg.Versions {
+				versions = append(versions, version)
+			}
+			if err := utils.SortVersions(versions); err == nil {
+				pg.RecommendedVersion = versions[len(versions)-1]
+			}
+		}
+		result = append(result, pg)
+	}
+	return result
+}
@@ -0,0 +1,290 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package airgapped
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/verybluebot/tarinator-go"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
+
+	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/helpers"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/carvelhelpers"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/centralconfig"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/constants"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/plugininventory"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/utils"
+)
+
+// PluginBundleContents describes the content of a plugin bundle
+type PluginBundleContents struct {
+	// PluginInventoryImage is the relative path of the plugin inventory image
+	PluginInventoryImage string `json:"pluginInventoryImage" yaml:"pluginInventoryImage"`
+	// CentralConfig describes the central configuration of the plugin
+	// inventory image, if any
+	CentralConfig *BundledCentralConfig `json:"centralConfig,omitempty" yaml:"centralConfig,omitempty"`
+	// Plugins lists the plugin binaries of the plugin bundle
+	Plugins []*BundledPlugin `json:"plugins" yaml:"plugins"`
+	// PluginGroups lists the plugin group versions of the plugin bundle
+	PluginGroups []*BundledPluginGroup `json:"pluginGroups" yaml:"pluginGroups"`
+	// DeltaBase is only set for a delta plugin bundle
+	DeltaBase *DeltaBaseInfo `json:"deltaBase,omitempty" yaml:"deltaBase,omitempty"`
+	// Signed tells whether the plugin migration manifest is signed
+	Signed bool `json:"signed" yaml:"signed"`
+	// SBOM is the software bill of materials file of the plugin bundle, if any
+	SBOM string `json:"sbom,omitempty" yaml:"sbom,omitempty"`
+	// Size is the total size of the image archives of the plugin bundle in bytes
+	Size int64 `json:"size" yaml:"size"`
+}
+
+// BundledCentralConfig describes the central configuration of a plugin bundle
+type BundledCentralConfig struct {
+	// Digest is the sha256 digest of the central config file
+	Digest string `json:"digest" yaml:"digest"`
+	// Version is the configuration endpoint update version of the central configuration
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+}
+
+// BundledPlugin describes a plugin binary of a plugin bundle
+type BundledPlugin struct {
+	Name    string `json:"name" yaml:"name"`
+	Target  string `json:"target" yaml:"target"`
+	Version string `json:"version" yaml:"version"`
+	OS      string `json:"os" yaml:"os"`
+	Arch    string `json:"arch" yaml:"arch"`
+	// Size is the size of the image archive in bytes
+	Size int64 `json:"size" yaml:"size"`
+}
+
+// BundledPluginGroup describes a plugin group version of a plugin bundle
+type BundledPluginGroup struct {
+	// ID is of the form vendor-publisher/name:version
+	ID string `json:"id" yaml:"id"`
+	// Plugins is the number of plugins of the plugin group version
+	Plugins int `json:"plugins" yaml:"plugins"`
+}
+
+// InspectPluginBundleOptions defines options for inspecting plugin bundle
+type InspectPluginBundleOptions struct {
+	Tar            string
+	ImageProcessor carvelhelpers.ImageOperationsImpl
+}
+
+// InspectPluginBundle returns the content of the given plugin bundle without
+// accessing any registry. The plugins and plugin groups of the bundle are read
+// from its plugin inventory metadata, and their details from the plugin inventory
+// database of the bundled plugin inventory image.
+func (o *InspectPluginBundleOptions) InspectPluginBundle() (*PluginBundleContents, error) {
+	tempDir, err := os.MkdirTemp("", "")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create temp directory")
+	}
+	defer os.RemoveAll(tempDir)
+
+	pluginBundleDir, manifest, err := extractPluginBundle(o.Tar, tempDir)
+	if err != nil {
+		return nil, err
+	}
+
+	contents := &PluginBundleContents{
+		PluginInventoryImage: manifest.RelativeInventoryImagePathWithTag,
+		Plugins:              []*BundledPlugin{},
+		PluginGroups:         []*BundledPluginGroup{},
+		DeltaBase:            manifest.DeltaBase,
+		Signed:               utils.PathExists(filepath.Join(pluginBundleDir, PluginMigrationManifestSignatureFile)),
+	}
+	if manifest.SBOM != nil {
+		contents.SBOM = manifest.SBOM.SourceFilePath
+	}
+	tarFiles := map[string]bool{}
+	for _, ic := range manifest.ImagesToCopy {
+		tarFiles[ic.SourceTarFilePath] = true
+		if info, err := os.Stat(filepath.Join(pluginBundleDir, ic.SourceTarFilePath)); err == nil {
+			contents.Size += info.Size()
+		}
+	}
+
+	// Read the plugin inventory database and the central configuration from the
+	// plugin inventory image of the extracted plugin bundle
+	inventoryDir := filepath.Join(tempDir, "inventory")
+	bundleRepo := "file://" + filepath.ToSlash(pluginBundleDir)
+	inventoryImage, err := utils.JoinURL(bundleRepo, manifest.RelativeInventoryImagePathWithTag)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while constructing the plugin inventory image")
+	}
+	if err := o.ImageProcessor.DownloadImageAndSaveFilesToDir(inventoryImage, inventoryDir); err != nil {
+		return nil, errors.Wrapf(err, "unable to read the plugin inventory image of the plugin bundle %q", o.Tar)
+	}
+	inventory := plugininventory.NewSQLiteInventory(filepath.Join(inventoryDir, plugininventory.SQliteDBFileName), bundleRepo)
+	contents.CentralConfig = readBundledCentralConfig(filepath.Join(inventoryDir, constants.CentralConfigFileName))
+
+	metadataDB := plugininventory.NewSQLiteInventoryMetadata(filepath.Join(pluginBundleDir, manifest.InventoryMetadataImage.SourceFilePath))
+	pis, err := metadataDB.GetPluginIdentifiers()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the plugins of the plugin inventory metadata")
+	}
+	for _, pi := range pis {
+		pes, err := inventory.GetPlugins(&plugininventory.PluginInventoryFilter{
+			Name:          pi.Name,
+			Target:        pi.Target,
+			Version:       pi.Version,
+			IncludeHidden: true,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read the plugin %q", pluginIdentifierToID(pi))
+		}
+		for _, pe := range pes {
+			for _, a := range pe.Artifacts[pi.Version] {
+				tarFile := pluginImageTarFileName(pi.Name, string(pi.Target), a.OS, a.Arch, pi.Version)
+				if !tarFiles[tarFile] {
+					continue
+				}
+				plugin := &BundledPlugin{Name: pi.Name, Target: string(pi.Target), Version: pi.Version, OS: a.OS, Arch: a.Arch}
+				if info, err := os.Stat(filepath.Join(pluginBundleDir, tarFile)); err == nil {
+					plugin.Size = info.Size()
+				}
+				contents.Plugins = append(contents.Plugins, plugin)
+			}
+		}
+	}
+	sort.Slice(contents.Plugins, func(i, j int) bool {
+		pi, pj := contents.Plugins[i], contents.Plugins[j]
+		if pi.Name != pj.Name {
+			return pi.Name < pj.Name
+		}
+		if pi.Target != pj.Target {
+			return pi.Target < pj.Target
+		}
+		if pi.Version != pj.Version {
+			return pi.Version < pj.Version
+		}
+		return pi.OS+"/"+pi.Arch < pj.OS+"/"+pj.Arch
+	})
+
+	pgis, err := metadataDB.GetPluginGroupIdentifiers()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the plugin groups of the plugin inventory metadata")
+	}
+	for _, pgi := range pgis {
+		pg := &BundledPluginGroup{ID: pluginGroupIdentifierToID(pgi)}
+		groups, err := inventory.GetPluginGroups(plugininventory.PluginGroupFilter{
+			Vendor:        pgi.Vendor,
+			Publisher:     pgi.Publisher,
+			Name:          pgi.Name,
+			Version:       pgi.Version,
+			IncludeHidden: true,
+		})
+		if err == nil && len(groups) == 1 {
+			pg.Plugins = len(groups[0].Versions[pgi.Version])
+		}
+		contents.PluginGroups = append(contents.PluginGroups, pg)
+	}
+	sort.Slice(contents.PluginGroups, func(i, j int) bool {
+		return contents.PluginGroups[i].ID < contents.PluginGroups[j].ID
+	})
+
+	return contents, nil
+}
+
+// PluginBundleDiff lists the plugin versions and plugin group versions that
+// were added to or removed from a plugin bundle compared to another one
+type PluginBundleDiff struct {
+	// AddedPlugins are of the form name@target:version
+	AddedPlugins []string `json:"addedPlugins" yaml:"addedPlugins"`
+	// RemovedPlugins are of the form name@target:version
+	RemovedPlugins []string `json:"removedPlugins" yaml:"removedPlugins"`
+	// AddedPluginGroups are of the form vendor-publisher/name:version
+	AddedPluginGroups []string `json:"addedPluginGroups" yaml:"addedPluginGroups"`
+	// RemovedPluginGroups are of the form vendor-publisher/name:version
+	RemovedPluginGroups []string `json:"removedPluginGroups" yaml:"removedPluginGroups"`
+}
+
+// DiffPluginBundles compares the plugin versions and plugin group versions of
+// the plugin bundle toTar with the ones of the plugin bundle fromTar
+func DiffPluginBundles(fromTar, toTar string) (*PluginBundleDiff, error) {
+	from, err := readPluginBundleContent(fromTar)
+	if err != nil {
+		return nil, err
+	}
+	to, err := readPluginBundleContent(toTar)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PluginBundleDiff{
+		AddedPlugins:        sortedDifference(to.plugins, from.plugins),
+		RemovedPlugins:      sortedDifference(from.plugins, to.plugins),
+		AddedPluginGroups:   sortedDifference(to.pluginGroups, from.pluginGroups),
+		RemovedPluginGroups: sortedDifference(from.pluginGroups, to.pluginGroups),
+	}, nil
+}
+
+// readPluginBundleContent returns the plugins and plugin groups listed in the
+// plugin inventory metadata of the plugin bundle
+func readPluginBundleContent(tarFile string) (*existingContent, error) {
+	tempDir, err := os.MkdirTemp("", "")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create temp directory")
+	}
+	defer os.RemoveAll(tempDir)
+
+	pluginBundleDir, manifest, err := extractPluginBundle(tarFile, tempDir)
+	if err != nil {
+		return nil, err
+	}
+	content, err := readExistingContent(filepath.Join(pluginBundleDir, manifest.InventoryMetadataImage.SourceFilePath), nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read the plugin inventory metadata of the plugin bundle %q", tarFile)
+	}
+	return content, nil
+}
+
+// extractPluginBundle extracts the plugin bundle to the specified directory and
+// returns the plugin bundle directory and its plugin migration manifest
+func extractPluginBundle(tarFile, dir string) (string, *PluginMigrationManifest, error) {
+	log.Infof("extracting %q for processing...", tarFile)
+	if err := tarinator.UnTarinate(dir, tarFile); err != nil {
+		return "", nil, errors.Wrapf(err, "unable to extract the plugin bundle %q", tarFile)
+	}
+	pluginBundleDir := filepath.Join(dir, PluginBundleDirName)
+	manifest, err := readPluginMigrationManifest(filepath.Join(pluginBundleDir, PluginMigrationManifestFile))
+	if err != nil {
+		return "", nil, err
+	}
+	if manifest.InventoryMetadataImage == nil {
+		return "", nil, errors.Errorf("the plugin bundle %q does not list its plugin inventory metadata", tarFile)
+	}
+	return pluginBundleDir, manifest, nil
+}
+
+// readBundledCentralConfig returns the details of the central config file,
+// or nil if the file does not exist
+func readBundledCentralConfig(centralConfigFile string) *BundledCentralConfig {
+	if !utils.PathExists(centralConfigFile) {
+		return nil
+	}
+	digest, err := helpers.GetDigest(centralConfigFile)
+	if err != nil {
+		return nil
+	}
+	cc := &BundledCentralConfig{Digest: "sha256:" + digest}
+	cc.Version, _ = centralconfig.NewCentralConfigReaderFromFile(centralConfigFile).GetTanzuConfigEndpointUpdateVersion()
+	return cc
+}
+
+// sortedDifference returns the sorted keys of a that are not in b
+func sortedDifference(a, b map[string]bool) []string {
+	diff := []string{}
+	for key := range a {
+		if !b[key] {
+			diff = append(diff, key)
+		}
+	}
+	sort.Strings(diff)
+	return diff
+}
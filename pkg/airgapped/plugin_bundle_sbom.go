@@ -0,0 +1,279 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package airgapped
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/helpers"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/buildinfo"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/plugininventory"
+)
+
+// Supported formats of the software bill of materials of a plugin bundle
+const (
+	SBOMFormatSPDX      = "spdx-json"
+	SBOMFormatCycloneDX = "cyclonedx-json"
+)
+
+// SBOMFormats lists the supported formats of the software bill of materials
+var SBOMFormats = []string{SBOMFormatSPDX, SBOMFormatCycloneDX}
+
+var sbomFileNames = map[string]string{
+	SBOMFormatSPDX:      "sbom.spdx.json",
+	SBOMFormatCycloneDX: "sbom.cdx.json",
+}
+
+var spdxIDInvalidChars = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// sbomPlugin is a plugin binary included in the plugin bundle
+type sbomPlugin struct {
+	name        string
+	target      string
+	version     string
+	os          string
+	arch        string
+	vendor      string
+	publisher   string
+	description string
+	image       string
+	digest      string
+}
+
+func (p *sbomPlugin) id() string {
+	return fmt.Sprintf("%s-%s-%s-%s-%s", p.name, p.target, p.version, p.os, p.arch)
+}
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name             string         `json:"name"`
+	SPDXID           string         `json:"SPDXID"`
+	VersionInfo      string         `json:"versionInfo"`
+	Supplier         string         `json:"supplier"`
+	DownloadLocation string         `json:"downloadLocation"`
+	FilesAnalyzed    bool           `json:"filesAnalyzed"`
+	Checksums        []spdxChecksum `json:"checksums,omitempty"`
+	Description      string         `json:"description,omitempty"`
+	Comment          string         `json:"comment,omitempty"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+type cycloneDXDocument struct {
+	BOMFormat    string               `json:"bomFormat"`
+	SpecVersion  string               `json:"specVersion"`
+	SerialNumber string               `json:"serialNumber"`
+	Version      int                  `json:"version"`
+	Metadata     cycloneDXMetadata    `json:"metadata"`
+	Components   []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string                   `json:"timestamp"`
+	Tools     cycloneDXMetadataTools   `json:"tools"`
+	Component cycloneDXMetadataSubject `json:"component"`
+}
+
+type cycloneDXMetadataTools struct {
+	Components []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXMetadataSubject struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+type cycloneDXComponent struct {
+	Type        string              `json:"type"`
+	BOMRef      string              `json:"bom-ref,omitempty"`
+	Name        string              `json:"name"`
+	Version     string              `json:"version,omitempty"`
+	Publisher   string              `json:"publisher,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Hashes      []cycloneDXHash     `json:"hashes,omitempty"`
+	Properties  []cycloneDXProperty `json:"properties,omitempty"`
+}
+
+type cycloneDXHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// saveSBOM generates the software bill of materials of the bundled plugins from
+// the plugin inventory metadata, saves it to the plugin bundle directory and
+// returns the BundledFileInfo to record in the plugin migration manifest
+func saveSBOM(format string, pes []*plugininventory.PluginInventoryEntry, pluginBundleDir string) (*BundledFileInfo, error) {
+	fileName, ok := sbomFileNames[format]
+	if !ok {
+		return nil, errors.Errorf("unsupported SBOM format %q, supported formats are %v", format, SBOMFormats)
+	}
+
+	plugins := getSBOMPlugins(pes)
+	var doc interface{}
+	if format == SBOMFormatSPDX {
+		doc = newSPDXDocument(plugins)
+	} else {
+		doc = newCycloneDXDocument(plugins)
+	}
+	bytes, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	sbomFile := filepath.Join(pluginBundleDir, fileName)
+	if err := os.WriteFile(sbomFile, bytes, 0644); err != nil {
+		return nil, err
+	}
+	digest, err := helpers.GetDigest(sbomFile)
+	if err != nil {
+		return nil, err
+	}
+	return &BundledFileInfo{SourceFilePath: fileName, SourceFileDigest: digest}, nil
+}
+
+// getSBOMPlugins returns the sorted list of plugin binaries of the plugin entries
+func getSBOMPlugins(pes []*plugininventory.PluginInventoryEntry) []*sbomPlugin {
+	var plugins []*sbomPlugin
+	for _, pe := range pes {
+		for version, artifacts := range pe.Artifacts {
+			for _, a := range artifacts {
+				plugins = append(plugins, &sbomPlugin{
+					name:        pe.Name,
+					target:      string(pe.Target),
+					version:     version,
+					os:          a.OS,
+					arch:        a.Arch,
+					vendor:      pe.Vendor,
+					publisher:   pe.Publisher,
+					description: pe.Description,
+					image:       a.Image,
+					digest:      a.Digest,
+				})
+			}
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].id() < plugins[j].id()
+	})
+	return plugins
+}
+
+func newSPDXDocument(plugins []*sbomPlugin) *spdxDocument {
+	doc := &spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              "tanzu-plugin-bundle",
+		DocumentNamespace: "https://spdx.org/spdxdocs/tanzu-plugin-bundle-" + uuid.NewString(),
+		CreationInfo: spdxCreationInfo{
+			Created:  time.Now().UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: tanzu-cli-" + buildinfo.Version},
+		},
+		Packages:      []spdxPackage{},
+		Relationships: []spdxRelationship{},
+	}
+	for _, p := range plugins {
+		pkg := spdxPackage{
+			Name:             p.name,
+			SPDXID:           "SPDXRef-Package-" + spdxIDInvalidChars.ReplaceAllString(p.id(), "-"),
+			VersionInfo:      p.version,
+			Supplier:         "Organization: " + p.vendor,
+			DownloadLocation: p.image,
+			Description:      p.description,
+			Comment:          fmt.Sprintf("target: %s, os: %s, arch: %s, publisher: %s", p.target, p.os, p.arch, p.publisher),
+		}
+		if p.image == "" {
+			pkg.DownloadLocation = "NOASSERTION"
+		}
+		if p.vendor == "" {
+			pkg.Supplier = "NOASSERTION"
+		}
+		if p.digest != "" {
+			pkg.Checksums = []spdxChecksum{{Algorithm: "SHA256", ChecksumValue: p.digest}}
+		}
+		doc.Packages = append(doc.Packages, pkg)
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SPDXElementID:      doc.SPDXID,
+			RelationshipType:   "DESCRIBES",
+			RelatedSPDXElement: pkg.SPDXID,
+		})
+	}
+	return doc
+}
+
+func newCycloneDXDocument(plugins []*sbomPlugin) *cycloneDXDocument {
+	doc := &cycloneDXDocument{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + uuid.NewString(),
+		Version:      1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Tools: cycloneDXMetadataTools{
+				Components: []cycloneDXComponent{{Type: "application", Name: "tanzu-cli", Version: buildinfo.Version}},
+			},
+			Component: cycloneDXMetadataSubject{Type: "application", Name: "tanzu-plugin-bundle"},
+		},
+		Components: []cycloneDXComponent{},
+	}
+	for _, p := range plugins {
+		c := cycloneDXComponent{
+			Type:        "application",
+			BOMRef:      p.id(),
+			Name:        p.name,
+			Version:     p.version,
+			Publisher:   p.vendor,
+			Description: p.description,
+			Properties: []cycloneDXProperty{
+				{Name: "tanzu:plugin:target", Value: p.target},
+				{Name: "tanzu:plugin:os", Value: p.os},
+				{Name: "tanzu:plugin:arch", Value: p.arch},
+				{Name: "tanzu:plugin:publisher", Value: p.publisher},
+				{Name: "tanzu:plugin:image", Value: p.image},
+			},
+		}
+		if p.digest != "" {
+			c.Hashes = []cycloneDXHash{{Alg: "SHA-256", Content: p.digest}}
+		}
+		doc.Components = append(doc.Components, c)
+	}
+	return doc
+}
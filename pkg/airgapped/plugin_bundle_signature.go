@@ -0,0 +1,46 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package airgapped
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cosignhelper"
+)
+
+// signPluginMigrationManifest signs the plugin migration manifest of the plugin
+// bundle with the cosign private key and saves the signature next to it
+func signPluginMigrationManifest(signingKey, pluginBundleDir string) error {
+	manifestBytes, err := os.ReadFile(filepath.Join(pluginBundleDir, PluginMigrationManifestFile))
+	if err != nil {
+		return errors.Wrap(err, "error while reading plugin migration manifest")
+	}
+	sig, err := cosignhelper.SignBlob(context.Background(), signingKey, manifestBytes)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(pluginBundleDir, PluginMigrationManifestSignatureFile), sig, 0644)
+}
+
+// verifyPluginMigrationManifestSignature verifies the signature of the plugin
+// migration manifest of the plugin bundle with the cosign public key
+func verifyPluginMigrationManifestSignature(publicKey, pluginBundleDir string) error {
+	manifestBytes, err := os.ReadFile(filepath.Join(pluginBundleDir, PluginMigrationManifestFile))
+	if err != nil {
+		return errors.Wrap(err, "error while reading plugin migration manifest")
+	}
+	sig, err := os.ReadFile(filepath.Join(pluginBundleDir, PluginMigrationManifestSignatureFile))
+	if err != nil {
+		return errors.New("the plugin bundle is not signed")
+	}
+	err = cosignhelper.VerifyBlobSignature(context.Background(), publicKey, manifestBytes, sig)
+	if err != nil {
+		return errors.Wrap(err, "the signature of the plugin migration manifest is invalid")
+	}
+	return nil
+}
@@ -5,14 +5,18 @@ package airgapped
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/sigstore/cosign/v2/pkg/cosign"
 	"github.com/verybluebot/tarinator-go"
 	"gopkg.in/yaml.v3"
 
@@ -25,6 +29,9 @@ import (
 )
 
 func TestAirgappedSuite(t *testing.T) {
+	// Don't wait between the retries of failed uploads
+	uploadRetryInitialDelay = time.Millisecond
+
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Airgapped package Suite")
 }
@@ -421,7 +428,7 @@ imagesToCopy:
 			// Verify the plugin bundle manifest file is accurate
 			bytes, err := os.ReadFile(filepath.Join(tempDir, PluginBundleDirName, PluginMigrationManifestFile))
 			Expect(err).NotTo(HaveOccurred())
-			Expect(string(bytes)).To(Equal(pluginBundleManifestCompleteRepositoryString))
+			Expect(manifestWithoutDigests(bytes, filepath.Join(tempDir, PluginBundleDirName))).To(Equal(pluginBundleManifestCompleteRepositoryString))
 			manifest := &PluginMigrationManifest{}
 			err = yaml.Unmarshal(bytes, &manifest)
 			Expect(err).NotTo(HaveOccurred())
@@ -456,7 +463,7 @@ imagesToCopy:
 			// Verify the plugin bundle manifest file is accurate
 			bytes, err := os.ReadFile(filepath.Join(tempDir, PluginBundleDirName, PluginMigrationManifestFile))
 			Expect(err).NotTo(HaveOccurred())
-			Expect(string(bytes)).To(Equal(pluginBundleManifestOCIImageOnlyString))
+			Expect(manifestWithoutDigests(bytes, filepath.Join(tempDir, PluginBundleDirName))).To(Equal(pluginBundleManifestOCIImageOnlyString))
 			manifest := &PluginMigrationManifest{}
 			err = yaml.Unmarshal(bytes, &manifest)
 			Expect(err).NotTo(HaveOccurred())
@@ -507,7 +514,7 @@ imagesToCopy:
 			// Verify the plugin bundle manifest file is accurate
 			bytes, err := os.ReadFile(filepath.Join(tempDir, PluginBundleDirName, PluginMigrationManifestFile))
 			Expect(err).NotTo(HaveOccurred())
-			Expect(string(bytes)).To(Equal(pluginBundleManifestDefaultGroupOnlyString))
+			Expect(manifestWithoutDigests(bytes, filepath.Join(tempDir, PluginBundleDirName))).To(Equal(pluginBundleManifestDefaultGroupOnlyString))
 			manifest := &PluginMigrationManifest{}
 			err = yaml.Unmarshal(bytes, &manifest)
 			Expect(err).NotTo(HaveOccurred())
@@ -542,7 +549,7 @@ imagesToCopy:
 			// Verify the plugin bundle manifest file is accurate
 			bytes, err := os.ReadFile(filepath.Join(tempDir, PluginBundleDirName, PluginMigrationManifestFile))
 			Expect(err).NotTo(HaveOccurred())
-			Expect(string(bytes)).To(Equal(pluginBundleManifestDefaultGroupAndFooPluginOnlyString))
+			Expect(manifestWithoutDigests(bytes, filepath.Join(tempDir, PluginBundleDirName))).To(Equal(pluginBundleManifestDefaultGroupAndFooPluginOnlyString))
 			manifest := &PluginMigrationManifest{}
 			err = yaml.Unmarshal(bytes, &manifest)
 			Expect(err).NotTo(HaveOccurred())
@@ -576,7 +583,7 @@ imagesToCopy:
 			// Verify the plugin bundle manifest file is accurate
 			bytes, err := os.ReadFile(filepath.Join(tempDir, PluginBundleDirName, PluginMigrationManifestFile))
 			Expect(err).NotTo(HaveOccurred())
-			Expect(string(bytes)).To(Equal(pluginBundleManifestFooPluginOnlyString))
+			Expect(manifestWithoutDigests(bytes, filepath.Join(tempDir, PluginBundleDirName))).To(Equal(pluginBundleManifestFooPluginOnlyString))
 			manifest := &PluginMigrationManifest{}
 			err = yaml.Unmarshal(bytes, &manifest)
 			Expect(err).NotTo(HaveOccurred())
@@ -610,7 +617,7 @@ imagesToCopy:
 			// Verify the plugin bundle manifest file is accurate
 			bytes, err := os.ReadFile(filepath.Join(tempDir, PluginBundleDirName, PluginMigrationManifestFile))
 			Expect(err).NotTo(HaveOccurred())
-			Expect(string(bytes)).To(Equal(pluginBundleManifestFooAndBarPluginOnlyString))
+			Expect(manifestWithoutDigests(bytes, filepath.Join(tempDir, PluginBundleDirName))).To(Equal(pluginBundleManifestFooAndBarPluginOnlyString))
 			manifest := &PluginMigrationManifest{}
 			err = yaml.Unmarshal(bytes, &manifest)
 			Expect(err).NotTo(HaveOccurred())
@@ -734,8 +741,898 @@ imagesToCopy:
 			Expect(err).NotTo(HaveOccurred())
 		})
 	})
+	var _ = Context("Tests for delta plugin bundles", func() {
+		// Delta plugin bundle manifest generated for the complete repository when the
+		// default plugin group and the essentials plugin group already exist
+		pluginBundleManifestDeltaString := `relativeInventoryImagePathWithTag: /plugin-inventory:latest
+inventoryMetadataImage:
+    sourceFilePath: plugin_inventory_metadata.db
+    relativeImagePathWithTag: /plugin-inventory-metadata:latest
+imagesToCopy:
+    - sourceTarFilePath: plugin-inventory-image.tar.gz
+      relativeImagePath: /plugin-inventory
+    - sourceTarFilePath: foo-global-darwin_amd64-v0.0.2.tar.gz
+      relativeImagePath: /path/darwin/amd64/global/foo
+    - sourceTarFilePath: foo-global-linux_amd64-v0.0.2.tar.gz
+      relativeImagePath: /path/linux/amd64/global/foo
+deltaBase:
+    plugins:
+        - bar@kubernetes:v0.0.1
+        - telemetry@global:v0.0.1
+    pluginGroups:
+        - fakevendor-fakepublisher/default:v1.0.0
+        - vmware-tanzucli/essentials:v0.0.1
+`
+
+		// downloadExistingMetadataStub fakes the image downloads and returns an inventory metadata
+		// database listing the specified plugins and plugin groups for the metadata image
+		downloadExistingMetadataStub := func(pis []*plugininventory.PluginIdentifier, pgis []*plugininventory.PluginGroupIdentifier) func(string, string) error {
+			return func(image, path string) error {
+				if !strings.Contains(image, "plugin-inventory-metadata") {
+					return downloadInventoryImageAndSaveFilesToDirStub(image, path)
+				}
+				dbFile := filepath.Join(path, plugininventory.SQliteInventoryMetadataDBFileName)
+				Expect(utils.SaveFile(dbFile, []byte{})).To(Succeed())
+				db := plugininventory.NewSQLiteInventoryMetadata(dbFile)
+				Expect(db.CreateInventoryMetadataDBSchema()).To(Succeed())
+				for _, pi := range pis {
+					Expect(db.InsertPluginIdentifier(pi)).To(Succeed())
+				}
+				for _, pgi := range pgis {
+					Expect(db.InsertPluginGroupIdentifier(pgi)).To(Succeed())
+				}
+				return nil
+			}
+		}
+		barPlugin := &plugininventory.PluginIdentifier{Name: "bar", Target: "kubernetes", Version: "v0.0.1"}
+		telemetryPlugin := &plugininventory.PluginIdentifier{Name: "telemetry", Target: "global", Version: "v0.0.1"}
+		defaultGroup := &plugininventory.PluginGroupIdentifier{Vendor: "fakevendor", Publisher: "fakepublisher", Name: "default", Version: "v1.0.0"}
+		essentialsGroup := &plugininventory.PluginGroupIdentifier{Vendor: "vmware", Publisher: "tanzucli", Name: "essentials", Version: "v0.0.1"}
+
+		// downloadBaseBundle downloads the plugin bundle of the default plugin group
+		downloadBaseBundle := func() string {
+			base := &DownloadPluginBundleOptions{
+				PluginInventoryImage: dpbo.PluginInventoryImage,
+				ToTar:                filepath.Join(tempTestDir, "base_plugin_bundle.tar"),
+				Groups:               []string{"fakevendor-fakepublisher/default:v1.0.0"},
+				ImageProcessor:       fakeImageOperations,
+			}
+			Expect(base.DownloadPluginBundle()).To(Succeed())
+			return base.ToTar
+		}
+
+		// verifyDeltaBundle verifies the manifest of the delta plugin bundle and
+		// returns the plugins listed in its inventory metadata
+		verifyDeltaBundle := func(expectedManifest string) []*plugininventory.PluginIdentifier {
+			tempDir, err := os.MkdirTemp("", "")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(tempDir)
+
+			Expect(tarinator.UnTarinate(tempDir, dpbo.ToTar)).To(Succeed())
+			bytes, err := os.ReadFile(filepath.Join(tempDir, PluginBundleDirName, PluginMigrationManifestFile))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(manifestWithoutDigests(bytes, filepath.Join(tempDir, PluginBundleDirName))).To(Equal(expectedManifest))
+
+			pis, err := plugininventory.NewSQLiteInventoryMetadata(filepath.Join(tempDir, PluginBundleDirName, plugininventory.SQliteInventoryMetadataDBFileName)).GetPluginIdentifiers()
+			Expect(err).NotTo(HaveOccurred())
+			return pis
+		}
+
+		BeforeEach(func() {
+			fakeImageOperations.DownloadImageAndSaveFilesToDirCalls(downloadInventoryImageAndSaveFilesToDirStub)
+			fakeImageOperations.CopyImageToTarCalls(copyImageToTarStub)
+		})
+
+		var _ = It("when a previous plugin bundle is specified, it should only download the plugins that are not part of it", func() {
+			dpbo.SinceBundle = downloadBaseBundle()
+
+			err := dpbo.DownloadPluginBundle()
+			Expect(err).NotTo(HaveOccurred())
+			pis := verifyDeltaBundle(pluginBundleManifestDeltaString)
+			Expect(pis).To(Equal([]*plugininventory.PluginIdentifier{{Name: "foo", Target: "global", Version: "v0.0.2"}}))
+		})
+
+		var _ = It("when the manifest of a previous plugin bundle is specified, it should only download the plugins that are not part of it", func() {
+			tempDir, err := os.MkdirTemp("", "")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(tempDir)
+			Expect(tarinator.UnTarinate(tempDir, downloadBaseBundle())).To(Succeed())
+			dpbo.SinceBundle = filepath.Join(tempDir, PluginBundleDirName, PluginMigrationManifestFile)
+
+			err = dpbo.DownloadPluginBundle()
+			Expect(err).NotTo(HaveOccurred())
+			verifyDeltaBundle(pluginBundleManifestDeltaString)
+		})
+
+		var _ = It("when a repository is specified, it should only download the plugins that are not published to it", func() {
+			fakeImageOperations.DownloadImageAndSaveFilesToDirCalls(downloadExistingMetadataStub(
+				[]*plugininventory.PluginIdentifier{barPlugin, telemetryPlugin},
+				[]*plugininventory.PluginGroupIdentifier{defaultGroup, essentialsGroup}))
+			dpbo.ExcludeExistingIn = "fake.newfakerepo.abc/plugin"
+
+			err := dpbo.DownloadPluginBundle()
+			Expect(err).NotTo(HaveOccurred())
+			verifyDeltaBundle(pluginBundleManifestDeltaString)
+
+			image, _ := fakeImageOperations.DownloadImageAndSaveFilesToDirArgsForCall(fakeImageOperations.DownloadImageAndSaveFilesToDirCallCount() - 1)
+			Expect(image).To(Equal("fake.newfakerepo.abc/plugin/plugin-inventory-metadata:latest"))
+		})
+
+		var _ = It("when the plugins published to the repository cannot be fetched, it should return an error", func() {
+			fakeImageOperations.DownloadImageAndSaveFilesToDirCalls(func(image, path string) error {
+				if strings.Contains(image, "plugin-inventory-metadata") {
+					return errors.New("fake error")
+				}
+				return downloadInventoryImageAndSaveFilesToDirStub(image, path)
+			})
+			dpbo.ExcludeExistingIn = "fake.newfakerepo.abc/plugin"
+
+			err := dpbo.DownloadPluginBundle()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("failed to download plugin inventory metadata image 'fake.newfakerepo.abc/plugin/plugin-inventory-metadata:latest'"))
+		})
+
+		var _ = It("when both a previous plugin bundle and a repository are specified, it should return an error", func() {
+			dpbo.SinceBundle = filepath.Join(tempTestDir, "base_plugin_bundle.tar")
+			dpbo.ExcludeExistingIn = "fake.newfakerepo.abc/plugin"
+
+			err := dpbo.DownloadPluginBundle()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("only one of the previous plugin bundle and the repository to exclude existing plugins from can be specified"))
+		})
+
+		var _ = It("when uploading a delta plugin bundle, it should verify that the content it builds upon exists in the destination repository", func() {
+			dpbo.SinceBundle = downloadBaseBundle()
+			Expect(dpbo.DownloadPluginBundle()).To(Succeed())
+			fakeImageOperations.CopyImageFromTarReturns(nil)
+			copyCount := fakeImageOperations.CopyImageFromTarCallCount()
+
+			// Nothing was published to the destination repository
+			fakeImageOperations.DownloadImageAndSaveFilesToDirReturns(errors.New("fake error"))
+			err := upbo.UploadPluginBundle()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`the delta plugin bundle cannot be applied to "fake.newfakerepo.abc/plugin" as no plugins were published to it`))
+
+			// Only part of the base content was published to the destination repository
+			fakeImageOperations.DownloadImageAndSaveFilesToDirCalls(downloadExistingMetadataStub(
+				[]*plugininventory.PluginIdentifier{barPlugin},
+				[]*plugininventory.PluginGroupIdentifier{defaultGroup}))
+			err = upbo.UploadPluginBundle()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("the following content it builds upon is missing: plugin telemetry@global:v0.0.1, plugin group vmware-tanzucli/essentials:v0.0.1"))
+			Expect(fakeImageOperations.CopyImageFromTarCallCount()).To(Equal(copyCount))
+
+			// The entire base content was published to the destination repository
+			fakeImageOperations.DownloadImageAndSaveFilesToDirCalls(downloadExistingMetadataStub(
+				[]*plugininventory.PluginIdentifier{barPlugin, telemetryPlugin},
+				[]*plugininventory.PluginGroupIdentifier{defaultGroup, essentialsGroup}))
+			err = upbo.UploadPluginBundle()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeImageOperations.CopyImageFromTarCallCount()).To(Equal(copyCount + 3))
+		})
+	})
+	var _ = Context("Tests for resumable and verifiable plugin bundles", func() {
+		const fakeDigest = "0123456789abcdef"
+
+		// readBundleManifest extracts the plugin bundle to the specified directory and returns its manifest
+		readBundleManifest := func(dir string) *PluginMigrationManifest {
+			Expect(tarinator.UnTarinate(dir, dpbo.ToTar)).To(Succeed())
+			manifest, err := readPluginMigrationManifest(filepath.Join(dir, PluginBundleDirName, PluginMigrationManifestFile))
+			Expect(err).NotTo(HaveOccurred())
+			return manifest
+		}
+
+		BeforeEach(func() {
+			fakeImageOperations.DownloadImageAndSaveFilesToDirCalls(downloadInventoryImageAndSaveFilesToDirStub)
+			fakeImageOperations.CopyImageToTarCalls(func(image, tarfile string) error {
+				return os.WriteFile(tarfile, []byte(image), 0644)
+			})
+			fakeImageOperations.GetImageDigestReturns("sha256", fakeDigest, nil)
+			fakeImageOperations.CopyImageFromTarReturns(nil)
+			Expect(dpbo.DownloadPluginBundle()).To(Succeed())
+
+			// Upload to a repository which already has some plugins
+			fakeImageOperations.DownloadImageAndSaveFilesToDirCalls(downloadInventoryMetadataImageWithExistingPlugins)
+			// The images don't exist in the destination repository
+			fakeImageOperations.GetImageDigestReturns("", "", errors.New("not found"))
+		})
+		AfterEach(func() {
+			fakeImageOperations.GetImageDigestReturns("", "", nil)
+			fakeImageOperations.CopyImageFromTarReturns(nil)
+		})
+
+		var _ = It("when downloading a plugin bundle, it should record the digests of the images", func() {
+			tempDir, err := os.MkdirTemp("", "")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(tempDir)
+
+			manifest := readBundleManifest(tempDir)
+			Expect(len(manifest.ImagesToCopy)).To(Equal(5))
+			for _, ic := range manifest.ImagesToCopy {
+				Expect(ic.ImageDigest).To(Equal("sha256:" + fakeDigest))
+				Expect(verifyFileDigest(filepath.Join(tempDir, PluginBundleDirName, ic.SourceTarFilePath), ic.SourceTarFileDigest)).To(Succeed())
+			}
+		})
+
+		var _ = It("when images already exist in the destination repository, it should not upload them", func() {
+			fakeImageOperations.GetImageDigestCalls(func(image string) (string, string, error) {
+				if strings.Contains(image, "/foo@sha256:"+fakeDigest) {
+					return "sha256", fakeDigest, nil
+				}
+				return "", "", errors.New("not found")
+			})
+			copyCount := fakeImageOperations.CopyImageFromTarCallCount()
+
+			Expect(upbo.UploadPluginBundle()).To(Succeed())
+			Expect(fakeImageOperations.CopyImageFromTarCallCount()).To(Equal(copyCount + 3))
+			for i := copyCount; i < fakeImageOperations.CopyImageFromTarCallCount(); i++ {
+				_, repoImagePath := fakeImageOperations.CopyImageFromTarArgsForCall(i)
+				Expect(repoImagePath).NotTo(HaveSuffix("/foo"))
+			}
+		})
+
+		var _ = It("when uploading an image fails temporarily, it should retry the upload", func() {
+			failures := 0
+			fakeImageOperations.CopyImageFromTarCalls(func(_, repoImagePath string) error {
+				if strings.HasSuffix(repoImagePath, "/bar") && failures < uploadAttempts-1 {
+					failures++
+					return errors.New("temporary error")
+				}
+				return nil
+			})
+			copyCount := fakeImageOperations.CopyImageFromTarCallCount()
+
+			Expect(upbo.UploadPluginBundle()).To(Succeed())
+			Expect(fakeImageOperations.CopyImageFromTarCallCount()).To(Equal(copyCount + 5 + uploadAttempts - 1))
+		})
+
+		var _ = It("when uploading images in parallel, it should upload all the images", func() {
+			upbo.Concurrency = 3
+			copyCount := fakeImageOperations.CopyImageFromTarCallCount()
+
+			Expect(upbo.UploadPluginBundle()).To(Succeed())
+			Expect(fakeImageOperations.CopyImageFromTarCallCount()).To(Equal(copyCount + 5))
+		})
+
+		var _ = It("when an upload is interrupted, it should resume the upload", func() {
+			fakeImageOperations.CopyImageFromTarCalls(func(_, repoImagePath string) error {
+				if strings.HasSuffix(repoImagePath, "/telemetry") {
+					return errors.New("fake error")
+				}
+				return nil
+			})
+			err := upbo.UploadPluginBundle()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`error while uploading image "fake.newfakerepo.abc/plugin/path/darwin/amd64/global/telemetry"`))
+
+			// The upload state lists the uploaded images
+			bytes, err := os.ReadFile(upbo.Tar + PluginBundleUploadStateFileSuffix)
+			Expect(err).NotTo(HaveOccurred())
+			state := &PluginBundleUploadState{}
+			Expect(yaml.Unmarshal(bytes, state)).To(Succeed())
+			Expect(state.DestinationRepo).To(Equal(upbo.DestinationRepo))
+			Expect(len(state.UploadedImages)).To(Equal(4))
+
+			// Only the image that failed is uploaded when resuming the upload
+			fakeImageOperations.CopyImageFromTarReturns(nil)
+			copyCount := fakeImageOperations.CopyImageFromTarCallCount()
+			Expect(upbo.UploadPluginBundle()).To(Succeed())
+			Expect(fakeImageOperations.CopyImageFromTarCallCount()).To(Equal(copyCount + 1))
+			Expect(utils.PathExists(upbo.Tar + PluginBundleUploadStateFileSuffix)).To(BeFalse())
+
+			// The upload state of a different destination repository is ignored
+			Expect(os.WriteFile(upbo.Tar+PluginBundleUploadStateFileSuffix, bytes, 0644)).To(Succeed())
+			upbo.DestinationRepo = "fake.otherfakerepo.abc/plugin"
+			copyCount = fakeImageOperations.CopyImageFromTarCallCount()
+			Expect(upbo.UploadPluginBundle()).To(Succeed())
+			Expect(fakeImageOperations.CopyImageFromTarCallCount()).To(Equal(copyCount + 5))
+		})
+
+		var _ = It("when verifying a plugin bundle, it should detect missing and corrupted files", func() {
+			vpbo := &VerifyPluginBundleOptions{Tar: dpbo.ToTar}
+			Expect(vpbo.VerifyPluginBundle()).To(Succeed())
+
+			// Corrupt one image archive and remove another
+			tempDir, err := os.MkdirTemp("", "")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(tempDir)
+			manifest := readBundleManifest(tempDir)
+			pluginBundleDir := filepath.Join(tempDir, PluginBundleDirName)
+			Expect(os.WriteFile(filepath.Join(pluginBundleDir, manifest.ImagesToCopy[1].SourceTarFilePath), []byte("corrupted"), 0644)).To(Succeed())
+			Expect(os.Remove(filepath.Join(pluginBundleDir, manifest.ImagesToCopy[2].SourceTarFilePath))).To(Succeed())
+			vpbo.Tar = filepath.Join(tempTestDir, "corrupted_plugin_bundle.tar")
+			Expect(tarinator.Tarinate([]string{pluginBundleDir}, vpbo.Tar)).To(Succeed())
+
+			err = vpbo.VerifyPluginBundle()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("the file %q of the plugin bundle is corrupted", manifest.ImagesToCopy[1].SourceTarFilePath)))
+			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("the file %q is missing from the plugin bundle", manifest.ImagesToCopy[2].SourceTarFilePath)))
+
+			// Corrupted images are not uploaded
+			upbo.Tar = vpbo.Tar
+			err = upbo.UploadPluginBundle()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Or(ContainSubstring("is corrupted"), ContainSubstring("is missing from the plugin bundle")))
+		})
+	})
+
+	var _ = Context("Tests for plugin bundles saved as OCI image layouts", func() {
+		BeforeEach(func() {
+			fakeImageOperations.CopyImageToTarCalls(copyImageToTarStub)
+			// The plugin inventory metadata image does not exist in a new OCI image layout
+			fakeImageOperations.DownloadImageAndSaveFilesToDirCalls(func(image, path string) error {
+				if strings.Contains(image, "plugin-inventory-metadata") {
+					return errors.New("not found")
+				}
+				return downloadInventoryImageAndSaveFilesToDirStub(image, path)
+			})
+			dpbo.ToTar = ""
+			dpbo.ToOCILayout = filepath.Join(tempTestDir, "plugins")
+		})
+
+		var _ = It("when an OCI image layout directory is specified, it should publish the plugin bundle to it", func() {
+			copyCount := fakeImageOperations.CopyImageFromTarCallCount()
+			pushCount := fakeImageOperations.PushImageCallCount()
+
+			Expect(dpbo.DownloadPluginBundle()).To(Succeed())
+			Expect(utils.PathExists(filepath.Join(dpbo.ToOCILayout, "oci-layout"))).To(BeTrue())
+
+			layoutRepo := "file://" + filepath.ToSlash(dpbo.ToOCILayout)
+			Expect(fakeImageOperations.CopyImageFromTarCallCount()).To(Equal(copyCount + 5))
+			for i := copyCount; i < fakeImageOperations.CopyImageFromTarCallCount(); i++ {
+				_, repoImagePath := fakeImageOperations.CopyImageFromTarArgsForCall(i)
+				Expect(repoImagePath).To(HavePrefix(layoutRepo + "/"))
+			}
+			Expect(fakeImageOperations.PushImageCallCount()).To(Equal(pushCount + 1))
+			metadataImage, _ := fakeImageOperations.PushImageArgsForCall(pushCount)
+			Expect(metadataImage).To(Equal(layoutRepo + "/plugin-inventory-metadata:latest"))
+		})
+
+		var _ = It("when both a tar file and an OCI image layout directory are specified, it should return an error", func() {
+			dpbo.ToTar = filepath.Join(tempTestDir, "plugin_bundle.tar")
+
+			err := dpbo.DownloadPluginBundle()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("only one of the tar file and the OCI image layout directory"))
+		})
+	})
+
+	var _ = Context("Tests for mirroring plugins", func() {
+		var (
+			mpo               *MirrorPluginsOptions
+			copiedImages      map[string]bool
+			publishedMetadata string
+		)
+
+		const sourceRepo = "fake.fakerepo.abc/plugin"
+
+		BeforeEach(func() {
+			mpo = &MirrorPluginsOptions{
+				PluginInventoryImage: dpbo.PluginInventoryImage,
+				DestinationRepo:      upbo.DestinationRepo,
+				ImageProcessor:       fakeImageOperations,
+			}
+			copiedImages = map[string]bool{}
+			publishedMetadata = ""
+
+			fakeImageOperations.CopyImageToTarCalls(copyImageToTarStub)
+			copyImageStub := func(_, repoImagePath string) error {
+				copiedImages[strings.TrimPrefix(repoImagePath, mpo.DestinationRepo)] = true
+				return nil
+			}
+			fakeImageOperations.CopyImageCalls(copyImageStub)
+			fakeImageOperations.CopyImageFromTarCalls(copyImageStub)
+			// The digest of an image only depends on its path, and the destination
+			// repository only has the images that were copied to it
+			fakeImageOperations.GetImageDigestCalls(func(image string) (string, string, error) {
+				relativeImage := strings.TrimPrefix(image, sourceRepo)
+				if strings.HasPrefix(image, mpo.DestinationRepo) {
+					relativeImage = strings.TrimPrefix(image, mpo.DestinationRepo)
+					if !copiedImages[GetImageRelativePath(relativeImage, "", false)] {
+						return "", "", errors.New("not found")
+					}
+				}
+				return "sha256", "digest-of-" + relativeImage, nil
+			})
+			fakeImageOperations.PushImageCalls(func(_ string, filePaths []string) error {
+				bytes, err := os.ReadFile(filePaths[0])
+				Expect(err).NotTo(HaveOccurred())
+				publishedMetadata = filepath.Join(tempTestDir, "published_metadata.db")
+				return os.WriteFile(publishedMetadata, bytes, 0644)
+			})
+			fakeImageOperations.DownloadImageAndSaveFilesToDirCalls(func(image, path string) error {
+				if !strings.HasPrefix(image, mpo.DestinationRepo) {
+					return downloadInventoryImageAndSaveFilesToDirStub(image, path)
+				}
+				if publishedMetadata == "" {
+					return errors.New("not found")
+				}
+				bytes, err := os.ReadFile(publishedMetadata)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(os.MkdirAll(path, 0755)).To(Succeed())
+				return os.WriteFile(filepath.Join(path, plugininventory.SQliteInventoryMetadataDBFileName), bytes, 0644)
+			})
+		})
+		AfterEach(func() {
+			fakeImageOperations.CopyImageCalls(nil)
+			fakeImageOperations.CopyImageFromTarCalls(nil)
+			fakeImageOperations.GetImageDigestCalls(nil)
+			fakeImageOperations.PushImageCalls(nil)
+		})
+
+		var _ = It("when using the dry run option, it should list the missing content without copying anything", func() {
+			mpo.DryRun = true
+			copyCount := fakeImageOperations.CopyImageCallCount()
+
+			diff, err := mpo.MirrorPlugins()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff.Plugins).To(Equal([]string{"bar@kubernetes:v0.0.1", "foo@global:v0.0.2", "telemetry@global:v0.0.1"}))
+			Expect(diff.PluginGroups).To(Equal([]string{"fakevendor-fakepublisher/default2:v1.0.0", "fakevendor-fakepublisher/default:v1.0.0", "vmware-tanzucli/essentials:v0.0.1"}))
+			Expect(diff.Images).To(Equal([]string{
+				"fake.newfakerepo.abc/plugin/path/darwin/amd64/global/foo:v0.0.2",
+				"fake.newfakerepo.abc/plugin/path/darwin/amd64/global/telemetry:v0.0.1",
+				"fake.newfakerepo.abc/plugin/path/darwin/amd64/kubernetes/bar:v0.0.1",
+				"fake.newfakerepo.abc/plugin/path/linux/amd64/global/foo:v0.0.2",
+				"fake.newfakerepo.abc/plugin/plugin-inventory:latest",
+			}))
+			Expect(fakeImageOperations.CopyImageCallCount()).To(Equal(copyCount))
+			Expect(copiedImages).To(BeEmpty())
+			Expect(publishedMetadata).To(BeEmpty())
+		})
+
+		var _ = It("when mirroring plugins, it should copy the missing images and merge the plugin inventory metadata, and do nothing when mirroring again", func() {
+			tarCopyCount := fakeImageOperations.CopyImageToTarCallCount()
+			diff, err := mpo.MirrorPlugins()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff.Images).To(HaveLen(5))
+			Expect(copiedImages).To(HaveLen(5))
+			// The images are copied directly from registry to registry
+			Expect(fakeImageOperations.CopyImageToTarCallCount()).To(Equal(tarCopyCount))
+			Expect(copiedImages).To(HaveKey("/plugin-inventory"))
+			Expect(publishedMetadata).NotTo(BeEmpty())
+			existing, err := readExistingContent(publishedMetadata, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(existing.plugins).To(HaveLen(3))
+			Expect(existing.pluginGroups).To(HaveLen(3))
+
+			copyCount := fakeImageOperations.CopyImageCallCount()
+			pushCount := fakeImageOperations.PushImageCallCount()
+			diff, err = mpo.MirrorPlugins()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff.Plugins).To(BeEmpty())
+			Expect(diff.PluginGroups).To(BeEmpty())
+			Expect(diff.Images).To(BeEmpty())
+			Expect(fakeImageOperations.CopyImageCallCount()).To(Equal(copyCount))
+			Expect(fakeImageOperations.PushImageCallCount()).To(Equal(pushCount))
+		})
+
+		var _ = It("when a group is specified, it should only mirror the plugins of the group and keep the already mirrored ones", func() {
+			mpo.Groups = []string{"fakevendor-fakepublisher/default:v1.0.0"}
+			_, err := mpo.MirrorPlugins()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(copiedImages).To(HaveLen(3))
+			Expect(copiedImages).To(HaveKey("/path/darwin/amd64/kubernetes/bar"))
+			Expect(copiedImages).To(HaveKey("/path/darwin/amd64/global/telemetry"))
+
+			mpo.Groups = nil
+			diff, err := mpo.MirrorPlugins()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff.Plugins).To(Equal([]string{"foo@global:v0.0.2"}))
+			Expect(diff.PluginGroups).To(Equal([]string{"fakevendor-fakepublisher/default2:v1.0.0"}))
+			Expect(copiedImages).To(HaveLen(5))
+			existing, err := readExistingContent(publishedMetadata, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(existing.plugins).To(HaveLen(3))
+			Expect(existing.pluginGroups).To(HaveLen(3))
+		})
+
+		var _ = It("when the destination repository is on the local filesystem, it should copy the images through tar files", func() {
+			mpo.DestinationRepo = "file://" + filepath.Join(tempTestDir, "mirror")
+			copyCount := fakeImageOperations.CopyImageCallCount()
+			tarCopyCount := fakeImageOperations.CopyImageFromTarCallCount()
+
+			_, err := mpo.MirrorPlugins()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(copiedImages).To(HaveLen(5))
+			Expect(fakeImageOperations.CopyImageCallCount()).To(Equal(copyCount))
+			Expect(fakeImageOperations.CopyImageFromTarCallCount()).To(Equal(tarCopyCount + 5))
+		})
+	})
+
+	var _ = Context("Tests for inspecting plugin bundles", func() {
+		BeforeEach(func() {
+			fakeImageOperations.CopyImageToTarCalls(func(image, tarfile string) error {
+				return os.WriteFile(tarfile, []byte(image), 0644)
+			})
+			fakeImageOperations.DownloadImageAndSaveFilesToDirCalls(func(image, path string) error {
+				if strings.HasPrefix(image, "file://") {
+					Expect(image).To(HaveSuffix("/" + PluginBundleDirName + "/plugin-inventory:latest"))
+					Expect(os.MkdirAll(path, 0755)).To(Succeed())
+					Expect(os.WriteFile(filepath.Join(path, constants.CentralConfigFileName), []byte("cli.core.tanzu_cli_config_endpoint_update_version: v2\n"), 0644)).To(Succeed())
+				}
+				return downloadInventoryImageAndSaveFilesToDirStub(image, path)
+			})
+		})
+
+		var _ = It("when inspecting a plugin bundle, it should list its plugins, plugin groups and central configuration", func() {
+			Expect(dpbo.DownloadPluginBundle()).To(Succeed())
+
+			ipbo := &InspectPluginBundleOptions{Tar: dpbo.ToTar, ImageProcessor: fakeImageOperations}
+			contents, err := ipbo.InspectPluginBundle()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(contents.PluginInventoryImage).To(Equal("/plugin-inventory:latest"))
+			Expect(contents.CentralConfig).NotTo(BeNil())
+			Expect(contents.CentralConfig.Version).To(Equal("v2"))
+			Expect(contents.CentralConfig.Digest).To(HavePrefix("sha256:"))
+			Expect(contents.Signed).To(BeFalse())
+
+			var plugins []string
+			for _, p := range contents.Plugins {
+				plugins = append(plugins, fmt.Sprintf("%s@%s:%s %s/%s", p.Name, p.Target, p.Version, p.OS, p.Arch))
+				Expect(p.Size).To(BeNumerically(">", 0))
+			}
+			Expect(plugins).To(Equal([]string{
+				"bar@kubernetes:v0.0.1 darwin/amd64",
+				"foo@global:v0.0.2 darwin/amd64",
+				"foo@global:v0.0.2 linux/amd64",
+				"telemetry@global:v0.0.1 darwin/amd64",
+			}))
+			Expect(contents.PluginGroups).To(Equal([]*BundledPluginGroup{
+				{ID: "fakevendor-fakepublisher/default2:v1.0.0", Plugins: 1},
+				{ID: "fakevendor-fakepublisher/default:v1.0.0", Plugins: 1},
+				{ID: "vmware-tanzucli/essentials:v0.0.1", Plugins: 1},
+			}))
+			Expect(contents.Size).To(BeNumerically(">", 0))
+		})
+
+		var _ = It("when comparing two plugin bundles, it should list the added and removed plugins and plugin groups", func() {
+			dpbo.Groups = []string{"fakevendor-fakepublisher/default:v1.0.0"}
+			Expect(dpbo.DownloadPluginBundle()).To(Succeed())
+			groupBundle := dpbo.ToTar
+
+			dpbo.Groups = nil
+			dpbo.ToTar = filepath.Join(tempTestDir, "plugin_bundle_complete.tar")
+			Expect(dpbo.DownloadPluginBundle()).To(Succeed())
+
+			diff, err := DiffPluginBundles(groupBundle, dpbo.ToTar)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff).To(Equal(&PluginBundleDiff{
+				AddedPlugins:        []string{"foo@global:v0.0.2"},
+				RemovedPlugins:      []string{},
+				AddedPluginGroups:   []string{"fakevendor-fakepublisher/default2:v1.0.0"},
+				RemovedPluginGroups: []string{},
+			}))
+
+			diff, err = DiffPluginBundles(dpbo.ToTar, groupBundle)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff.AddedPlugins).To(BeEmpty())
+			Expect(diff.RemovedPlugins).To(Equal([]string{"foo@global:v0.0.2"}))
+			Expect(diff.RemovedPluginGroups).To(Equal([]string{"fakevendor-fakepublisher/default2:v1.0.0"}))
+
+			_, err = DiffPluginBundles(groupBundle, filepath.Join(tempTestDir, "does_not_exist.tar"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unable to extract the plugin bundle"))
+		})
+	})
+
+	var _ = Context("Tests for signed plugin bundles", func() {
+		var signingKey, verificationKey string
+
+		// generateKeyPair generates a cosign key pair in the specified directory
+		generateKeyPair := func(dir string) (string, string) {
+			keys, err := cosign.GenerateKeyPair(func(bool) ([]byte, error) { return []byte{}, nil })
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+			privateKey := filepath.Join(dir, "cosign.key")
+			publicKey := filepath.Join(dir, "cosign.pub")
+			Expect(os.WriteFile(privateKey, keys.PrivateBytes, 0600)).To(Succeed())
+			Expect(os.WriteFile(publicKey, keys.PublicBytes, 0644)).To(Succeed())
+			return privateKey, publicKey
+		}
+
+		// tamperBundle replaces the plugin migration manifest of the plugin bundle and
+		// returns the path of the tampered plugin bundle
+		tamperBundle := func(modify func(manifest []byte) []byte) string {
+			tempDir, err := os.MkdirTemp("", "")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(tempDir)
+			Expect(tarinator.UnTarinate(tempDir, dpbo.ToTar)).To(Succeed())
+			pluginBundleDir := filepath.Join(tempDir, PluginBundleDirName)
+			manifestFile := filepath.Join(pluginBundleDir, PluginMigrationManifestFile)
+			manifest, err := os.ReadFile(manifestFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.WriteFile(manifestFile, modify(manifest), 0644)).To(Succeed())
+			tamperedTar := filepath.Join(tempTestDir, "tampered_plugin_bundle.tar")
+			Expect(tarinator.Tarinate([]string{pluginBundleDir}, tamperedTar)).To(Succeed())
+			return tamperedTar
+		}
+
+		BeforeEach(func() {
+			os.Setenv("COSIGN_PASSWORD", "")
+			signingKey, verificationKey = generateKeyPair(filepath.Join(tempTestDir, "keys"))
+			fakeImageOperations.DownloadImageAndSaveFilesToDirCalls(downloadInventoryImageAndSaveFilesToDirStub)
+			fakeImageOperations.CopyImageToTarCalls(copyImageToTarStub)
+			dpbo.SigningKey = signingKey
+		})
+		AfterEach(func() {
+			os.Unsetenv("COSIGN_PASSWORD")
+		})
+
+		var _ = It("when a signing key is specified, it should sign the plugin bundle and the signature should be verified before uploading it", func() {
+			Expect(dpbo.DownloadPluginBundle()).To(Succeed())
+
+			vpbo := &VerifyPluginBundleOptions{Tar: dpbo.ToTar, VerificationKey: verificationKey}
+			Expect(vpbo.VerifyPluginBundle()).To(Succeed())
+
+			fakeImageOperations.DownloadImageAndSaveFilesToDirCalls(downloadInventoryMetadataImageWithNoExistingPlugins)
+			copyCount := fakeImageOperations.CopyImageFromTarCallCount()
+			upbo.VerificationKey = verificationKey
+			Expect(upbo.UploadPluginBundle()).To(Succeed())
+			Expect(fakeImageOperations.CopyImageFromTarCallCount()).To(Equal(copyCount + 5))
+		})
+
+		var _ = It("when the plugin migration manifest was altered, it should not upload anything", func() {
+			Expect(dpbo.DownloadPluginBundle()).To(Succeed())
+			tamperedTar := tamperBundle(func(manifest []byte) []byte {
+				return []byte(strings.Replace(string(manifest), "/path/linux/amd64/global/foo", "/path/linux/amd64/global/evil", 1))
+			})
+
+			vpbo := &VerifyPluginBundleOptions{Tar: tamperedTar, VerificationKey: verificationKey}
+			err := vpbo.VerifyPluginBundle()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("the signature of the plugin migration manifest is invalid"))
+
+			copyCount := fakeImageOperations.CopyImageFromTarCallCount()
+			pushCount := fakeImageOperations.PushImageCallCount()
+			upbo.Tar = tamperedTar
+			upbo.VerificationKey = verificationKey
+			err = upbo.UploadPluginBundle()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("the signature of the plugin migration manifest is invalid"))
+			Expect(fakeImageOperations.CopyImageFromTarCallCount()).To(Equal(copyCount))
+			Expect(fakeImageOperations.PushImageCallCount()).To(Equal(pushCount))
+		})
+
+		var _ = It("when the plugin bundle is not signed or signed with another key, it should return an error", func() {
+			dpbo.SigningKey = ""
+			Expect(dpbo.DownloadPluginBundle()).To(Succeed())
+			vpbo := &VerifyPluginBundleOptions{Tar: dpbo.ToTar, VerificationKey: verificationKey}
+			err := vpbo.VerifyPluginBundle()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("the plugin bundle is not signed"))
+
+			Expect(os.Remove(dpbo.ToTar)).To(Succeed())
+			dpbo.SigningKey, _ = generateKeyPair(filepath.Join(tempTestDir, "otherkeys"))
+			Expect(dpbo.DownloadPluginBundle()).To(Succeed())
+			err = vpbo.VerifyPluginBundle()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("the signature of the plugin migration manifest is invalid"))
+		})
+
+		var _ = It("when an SBOM format is specified, it should include the SBOM of the plugins in the signed plugin bundle", func() {
+			for format, expectedContent := range map[string]string{
+				SBOMFormatSPDX:      `"spdxVersion": "SPDX-2.3"`,
+				SBOMFormatCycloneDX: `"bomFormat": "CycloneDX"`,
+			} {
+				dpbo.SBOMFormat = format
+				Expect(os.RemoveAll(dpbo.ToTar)).To(Succeed())
+				Expect(dpbo.DownloadPluginBundle()).To(Succeed())
+
+				tempDir, err := os.MkdirTemp("", "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(tarinator.UnTarinate(tempDir, dpbo.ToTar)).To(Succeed())
+				pluginBundleDir := filepath.Join(tempDir, PluginBundleDirName)
+				manifest, err := readPluginMigrationManifest(filepath.Join(pluginBundleDir, PluginMigrationManifestFile))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(manifest.SBOM).NotTo(BeNil())
+				Expect(manifest.SBOM.SourceFilePath).To(Equal(sbomFileNames[format]))
+				sbom, err := os.ReadFile(filepath.Join(pluginBundleDir, manifest.SBOM.SourceFilePath))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(sbom)).To(ContainSubstring(expectedContent))
+				Expect(string(sbom)).To(ContainSubstring(`"name": "foo"`))
+				Expect(string(sbom)).To(ContainSubstring(`"name": "bar"`))
+				os.RemoveAll(tempDir)
+
+				// The SBOM is covered by the signature of the plugin migration manifest
+				vpbo := &VerifyPluginBundleOptions{Tar: dpbo.ToTar, VerificationKey: verificationKey}
+				Expect(vpbo.VerifyPluginBundle()).To(Succeed())
+			}
+
+			dpbo.SBOMFormat = "invalid"
+			err := dpbo.DownloadPluginBundle()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`unsupported SBOM format "invalid"`))
+		})
+
+		var _ = It("when signing a plugin bundle saved as an OCI image layout, it should return an error", func() {
+			dpbo.ToTar = ""
+			dpbo.ToOCILayout = filepath.Join(tempTestDir, "plugins")
+			err := dpbo.DownloadPluginBundle()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("only supported when saving the plugin bundle to a tar file"))
+		})
+	})
+
+	var _ = Context("Tests for filtering the plugins of a plugin bundle", func() {
+		BeforeEach(func() {
+			fakeImageOperations.DownloadImageAndSaveFilesToDirCalls(downloadInventoryImageAndSaveFilesToDirStub)
+			fakeImageOperations.CopyImageToTarCalls(copyImageToTarStub)
+		})
+
+		var _ = It("when platforms are specified, it should only download the plugin binaries of these platforms", func() {
+			dpbo.OSArch = []string{"linux/amd64"}
+			Expect(dpbo.DownloadPluginBundle()).To(Succeed())
+
+			tempDir, err := os.MkdirTemp("", "")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(tempDir)
+			Expect(tarinator.UnTarinate(tempDir, dpbo.ToTar)).To(Succeed())
+			pluginBundleDir := filepath.Join(tempDir, PluginBundleDirName)
+
+			bytes, err := os.ReadFile(filepath.Join(pluginBundleDir, PluginMigrationManifestFile))
+			Expect(err).NotTo(HaveOccurred())
+			manifest := &PluginMigrationManifest{}
+			Expect(yaml.Unmarshal(bytes, &manifest)).To(Succeed())
+			var tarFiles []string
+			for _, ic := range manifest.ImagesToCopy {
+				tarFiles = append(tarFiles, ic.SourceTarFilePath)
+			}
+			Expect(tarFiles).To(Equal([]string{"plugin-inventory-image.tar.gz", "foo-global-linux_amd64-v0.0.2.tar.gz"}))
+
+			// The plugin inventory updated with the inventory metadata of the plugin
+			// bundle should only refer to the plugin binaries that were bundled
+			inventoryDir := filepath.Join(tempDir, "inventory")
+			Expect(downloadInventoryImageAndSaveFilesToDirStub("", inventoryDir)).To(Succeed())
+			inventoryFile := filepath.Join(inventoryDir, plugininventory.SQliteDBFileName)
+			metadataDB := plugininventory.NewSQLiteInventoryMetadata(filepath.Join(pluginBundleDir, manifest.InventoryMetadataImage.SourceFilePath))
+			Expect(metadataDB.UpdatePluginInventoryDatabase(inventoryFile)).To(Succeed())
+			pluginEntries, err := plugininventory.NewSQLiteInventory(inventoryFile, "").GetAllPlugins()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pluginEntries).To(HaveLen(1))
+			Expect(pluginEntries[0].Name).To(Equal("foo"))
+			Expect(pluginEntries[0].Artifacts["v0.0.2"]).To(HaveLen(1))
+			Expect(pluginEntries[0].Artifacts["v0.0.2"][0].OS).To(Equal("linux"))
+		})
+
+		var _ = It("when platforms are specified with plugin groups, it should only download the plugin binaries of these platforms", func() {
+			dpbo.Groups = []string{"fakevendor-fakepublisher/default:v1.0.0"}
+			dpbo.Plugins = []string{"foo"}
+			dpbo.OSArch = []string{"linux/amd64", "darwin/arm64"}
+			dpbo.ToTar = ""
+			dpbo.DryRun = true
+
+			// The plugins of the plugin groups without any binary for these platforms are not downloaded
+			pluginEntries, pluginGroups, err := dpbo.getSelectedPluginInfo()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pluginGroups).To(HaveLen(2))
+			Expect(pluginEntries).To(HaveLen(1))
+			Expect(pluginEntries[0].Name).To(Equal("foo"))
+			Expect(pluginEntries[0].Artifacts["v0.0.2"]).To(HaveLen(1))
+			Expect(pluginEntries[0].Artifacts["v0.0.2"][0].OS).To(Equal("linux"))
+		})
+
+		var _ = It("when invalid filters are specified, it should return an error", func() {
+			dpbo.OSArch = []string{"linux"}
+			err := dpbo.DownloadPluginBundle()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`invalid platform "linux"`))
+
+			dpbo.OSArch = nil
+			dpbo.LatestNVersions = -1
+			err = dpbo.DownloadPluginBundle()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid number of latest versions -1"))
+
+			dpbo.LatestNVersions = 0
+			dpbo.SinceVersion = "latest"
+			err = dpbo.DownloadPluginBundle()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`invalid version "latest"`))
+		})
+
+		var _ = It("when version filters are specified, it should only keep the selected versions of the plugins", func() {
+			newPluginEntry := func() *plugininventory.PluginInventoryEntry {
+				pe := &plugininventory.PluginInventoryEntry{
+					Name:               "foo",
+					Target:             "global",
+					RecommendedVersion: "v2.0.0",
+					Artifacts:          distribution.Artifacts{},
+				}
+				for _, v := range []string{"v1.0.0", "v1.1.0", "v2.0.0", "v2.1.0"} {
+					pe.Artifacts[v] = distribution.ArtifactList{{OS: "linux", Arch: "amd64", Image: "path/linux/amd64/global/foo:" + v}}
+				}
+				return pe
+			}
+			versions := func(pe *plugininventory.PluginInventoryEntry) []string {
+				var vs []string
+				for v := range pe.Artifacts {
+					vs = append(vs, v)
+				}
+				Expect(utils.SortVersions(vs)).To(Succeed())
+				return vs
+			}
+
+			pe := newPluginEntry()
+			dpbo.LatestNVersions = 1
+			Expect(dpbo.filterPluginVersions([]*plugininventory.PluginInventoryEntry{pe})).To(Succeed())
+			Expect(versions(pe)).To(Equal([]string{"v2.0.0", "v2.1.0"}))
+
+			pe = newPluginEntry()
+			dpbo.LatestNVersions = 3
+			Expect(dpbo.filterPluginVersions([]*plugininventory.PluginInventoryEntry{pe})).To(Succeed())
+			Expect(versions(pe)).To(Equal([]string{"v1.1.0", "v2.0.0", "v2.1.0"}))
+
+			pe = newPluginEntry()
+			dpbo.LatestNVersions = 0
+			dpbo.SinceVersion = "v1.1.0"
+			Expect(dpbo.filterPluginVersions([]*plugininventory.PluginInventoryEntry{pe})).To(Succeed())
+			Expect(versions(pe)).To(Equal([]string{"v1.1.0", "v2.0.0", "v2.1.0"}))
+
+			pe = newPluginEntry()
+			dpbo.SinceVersion = "v2.1.0"
+			Expect(dpbo.filterPluginVersions([]*plugininventory.PluginInventoryEntry{pe})).To(Succeed())
+			Expect(versions(pe)).To(Equal([]string{"v2.1.0"}))
+		})
+
+		var _ = It("when version filters are specified with a plugin without version, it should consider all the versions of the plugin", func() {
+			dpbo.Plugins = []string{"foo@global"}
+			dpbo.SinceVersion = "v0.0.1"
+			dpbo.ToTar = ""
+			dpbo.DryRun = true
+
+			pluginEntries, _, err := dpbo.getSelectedPluginInfo()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pluginEntries).To(HaveLen(2))
+
+			dpbo.Plugins = []string{"foo@global"}
+			dpbo.SinceVersion = "v0.0.3"
+			_, _, err = dpbo.getSelectedPluginInfo()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`no versions of plugin "foo@global" match the version filters`))
+		})
+
+		var _ = It("when version filters are specified without plugin groups, it should only keep the plugin group versions whose plugins are all bundled", func() {
+			dpbo.ToTar = ""
+			dpbo.DryRun = true
+
+			dpbo.SinceVersion = "v0.0.1"
+			pluginEntries, pluginGroups, err := dpbo.getSelectedPluginInfo()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pluginEntries).To(HaveLen(3))
+			Expect(pluginGroups).To(HaveLen(3))
+
+			// The versions of bar and telemetry, which are the only plugins of the
+			// plugin groups, are filtered out
+			dpbo.SinceVersion = "v0.0.2"
+			pluginEntries, pluginGroups, err = dpbo.getSelectedPluginInfo()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pluginEntries).To(HaveLen(1))
+			Expect(pluginEntries[0].Name).To(Equal("foo"))
+			Expect(pluginGroups).To(BeEmpty())
+		})
+	})
 })
 
+// manifestWithoutDigests verifies that the digests recorded in the plugin migration
+// manifest match the files of the plugin bundle, and returns the manifest without
+// the digests
+func manifestWithoutDigests(manifestBytes []byte, pluginBundleDir string) string {
+	manifest := &PluginMigrationManifest{}
+	Expect(yaml.Unmarshal(manifestBytes, &manifest)).To(Succeed())
+	for _, ic := range manifest.ImagesToCopy {
+		Expect(verifyFileDigest(filepath.Join(pluginBundleDir, ic.SourceTarFilePath), ic.SourceTarFileDigest)).To(Succeed())
+		Expect(ic.SourceTarFileDigest).NotTo(BeEmpty())
+		ic.SourceTarFileDigest = ""
+	}
+	Expect(verifyFileDigest(filepath.Join(pluginBundleDir, manifest.InventoryMetadataImage.SourceFilePath), manifest.InventoryMetadataImage.SourceFileDigest)).To(Succeed())
+	Expect(manifest.InventoryMetadataImage.SourceFileDigest).NotTo(BeEmpty())
+	manifest.InventoryMetadataImage.SourceFileDigest = ""
+
+	bytes, err := yaml.Marshal(manifest)
+	Expect(err).NotTo(HaveOccurred())
+	return string(bytes)
+}
+
 // Create incorrect plugin bundle tar file with empty content
 func createIncorrectPluginBundleTarFile(dir string) string {
 	tarFile := filepath.Join(dir, "incorrect-plugin-bundle.tar")
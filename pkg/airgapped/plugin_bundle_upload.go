@@ -4,10 +4,16 @@
 package airgapped
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
 
 	"github.com/verybluebot/tarinator-go"
 
@@ -15,20 +21,52 @@ import (
 
 	"github.com/vmware-tanzu/tanzu-cli/pkg/carvelhelpers"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/plugininventory"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/registry"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/utils"
 	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
 )
 
+// These variables can be replaced in tests
+var (
+	// uploadAttempts is the number of attempts made to upload an image
+	uploadAttempts = 3
+	// uploadRetryInitialDelay is the delay before the first retry of an
+	// upload. The delay doubles with each retry.
+	uploadRetryInitialDelay = 2 * time.Second
+)
+
 // UploadPluginBundleOptions defines options for uploading plugin bundle
 type UploadPluginBundleOptions struct {
 	Tar             string
 	DestinationRepo string
+	// Concurrency is the number of images uploaded in parallel
+	Concurrency int
+	// VerificationKey is the cosign public key used to verify the signature of
+	// the plugin bundle before publishing anything. The signature is not
+	// verified if empty.
+	VerificationKey string
 
 	ImageProcessor carvelhelpers.ImageOperationsImpl
 }
 
+// uploadState tracks the images uploaded to the destination repository and
+// saves them to the upload state file after each upload
+type uploadState struct {
+	PluginBundleUploadState
+	file     string
+	uploaded map[string]bool
+	mutex    sync.Mutex
+}
+
 // UploadPluginBundle uploads the given plugin bundle to the specified remote repository
 func (o *UploadPluginBundleOptions) UploadPluginBundle() error {
+	// Images are published to the local filesystem in an OCI image layout
+	if registry.IsLocalImage(o.DestinationRepo) {
+		if err := registry.InitLocalImageRepository(o.DestinationRepo); err != nil {
+			return err
+		}
+	}
+
 	// create a temporary directory
 	tempDir, err := os.MkdirTemp("", "")
 	if err != nil {
@@ -45,47 +83,56 @@ func (o *UploadPluginBundleOptions) UploadPluginBundle() error {
 
 	// Read the plugin migration manifest file
 	pluginBundleDir := filepath.Join(tempDir, PluginBundleDirName)
-	bytes, err := os.ReadFile(filepath.Join(pluginBundleDir, PluginMigrationManifestFile))
+	manifest, err := readPluginMigrationManifest(filepath.Join(pluginBundleDir, PluginMigrationManifestFile))
 	if err != nil {
-		return errors.Wrap(err, "error while reading plugin migration manifest")
+		return err
 	}
-	manifest := &PluginMigrationManifest{}
-	err = yaml.Unmarshal(bytes, &manifest)
+
+	// Make sure the plugin bundle was not altered before publishing anything
+	if o.VerificationKey != "" {
+		if err := o.verifySignedPluginBundle(manifest, pluginBundleDir); err != nil {
+			return err
+		}
+	}
+
+	// Fetch the plugin inventory metadata already published to the remote repository
+	pluginInventoryMetadataImageWithTag, err := utils.JoinURL(o.DestinationRepo, manifest.InventoryMetadataImage.RelativeImagePathWithTag)
 	if err != nil {
-		return errors.Wrap(err, "error while parsing plugin migration manifest")
+		return errors.Wrap(err, "error while constructing the plugin inventory metadata image with tag")
 	}
+	existingPluginInventoryMetadataDBFilePath := o.fetchPluginInventoryMetadata(pluginInventoryMetadataImageWithTag, tempDir)
 
-	// Iterate through all the images and publish them to the remote repository
-	for _, ic := range manifest.ImagesToCopy {
-		imageTar := filepath.Join(pluginBundleDir, ic.SourceTarFilePath)
-		repoImagePath, err := utils.JoinURL(o.DestinationRepo, ic.RelativeImagePath)
-		if err != nil {
-			return errors.Wrap(err, "error while constructing the repo image path")
-		}
-		log.Infof("---------------------------")
-		log.Infof("uploading image %q", repoImagePath)
-		err = o.ImageProcessor.CopyImageFromTar(imageTar, repoImagePath)
-		if err != nil {
-			return errors.Wrap(err, "error while uploading image")
+	// Make sure a delta plugin bundle applies cleanly before publishing anything
+	if manifest.DeltaBase != nil {
+		if err := o.validateDeltaBase(manifest.DeltaBase, existingPluginInventoryMetadataDBFilePath); err != nil {
+			return err
 		}
 	}
+
+	// Publish all the images to the remote repository, resuming any previous upload
+	state := o.loadUploadState()
+	err = o.uploadImages(manifest.ImagesToCopy, pluginBundleDir, state)
+	if err != nil {
+		return err
+	}
 	log.Infof("---------------------------")
 	log.Infof("---------------------------")
 
 	// Publish plugin inventory metadata image after merging inventory metadata
 	log.Infof("publishing plugin inventory metadata image...")
 	bundledPluginInventoryMetadataDBFilePath := filepath.Join(pluginBundleDir, manifest.InventoryMetadataImage.SourceFilePath)
-	pluginInventoryMetadataImageWithTag, err := utils.JoinURL(o.DestinationRepo, manifest.InventoryMetadataImage.RelativeImagePathWithTag)
-	if err != nil {
-		return errors.Wrap(err, "error while constructing the plugin inventory metadata image with tag")
+	if err := verifyFileDigest(bundledPluginInventoryMetadataDBFilePath, manifest.InventoryMetadataImage.SourceFileDigest); err != nil {
+		return err
 	}
-	err = o.mergePluginInventoryMetadata(pluginInventoryMetadataImageWithTag, bundledPluginInventoryMetadataDBFilePath, tempDir)
+	err = mergePluginInventoryMetadata(pluginInventoryMetadataImageWithTag, bundledPluginInventoryMetadataDBFilePath, existingPluginInventoryMetadataDBFilePath)
 	if err != nil {
 		return errors.Wrap(err, "error while merging the plugin inventory metadata database before uploading metadata image")
 	}
 
 	log.Infof("uploading image %q", pluginInventoryMetadataImageWithTag)
-	err = o.ImageProcessor.PushImage(pluginInventoryMetadataImageWithTag, []string{bundledPluginInventoryMetadataDBFilePath})
+	err = retryWithBackoff(func() error {
+		return o.ImageProcessor.PushImage(pluginInventoryMetadataImageWithTag, []string{bundledPluginInventoryMetadataDBFilePath})
+	})
 	if err != nil {
 		return errors.Wrap(err, "error while uploading image")
 	}
@@ -98,24 +145,193 @@ func (o *UploadPluginBundleOptions) UploadPluginBundle() error {
 	}
 	log.Infof("successfully published all plugin images to %q", joinedURL)
 
+	// The upload is complete and does not need to be resumed
+	_ = os.Remove(state.file)
+
 	return nil
 }
 
-// mergePluginInventoryMetadata merges the downloaded plugin inventory metadata with
-// existing plugin inventory metadata available on the remote repository
-func (o *UploadPluginBundleOptions) mergePluginInventoryMetadata(pluginInventoryMetadataImageWithTag, bundledPluginInventoryMetadataDBFilePath, tempDir string) error {
-	tempPluginInventoryMetadataDir := filepath.Join(tempDir, "inventory-metadata")
-	err := o.ImageProcessor.DownloadImageAndSaveFilesToDir(pluginInventoryMetadataImageWithTag, tempPluginInventoryMetadataDir)
-	if err == nil {
-		downloadedPluginInventoryMetadataDBFilePath := filepath.Join(tempPluginInventoryMetadataDir, plugininventory.SQliteInventoryMetadataDBFileName)
-		pluginInventoryDB := plugininventory.NewSQLiteInventoryMetadata(bundledPluginInventoryMetadataDBFilePath)
-		err = pluginInventoryDB.MergeInventoryMetadataDatabase(downloadedPluginInventoryMetadataDBFilePath)
-		if err != nil {
+// verifySignedPluginBundle verifies the signature of the plugin migration
+// manifest and that all the files of the plugin bundle match their digests
+// recorded in the signed manifest
+func (o *UploadPluginBundleOptions) verifySignedPluginBundle(manifest *PluginMigrationManifest, pluginBundleDir string) error {
+	if err := verifyPluginMigrationManifestSignature(o.VerificationKey, pluginBundleDir); err != nil {
+		return errors.Wrapf(err, "the plugin bundle %q is invalid", o.Tar)
+	}
+	unverified, errs := verifyPluginBundleFiles(manifest, pluginBundleDir)
+	if len(errs) != 0 {
+		return errors.Wrapf(kerrors.NewAggregate(errs), "the plugin bundle %q is invalid", o.Tar)
+	}
+	if unverified != 0 {
+		return errors.Errorf("%d files of the signed plugin bundle %q do not have a recorded digest", unverified, o.Tar)
+	}
+	log.Infof("successfully verified the signature of the plugin bundle %q", o.Tar)
+	return nil
+}
+
+// uploadImages uploads the images to the remote repository in parallel. The images
+// that were uploaded by a previous attempt, as well as the ones that already exist
+// in the remote repository, are skipped.
+func (o *UploadPluginBundleOptions) uploadImages(images []*ImageCopyInfo, pluginBundleDir string, state *uploadState) error {
+	g, ctx := errgroup.WithContext(context.Background())
+	g.SetLimit(max(o.Concurrency, 1))
+	for _, ic := range images {
+		g.Go(func() error {
+			// Stop uploading images as soon as one upload fails
+			if ctx.Err() != nil {
+				return nil
+			}
+			return o.uploadImage(ic, pluginBundleDir, state)
+		})
+	}
+	return g.Wait()
+}
+
+// uploadImage uploads the image archive to the remote repository after
+// verifying its integrity, unless the image was already uploaded
+func (o *UploadPluginBundleOptions) uploadImage(ic *ImageCopyInfo, pluginBundleDir string, state *uploadState) error {
+	repoImagePath, err := utils.JoinURL(o.DestinationRepo, ic.RelativeImagePath)
+	if err != nil {
+		return errors.Wrap(err, "error while constructing the repo image path")
+	}
+	if state.isUploaded(ic) {
+		log.Infof("skipping image %q which was uploaded by a previous attempt", repoImagePath)
+		return nil
+	}
+	if ic.ImageDigest != "" && o.imageExists(repoImagePath, ic.ImageDigest) {
+		log.Infof("skipping image %q which already exists", repoImagePath)
+		state.markUploaded(ic)
+		return nil
+	}
+
+	imageTar := filepath.Join(pluginBundleDir, ic.SourceTarFilePath)
+	if err := verifyFileDigest(imageTar, ic.SourceTarFileDigest); err != nil {
+		return err
+	}
+	log.Infof("uploading image %q", repoImagePath)
+	err = retryWithBackoff(func() error {
+		return o.ImageProcessor.CopyImageFromTar(imageTar, repoImagePath)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error while uploading image %q", repoImagePath)
+	}
+	state.markUploaded(ic)
+	return nil
+}
+
+// imageExists returns true if the image with the specified digest exists in the remote repository
+func (o *UploadPluginBundleOptions) imageExists(repoImagePath, imageDigest string) bool {
+	hashAlgorithm, hashHexVal, err := o.ImageProcessor.GetImageDigest(repoImagePath + "@" + imageDigest)
+	return err == nil && fmt.Sprintf("%s:%s", hashAlgorithm, hashHexVal) == imageDigest
+}
+
+// retryWithBackoff invokes the operation until it succeeds, doubling the
+// delay between attempts, and returns the error of the last attempt
+func retryWithBackoff(operation func() error) error {
+	delay := uploadRetryInitialDelay
+	for attempt := 1; ; attempt++ {
+		err := operation()
+		if err == nil || attempt >= uploadAttempts {
 			return err
 		}
-		log.Infof("plugin inventory metadata image %q is present. Merging the plugin inventory metadata", pluginInventoryMetadataImageWithTag)
-	} else {
+		log.Warningf("attempt %d of %d failed, retrying in %v: %v", attempt, uploadAttempts, delay, err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// loadUploadState reads the state of a previous upload of the plugin bundle to the
+// same destination repository. A new state is returned if there is no such upload.
+func (o *UploadPluginBundleOptions) loadUploadState() *uploadState {
+	state := &uploadState{
+		PluginBundleUploadState: PluginBundleUploadState{DestinationRepo: o.DestinationRepo},
+		file:                    o.Tar + PluginBundleUploadStateFileSuffix,
+		uploaded:                map[string]bool{},
+	}
+	bytes, err := os.ReadFile(state.file)
+	if err != nil {
+		return state
+	}
+	previous := PluginBundleUploadState{}
+	if err := yaml.Unmarshal(bytes, &previous); err != nil || previous.DestinationRepo != o.DestinationRepo {
+		return state
+	}
+	log.Infof("resuming the previous upload of %q to %q", o.Tar, o.DestinationRepo)
+	state.UploadedImages = previous.UploadedImages
+	for _, image := range previous.UploadedImages {
+		state.uploaded[image] = true
+	}
+	return state
+}
+
+func uploadStateKey(ic *ImageCopyInfo) string {
+	return ic.SourceTarFilePath + "@" + ic.SourceTarFileDigest
+}
+
+func (s *uploadState) isUploaded(ic *ImageCopyInfo) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.uploaded[uploadStateKey(ic)]
+}
+
+// markUploaded records the upload of the image and saves the upload state file
+func (s *uploadState) markUploaded(ic *ImageCopyInfo) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	key := uploadStateKey(ic)
+	if s.uploaded[key] {
+		return
+	}
+	s.uploaded[key] = true
+	s.UploadedImages = append(s.UploadedImages, key)
+
+	bytes, err := yaml.Marshal(&s.PluginBundleUploadState)
+	if err == nil {
+		err = os.WriteFile(s.file, bytes, 0644)
+	}
+	if err != nil {
+		log.Warningf("unable to save the upload state to %q, the upload will not be resumable: %v", s.file, err)
+	}
+}
+
+// readPluginMigrationManifest reads the specified plugin migration manifest file
+func readPluginMigrationManifest(manifestFile string) (*PluginMigrationManifest, error) {
+	bytes, err := os.ReadFile(manifestFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while reading plugin migration manifest")
+	}
+	manifest := &PluginMigrationManifest{}
+	err = yaml.Unmarshal(bytes, &manifest)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while parsing plugin migration manifest")
+	}
+	return manifest, nil
+}
+
+// fetchPluginInventoryMetadata downloads the plugin inventory metadata available on
+// the remote repository and returns the path of its database, or an empty string
+// if the remote repository does not have any plugin inventory metadata
+func (o *UploadPluginBundleOptions) fetchPluginInventoryMetadata(pluginInventoryMetadataImageWithTag, tempDir string) string {
+	tempPluginInventoryMetadataDir := filepath.Join(tempDir, "inventory-metadata")
+	err := o.ImageProcessor.DownloadImageAndSaveFilesToDir(pluginInventoryMetadataImageWithTag, tempPluginInventoryMetadataDir)
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(tempPluginInventoryMetadataDir, plugininventory.SQliteInventoryMetadataDBFileName)
+}
+
+// mergePluginInventoryMetadata merges the downloaded plugin inventory metadata with
+// existing plugin inventory metadata available on the remote repository
+func mergePluginInventoryMetadata(pluginInventoryMetadataImageWithTag, bundledPluginInventoryMetadataDBFilePath, existingPluginInventoryMetadataDBFilePath string) error {
+	if existingPluginInventoryMetadataDBFilePath == "" {
 		log.Infof("plugin inventory metadata image %q is not present. Skipping merging of the plugin inventory metadata", pluginInventoryMetadataImageWithTag)
+		return nil
+	}
+	pluginInventoryDB := plugininventory.NewSQLiteInventoryMetadata(bundledPluginInventoryMetadataDBFilePath)
+	err := pluginInventoryDB.MergeInventoryMetadataDatabase(existingPluginInventoryMetadataDBFilePath)
+	if err != nil {
+		return err
 	}
+	log.Infof("plugin inventory metadata image %q is present. Merging the plugin inventory metadata", pluginInventoryMetadataImageWithTag)
 	return nil
 }
@@ -0,0 +1,125 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package airgapped
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/verybluebot/tarinator-go"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
+
+	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/helpers"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/plugininventory"
+)
+
+// VerifyPluginBundleOptions defines options for verifying plugin bundle
+type VerifyPluginBundleOptions struct {
+	Tar string
+	// VerificationKey is the cosign public key used to verify the signature
+	// of the plugin migration manifest. The signature is not verified if empty.
+	VerificationKey string
+}
+
+// VerifyPluginBundle verifies the integrity of the given plugin bundle without
+// accessing any registry. It checks that all the files listed in the plugin
+// migration manifest are present in the bundle and match their recorded digests.
+func (o *VerifyPluginBundleOptions) VerifyPluginBundle() error {
+	tempDir, err := os.MkdirTemp("", "")
+	if err != nil {
+		return errors.Wrap(err, "unable to create temp directory")
+	}
+	defer os.RemoveAll(tempDir)
+
+	log.Infof("extracting %q for processing...", o.Tar)
+	err = tarinator.UnTarinate(tempDir, o.Tar)
+	if err != nil {
+		return errors.Wrap(err, "unable to extract provided file")
+	}
+
+	pluginBundleDir := filepath.Join(tempDir, PluginBundleDirName)
+	manifest, err := readPluginMigrationManifest(filepath.Join(pluginBundleDir, PluginMigrationManifestFile))
+	if err != nil {
+		return err
+	}
+
+	if o.VerificationKey != "" {
+		if err := verifyPluginMigrationManifestSignature(o.VerificationKey, pluginBundleDir); err != nil {
+			return errors.Wrapf(err, "the plugin bundle %q is invalid", o.Tar)
+		}
+		log.Infof("successfully verified the signature of the plugin bundle %q", o.Tar)
+	}
+
+	unverified, errs := verifyPluginBundleFiles(manifest, pluginBundleDir)
+	if len(errs) != 0 {
+		return errors.Wrapf(kerrors.NewAggregate(errs), "the plugin bundle %q is invalid", o.Tar)
+	}
+	if unverified != 0 && o.VerificationKey != "" {
+		return errors.Errorf("%d files of the signed plugin bundle %q do not have a recorded digest", unverified, o.Tar)
+	}
+	if unverified != 0 {
+		log.Warningf("%d files of the plugin bundle do not have a recorded digest and could only be checked for presence", unverified)
+	}
+	log.Infof("successfully verified the %d images of the plugin bundle %q", len(manifest.ImagesToCopy), o.Tar)
+	return nil
+}
+
+// verifyPluginBundleFiles verifies that all the files listed in the plugin
+// migration manifest are present in the plugin bundle and match their recorded
+// digests. It returns the number of files without a recorded digest.
+func verifyPluginBundleFiles(manifest *PluginMigrationManifest, pluginBundleDir string) (int, []error) {
+	var errs []error
+	var unverified int
+	for _, ic := range manifest.ImagesToCopy {
+		if ic.SourceTarFileDigest == "" {
+			unverified++
+		}
+		if err := verifyFileDigest(filepath.Join(pluginBundleDir, ic.SourceTarFilePath), ic.SourceTarFileDigest); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if manifest.InventoryMetadataImage == nil {
+		errs = append(errs, errors.New("the plugin migration manifest does not list the plugin inventory metadata"))
+	} else {
+		metadataDBFile := filepath.Join(pluginBundleDir, manifest.InventoryMetadataImage.SourceFilePath)
+		if manifest.InventoryMetadataImage.SourceFileDigest == "" {
+			unverified++
+		}
+		if err := verifyFileDigest(metadataDBFile, manifest.InventoryMetadataImage.SourceFileDigest); err != nil {
+			errs = append(errs, err)
+		} else if _, err := plugininventory.NewSQLiteInventoryMetadata(metadataDBFile).GetPluginIdentifiers(); err != nil {
+			errs = append(errs, errors.Wrapf(err, "invalid plugin inventory metadata %q", manifest.InventoryMetadataImage.SourceFilePath))
+		}
+	}
+
+	if manifest.SBOM != nil {
+		if err := verifyFileDigest(filepath.Join(pluginBundleDir, manifest.SBOM.SourceFilePath), manifest.SBOM.SourceFileDigest); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return unverified, errs
+}
+
+// verifyFileDigest verifies that the file exists and, if a digest is specified,
+// that its sha256 digest matches
+func verifyFileDigest(file, expectedDigest string) error {
+	if _, err := os.Stat(file); err != nil {
+		return errors.Errorf("the file %q is missing from the plugin bundle", filepath.Base(file))
+	}
+	if expectedDigest == "" {
+		return nil
+	}
+	digest, err := helpers.GetDigest(file)
+	if err != nil {
+		return errors.Wrapf(err, "unable to calculate the digest of %q", filepath.Base(file))
+	}
+	if digest != expectedDigest {
+		return errors.Errorf("the file %q of the plugin bundle is corrupted. expected digest: %s, actual digest: %s", filepath.Base(file), expectedDigest, digest)
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package airgapped
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/carvelhelpers"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/plugininventory"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/registry"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/utils"
+)
+
+// MirrorPluginsOptions defines options for mirroring the plugins of a plugin
+// inventory image to another repository, directly from registry to registry
+type MirrorPluginsOptions struct {
+	PluginInventoryImage string
+	DestinationRepo      string
+	// Groups restricts the mirrored plugins to the plugins of the specified
+	// plugin group versions. All the plugins are mirrored if empty.
+	Groups []string
+	// Concurrency is the number of images copied in parallel
+	Concurrency int
+	// DryRun only returns what is missing from the destination repository
+	// without copying anything
+	DryRun         bool
+	ImageProcessor carvelhelpers.ImageOperationsImpl
+}
+
+// PluginMirrorDiff is the content of the source plugin inventory that is
+// missing from the destination repository of a mirror
+type PluginMirrorDiff struct {
+	// Plugins are the missing plugin versions, as name@target:version
+	Plugins []string `json:"plugins" yaml:"plugins"`
+	// PluginGroups are the missing plugin group versions, as vendor-publisher/name:version
+	PluginGroups []string `json:"pluginGroups" yaml:"pluginGroups"`
+	// Images are the images missing from the destination repository, or
+	// differing from the source ones, as their path in the destination repository
+	Images []string `json:"images" yaml:"images"`
+}
+
+// mirrorImage is an image of the source plugin inventory with its path
+// in the destination repository
+type mirrorImage struct {
+	source string
+	// destinationRepo is the image path in the destination repository without the tag
+	destinationRepo string
+	// destination is the image path in the destination repository with the tag
+	destination string
+}
+
+// MirrorPlugins copies the plugins of the plugin inventory image, and the plugin
+// inventory image itself, that are missing from the destination repository, and
+// merges the plugin inventory metadata of the destination repository with the
+// mirrored plugins and plugin groups. Mirroring again without any change to the
+// source plugin inventory does not copy or publish anything.
+// The returned PluginMirrorDiff lists what was missing from the destination repository.
+func (o *MirrorPluginsOptions) MirrorPlugins() (*PluginMirrorDiff, error) {
+	// Select the plugins to mirror the same way they are selected to download a plugin bundle
+	dpbo := &DownloadPluginBundleOptions{
+		PluginInventoryImage: o.PluginInventoryImage,
+		Groups:               o.Groups,
+		DryRun:               true,
+		ImageProcessor:       o.ImageProcessor,
+	}
+	if err := dpbo.validateOptions(); err != nil {
+		return nil, err
+	}
+	selectedPluginEntries, selectedPluginGroups, err := dpbo.getSelectedPluginInfo()
+	if err != nil {
+		return nil, errors.Wrap(err, "error while getting selected plugin and plugin group information")
+	}
+
+	// Images are published to the local filesystem in an OCI image layout
+	if registry.IsLocalImage(o.DestinationRepo) && !o.DryRun {
+		if err := registry.InitLocalImageRepository(o.DestinationRepo); err != nil {
+			return nil, err
+		}
+	}
+
+	tempDir, err := os.MkdirTemp("", "")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create temp directory")
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Fetch the plugin inventory metadata already published to the destination repository
+	upbo := &UploadPluginBundleOptions{DestinationRepo: o.DestinationRepo, ImageProcessor: o.ImageProcessor}
+	pluginInventoryMetadataImage, err := GetPluginInventoryMetadataImage(o.PluginInventoryImage)
+	if err != nil {
+		return nil, err
+	}
+	pluginInventoryMetadataImageWithTag, err := utils.JoinURL(o.DestinationRepo, GetImageRelativePath(pluginInventoryMetadataImage, path.Dir(o.PluginInventoryImage), true))
+	if err != nil {
+		return nil, errors.Wrap(err, "error while constructing the plugin inventory metadata image with tag")
+	}
+	log.Infof("getting the plugins already published to %q...", o.DestinationRepo)
+	existingPluginInventoryMetadataDBFilePath := upbo.fetchPluginInventoryMetadata(pluginInventoryMetadataImageWithTag, tempDir)
+
+	diff, pluginImages, inventoryImage, err := o.getMissingContent(selectedPluginEntries, selectedPluginGroups, existingPluginInventoryMetadataDBFilePath)
+	if err != nil {
+		return nil, err
+	}
+	if o.DryRun {
+		return diff, nil
+	}
+	if len(diff.Plugins) == 0 && len(diff.PluginGroups) == 0 && len(diff.Images) == 0 && existingPluginInventoryMetadataDBFilePath != "" {
+		log.Infof("%q is already up to date with %q", o.DestinationRepo, o.PluginInventoryImage)
+		return diff, nil
+	}
+
+	// Copy the plugin inventory image once all the plugin images it refers to are
+	// copied, and publish the plugin inventory metadata last, as it makes the
+	// mirrored plugins and plugin groups available from the destination repository
+	if err := o.copyImages(pluginImages, tempDir); err != nil {
+		return nil, err
+	}
+	if inventoryImage != nil {
+		if err := o.copyImages([]*mirrorImage{inventoryImage}, tempDir); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Infof("publishing plugin inventory metadata image...")
+	inventoryMetadataDir := filepath.Join(tempDir, "mirrored-inventory-metadata")
+	if err := os.Mkdir(inventoryMetadataDir, os.ModePerm); err != nil {
+		return nil, errors.Wrap(err, "unable to create temp directory")
+	}
+	inventoryMetadataImageInfo, err := dpbo.savePluginInventoryMetadata(selectedPluginGroups, selectedPluginEntries, inventoryMetadataDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while saving plugin inventory metadata")
+	}
+	mirroredPluginInventoryMetadataDBFilePath := filepath.Join(inventoryMetadataDir, inventoryMetadataImageInfo.SourceFilePath)
+	err = mergePluginInventoryMetadata(pluginInventoryMetadataImageWithTag, mirroredPluginInventoryMetadataDBFilePath, existingPluginInventoryMetadataDBFilePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while merging the plugin inventory metadata database before uploading metadata image")
+	}
+	log.Infof("uploading image %q", pluginInventoryMetadataImageWithTag)
+	err = retryWithBackoff(func() error {
+		return o.ImageProcessor.PushImage(pluginInventoryMetadataImageWithTag, []string{mirroredPluginInventoryMetadataDBFilePath})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error while uploading image")
+	}
+
+	log.Infof("successfully mirrored %d plugin versions and %d plugin group versions to %q", len(diff.Plugins), len(diff.PluginGroups), o.DestinationRepo)
+	return diff, nil
+}
+
+// getMissingContent returns the plugin versions and plugin group versions missing
+// from the plugin inventory metadata of the destination repository, as well as the
+// images of the selected plugins and the plugin inventory image that are missing
+// from the destination repository or differ from the source ones
+func (o *MirrorPluginsOptions) getMissingContent(pes []*plugininventory.PluginInventoryEntry, pgs []*plugininventory.PluginGroup, existingMetadataDBFile string) (*PluginMirrorDiff, []*mirrorImage, *mirrorImage, error) {
+	existing := &existingContent{plugins: map[string]bool{}, pluginGroups: map[string]bool{}}
+	if existingMetadataDBFile != "" {
+		var err error
+		existing, err = readExistingContent(existingMetadataDBFile, nil)
+		if err != nil {
+			return nil, nil, nil, errors.Wrapf(err, "unable to read the plugin inventory metadata of %q", o.DestinationRepo)
+		}
+	}
+
+	diff := &PluginMirrorDiff{Plugins: []string{}, PluginGroups: []string{}, Images: []string{}}
+	var sourceImages []string
+	for _, pe := range pes {
+		for version, artifacts := range pe.Artifacts {
+			id := pluginIdentifierToID(&plugininventory.PluginIdentifier{Name: pe.Name, Target: pe.Target, Version: version})
+			if !existing.plugins[id] {
+				diff.Plugins = append(diff.Plugins, id)
+			}
+			for _, a := range artifacts {
+				sourceImages = append(sourceImages, a.Image)
+			}
+		}
+	}
+	for _, pg := range pgs {
+		for version := range pg.Versions {
+			id := pluginGroupIdentifierToID(&plugininventory.PluginGroupIdentifier{Vendor: pg.Vendor, Publisher: pg.Publisher, Name: pg.Name, Version: version})
+			if !existing.pluginGroups[id] {
+				diff.PluginGroups = append(diff.PluginGroups, id)
+			}
+		}
+	}
+	sort.Strings(diff.Plugins)
+	sort.Strings(diff.PluginGroups)
+	sort.Strings(sourceImages)
+
+	var pluginImages []*mirrorImage
+	for _, image := range sourceImages {
+		mi, err := o.getMissingImage(image)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if mi != nil {
+			pluginImages = append(pluginImages, mi)
+			diff.Images = append(diff.Images, mi.destination)
+		}
+	}
+	inventoryImage, err := o.getMissingImage(o.PluginInventoryImage)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if inventoryImage != nil {
+		diff.Images = append(diff.Images, inventoryImage.destination)
+	}
+	return diff, pluginImages, inventoryImage, nil
+}
+
+// getMissingImage returns the image to copy if the source image does not exist in
+// the destination repository with the same digest, or nil if it does
+func (o *MirrorPluginsOptions) getMissingImage(image string) (*mirrorImage, error) {
+	relativeImagePath := GetImageRelativePath(image, path.Dir(o.PluginInventoryImage), false)
+	destinationRepo, err := utils.JoinURL(o.DestinationRepo, relativeImagePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while constructing the repo image path")
+	}
+	destination, err := utils.JoinURL(o.DestinationRepo, GetImageRelativePath(image, path.Dir(o.PluginInventoryImage), true))
+	if err != nil {
+		return nil, errors.Wrap(err, "error while constructing the repo image path")
+	}
+
+	hashAlgorithm, hashHexVal, err := o.ImageProcessor.GetImageDigest(image)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get the digest of image %q", image)
+	}
+	sourceDigest := fmt.Sprintf("%s:%s", hashAlgorithm, hashHexVal)
+	if hashAlgorithm, hashHexVal, err := o.ImageProcessor.GetImageDigest(destination); err == nil && hashHexVal != "" && fmt.Sprintf("%s:%s", hashAlgorithm, hashHexVal) == sourceDigest {
+		return nil, nil
+	}
+	return &mirrorImage{source: image, destinationRepo: destinationRepo, destination: destination}, nil
+}
+
+// copyImages copies the images to the destination repository in parallel, directly
+// from registry to registry, or through a temporary tar file for each image if
+// the destination repository is on the local filesystem
+func (o *MirrorPluginsOptions) copyImages(images []*mirrorImage, tempDir string) error {
+	g, ctx := errgroup.WithContext(context.Background())
+	g.SetLimit(max(o.Concurrency, 1))
+	for i, mi := range images {
+		g.Go(func() error {
+			// Stop copying images as soon as one copy fails
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			log.Infof("copying image %q to %q", mi.source, mi.destination)
+			err := retryWithBackoff(func() error {
+				if !registry.IsLocalImage(o.DestinationRepo) {
+					return o.ImageProcessor.CopyImage(mi.source, mi.destinationRepo)
+				}
+				imageTar := filepath.Join(tempDir, fmt.Sprintf("image-%d.tar.gz", i))
+				defer os.Remove(imageTar)
+				if err := o.ImageProcessor.CopyImageToTar(mi.source, imageTar); err != nil {
+					return err
+				}
+				return o.ImageProcessor.CopyImageFromTar(imageTar, mi.destinationRepo)
+			})
+			if err != nil {
+				return errors.Wrapf(err, "error while copying image %q", mi.source)
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
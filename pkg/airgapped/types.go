@@ -7,21 +7,69 @@ package airgapped
 const PluginBundleDirName = "plugin_bundle"
 const PluginMigrationManifestFile = "plugin_migration_manifest.yaml"
 
+// pluginInventoryImageTarFileName is the tar file of the plugin bundle the plugin
+// inventory image is saved to
+const pluginInventoryImageTarFileName = "plugin-inventory-image.tar.gz"
+
+// PluginMigrationManifestSignatureFile is the cosign signature of the plugin
+// migration manifest of a signed plugin bundle
+const PluginMigrationManifestSignatureFile = PluginMigrationManifestFile + ".sig"
+
+// PluginBundleUploadStateFileSuffix is appended to the path of a plugin bundle
+// to store the state of its upload, which allows resuming an interrupted upload
+const PluginBundleUploadStateFileSuffix = ".upload-state.yaml"
+
 // PluginMigrationManifest defines struct for plugin bundle manifest
 type PluginMigrationManifest struct {
 	RelativeInventoryImagePathWithTag string            `yaml:"relativeInventoryImagePathWithTag"`
 	InventoryMetadataImage            *ImagePublishInfo `yaml:"inventoryMetadataImage"`
 	ImagesToCopy                      []*ImageCopyInfo  `yaml:"imagesToCopy"`
+	// DeltaBase is only set for a delta plugin bundle and lists the plugins and
+	// plugin groups that were excluded from the bundle because they already
+	// exist in the repository the bundle is uploaded to
+	DeltaBase *DeltaBaseInfo `yaml:"deltaBase,omitempty"`
+	// SBOM is the software bill of materials of the bundled plugins, if any
+	SBOM *BundledFileInfo `yaml:"sbom,omitempty"`
+}
+
+// DeltaBaseInfo lists the plugins and plugin groups a delta plugin bundle
+// builds upon
+type DeltaBaseInfo struct {
+	// Plugins are of the form name@target:version
+	Plugins []string `yaml:"plugins,omitempty"`
+	// PluginGroups are of the form vendor-publisher/name:version
+	PluginGroups []string `yaml:"pluginGroups,omitempty"`
 }
 
 // ImageCopyInfo maps the relative image path and local relative file path
 type ImageCopyInfo struct {
 	SourceTarFilePath string `yaml:"sourceTarFilePath"`
 	RelativeImagePath string `yaml:"relativeImagePath"`
+	// SourceTarFileDigest is the sha256 digest of the source tar file
+	SourceTarFileDigest string `yaml:"sourceTarFileDigest,omitempty"`
+	// ImageDigest is the digest of the image, e.g. sha256:<hex>
+	ImageDigest string `yaml:"imageDigest,omitempty"`
 }
 
 // ImagePublishInfo maps the relative image path and local relative file path
 type ImagePublishInfo struct {
 	SourceFilePath           string `yaml:"sourceFilePath"`
 	RelativeImagePathWithTag string `yaml:"relativeImagePathWithTag"`
+	// SourceFileDigest is the sha256 digest of the source file
+	SourceFileDigest string `yaml:"sourceFileDigest,omitempty"`
+}
+
+// BundledFileInfo describes a file of the plugin bundle
+type BundledFileInfo struct {
+	SourceFilePath string `yaml:"sourceFilePath"`
+	// SourceFileDigest is the sha256 digest of the source file
+	SourceFileDigest string `yaml:"sourceFileDigest"`
+}
+
+// PluginBundleUploadState records the images of a plugin bundle that were
+// uploaded to a destination repository
+type PluginBundleUploadState struct {
+	DestinationRepo string `yaml:"destinationRepo"`
+	// UploadedImages are of the form <sourceTarFilePath>@<sourceTarFileDigest>
+	UploadedImages []string `yaml:"uploadedImages"`
 }
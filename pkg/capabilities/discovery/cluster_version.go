@@ -0,0 +1,78 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package discovery
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// KubernetesVersion represents the Kubernetes version of a cluster.
+func KubernetesVersion(queryName string) *QueryKubernetesVersion {
+	return &QueryKubernetesVersion{
+		name: queryName,
+	}
+}
+
+// QueryKubernetesVersion provides insight to the clusters Kubernetes version
+type QueryKubernetesVersion struct {
+	name          string
+	minVersion    string
+	serverVersion string
+}
+
+// Name returns the name of the query.
+func (q *QueryKubernetesVersion) Name() string {
+	return q.name
+}
+
+// AtLeast checks if the Kubernetes version of the cluster is the specified version or a later one.
+// This method can be omitted to query any version.
+func (q *QueryKubernetesVersion) AtLeast(minVersion string) *QueryKubernetesVersion {
+	q.minVersion = minVersion
+	return q
+}
+
+// ServerVersion returns the Kubernetes version of the cluster discovered when running the query.
+func (q *QueryKubernetesVersion) ServerVersion() string {
+	return q.serverVersion
+}
+
+// Run discovery.
+func (q *QueryKubernetesVersion) Run(config *clusterQueryClientConfig) (bool, error) {
+	if config == nil {
+		return false, fmt.Errorf("clusterQueryClientConfig must not be nil")
+	}
+
+	var minVersion *version.Version
+	if q.minVersion != "" {
+		var err error
+		if minVersion, err = version.ParseGeneric(q.minVersion); err != nil {
+			return false, fmt.Errorf("failed Kubernetes version query validation: %w", err)
+		}
+	}
+
+	info, err := config.discoveryClientset.ServerVersion()
+	if err != nil {
+		return false, fmt.Errorf("failed to discover server version: %w", err)
+	}
+	q.serverVersion = info.GitVersion
+
+	if minVersion == nil {
+		return true, nil
+	}
+	// The generic parsing ignores any pre-release or build information that
+	// providers add to the version, e.g. v1.28.3+vmware.1
+	serverVersion, err := version.ParseGeneric(info.GitVersion)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse server version %q: %w", info.GitVersion, err)
+	}
+	return serverVersion.AtLeast(minVersion), nil
+}
+
+// Reason surfaces what didn't match.
+func (q *QueryKubernetesVersion) Reason() string {
+	return fmt.Sprintf("version=%s minVersion=%s status=unmatched", q.serverVersion, q.minVersion)
+}
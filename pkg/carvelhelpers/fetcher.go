@@ -5,6 +5,7 @@ package carvelhelpers
 
 import (
 	"os"
+	"reflect"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -34,9 +35,63 @@ func GetImageDigest(imageWithTag string) (string, string, error) {
 	return NewImageOperationsImpl().GetImageDigest(imageWithTag)
 }
 
+// newRegistryForImage returns a new registry object to access the specified image,
+// which is either stored in a remote registry or on the local filesystem
+func newRegistryForImage(imageWithTag string) (registry.Registry, error) {
+	if registry.IsLocalImage(imageWithTag) {
+		return registry.NewLocal(), nil
+	}
+	registryName, err := registry.GetRegistryName(imageWithTag)
+	if err != nil {
+		return nil, err
+	}
+	reg, err := newRegistry(registryName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to initialize registry")
+	}
+	return reg, nil
+}
+
 // newRegistry returns a new registry object by also taking
 // into account for any custom registry provided by the user
 func newRegistry(registryHost string) (registry.Registry, error) {
+	registryOpts, err := newRegistryOpts(registryHost)
+	if err != nil {
+		return nil, err
+	}
+	return registry.New(registryOpts)
+}
+
+// newRegistryForCopy returns a new registry object to copy an image directly between
+// two remote registries. As imgpkg applies the same options to both registries, it
+// returns nil if the registries must be accessed with different options, e.g., when
+// only one of them skips the certificate verification, for the image to be copied
+// through a tar file instead.
+func newRegistryForCopy(sourceImageName, destImageRepo string) (registry.Registry, error) {
+	if registry.IsLocalImage(sourceImageName) || registry.IsLocalImage(destImageRepo) {
+		return registry.NewLocal(), nil
+	}
+	var registryOpts []*ctlimg.Opts
+	for _, image := range []string{sourceImageName, destImageRepo} {
+		registryName, err := registry.GetRegistryName(image)
+		if err != nil {
+			return nil, err
+		}
+		opts, err := newRegistryOpts(registryName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to initialize registry")
+		}
+		registryOpts = append(registryOpts, opts)
+	}
+	if !reflect.DeepEqual(registryOpts[0], registryOpts[1]) {
+		return nil, nil
+	}
+	return registry.New(registryOpts[0])
+}
+
+// newRegistryOpts returns the options to access the registry by also
+// taking into account for any custom registry provided by the user
+func newRegistryOpts(registryHost string) (*ctlimg.Opts, error) {
 	registryOpts := &ctlimg.Opts{}
 
 	authenticatedRegistries := strings.Split(os.Getenv(constants.AuthenticatedRegistry), ",")
@@ -51,5 +106,5 @@ func newRegistry(registryHost string) (registry.Registry, error) {
 	registryOpts.CACertPaths = regCertOptions.CACertPaths
 	registryOpts.VerifyCerts = !(regCertOptions.SkipCertVerify)
 	registryOpts.Insecure = regCertOptions.Insecure
-	return registry.New(registryOpts)
+	return registryOpts, nil
 }
@@ -0,0 +1,60 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package carvelhelpers
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configlib "github.com/vmware-tanzu/tanzu-plugin-runtime/config"
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+)
+
+var _ = Describe("Unit tests for newRegistryForCopy", func() {
+	var (
+		tanzuConfigFile   *os.File
+		tanzuConfigFileNG *os.File
+		err               error
+	)
+
+	BeforeEach(func() {
+		tanzuConfigFile, err = os.CreateTemp("", "config")
+		Expect(err).To(BeNil())
+		os.Setenv("TANZU_CONFIG", tanzuConfigFile.Name())
+
+		tanzuConfigFileNG, err = os.CreateTemp("", "config_ng")
+		Expect(err).To(BeNil())
+		os.Setenv("TANZU_CONFIG_NEXT_GEN", tanzuConfigFileNG.Name())
+
+		err = configlib.SetCert(&configtypes.Cert{Host: "insecure.example.com", SkipCertVerify: "true", Insecure: "true"})
+		Expect(err).To(BeNil())
+	})
+	AfterEach(func() {
+		os.Unsetenv("TANZU_CONFIG")
+		os.Unsetenv("TANZU_CONFIG_NEXT_GEN")
+		os.RemoveAll(tanzuConfigFile.Name())
+		os.RemoveAll(tanzuConfigFileNG.Name())
+	})
+
+	It("should copy directly between registries accessed with the same options", func() {
+		reg, err := newRegistryForCopy("source.example.com/plugins/foo:v1.0.0", "destination.example.com/plugins/foo:v1.0.0")
+		Expect(err).To(BeNil())
+		Expect(reg).ToNot(BeNil())
+
+		reg, err = newRegistryForCopy("insecure.example.com/plugins/foo:v1.0.0", "insecure.example.com/mirror/foo:v1.0.0")
+		Expect(err).To(BeNil())
+		Expect(reg).ToNot(BeNil())
+	})
+	It("should not copy directly between registries accessed with different options", func() {
+		reg, err := newRegistryForCopy("insecure.example.com/plugins/foo:v1.0.0", "destination.example.com/plugins/foo:v1.0.0")
+		Expect(err).To(BeNil())
+		Expect(reg).To(BeNil())
+
+		reg, err = newRegistryForCopy("source.example.com/plugins/foo:v1.0.0", "insecure.example.com/plugins/foo:v1.0.0")
+		Expect(err).To(BeNil())
+		Expect(reg).To(BeNil())
+	})
+})
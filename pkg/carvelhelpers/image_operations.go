@@ -10,7 +10,6 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/helpers"
-	"github.com/vmware-tanzu/tanzu-cli/pkg/registry"
 )
 
 // ImageOperationOptions implements the ImageOperationsImpl interface by using `imgpkg` library
@@ -24,42 +23,54 @@ func NewImageOperationsImpl() ImageOperationsImpl {
 // CopyImageToTar downloads the image as tar file
 // This is equivalent to `imgpkg copy --image <image> --to-tar <tar-file-path>` command
 func (i *ImageOperationOptions) CopyImageToTar(sourceImageName, destTarFile string) error {
-	registryName, err := registry.GetRegistryName(sourceImageName)
+	reg, err := newRegistryForImage(sourceImageName)
 	if err != nil {
 		return err
 	}
-	reg, err := newRegistry(registryName)
-	if err != nil {
-		return errors.Wrapf(err, "unable to initialize registry")
-	}
 	return reg.CopyImageToTar(sourceImageName, destTarFile)
 }
 
 // CopyImageFromTar publishes the image to destination repository from specified tar file
 // This is equivalent to `imgpkg copy --tar <file> --to-repo <dest-repo>` command
 func (i *ImageOperationOptions) CopyImageFromTar(sourceTarFile, destImageRepo string) error {
-	registryName, err := registry.GetRegistryName(destImageRepo)
+	reg, err := newRegistryForImage(destImageRepo)
 	if err != nil {
 		return err
 	}
-	reg, err := newRegistry(registryName)
+	return reg.CopyImageFromTar(sourceTarFile, destImageRepo)
+}
+
+// CopyImage copies the image to the destination repository, directly from registry to registry
+// This is equivalent to `imgpkg copy --image <image> --to-repo <dest-repo>` command
+// The image is copied through a tar file if the registries are accessed with different options.
+func (i *ImageOperationOptions) CopyImage(sourceImageName, destImageRepo string) error {
+	reg, err := newRegistryForCopy(sourceImageName, destImageRepo)
 	if err != nil {
-		return errors.Wrapf(err, "unable to initialize registry")
+		return err
 	}
-	return reg.CopyImageFromTar(sourceTarFile, destImageRepo)
+	if reg != nil {
+		return reg.CopyImage(sourceImageName, destImageRepo)
+	}
+
+	tempDir, err := os.MkdirTemp("", "")
+	if err != nil {
+		return errors.Wrap(err, "unable to create temporary directory")
+	}
+	defer os.RemoveAll(tempDir)
+	tarFile := filepath.Join(tempDir, "image.tar")
+	if err := i.CopyImageToTar(sourceImageName, tarFile); err != nil {
+		return err
+	}
+	return i.CopyImageFromTar(tarFile, destImageRepo)
 }
 
 // DownloadImageAndSaveFilesToDir reads a plain OCI image and saves its
 // files to the specified location.
 func (i *ImageOperationOptions) DownloadImageAndSaveFilesToDir(imageWithTag, destinationDir string) error {
-	registryName, err := registry.GetRegistryName(imageWithTag)
+	reg, err := newRegistryForImage(imageWithTag)
 	if err != nil {
 		return err
 	}
-	reg, err := newRegistry(registryName)
-	if err != nil {
-		return errors.Wrapf(err, "unable to initialize registry")
-	}
 	err = reg.DownloadImage(imageWithTag, destinationDir)
 	if err != nil {
 		return errors.Wrap(err, "error downloading image")
@@ -71,27 +82,19 @@ func (i *ImageOperationOptions) DownloadImageAndSaveFilesToDir(imageWithTag, des
 // It takes os environment variables for custom repository and proxy
 // configuration into account while downloading image from repository
 func (i *ImageOperationOptions) GetFilesMapFromImage(imageWithTag string) (map[string][]byte, error) {
-	registryName, err := registry.GetRegistryName(imageWithTag)
+	reg, err := newRegistryForImage(imageWithTag)
 	if err != nil {
 		return nil, err
 	}
-	reg, err := newRegistry(registryName)
-	if err != nil {
-		return nil, errors.Wrapf(err, "unable to initialize registry")
-	}
 	return reg.GetFiles(imageWithTag)
 }
 
 // GetImageDigest gets digest of the image
 func (i *ImageOperationOptions) GetImageDigest(imageWithTag string) (string, string, error) {
-	registryName, err := registry.GetRegistryName(imageWithTag)
+	reg, err := newRegistryForImage(imageWithTag)
 	if err != nil {
 		return "", "", err
 	}
-	reg, err := newRegistry(registryName)
-	if err != nil {
-		return "", "", errors.Wrapf(err, "unable to initialize registry")
-	}
 
 	hashAlgorithm, hashHexVal, err := reg.GetImageDigest(imageWithTag)
 	if err != nil {
@@ -103,27 +106,19 @@ func (i *ImageOperationOptions) GetImageDigest(imageWithTag string) (string, str
 
 // PushImage publishes the image to the specified location
 func (i *ImageOperationOptions) PushImage(imageWithTag string, filePaths []string) error {
-	registryName, err := registry.GetRegistryName(imageWithTag)
+	reg, err := newRegistryForImage(imageWithTag)
 	if err != nil {
 		return err
 	}
-	reg, err := newRegistry(registryName)
-	if err != nil {
-		return errors.Wrapf(err, "unable to initialize registry")
-	}
 	return reg.PushImage(imageWithTag, filePaths)
 }
 
 // ResolveImage invokes `imgpkg tag resolve -i <image>` command
 func (i *ImageOperationOptions) ResolveImage(imageWithTag string) error {
-	registryName, err := registry.GetRegistryName(imageWithTag)
+	reg, err := newRegistryForImage(imageWithTag)
 	if err != nil {
 		return err
 	}
-	reg, err := newRegistry(registryName)
-	if err != nil {
-		return errors.Wrapf(err, "unable to initialize registry")
-	}
 	return reg.ResolveImage(imageWithTag)
 }
 
@@ -148,3 +143,23 @@ func (i *ImageOperationOptions) GetFileDigestFromImage(imageWithTag, fileName st
 	}
 	return digest, nil
 }
+
+// PushReferrer publishes the content as an OCI artifact of the given artifact
+// type referring to the specified image
+func (i *ImageOperationOptions) PushReferrer(imageWithTag, artifactType string, content []byte) error {
+	reg, err := newRegistryForImage(imageWithTag)
+	if err != nil {
+		return err
+	}
+	return reg.PushReferrer(imageWithTag, artifactType, content)
+}
+
+// GetReferrers gets the content of the OCI artifacts of the given artifact
+// type referring to the specified image
+func (i *ImageOperationOptions) GetReferrers(imageWithTag, artifactType string) ([][]byte, error) {
+	reg, err := newRegistryForImage(imageWithTag)
+	if err != nil {
+		return nil, err
+	}
+	return reg.GetReferrers(imageWithTag, artifactType)
+}
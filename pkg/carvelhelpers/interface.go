@@ -13,6 +13,10 @@ type ImageOperationsImpl interface {
 	// CopyImageFromTar publishes the image to destination repository from specified tar file
 	// This is equivalent to `imgpkg copy --tar <file> --to-repo <dest-repo>` command
 	CopyImageFromTar(sourceTarFile, destImageRepo string) error
+	// CopyImage copies the image to the destination repository, directly from registry to registry
+	// This is equivalent to `imgpkg copy --image <image> --to-repo <dest-repo>` command
+	// The image is copied through a tar file if the registries are accessed with different options.
+	CopyImage(sourceImageName, destImageRepo string) error
 	// DownloadImageAndSaveFilesToDir reads a plain OCI image and saves its
 	// files to the specified location.
 	DownloadImageAndSaveFilesToDir(imageWithTag, destinationDir string) error
@@ -29,4 +33,10 @@ type ImageOperationsImpl interface {
 	ResolveImage(imageWithTag string) error
 	// GetFileDigestFromImage invokes `DownloadImageAndSaveFilesToDir` to fetch the image and returns the digest of the specified file
 	GetFileDigestFromImage(imageWithTag, fileName string) (string, error)
+	// PushReferrer publishes the content as an OCI artifact of the given artifact
+	// type referring to the specified image
+	PushReferrer(imageWithTag, artifactType string, content []byte) error
+	// GetReferrers gets the content of the OCI artifacts of the given artifact
+	// type referring to the specified image
+	GetReferrers(imageWithTag, artifactType string) ([][]byte, error)
 }
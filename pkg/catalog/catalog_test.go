@@ -198,3 +198,101 @@ func Test_ContextCatalog_With_Context(t *testing.T) {
 	pd, exists = cc3.Get("fakeplugin1")
 	assert.False(exists)
 }
+
+func Test_DeletePluginInstallations(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "test-catalog")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	common.DefaultCacheDir = dir
+
+	pd1 := cli.PluginInfo{
+		Name:             "fakeplugin1",
+		InstallationPath: "/path/to/plugin/fakeplugin1",
+		Version:          "1.0.0",
+	}
+	pd2 := cli.PluginInfo{
+		Name:             "fakeplugin2",
+		InstallationPath: "/path/to/plugin/fakeplugin2",
+		Version:          "2.0.0",
+	}
+
+	cc, err := NewContextCatalogUpdater("")
+	assert.Nil(err)
+	assert.Nil(cc.Upsert(&pd1))
+	assert.Nil(cc.Upsert(&pd2))
+	cc.Unlock()
+
+	cc, err = NewContextCatalogUpdater("server")
+	assert.Nil(err)
+	assert.Nil(cc.Upsert(&pd2))
+	cc.Unlock()
+
+	plugins, err := ListPluginInstallations()
+	assert.Nil(err)
+	assert.Equal(2, len(plugins))
+	assert.Equal(pd1.InstallationPath, plugins[0].InstallationPath)
+	assert.Equal(pd2.InstallationPath, plugins[1].InstallationPath)
+
+	err = DeletePluginInstallations([]string{pd2.InstallationPath})
+	assert.Nil(err)
+
+	plugins, err = ListPluginInstallations()
+	assert.Nil(err)
+	assert.Equal(1, len(plugins))
+	assert.Equal(pd1.InstallationPath, plugins[0].InstallationPath)
+
+	// The associations referring to the deleted installation are removed
+	cc2, err := NewContextCatalog("")
+	assert.Nil(err)
+	_, exists := cc2.Get("fakeplugin2")
+	assert.False(exists)
+	_, exists = cc2.Get("fakeplugin1")
+	assert.True(exists)
+
+	cc3, err := NewContextCatalog("server")
+	assert.Nil(err)
+	assert.Empty(cc3.List())
+}
+
+func Test_DeleteContextPlugins(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "test-catalog")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	common.DefaultCacheDir = dir
+
+	pd := cli.PluginInfo{
+		Name:             "fakeplugin",
+		InstallationPath: "/path/to/plugin/fakeplugin",
+		Version:          "1.0.0",
+	}
+	for _, context := range []string{"", "server1", "server2"} {
+		cc, err := NewContextCatalogUpdater(context)
+		assert.Nil(err)
+		assert.Nil(cc.Upsert(&pd))
+		cc.Unlock()
+	}
+
+	assert.Nil(DeleteContextPlugins("server1"))
+	// Deleting the plugins of a context without plugins is a no-op
+	assert.Nil(DeleteContextPlugins("unknown"))
+
+	c, _, err := getCatalogCache(false)
+	assert.Nil(err)
+	assert.NotContains(c.ServerPlugins, "server1")
+	assert.Contains(c.ServerPlugins, "server2")
+
+	// The plugin installation is kept for the standalone and other context associations
+	for _, context := range []string{"", "server2"} {
+		cc, err := NewContextCatalog(context)
+		assert.Nil(err)
+		_, exists := cc.Get("fakeplugin")
+		assert.True(exists)
+	}
+	plugins, err := ListPluginInstallations()
+	assert.Nil(err)
+	assert.Equal(1, len(plugins))
+}
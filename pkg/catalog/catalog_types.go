@@ -34,6 +34,14 @@ func (pa PluginAssociation) Map() map[string]string {
 	return pa
 }
 
+// PluginVersionRange is the range of versions of a plugin supported by a context
+type PluginVersionRange struct {
+	// MinVersion is the oldest supported version of the plugin, if any
+	MinVersion string `json:"minVersion,omitempty" yaml:"minVersion,omitempty"`
+	// MaxVersion is the most recent supported version of the plugin, if any
+	MaxVersion string `json:"maxVersion,omitempty" yaml:"maxVersion,omitempty"`
+}
+
 // Catalog is the Schema for the plugin catalog data
 type Catalog struct {
 	// PluginInfos is a list of PluginInfo
@@ -48,6 +56,8 @@ type Catalog struct {
 	StandAlonePlugins PluginAssociation `json:"standAlonePlugins,omitempty" yaml:"standAlonePlugins,omitempty"`
 	// ServerPlugins links a server and a set of associated plugin installations.
 	ServerPlugins map[string]PluginAssociation `json:"serverPlugins,omitempty" yaml:"serverPlugins,omitempty"`
+	// ServerPluginVersionRanges links a server and the range of versions it supports for each plugin.
+	ServerPluginVersionRanges map[string]map[string]PluginVersionRange `json:"serverPluginVersionRanges,omitempty" yaml:"serverPluginVersionRanges,omitempty"`
 }
 
 // CatalogList contains a list of Catalog
@@ -4,8 +4,13 @@
 package catalog
 
 import (
+	"slices"
+	"sort"
+
 	configlib "github.com/vmware-tanzu/tanzu-plugin-runtime/config"
 	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
 )
 
 // DeleteIncorrectPluginEntriesFromCatalog deletes the old plugin entries associated with
@@ -71,3 +76,81 @@ func MigrateContextPluginsAsStandaloneIfNeeded() {
 	}
 	_ = saveCatalogCache(c, lockedFile)
 }
+
+// DeleteContextPlugins removes the association between the context and the
+// plugins installed for it from the catalog cache, along with the range of
+// plugin versions supported by the context. The plugin installations
+// themselves are kept, as they can be shared with other contexts.
+func DeleteContextPlugins(contextName string) error {
+	c, lockedFile, err := getCatalogCache(true)
+	if err != nil {
+		return err
+	}
+	defer lockedFile.Close()
+
+	_, hasPlugins := c.ServerPlugins[contextName]
+	_, hasVersionRanges := c.ServerPluginVersionRanges[contextName]
+	if !hasPlugins && !hasVersionRanges {
+		return nil
+	}
+	delete(c.ServerPlugins, contextName)
+	delete(c.ServerPluginVersionRanges, contextName)
+	return saveCatalogCache(c, lockedFile)
+}
+
+// ListPluginInstallations returns all the plugin installations known to the
+// catalog, whether they are stand-alone or associated with a context.
+func ListPluginInstallations() ([]cli.PluginInfo, error) {
+	c, _, err := getCatalogCache(false)
+	if err != nil {
+		return nil, err
+	}
+
+	plugins := make([]cli.PluginInfo, 0, len(c.IndexByPath))
+	for _, pi := range c.IndexByPath {
+		plugins = append(plugins, pi)
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		if plugins[i].Name != plugins[j].Name {
+			return plugins[i].Name < plugins[j].Name
+		}
+		return plugins[i].InstallationPath < plugins[j].InstallationPath
+	})
+	return plugins, nil
+}
+
+// DeletePluginInstallations removes the specified plugin installations from the
+// catalog along with every stand-alone or context association referring to them.
+// The plugin binaries are not deleted.
+func DeletePluginInstallations(installationPaths []string) error {
+	c, lockedFile, err := getCatalogCache(true)
+	if err != nil {
+		return err
+	}
+	defer lockedFile.Close()
+
+	for _, path := range installationPaths {
+		delete(c.IndexByPath, path)
+		for name, paths := range c.IndexByName {
+			paths = slices.DeleteFunc(paths, func(p string) bool { return p == path })
+			if len(paths) == 0 {
+				delete(c.IndexByName, name)
+			} else {
+				c.IndexByName[name] = paths
+			}
+		}
+
+		pluginAssociations := []PluginAssociation{c.StandAlonePlugins}
+		for _, spa := range c.ServerPlugins {
+			pluginAssociations = append(pluginAssociations, spa)
+		}
+		for _, pa := range pluginAssociations {
+			for name, p := range pa {
+				if p == path {
+					pa.Remove(name)
+				}
+			}
+		}
+	}
+	return saveCatalogCache(c, lockedFile)
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package catalog
+
+import (
+	"reflect"
+
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+)
+
+// SetContextPluginVersionRanges records the range of versions supported by the context
+// for each plugin, keyed by PluginNameTarget(), replacing the ranges recorded before.
+// The catalog cache is only updated if the ranges changed.
+func SetContextPluginVersionRanges(contextName string, ranges map[string]PluginVersionRange) error {
+	c, _, err := getCatalogCache(false)
+	if err != nil {
+		return err
+	}
+	if len(ranges) == 0 && len(c.ServerPluginVersionRanges[contextName]) == 0 ||
+		reflect.DeepEqual(c.ServerPluginVersionRanges[contextName], ranges) {
+		return nil
+	}
+
+	c, lockedFile, err := getCatalogCache(true)
+	if err != nil {
+		return err
+	}
+	defer lockedFile.Close()
+
+	if c.ServerPluginVersionRanges == nil {
+		c.ServerPluginVersionRanges = map[string]map[string]PluginVersionRange{}
+	}
+	if len(ranges) == 0 {
+		delete(c.ServerPluginVersionRanges, contextName)
+	} else {
+		c.ServerPluginVersionRanges[contextName] = ranges
+	}
+	return saveCatalogCache(c, lockedFile)
+}
+
+// GetContextPluginVersionRange returns the range of versions of the plugin supported by
+// the context, and false if the context did not specify any range for the plugin
+func GetContextPluginVersionRange(contextName, pluginName string, target configtypes.Target) (PluginVersionRange, bool, error) {
+	c, _, err := getCatalogCache(false)
+	if err != nil {
+		return PluginVersionRange{}, false, err
+	}
+	versionRange, exists := c.ServerPluginVersionRanges[contextName][PluginNameTarget(pluginName, target)]
+	return versionRange, exists, nil
+}
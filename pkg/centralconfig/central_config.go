@@ -57,6 +57,12 @@ func newCentralConfigReader(pluginDiscoveryName string) CentralConfig {
 	return &centralConfigYamlReader{configFile: centralConfigFile}
 }
 
+// NewCentralConfigReaderFromFile returns a CentralConfig reader that reads the central
+// configuration values from the specified central config file, e.g. the one of a plugin bundle.
+func NewCentralConfigReaderFromFile(centralConfigFile string) CentralConfig {
+	return &centralConfigYamlReader{configFile: centralConfigFile}
+}
+
 // newDefaultCentralConfigReader returns a CentralConfig reader that can be used to read default central configuration values.
 //
 // Note: This function is currently private because the pre-initialized `DefaultCentralConfigReader` object should be used instead.
@@ -52,6 +52,9 @@ type Plugin struct {
 
 	// Versions available for plugin.
 	Versions []string `json:"versions" yaml:"versions"`
+
+	// ReleaseNotes are the release notes of the versions of the plugin, by version.
+	ReleaseNotes map[string]string `json:"releaseNotes,omitempty" yaml:"releaseNotes,omitempty"`
 }
 
 // PluginGroupManifest is used to parse metadata about Plugin Groups
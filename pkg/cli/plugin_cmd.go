@@ -20,6 +20,35 @@ import (
 	"github.com/vmware-tanzu/tanzu-cli/pkg/common"
 )
 
+// PluginCompletionFunc provides the completions for a plugin command without
+// invoking the plugin. The cmdPath is the path of the CLI command representing
+// the plugin (excluding the root command). The last return value should be
+// false if the completion cannot be provided, in which case the plugin will be
+// invoked to provide it.
+type PluginCompletionFunc func(p *PluginInfo, cmdPath, args []string, toComplete string) ([]string, cobra.ShellCompDirective, bool)
+
+// pluginCompletionFunc is consulted, when set, before delegating the completion to the plugin
+var pluginCompletionFunc PluginCompletionFunc
+
+// SetPluginCompletionFunc sets the function to use to complete plugin commands
+// without invoking the plugin. Passing nil always delegates completion to the plugin.
+func SetPluginCompletionFunc(f PluginCompletionFunc) {
+	pluginCompletionFunc = f
+}
+
+// PluginAvailabilityFunc returns an error describing why the plugin cannot be
+// used in the current environment, in which case the plugin is not invoked.
+type PluginAvailabilityFunc func(p *PluginInfo) error
+
+// pluginAvailabilityFunc is consulted, when set, before invoking a plugin command
+var pluginAvailabilityFunc PluginAvailabilityFunc
+
+// SetPluginAvailabilityFunc sets the function to use to verify that a plugin
+// can be used before invoking it. Passing nil always invokes the plugin.
+func SetPluginAvailabilityFunc(f PluginAvailabilityFunc) {
+	pluginAvailabilityFunc = f
+}
+
 // CommandMapProcessor process the plugin's command map to
 // determine how commands should be mapped in the CLI command tree.
 type CommandMapProcessor interface {
@@ -183,6 +212,11 @@ func getCmdForPluginEx(p *PluginInfo, cmdName string, mapEntry *plugin.CommandMa
 		Use:   cmdName,
 		Short: description,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if pluginAvailabilityFunc != nil {
+				if err := pluginAvailabilityFunc(p); err != nil {
+					return err
+				}
+			}
 			if len(srcHierarchy) > 0 {
 				args = append(srcHierarchy, args...)
 			}
@@ -208,6 +242,14 @@ func getCmdForPluginEx(p *PluginInfo, cmdName string, mapEntry *plugin.CommandMa
 	}
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		// Subcommand and flag names can be completed without invoking the plugin
+		if pluginCompletionFunc != nil {
+			cmdPath := strings.Fields(cmd.CommandPath())[1:]
+			if comps, directive, ok := pluginCompletionFunc(p, cmdPath, args, toComplete); ok {
+				return comps, directive
+			}
+		}
+
 		// Parses the completion info provided by cobra.Command. This should be formatted similar to:
 		//   help	Help about any command
 		//   :4
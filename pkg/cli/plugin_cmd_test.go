@@ -60,6 +60,44 @@ func TestGetCmdForPlugin(t *testing.T) {
 	assert.Equal("", annotations[common.AnnotationForCmdSrcPath])
 }
 
+func TestGetCmdForUnavailablePlugin(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "tanzu-cli-getcmd")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	path, err := setupFakePlugin(dir, "fakefoo", "")
+	assert.Nil(err)
+
+	pi := &PluginInfo{
+		Name:             "fakefoo",
+		Description:      "Fake foo",
+		Group:            plugin.SystemCmdGroup,
+		InstallationPath: path,
+	}
+
+	var checkedPlugin *PluginInfo
+	SetPluginAvailabilityFunc(func(p *PluginInfo) error {
+		checkedPlugin = p
+		return fmt.Errorf("plugin %q is unavailable", p.Name)
+	})
+	defer SetPluginAvailabilityFunc(nil)
+
+	cmd := GetCmdForPlugin(pi)
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	err = cmd.Execute()
+	assert.NotNil(err)
+	assert.Contains(err.Error(), `plugin "fakefoo" is unavailable`)
+	assert.Equal(pi, checkedPlugin)
+
+	SetPluginAvailabilityFunc(func(p *PluginInfo) error { return nil })
+	err = cmd.Execute()
+	assert.Nil(err)
+}
+
 func TestGetCmdForRemappedPlugin(t *testing.T) {
 	assert := assert.New(t)
 
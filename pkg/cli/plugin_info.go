@@ -86,8 +86,61 @@ type PluginInfo struct {
 	// or more parts of the plugin's command tree will be remapped in the Tanzu CLI
 	// EXPERIMENTAL: subject to change prior to the next official minor release
 	CommandMap []plugin.CommandMapEntry `json:"commandMap,omitempty" yaml:"commandMap,omitempty"`
+
+	// ContextEventHooks specifies the context events for which the CLI should invoke
+	// the 'context-hook' command of the plugin.
+	// EXPERIMENTAL: subject to change prior to the next official minor release
+	ContextEventHooks []ContextEvent `json:"contextEventHooks,omitempty" yaml:"contextEventHooks,omitempty"`
+
+	// DiagnosticChecks specifies whether the plugin provides diagnostic checks
+	// through its 'doctor' command, which the CLI invokes as part of 'tanzu doctor'.
+	// EXPERIMENTAL: subject to change prior to the next official minor release
+	DiagnosticChecks bool `json:"diagnosticChecks,omitempty" yaml:"diagnosticChecks,omitempty"`
+
+	// ClusterRequirements specifies the capabilities the cluster of the active context
+	// must provide for the plugin to be usable with that cluster.
+	// EXPERIMENTAL: subject to change prior to the next official minor release
+	ClusterRequirements *ClusterRequirements `json:"clusterRequirements,omitempty" yaml:"clusterRequirements,omitempty"`
 }
 
+// ClusterRequirements describes the capabilities a cluster must provide for a plugin
+type ClusterRequirements struct {
+	// MinKubernetesVersion is the minimum Kubernetes version of the cluster. E.g., v1.26.0
+	MinKubernetesVersion string `json:"minKubernetesVersion,omitempty" yaml:"minKubernetesVersion,omitempty"`
+
+	// APIs are the APIs that must be served by the cluster
+	APIs []ClusterAPIRequirement `json:"apis,omitempty" yaml:"apis,omitempty"`
+}
+
+// ClusterAPIRequirement describes an API group that must be served by a cluster.
+// Omitting the versions or the resource matches any version or resource of the group.
+type ClusterAPIRequirement struct {
+	// Group is the name of the API group. E.g., apps
+	Group string `json:"group" yaml:"group"`
+
+	// Versions are the versions of the API group that must all be served
+	Versions []string `json:"versions,omitempty" yaml:"versions,omitempty"`
+
+	// Resource is the resource that must be served in the API group. E.g., deployments
+	Resource string `json:"resource,omitempty" yaml:"resource,omitempty"`
+}
+
+// ContextEvent is an event related to CLI contexts that plugins can react to
+type ContextEvent string
+
+const (
+	// ContextEventCreated is triggered after a context is created
+	ContextEventCreated ContextEvent = "context-created"
+	// ContextEventActivated is triggered after a context is set as the active context
+	ContextEventActivated ContextEvent = "context-activated"
+	// ContextEventDeleted is triggered after a context is deleted
+	ContextEventDeleted ContextEvent = "context-deleted"
+	// ContextEventPreLogin is triggered before logging in to the endpoint of a context
+	ContextEventPreLogin ContextEvent = "pre-login"
+	// ContextEventPostLogin is triggered after successfully logging in to the endpoint of a context
+	ContextEventPostLogin ContextEvent = "post-login"
+)
+
 // PluginInfoSorter sorts PluginInfo objects.
 type PluginInfoSorter []PluginInfo
 
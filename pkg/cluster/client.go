@@ -27,6 +27,7 @@ import (
 	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
 
 	cliv1alpha1 "github.com/vmware-tanzu/tanzu-cli/apis/cli/v1alpha1"
+	cliv1alpha2 "github.com/vmware-tanzu/tanzu-cli/apis/cli/v1alpha2"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/constants"
 )
 
@@ -38,6 +39,7 @@ func init() {
 	_ = corev1.AddToScheme(scheme)
 	_ = appsv1.AddToScheme(scheme)
 	_ = cliv1alpha1.AddToScheme(scheme)
+	_ = cliv1alpha2.AddToScheme(scheme)
 }
 
 // Client provides various aspects of interaction with a Kubernetes cluster provisioned by TKG
@@ -46,13 +48,19 @@ func init() {
 type Client interface {
 	// ListCLIPluginResources lists CLIPlugin resources across all namespaces
 	ListCLIPluginResources() ([]cliv1alpha1.CLIPlugin, error)
+	// ListCLIPluginV1alpha2Resources lists v1alpha2 CLIPlugin resources across all namespaces
+	ListCLIPluginV1alpha2Resources() ([]cliv1alpha2.CLIPlugin, error)
 	// VerifyCLIPluginCRD returns true if CRD exists else return false
 	VerifyCLIPluginCRD() (bool, error)
+	// VerifyCLIPluginV1alpha2API returns true if the v1alpha2 version of the CLIPlugin API is served else return false
+	VerifyCLIPluginV1alpha2API() (bool, error)
 	// GetCLIPluginImageRepositoryOverride returns map of image repository override
 	GetCLIPluginImageRepositoryOverride() (map[string]string, error)
 
 	// BuildClusterQuery builds ClusterQuery with Dynamic client and Discovery client
 	BuildClusterQuery() (*capdiscovery.ClusterQuery, error)
+	// BuildClusterQueryClient builds ClusterQueryClient with Dynamic client and Discovery client
+	BuildClusterQueryClient() (*capdiscovery.ClusterQueryClient, error)
 }
 
 //go:generate counterfeiter -o ../fakes/CrtClient_fake.go --fake-name CrtClientFake . CrtClient
@@ -151,7 +159,7 @@ func (c *client) VerifyCLIPluginCRD() (bool, error) {
 }
 
 func (c *client) BuildClusterQuery() (*capdiscovery.ClusterQuery, error) {
-	clusterQueryClient, err := capdiscovery.NewClusterQueryClient(c.DynamicClient, c.DiscoveryClient)
+	clusterQueryClient, err := c.BuildClusterQueryClient()
 	if err != nil {
 		return nil, err
 	}
@@ -162,6 +170,35 @@ func (c *client) BuildClusterQuery() (*capdiscovery.ClusterQuery, error) {
 	return clusterQueryClient.Query(queryObject), nil
 }
 
+// BuildClusterQueryClient builds ClusterQueryClient with Dynamic client and Discovery client
+func (c *client) BuildClusterQueryClient() (*capdiscovery.ClusterQueryClient, error) {
+	return capdiscovery.NewClusterQueryClient(c.DynamicClient, c.DiscoveryClient)
+}
+
+// VerifyCLIPluginV1alpha2API returns true if the v1alpha2 version of the CLIPlugin API is served else return false
+func (c *client) VerifyCLIPluginV1alpha2API() (bool, error) {
+	clusterQueryClient, err := c.BuildClusterQueryClient()
+	if err != nil {
+		return false, err
+	}
+
+	queryObject := capdiscovery.Group("cliPluginsV1alpha2", cliv1alpha2.GroupVersion.Group).
+		WithVersions(cliv1alpha2.GroupVersion.Version).
+		WithResource("cliplugins")
+
+	return clusterQueryClient.Query(queryObject).Execute()
+}
+
+// ListCLIPluginV1alpha2Resources lists v1alpha2 CLIPlugin resources across all namespaces
+func (c *client) ListCLIPluginV1alpha2Resources() ([]cliv1alpha2.CLIPlugin, error) {
+	var cliPlugins cliv1alpha2.CLIPluginList
+	err := c.CrtClient.ListObjects(context.TODO(), &cliPlugins, &crtclient.ListOptions{Namespace: ""})
+	if err != nil {
+		return nil, err
+	}
+	return cliPlugins.Items, nil
+}
+
 // ListCLIPluginResources lists CLIPlugin resources across all namespaces
 func (c *client) ListCLIPluginResources() ([]cliv1alpha1.CLIPlugin, error) {
 	var cliPlugins cliv1alpha1.CLIPluginList
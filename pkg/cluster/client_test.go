@@ -70,6 +70,19 @@ var _ = Describe("New Cluster Client Tests", func() {
 				Expect(err).To(BeNil())
 			})
 		})
+		Context("when list v1alpha2 plugin's don't return any plugins ", func() {
+			BeforeEach(func() {
+				discoveryClientFactoryFake.NewDiscoveryClientForConfigReturns(&discovery.DiscoveryClient{}, nil)
+				discoveryClientFactoryFake.ServerVersionReturns(nil, nil)
+				clusterClient, _ = cluster.NewClient(kubeconfigFile, "foo-context", nil, options)
+				crtClientFake.ListObjectsReturns(nil)
+			})
+			It("return empty plugins and no error", func() {
+				plugins, err := clusterClient.ListCLIPluginV1alpha2Resources()
+				Expect(plugins).To(BeNil())
+				Expect(err).To(BeNil())
+			})
+		})
 		Context("when BuildClusterQuery() called", func() {
 			BeforeEach(func() {
 				discoveryClientFactoryFake.NewDiscoveryClientForConfigReturns(&discovery.DiscoveryClient{}, nil)
@@ -4,16 +4,19 @@
 package command
 
 import (
-	"errors"
 	"fmt"
 	"io"
 	"os"
 	"strings"
 
 	"github.com/lithammer/dedent"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
 
 	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/plugincmdtree"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/pluginsupplier"
 	"github.com/vmware-tanzu/tanzu-plugin-runtime/plugin"
 )
 
@@ -32,7 +35,11 @@ var (
 The shell completion code must be evaluated to provide completion. See Examples
 for how to perform this for your given shell.
 
-Note for bash users: make sure the bash-completions package has been installed.`
+Note for bash users: make sure the bash-completions package has been installed.
+
+Subcommands and flags of plugins are completed from the plugin command tree
+cache without invoking the plugins. Use --refresh-cache to regenerate this
+cache after plugins have changed.`
 
 	completionExamples = dedent.Dedent(`
 		# Bash instructions:
@@ -86,10 +93,16 @@ Note for bash users: make sure the bash-completions package has been installed.`
 
 		  ## If you invoke the 'tanzu' command using a different name or an alias such as,
 		  ## for example, 'tz', you must also include the following in your powershell $PROFILE.
-		  Register-ArgumentCompleter -CommandName 'tz' -ScriptBlock ${__tanzuCompleterBlock}`)
+		  Register-ArgumentCompleter -CommandName 'tz' -ScriptBlock ${__tanzuCompleterBlock}
+
+		# Regenerate the cache used to complete plugin commands:
+
+		  tanzu completion --refresh-cache`)
 )
 
 func newCompletionCmd() *cobra.Command {
+	var refreshCache bool
+
 	// completionCmd represents the completion command
 	completionCmd := &cobra.Command{
 		Use:                   fmt.Sprintf("completion [%v]", strings.Join(completionShells, "|")),
@@ -104,12 +117,22 @@ func newCompletionCmd() *cobra.Command {
 			return activeHelpNoMoreArgs(nil), cobra.ShellCompDirectiveNoFileComp
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if refreshCache {
+				if err := refreshPluginCommandTreeCache(cmd.Root()); err != nil {
+					return err
+				}
+				// Refreshing the cache does not require a shell to be specified
+				if len(args) == 0 {
+					return nil
+				}
+			}
 			return runCompletion(os.Stdout, cmd, args)
 		},
 		Annotations: map[string]string{
 			"group": string(plugin.SystemCmdGroup),
 		},
 	}
+	completionCmd.Flags().BoolVar(&refreshCache, "refresh-cache", false, "regenerate the plugin command tree cache used to complete plugin commands")
 	completionCmd.SetUsageFunc(cli.SubCmdUsageFunc)
 
 	return completionCmd
@@ -135,3 +158,29 @@ func runCompletion(out io.Writer, cmd *cobra.Command, args []string) error {
 		return errors.New("unrecognized shell type specified")
 	}
 }
+
+// refreshPluginCommandTreeCache regenerates the command tree of every installed
+// plugin so that plugin subcommands and flags can be completed without invoking
+// the plugins.
+func refreshPluginCommandTreeCache(rootCmd *cobra.Command) error {
+	plugins, err := pluginsupplier.GetInstalledPlugins()
+	if err != nil {
+		return err
+	}
+
+	cache, err := plugincmdtree.NewCache()
+	if err != nil {
+		return err
+	}
+	if err := cache.DeleteTree(); err != nil {
+		return errors.Wrap(err, "failed to delete the plugin command tree cache")
+	}
+
+	var errorList []error
+	for i := range plugins {
+		if _, err := cache.GetPluginTree(rootCmd, &plugins[i]); err != nil {
+			errorList = append(errorList, err)
+		}
+	}
+	return kerrors.NewAggregate(errorList)
+}
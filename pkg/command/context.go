@@ -43,10 +43,12 @@ import (
 	"github.com/vmware-tanzu/tanzu-cli/pkg/auth/uaa"
 	kubecfg "github.com/vmware-tanzu/tanzu-cli/pkg/auth/utils/kubeconfig"
 	wcpauth "github.com/vmware-tanzu/tanzu-cli/pkg/auth/wcp"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/catalog"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/constants"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/discovery"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/pluginmanager"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/pluginsupplier"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/utils"
 )
 
@@ -235,6 +237,7 @@ func createCtx(cmd *cobra.Command, args []string) (err error) {
 	if err != nil {
 		return err
 	}
+	runContextEventHooks(cli.ContextEventPreLogin, ctx)
 	if ctx.ContextType == configtypes.ContextTypeK8s {
 		err = k8sLogin(ctx)
 	} else if ctx.ContextType == configtypes.ContextTypeTanzu {
@@ -247,6 +250,7 @@ func createCtx(cmd *cobra.Command, args []string) (err error) {
 	if err != nil {
 		return err
 	}
+	runContextEventHooks(cli.ContextEventPostLogin, ctx)
 
 	// TODO: update the below conditional check (and in login command) after context scope plugin support
 	//       is implemented for tanzu context(Tanzu Platform for Kubernetes)
@@ -256,9 +260,20 @@ func createCtx(cmd *cobra.Command, args []string) (err error) {
 			log.Warningf("unable to automatically sync the plugins recommended by the new context. Please run 'tanzu plugin sync' to sync plugins manually, error: '%v'", err.Error())
 		}
 	}
+
+	// Notify the plugins once the recommended plugins are installed so they can also react
+	runContextEventHooks(cli.ContextEventCreated, ctx)
 	return nil
 }
 
+// runContextEventHooks invokes the plugins that registered for the context event.
+// Failures of the plugins are reported but do not fail the context operation.
+var runContextEventHooks = func(event cli.ContextEvent, ctx *configtypes.Context) {
+	if err := pluginmanager.RunContextEventHooks(event, ctx); err != nil {
+		log.Warningf("some plugins failed to process the %q event of context %q: %v", event, ctx.Name, err)
+	}
+}
+
 func validateContextCreateFlagValues() error {
 	if contextTypeStr == string(configtypes.ContextTypeTanzu) && kubeConfig != "" {
 		return fmt.Errorf("the '--kubeconfig' flag is not applicable when creating a context of type 'tanzu'")
@@ -287,13 +302,22 @@ func syncContextPlugins(cmd *cobra.Command, contextType configtypes.ContextType,
 	}
 
 	// update plugins installation status
-	pluginmanager.UpdatePluginsInstallationStatus(plugins)
+	contextScopedPlugins := pluginsupplier.IsContextScopedPluginsEnabled()
+	if contextScopedPlugins {
+		pluginmanager.UpdateContextPluginsInstallationStatus(plugins, ctxName)
+	} else {
+		pluginmanager.UpdatePluginsInstallationStatus(plugins)
+	}
 
 	// sort the plugins based on the plugin name
 	sort.Sort(discovery.DiscoveredSorter(plugins))
 
+	// once the plugins are installed, report the ones the cluster of the context cannot support
+	defer warnPluginsUnavailableForCluster(plugins, ctxName)
+
 	pluginsNeedToBeInstalled := []discovery.Discovered{}
 	for idx := range plugins {
+		warnDeprecatedOrUnsupportedPlugin(&plugins[idx], ctxName)
 		if plugins[idx].Status == common.PluginStatusNotInstalled || plugins[idx].Status == common.PluginStatusUpdateAvailable {
 			pluginsNeedToBeInstalled = append(pluginsNeedToBeInstalled, plugins[idx])
 		}
@@ -308,7 +332,11 @@ func syncContextPlugins(cmd *cobra.Command, contextType configtypes.ContextType,
 	log.Infof("Installing the following plugins recommended by context '%s':", ctxName)
 	displayToBeInstalledPluginsAsTable(plugins, cmd.ErrOrStderr())
 	for i := range pluginsNeedToBeInstalled {
-		err = pluginmanager.InstallStandalonePlugin(pluginsNeedToBeInstalled[i].Name, pluginsNeedToBeInstalled[i].RecommendedVersion, pluginsNeedToBeInstalled[i].Target)
+		if contextScopedPlugins {
+			err = pluginmanager.InstallContextPlugin(pluginsNeedToBeInstalled[i].Name, pluginsNeedToBeInstalled[i].RecommendedVersion, pluginsNeedToBeInstalled[i].Target, ctxName)
+		} else {
+			err = pluginmanager.InstallStandalonePlugin(pluginsNeedToBeInstalled[i].Name, pluginsNeedToBeInstalled[i].RecommendedVersion, pluginsNeedToBeInstalled[i].Target)
+		}
 		if err != nil {
 			errList = append(errList, err)
 		}
@@ -321,6 +349,54 @@ func syncContextPlugins(cmd *cobra.Command, contextType configtypes.ContextType,
 	return err
 }
 
+// warnPluginsUnavailableForCluster warns about the installed recommended plugins
+// whose cluster requirements are not met by the cluster of the context
+func warnPluginsUnavailableForCluster(recommendedPlugins []discovery.Discovered, ctxName string) {
+	ctx, err := config.GetContext(ctxName)
+	if err != nil {
+		return
+	}
+	installedPlugins, err := pluginsupplier.GetInstalledPlugins()
+	if err != nil {
+		return
+	}
+
+	var plugins []cli.PluginInfo
+	for i := range installedPlugins {
+		for j := range recommendedPlugins {
+			if installedPlugins[i].Name == recommendedPlugins[j].Name && installedPlugins[i].Target == recommendedPlugins[j].Target {
+				plugins = append(plugins, installedPlugins[i])
+				break
+			}
+		}
+	}
+
+	unavailablePlugins, err := pluginmanager.CheckClusterRequirements(plugins, ctx)
+	if err != nil {
+		log.Warningf("unable to verify the cluster requirements of the plugins recommended by context '%s': %v", ctxName, err)
+		return
+	}
+	for _, unavailable := range unavailablePlugins {
+		log.Warning(unavailable.Error())
+	}
+}
+
+// warnDeprecatedOrUnsupportedPlugin warns if the plugin is deprecated by the context
+// or if the installed version of the plugin is not supported by the context
+func warnDeprecatedOrUnsupportedPlugin(p *discovery.Discovered, ctxName string) {
+	if p.Deprecated {
+		msg := fmt.Sprintf("plugin '%s' is deprecated by context '%s'", p.Name, ctxName)
+		if p.ReplacedBy != "" {
+			msg += fmt.Sprintf(", use plugin '%s' instead", p.ReplacedBy)
+		}
+		log.Warning(msg)
+	}
+	if p.InstalledVersion != "" && !p.IsVersionSupported(p.InstalledVersion) {
+		log.Warningf("installed version '%s' of plugin '%s' is not supported by context '%s' (supported versions: %s)",
+			p.InstalledVersion, p.Name, ctxName, utils.DescribeVersionRange(p.MinVersion, p.MaxVersion))
+	}
+}
+
 // displayToBeInstalledPluginsAsTable takes a list of plugins and displays the plugin info as a table
 func displayToBeInstalledPluginsAsTable(plugins []discovery.Discovered, writer io.Writer) {
 	outputPlugins := component.NewOutputWriterWithOptions(writer, outputFormat, []component.OutputWriterOption{}, "Name", "Target", "Current", "Installing")
@@ -1583,7 +1659,13 @@ func deleteCtx(_ *cobra.Command, args []string) error {
 	}
 
 	deleteKubeconfigContext(ctx)
+	if pluginsupplier.IsContextScopedPluginsEnabled() {
+		if err := catalog.DeleteContextPlugins(name); err != nil {
+			log.Warningf("Failed to remove the plugins installed for context %q: %v", name, err)
+		}
+	}
 	log.Successf("Successfully deleted context %q", name)
+	runContextEventHooks(cli.ContextEventDeleted, ctx)
 	return nil
 }
 
@@ -1686,6 +1768,7 @@ func useCtx(cmd *cobra.Command, args []string) error { //nolint:gocyclo
 			log.Warningf("unable to automatically sync the plugins recommended by the active context. Please run 'tanzu plugin sync' to sync plugins manually, error: '%v'", err.Error())
 		}
 	}
+	runContextEventHooks(cli.ContextEventActivated, ctx)
 	return nil
 }
 
@@ -26,8 +26,11 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 
 	"github.com/vmware-tanzu/tanzu-cli/pkg/auth/common"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/catalog"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/centralconfig"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/centralconfig/fakes"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+	clicommon "github.com/vmware-tanzu/tanzu-cli/pkg/common"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/constants"
 	"github.com/vmware-tanzu/tanzu-plugin-runtime/config"
 	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
@@ -469,6 +472,27 @@ clusterOpts:
 			Expect(err.Error()).To(ContainSubstring("context test-tanzu-context not found"))
 
 		})
+		It("should delete context successfully and remove the plugins installed for the context when context-scoped plugins are enabled", func() {
+			cacheDir, err := os.MkdirTemp("", "test-catalog")
+			Expect(err).To(BeNil())
+			defer os.RemoveAll(cacheDir)
+			defer func(dir string) { clicommon.DefaultCacheDir = dir }(clicommon.DefaultCacheDir)
+			clicommon.DefaultCacheDir = cacheDir
+
+			cc, err := catalog.NewContextCatalogUpdater(existingContext)
+			Expect(err).To(BeNil())
+			Expect(cc.Upsert(&cli.PluginInfo{Name: "fakeplugin", InstallationPath: "/path/to/plugin/fakeplugin", Version: "v1.0.0"})).To(Succeed())
+			cc.Unlock()
+
+			os.Setenv(constants.ContextScopedPlugins, "true")
+			defer os.Unsetenv(constants.ContextScopedPlugins)
+			err = deleteCtx(cmd, []string{existingContext})
+			Expect(err).To(BeNil())
+
+			c, err := catalog.NewContextCatalog(existingContext)
+			Expect(err).To(BeNil())
+			Expect(c.List()).To(BeEmpty())
+		})
 	})
 
 	Describe("tanzu context get-token", func() {
@@ -86,7 +86,15 @@ func newUpdateDiscoverySourceCmd() *cobra.Command {
 		DisableFlagsInUseLine: true,
 		Example: `
     # Update the discovery source for an air-gapped scenario. The URI must be an OCI image.
-    tanzu plugin source update default --uri registry.example.com/tanzu/plugin-inventory:latest`,
+    tanzu plugin source update default --uri registry.example.com/tanzu/plugin-inventory:latest
+
+    # Update the discovery source to use an OCI image layout created by "tanzu plugin download-bundle --to-oci-layout"
+    # or an extracted plugin bundle, without any registry.
+    tanzu plugin source update default --uri file:///opt/tanzu-plugins/plugin-inventory:latest
+
+    # Update the discovery source to use a static plugin repository created by "tanzu builder inventory init --local-repository"
+    # and served by a web server. When stored on the filesystem, use file:///opt/tanzu-repository/plugin-inventory:latest instead.
+    tanzu plugin source update default --uri https://plugins.example.com/repository/plugin_inventory.db`,
 		Args:              cobra.ExactArgs(1),
 		ValidArgsFunction: completeUpdateDiscoverySource,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -0,0 +1,527 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cpuguy83/go-md2man/v2/md2man"
+	"github.com/fatih/color"
+	"github.com/pkg/errors"
+	"github.com/russross/blackfriday/v2"
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+	"golang.org/x/term"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/constants"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/plugincmdtree"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/pluginsupplier"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/utils"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/component"
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/plugin"
+)
+
+const (
+	docsFormatMarkdown = "markdown"
+	docsFormatHTML     = "html"
+	docsFormatMan      = "man"
+
+	// defaultDocsPager is used when neither TANZU_CLI_PAGER nor PAGER are set
+	defaultDocsPager = "less -R"
+)
+
+var (
+	docsNoPager       bool
+	docsExportFormat  string
+	docsExportDir     string
+	docsSearchVerbose bool
+
+	markdownLinkRegex = regexp.MustCompile(`\[([^\]]+)\]\([^)]+\.md\)`)
+	// pluginDocLinkRegex matches the links between the commands of a plugin, but not to the root command
+	pluginDocLinkRegex = regexp.MustCompile(`\[tanzu ([^\]]+)\]\(tanzu_([^)]+\.md)\)`)
+	// markdownFileLinkRegex matches the link targets to the markdown documentation of commands
+	markdownFileLinkRegex = regexp.MustCompile(`\(([^)]+)\.md\)`)
+)
+
+// commandDoc holds the markdown documentation of a single command
+type commandDoc struct {
+	// CommandPath is the full path of the command, e.g. "tanzu plugin install"
+	CommandPath string
+	// Short is the short description of the command
+	Short string
+	// FileName is the name of the markdown file of the command
+	FileName string
+	// Content is the markdown documentation of the command
+	Content string
+}
+
+const docsLongDesc = `Display the documentation of the Tanzu CLI commands and of the commands
+of all installed plugins.
+
+When a command is specified, its documentation is displayed, otherwise
+the list of all documented commands is displayed.
+The documentation of plugins is generated when first needed and is cached
+until the plugin is updated or removed.`
+
+func newDocsCmd() *cobra.Command {
+	var docsCmd = &cobra.Command{
+		Use:   "docs [command]",
+		Short: "Display the documentation of the CLI and plugin commands",
+		Long:  docsLongDesc,
+		Example: `
+    # List all documented commands
+    tanzu docs
+
+    # Display the documentation of the 'tanzu plugin install' command
+    tanzu docs plugin install
+
+    # Search the documentation of all commands
+    tanzu docs search kubeconfig
+
+    # Export the documentation of all commands as HTML
+    tanzu docs export --format html --output-dir ./tanzu-docs`,
+		ValidArgsFunction: completeDocsCommandPath,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			docs, err := collectCommandDocs(cmd.Root())
+			if err != nil {
+				return err
+			}
+
+			var buf bytes.Buffer
+			useColor := isTerminalOutput(cmd.OutOrStdout())
+			if len(args) == 0 {
+				renderDocsIndex(&buf, docs, useColor)
+			} else {
+				commandPath := strings.Join(append([]string{cmd.Root().Name()}, args...), " ")
+				cmdDoc := findCommandDoc(docs, commandPath)
+				if cmdDoc == nil {
+					return fmt.Errorf("no documentation found for the command %q", commandPath)
+				}
+				renderMarkdown(&buf, cmdDoc.Content, useColor)
+			}
+			return pageOutput(cmd.OutOrStdout(), buf.Bytes(), docsNoPager)
+		},
+		Annotations: map[string]string{
+			"group": string(plugin.SystemCmdGroup),
+		},
+	}
+
+	docsCmd.Flags().BoolVar(&docsNoPager, "no-pager", false, "do not display the documentation through a pager")
+	docsCmd.SetUsageFunc(cli.SubCmdUsageFunc)
+
+	docsCmd.AddCommand(
+		newDocsSearchCmd(),
+		newDocsExportCmd(),
+	)
+
+	return docsCmd
+}
+
+func newDocsSearchCmd() *cobra.Command {
+	var searchCmd = &cobra.Command{
+		Use:               "search TEXT",
+		Short:             "Search the documentation of all commands",
+		Long:              "Search the documentation of the CLI and plugin commands for the specified text. The search is case-insensitive.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: noMoreCompletions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			docs, err := collectCommandDocs(cmd.Root())
+			if err != nil {
+				return err
+			}
+
+			outputWriter := component.NewOutputWriterWithOptions(cmd.OutOrStdout(), outputFormat, []component.OutputWriterOption{}, "Command", "Description", "Match")
+			for _, result := range searchCommandDocs(docs, args[0]) {
+				match := result.Matches[0]
+				if docsSearchVerbose {
+					match = strings.Join(result.Matches, "\n")
+				}
+				outputWriter.AddRow(result.Doc.CommandPath, result.Doc.Short, match)
+			}
+			outputWriter.Render()
+			return nil
+		},
+	}
+
+	f := searchCmd.Flags()
+	f.BoolVar(&docsSearchVerbose, "all-matches", false, "show all matching lines of each command instead of only the first one")
+	f.StringVarP(&outputFormat, "output", "o", "", "output format (yaml|json|table)")
+	utils.PanicOnErr(searchCmd.RegisterFlagCompletionFunc("output", completionGetOutputFormats))
+
+	return searchCmd
+}
+
+func newDocsExportCmd() *cobra.Command {
+	var exportCmd = &cobra.Command{
+		Use:               "export",
+		Short:             "Export the documentation of all commands",
+		Long:              "Export the documentation of the CLI and plugin commands as markdown, HTML or man pages.",
+		Args:              cobra.NoArgs,
+		ValidArgsFunction: noMoreCompletions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if docsExportDir == "" {
+				return errors.New("the output directory must be specified with the '--output-dir' flag")
+			}
+			docs, err := collectCommandDocs(cmd.Root())
+			if err != nil {
+				return err
+			}
+			if err := exportCommandDocs(docs, docsExportFormat, docsExportDir); err != nil {
+				return err
+			}
+			log.Successf("Exported the documentation of %d commands to %q", len(docs), docsExportDir)
+			return nil
+		},
+	}
+
+	f := exportCmd.Flags()
+	f.StringVarP(&docsExportFormat, "format", "f", docsFormatMarkdown, fmt.Sprintf("format of the exported documentation (%s|%s|%s)", docsFormatMarkdown, docsFormatHTML, docsFormatMan))
+	utils.PanicOnErr(exportCmd.RegisterFlagCompletionFunc("format", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{docsFormatMarkdown, docsFormatHTML, docsFormatMan}, cobra.ShellCompDirectiveNoFileComp
+	}))
+	// Shell completion for this flag is directory completion
+	f.StringVarP(&docsExportDir, "output-dir", "d", "", "directory in which to export the documentation")
+	utils.PanicOnErr(exportCmd.MarkFlagDirname("output-dir"))
+
+	return exportCmd
+}
+
+// collectCommandDocs generates the markdown documentation of the core commands
+// and combines it with the cached documentation of every installed plugin
+func collectCommandDocs(rootCmd *cobra.Command) ([]*commandDoc, error) {
+	tmpDir, err := os.MkdirTemp("", "tanzu-docs")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := doc.GenMarkdownTree(rootCmd, tmpDir); err != nil {
+		return nil, errors.Wrap(err, "failed to generate the documentation of the core commands")
+	}
+	docsByPath, err := readCommandDocs(tmpDir, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	plugins, err := pluginsupplier.GetInstalledPlugins()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the installed plugins")
+	}
+	for i := range plugins {
+		pluginDocsDir, err := plugincmdtree.GetPluginDocsDir(&plugins[i])
+		if err != nil {
+			log.Warningf("Skipping the documentation of plugin %q: %v", plugins[i].Name, err)
+			continue
+		}
+		// The docs of a plugin take precedence over the placeholder docs generated
+		// for the plugin command by the CLI itself, except for the root command
+		if _, err := readCommandDocs(pluginDocsDir, &plugins[i], docsByPath); err != nil {
+			log.Warningf("Skipping the documentation of plugin %q: %v", plugins[i].Name, err)
+		}
+	}
+
+	docs := make([]*commandDoc, 0, len(docsByPath))
+	for _, d := range docsByPath {
+		docs = append(docs, d)
+	}
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].CommandPath < docs[j].CommandPath
+	})
+	return docs, nil
+}
+
+// readCommandDocs reads the markdown files in the specified directory and adds them
+// to the docsByPath map, keyed by command path, which is created if nil. The docs
+// of a plugin are placed under the same command path as the plugin commands.
+func readCommandDocs(dir string, p *cli.PluginInfo, docsByPath map[string]*commandDoc) (map[string]*commandDoc, error) {
+	rootDocFile := ""
+	if docsByPath == nil {
+		docsByPath = make(map[string]*commandDoc)
+	} else {
+		rootDocFile = "tanzu.md"
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".md" || file.Name() == rootDocFile {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		d := newCommandDoc(file.Name(), string(b))
+		if p == nil {
+			docsByPath[d.CommandPath] = d
+			continue
+		}
+
+		cmdPath := plugincmdtree.GetPluginCommandPath(d.CommandPath, p)
+		if cmdPath == d.CommandPath || p.Target == configtypes.TargetK8s {
+			// kubernetes plugins are also available at the root level
+			docsByPath[d.CommandPath] = d
+		}
+		if cmdPath != d.CommandPath {
+			docsByPath[cmdPath] = newTargetedCommandDoc(d, p.Target)
+		}
+	}
+	return docsByPath, nil
+}
+
+// newTargetedCommandDoc returns a copy of the documentation of a plugin command
+// moved under the target of the plugin, along with the links to the other commands
+func newTargetedCommandDoc(d *commandDoc, target configtypes.Target) *commandDoc {
+	rootCmd, subCmdPath, _ := strings.Cut(d.CommandPath, " ")
+	cmdPath := strings.Join([]string{rootCmd, string(target), subCmdPath}, " ")
+
+	lines := strings.Split(d.Content, "\n")
+	for i := range lines {
+		trimmed := strings.TrimLeft(lines[i], "# ")
+		if trimmed == d.CommandPath || strings.HasPrefix(trimmed, d.CommandPath+" ") {
+			lines[i] = strings.Replace(lines[i], d.CommandPath, cmdPath, 1)
+		}
+	}
+	content := pluginDocLinkRegex.ReplaceAllString(strings.Join(lines, "\n"), fmt.Sprintf("[%s %s $1](%s_%s_$2)", rootCmd, target, rootCmd, target))
+
+	return &commandDoc{
+		CommandPath: cmdPath,
+		Short:       d.Short,
+		FileName:    strings.ReplaceAll(cmdPath, " ", "_") + ".md",
+		Content:     content,
+	}
+}
+
+func newCommandDoc(fileName, content string) *commandDoc {
+	d := &commandDoc{
+		CommandPath: strings.ReplaceAll(strings.TrimSuffix(fileName, ".md"), "_", " "),
+		FileName:    fileName,
+		Content:     content,
+	}
+
+	inHeader := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(line, "## ") {
+			d.CommandPath = strings.TrimSpace(strings.TrimPrefix(line, "## "))
+			inHeader = true
+			continue
+		}
+		if inHeader && trimmed != "" {
+			d.Short = trimmed
+			break
+		}
+	}
+	return d
+}
+
+func findCommandDoc(docs []*commandDoc, commandPath string) *commandDoc {
+	for _, d := range docs {
+		if d.CommandPath == commandPath {
+			return d
+		}
+	}
+	return nil
+}
+
+// docsSearchResult holds the lines of a command documentation matching a search
+type docsSearchResult struct {
+	Doc     *commandDoc
+	Matches []string
+}
+
+// searchCommandDocs returns the documentation of the commands containing the
+// specified text, along with the matching lines
+func searchCommandDocs(docs []*commandDoc, text string) []docsSearchResult {
+	text = strings.ToLower(text)
+
+	var results []docsSearchResult
+	for _, d := range docs {
+		var matches []string
+		for _, line := range strings.Split(d.Content, "\n") {
+			line = strings.TrimSpace(line)
+			if strings.Contains(strings.ToLower(line), text) {
+				matches = append(matches, strings.TrimLeft(line, "#* "))
+			}
+		}
+		if len(matches) > 0 {
+			results = append(results, docsSearchResult{Doc: d, Matches: matches})
+		}
+	}
+	return results
+}
+
+func renderDocsIndex(w io.Writer, docs []*commandDoc, useColor bool) {
+	bold := color.New(color.Bold)
+	cyanBold := color.New(color.FgCyan).Add(color.Bold)
+	if !useColor {
+		bold.DisableColor()
+		cyanBold.DisableColor()
+	}
+
+	maxLen := 0
+	for _, d := range docs {
+		if len(d.CommandPath) > maxLen {
+			maxLen = len(d.CommandPath)
+		}
+	}
+
+	_, _ = bold.Fprintln(w, "Documented commands:")
+	fmt.Fprintln(w)
+	for _, d := range docs {
+		fmt.Fprintf(w, "  %s%s  %s\n", cyanBold.Sprint(d.CommandPath), strings.Repeat(" ", maxLen-len(d.CommandPath)), d.Short)
+	}
+}
+
+// renderMarkdown renders the markdown generated for a command for display in a
+// terminal. Headers are emphasized and the examples are highlighted.
+func renderMarkdown(w io.Writer, content string, useColor bool) {
+	cyanBold := color.New(color.FgCyan).Add(color.Bold)
+	bold := color.New(color.Bold)
+	comment := color.New(color.Faint)
+	command := color.New(color.FgGreen)
+	if !useColor {
+		for _, c := range []*color.Color{cyanBold, bold, comment, command} {
+			c.DisableColor()
+		}
+	}
+
+	inCodeBlock := false
+	for _, line := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			inCodeBlock = !inCodeBlock
+			continue
+		case inCodeBlock && strings.HasPrefix(trimmed, "#"):
+			fmt.Fprintf(w, "    %s\n", comment.Sprint(line))
+		case inCodeBlock && trimmed != "":
+			fmt.Fprintf(w, "    %s\n", command.Sprint(line))
+		case inCodeBlock:
+			fmt.Fprintln(w)
+		case strings.HasPrefix(line, "## "):
+			fmt.Fprintln(w, cyanBold.Sprint(strings.ToUpper(strings.TrimPrefix(line, "## "))))
+		case strings.HasPrefix(line, "### "):
+			fmt.Fprintln(w, bold.Sprint(strings.TrimPrefix(line, "### ")))
+		default:
+			fmt.Fprintln(w, markdownLinkRegex.ReplaceAllString(line, "$1"))
+		}
+	}
+}
+
+// exportCommandDocs writes the documentation of all commands to the output
+// directory in the specified format
+func exportCommandDocs(docs []*commandDoc, format, outputDir string) error {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return errors.Wrapf(err, "failed to create the output directory %q", outputDir)
+	}
+
+	for _, d := range docs {
+		baseName := strings.TrimSuffix(d.FileName, ".md")
+		var fileName string
+		var content []byte
+		switch strings.ToLower(format) {
+		case docsFormatMarkdown, "md":
+			fileName, content = d.FileName, []byte(d.Content)
+		case docsFormatHTML:
+			fileName, content = baseName+".html", renderHTML(d)
+		case docsFormatMan:
+			fileName, content = strings.ReplaceAll(baseName, "_", "-")+".1", renderManPage(d)
+		default:
+			return fmt.Errorf("unsupported documentation format %q, choose one of: %s, %s, %s", format, docsFormatMarkdown, docsFormatHTML, docsFormatMan)
+		}
+		if err := os.WriteFile(filepath.Join(outputDir, fileName), content, 0644); err != nil {
+			return errors.Wrapf(err, "failed to write the documentation of %q", d.CommandPath)
+		}
+	}
+	return nil
+}
+
+func renderHTML(d *commandDoc) []byte {
+	// Point the links to the other commands to their HTML pages
+	content := markdownFileLinkRegex.ReplaceAllString(d.Content, "($1.html)")
+	body := blackfriday.Run([]byte(content))
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n</head>\n<body>\n", html.EscapeString(d.CommandPath))
+	buf.Write(body)
+	buf.WriteString("</body>\n</html>\n")
+	return buf.Bytes()
+}
+
+func renderManPage(d *commandDoc) []byte {
+	title := strings.ToUpper(strings.ReplaceAll(d.CommandPath, " ", "-"))
+	content := markdownLinkRegex.ReplaceAllString(d.Content, "$1")
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, ".TH \"%s\" \"1\" \"\" \"Tanzu CLI\" \"Tanzu Manual\"\n", title)
+	buf.Write(md2man.Render([]byte(content)))
+	return buf.Bytes()
+}
+
+// pageOutput displays the output through a pager when writing to a terminal.
+// The pager can be configured with the TANZU_CLI_PAGER or PAGER environment variables.
+func pageOutput(w io.Writer, output []byte, noPager bool) error {
+	pager := os.Getenv(constants.DocsPager)
+	if pager == "" {
+		pager = os.Getenv("PAGER")
+	}
+	if pager == "" {
+		pager = defaultDocsPager
+	}
+
+	if noPager || !isTerminalOutput(w) {
+		_, err := w.Write(output)
+		return err
+	}
+
+	pagerArgs := strings.Fields(pager)
+	pagerCmd := exec.Command(pagerArgs[0], pagerArgs[1:]...) //nolint:gosec
+	pagerCmd.Stdin = bytes.NewReader(output)
+	pagerCmd.Stdout = w
+	pagerCmd.Stderr = os.Stderr
+	if err := pagerCmd.Start(); err != nil {
+		// Fallback to printing the output directly if the pager is not available
+		log.V(6).Infof("unable to use pager %q: %v", pager, err)
+		_, err = w.Write(output)
+		return err
+	}
+	return pagerCmd.Wait()
+}
+
+func isTerminalOutput(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// completeDocsCommandPath completes the next word of the command path for
+// which to display the documentation
+func completeDocsCommandPath(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	current, remaining, err := cmd.Root().Find(args)
+	if err != nil || len(remaining) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var comps []string
+	for _, subCmd := range current.Commands() {
+		if subCmd.IsAvailableCommand() && strings.HasPrefix(subCmd.Name(), toComplete) {
+			comps = append(comps, fmt.Sprintf("%s\t%s", subCmd.Name(), subCmd.Short))
+		}
+	}
+	return comps, cobra.ShellCompDirectiveNoFileComp
+}
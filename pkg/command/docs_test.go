@@ -0,0 +1,188 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+)
+
+const sampleInstallDoc = "## tanzu plugin install\n\n" +
+	"Install a plugin\n\n" +
+	"### Synopsis\n\n" +
+	"Install a specific plugin by name or specify all to install all plugins of a group\n\n" +
+	"```\ntanzu plugin install [PLUGIN_NAME] [flags]\n```\n\n" +
+	"### Examples\n\n" +
+	"```\n" +
+	"    # Install the latest version of the cluster plugin\n" +
+	"    tanzu plugin install cluster --target k8s\n" +
+	"```\n\n" +
+	"### SEE ALSO\n\n" +
+	"* [tanzu plugin](tanzu_plugin.md)\t - Manage CLI plugins\n"
+
+const sampleContextDoc = "## tanzu context use\n\n" +
+	"Set the context to be used by default\n\n" +
+	"### Synopsis\n\n" +
+	"Set the context to be used by default and update the kubeconfig\n"
+
+func sampleCommandDocs() []*commandDoc {
+	return []*commandDoc{
+		newCommandDoc("tanzu_context_use.md", sampleContextDoc),
+		newCommandDoc("tanzu_plugin_install.md", sampleInstallDoc),
+	}
+}
+
+func TestNewCommandDoc(t *testing.T) {
+	d := newCommandDoc("tanzu_plugin_install.md", sampleInstallDoc)
+	assert.Equal(t, "tanzu plugin install", d.CommandPath)
+	assert.Equal(t, "Install a plugin", d.Short)
+	assert.Equal(t, "tanzu_plugin_install.md", d.FileName)
+
+	// The command path falls back to the file name without a header
+	d = newCommandDoc("tanzu_foo_bar.md", "no header")
+	assert.Equal(t, "tanzu foo bar", d.CommandPath)
+	assert.Empty(t, d.Short)
+}
+
+func TestSearchCommandDocs(t *testing.T) {
+	docs := sampleCommandDocs()
+
+	results := searchCommandDocs(docs, "KUBECONFIG")
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, "tanzu context use", results[0].Doc.CommandPath)
+	assert.Equal(t, []string{"Set the context to be used by default and update the kubeconfig"}, results[0].Matches)
+
+	results = searchCommandDocs(docs, "tanzu")
+	assert.Equal(t, 2, len(results))
+
+	results = searchCommandDocs(docs, "not-found")
+	assert.Empty(t, results)
+}
+
+func TestFindCommandDoc(t *testing.T) {
+	docs := sampleCommandDocs()
+	assert.Equal(t, docs[1], findCommandDoc(docs, "tanzu plugin install"))
+	assert.Nil(t, findCommandDoc(docs, "tanzu plugin"))
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	var out bytes.Buffer
+	renderMarkdown(&out, sampleInstallDoc, false)
+
+	rendered := out.String()
+	assert.Contains(t, rendered, "TANZU PLUGIN INSTALL\n")
+	assert.Contains(t, rendered, "Examples\n")
+	assert.Contains(t, rendered, "    # Install the latest version of the cluster plugin\n")
+	// Links to other commands are replaced by their text
+	assert.Contains(t, rendered, "* tanzu plugin\t - Manage CLI plugins\n")
+	assert.NotContains(t, rendered, "```")
+	assert.NotContains(t, rendered, "tanzu_plugin.md")
+}
+
+func TestRenderDocsIndex(t *testing.T) {
+	var out bytes.Buffer
+	renderDocsIndex(&out, sampleCommandDocs(), false)
+
+	assert.Equal(t, "Documented commands:\n\n"+
+		"  tanzu context use     Set the context to be used by default\n"+
+		"  tanzu plugin install  Install a plugin\n", out.String())
+}
+
+func TestExportCommandDocs(t *testing.T) {
+	tests := []struct {
+		format       string
+		expectedFile string
+		expectedText string
+	}{
+		{
+			format:       docsFormatMarkdown,
+			expectedFile: "tanzu_plugin_install.md",
+			expectedText: "## tanzu plugin install",
+		},
+		{
+			format:       docsFormatHTML,
+			expectedFile: "tanzu_plugin_install.html",
+			expectedText: `<a href="tanzu_plugin.html">tanzu plugin</a>`,
+		},
+		{
+			format:       docsFormatMan,
+			expectedFile: "tanzu-plugin-install.1",
+			expectedText: `.TH "TANZU-PLUGIN-INSTALL" "1"`,
+		},
+	}
+
+	for _, spec := range tests {
+		t.Run(spec.format, func(t *testing.T) {
+			outputDir := filepath.Join(t.TempDir(), "docs")
+			err := exportCommandDocs(sampleCommandDocs(), spec.format, outputDir)
+			assert.NoError(t, err)
+
+			files, err := os.ReadDir(outputDir)
+			assert.NoError(t, err)
+			assert.Equal(t, 2, len(files))
+
+			b, err := os.ReadFile(filepath.Join(outputDir, spec.expectedFile))
+			assert.NoError(t, err)
+			assert.True(t, strings.Contains(string(b), spec.expectedText), "unexpected content: %s", string(b))
+		})
+	}
+
+	err := exportCommandDocs(sampleCommandDocs(), "pdf", t.TempDir())
+	assert.ErrorContains(t, err, "unsupported documentation format")
+}
+
+func TestReadCommandDocsForPluginTargets(t *testing.T) {
+	const clusterDoc = "## tanzu cluster\n\nKubernetes cluster operations\n\n" +
+		"```\ntanzu cluster [flags]\n```\n\n" +
+		"### SEE ALSO\n\n" +
+		"* [tanzu](tanzu.md)\t - The Tanzu CLI\n" +
+		"* [tanzu cluster list](tanzu_cluster_list.md)\t - List clusters\n"
+
+	docsByPath := map[string]*commandDoc{}
+	for _, target := range []configtypes.Target{configtypes.TargetK8s, configtypes.TargetTMC} {
+		dir := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "tanzu_cluster.md"), []byte(clusterDoc), 0644))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "tanzu.md"), []byte("## tanzu\n\nroot"), 0644))
+		_, err := readCommandDocs(dir, &cli.PluginInfo{Name: "cluster", Target: target}, docsByPath)
+		assert.NoError(t, err)
+	}
+
+	// The root command docs of the plugins are ignored, and the docs of the
+	// kubernetes plugin are available both at the root and under its target
+	assert.Equal(t, 3, len(docsByPath))
+	assert.Equal(t, "tanzu_cluster.md", docsByPath["tanzu cluster"].FileName)
+	assert.Equal(t, "tanzu_kubernetes_cluster.md", docsByPath["tanzu kubernetes cluster"].FileName)
+
+	tmcDoc := docsByPath["tanzu mission-control cluster"]
+	assert.NotNil(t, tmcDoc)
+	assert.Equal(t, "tanzu_mission-control_cluster.md", tmcDoc.FileName)
+	assert.Equal(t, "Kubernetes cluster operations", tmcDoc.Short)
+	assert.Contains(t, tmcDoc.Content, "## tanzu mission-control cluster\n")
+	assert.Contains(t, tmcDoc.Content, "\ntanzu mission-control cluster [flags]\n")
+	assert.Contains(t, tmcDoc.Content, "[tanzu](tanzu.md)")
+	assert.Contains(t, tmcDoc.Content, "[tanzu mission-control cluster list](tanzu_mission-control_cluster_list.md)")
+}
+
+func TestRenderHTMLEscapesTitle(t *testing.T) {
+	d := newCommandDoc("tanzu_foo.md", "## tanzu foo <bar>\n\nFoo\n")
+	assert.Contains(t, string(renderHTML(d)), "<title>tanzu foo &lt;bar&gt;</title>")
+}
+
+func TestPageOutputWithoutTerminal(t *testing.T) {
+	// Writing to a buffer never uses the pager
+	t.Setenv("TANZU_CLI_PAGER", "false")
+	var out bytes.Buffer
+	err := pageOutput(&out, []byte("some docs\n"), false)
+	assert.NoError(t, err)
+	assert.Equal(t, "some docs\n", out.String())
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/component"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/plugin"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/doctor"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/pluginsupplier"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/utils"
+)
+
+var doctorFix bool
+
+func newDoctorCmd() *cobra.Command {
+	var doctorCmd = &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose problems with the CLI installation",
+		Long: "Diagnose problems with the CLI installation by checking the plugin catalog, the plugin binaries, " +
+			"the plugin command tree cache, the configuration files and their locks, the plugin discovery sources, " +
+			"the public key used to verify the discovery images and the EULA and CEIP settings. " +
+			"Installed plugins can contribute their own checks.",
+		Example: `
+    # Run all the diagnostic checks
+    tanzu doctor
+
+    # Run all the diagnostic checks and repair the problems that can safely be repaired
+    tanzu doctor --fix
+
+    # Output the results of the checks as JSON
+    tanzu doctor -o json`,
+		Args:              cobra.NoArgs,
+		ValidArgsFunction: noMoreCompletions,
+		Annotations: map[string]string{
+			"group": string(plugin.SystemCmdGroup),
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			checks := doctor.BuiltinChecks()
+			plugins, err := pluginsupplier.GetInstalledPlugins()
+			if err != nil {
+				log.Warningf("unable to get the installed plugins, skipping the checks provided by plugins: %v", err)
+			}
+			checks = append(checks, doctor.PluginChecks(plugins)...)
+
+			results := doctor.Run(checks, doctorFix)
+
+			outputWriter := component.NewOutputWriterWithOptions(cmd.OutOrStdout(), outputFormat, []component.OutputWriterOption{}, "Check", "Status", "Message")
+			for _, r := range results {
+				outputWriter.AddRow(r.Check, r.Status, r.Message)
+			}
+			outputWriter.Render()
+
+			if doctor.HasErrors(results) {
+				return errors.New("some diagnostic checks failed")
+			}
+			return nil
+		},
+	}
+	doctorCmd.SetUsageFunc(cli.SubCmdUsageFunc)
+
+	f := doctorCmd.Flags()
+	f.BoolVar(&doctorFix, "fix", false, "repair the problems that can safely be repaired")
+	f.StringVarP(&outputFormat, "output", "o", "", "output format (yaml|json|table)")
+	utils.PanicOnErr(doctorCmd.RegisterFlagCompletionFunc("output", completionGetOutputFormats))
+
+	return doctorCmd
+}
@@ -96,6 +96,7 @@ func login(_ *cobra.Command, _ []string) (err error) {
 		return err
 	}
 
+	runContextEventHooks(cli.ContextEventPreLogin, ctx)
 	err = globalTanzuLogin(ctx, prepareTanzuContextName)
 	if err != nil {
 		return err
@@ -112,6 +113,7 @@ func login(_ *cobra.Command, _ []string) (err error) {
 	if err != nil {
 		return errors.Wrap(err, "failed updating the context %q after kubeconfig update")
 	}
+	runContextEventHooks(cli.ContextEventPostLogin, ctx)
 
 	// TODO: uncomment the below context plugin sync call once context scope plugin support
 	//       is implemented for tanzu context(Tanzu Platform for Kubernetes)
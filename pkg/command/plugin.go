@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -24,18 +25,20 @@ import (
 	"github.com/vmware-tanzu/tanzu-cli/pkg/plugininventory"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/pluginmanager"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/pluginsupplier"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/provenance"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/utils"
 	"github.com/vmware-tanzu/tanzu-plugin-runtime/config"
 	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
 )
 
 var (
-	local        string
-	version      string
-	forceDelete  bool
-	outputFormat string
-	targetStr    string
-	group        string
+	local          string
+	version        string
+	forceDelete    bool
+	outputFormat   string
+	targetStr      string
+	group          string
+	showProvenance bool
 )
 
 const (
@@ -74,6 +77,9 @@ func newPluginCmd() *cobra.Command {
 		newPluginGroupCmd(),
 		newDownloadBundlePluginCmd(),
 		newUploadBundlePluginCmd(),
+		newVerifyBundlePluginCmd(),
+		newBundlePluginCmd(),
+		newMirrorPluginCmd(),
 	)
 
 	return pluginCmd
@@ -141,6 +147,14 @@ func newDescribePluginCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if showProvenance {
+				statement, err := pluginmanager.GetPluginProvenance(pd)
+				if err != nil {
+					return err
+				}
+				displayPluginProvenance(statement, cmd.OutOrStdout())
+				return nil
+			}
 			output.AddRow(pd.Name, pd.Version, pd.Status, pd.Target, pd.Description, pd.InstallationPath)
 			output.Render()
 			return nil
@@ -153,9 +167,43 @@ func newDescribePluginCmd() *cobra.Command {
 	describeCmd.Flags().StringVarP(&targetStr, "target", "t", "", targetFlagDesc)
 	utils.PanicOnErr(describeCmd.RegisterFlagCompletionFunc("target", completeTargetsForInstalledPlugins))
 
+	describeCmd.Flags().BoolVarP(&showProvenance, "provenance", "", false, "show the build provenance of the installed plugin binary")
+
 	return describeCmd
 }
 
+// displayPluginProvenance displays a summary of the provenance statement, or
+// the complete statement for the yaml and json output formats
+func displayPluginProvenance(statement *provenance.Statement, writer io.Writer) {
+	if outputFormat != string(component.ListTableOutputType) && outputFormat != string(component.TableOutputType) {
+		component.NewObjectWriter(writer, outputFormat, statement).Render()
+		return
+	}
+
+	build := statement.Predicate.BuildDefinition
+	source, commit := "", ""
+	if s := statement.Source(); s != nil {
+		source, commit = s.URI, s.Digest["gitCommit"]
+	}
+	var subjects []string
+	for _, s := range statement.Subject {
+		subjects = append(subjects, fmt.Sprintf("%s@sha256:%s", s.Name, s.Digest["sha256"]))
+	}
+	var dependencies []string
+	for _, d := range build.ResolvedDependencies {
+		if _, ok := d.Digest["gitCommit"]; !ok {
+			dependencies = append(dependencies, strings.TrimPrefix(d.URI, "pkg:golang/"))
+		}
+	}
+
+	output := component.NewOutputWriterWithOptions(writer, outputFormat, []component.OutputWriterOption{}, "subject", "source", "commit", "goVersion", "ldflags", "goflags", "tags", "reproducible", "builtOn", "builder", "dependencies")
+	output.AddRow(strings.Join(subjects, ", "), source, commit, build.InternalParameters.GoVersion,
+		build.ExternalParameters.LDFlags, build.ExternalParameters.GoFlags, build.ExternalParameters.Tags,
+		build.ExternalParameters.Reproducible, statement.Predicate.RunDetails.Metadata.FinishedOn.Format(time.RFC3339),
+		statement.Predicate.RunDetails.Builder.ID, strings.Join(dependencies, ", "))
+	output.Render()
+}
+
 func newInstallPluginCmd() *cobra.Command { //nolint:funlen
 	var installPluginCmd = &cobra.Command{
 		Use:   "install [" + pluginNameCaps + "]",
@@ -5,6 +5,7 @@ package command
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"path/filepath"
@@ -14,8 +15,11 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/component"
+
 	"github.com/vmware-tanzu/tanzu-cli/pkg/airgapped"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/carvelhelpers"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/constants"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/plugininventory"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/utils"
@@ -24,10 +28,18 @@ import (
 type downloadPluginBundleOptions struct {
 	pluginDiscoveryOCIImage string
 	tarFile                 string
+	ociLayoutDir            string
 	groups                  []string
 	plugins                 []string
 	refreshConfigOnly       bool
 	dryRun                  bool
+	sinceBundle             string
+	excludeExistingIn       string
+	signingKey              string
+	sbomFormat              string
+	osArch                  []string
+	latestNVersions         int
+	sinceVersion            string
 }
 
 var (
@@ -54,19 +66,43 @@ to an internet-restricted environment. Please also see the "upload-bundle" comma
     tanzu plugin download-bundle --plugin cluster:v1.0.0 --to-tar /tmp/plugin_bundle_cluster.tar.gz
 
     # Download a plugin bundle with the entire plugin repository from a custom discovery source
-    tanzu plugin download-bundle --image custom.registry.vmware.com/tkg/tanzu-plugins/plugin-inventory:latest --to-tar /tmp/plugin_bundle_complete.tar.gz`,
+    tanzu plugin download-bundle --image custom.registry.vmware.com/tkg/tanzu-plugins/plugin-inventory:latest --to-tar /tmp/plugin_bundle_complete.tar.gz
+
+    # Download a delta plugin bundle with only the plugins that are not part of a previous plugin bundle
+    tanzu plugin download-bundle --since-bundle /tmp/plugin_bundle_complete.tar.gz --to-tar /tmp/plugin_bundle_delta.tar.gz
+
+    # Download a delta plugin bundle with only the plugins that are not yet uploaded to a repository
+    tanzu plugin download-bundle --exclude-existing-in custom.registry.company.com/tanzu-plugins/ --to-tar /tmp/plugin_bundle_delta.tar.gz
+
+    # Download a plugin bundle as an OCI image layout directory which can be used as a discovery source without any registry
+    tanzu plugin download-bundle --group vmware-tkg/default:v1.0.0 --to-oci-layout /opt/tanzu-plugins
+    tanzu plugin source update default --uri file:///opt/tanzu-plugins/plugin-inventory:latest
+
+    # Download a plugin bundle signed with a cosign key and including an SPDX software bill of materials of the plugins
+    tanzu plugin download-bundle --group vmware-tkg/default:v1.0.0 --to-tar /tmp/plugin_bundle_signed.tar.gz --signing-key cosign.key --sbom-format spdx-json
+
+    # Download a plugin bundle with only the linux/amd64 binaries of the two latest versions of each plugin
+    tanzu plugin download-bundle --to-tar /tmp/plugin_bundle_linux.tar.gz --os-arch linux/amd64 --latest-n-versions 2`,
 		ValidArgsFunction: completeDownloadBundle,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if !dpbo.dryRun && dpbo.tarFile == "" {
-				return errors.New("flag '--to-tar' is required")
+			if !dpbo.dryRun && dpbo.tarFile == "" && dpbo.ociLayoutDir == "" {
+				return errors.New("one of the flags '--to-tar' or '--to-oci-layout' is required")
 			}
 			options := airgapped.DownloadPluginBundleOptions{
 				PluginInventoryImage: dpbo.pluginDiscoveryOCIImage,
 				ToTar:                dpbo.tarFile,
+				ToOCILayout:          dpbo.ociLayoutDir,
 				Groups:               dpbo.groups,
 				Plugins:              dpbo.plugins,
 				RefreshConfigOnly:    dpbo.refreshConfigOnly,
 				DryRun:               dpbo.dryRun,
+				SinceBundle:          dpbo.sinceBundle,
+				ExcludeExistingIn:    dpbo.excludeExistingIn,
+				SigningKey:           dpbo.signingKey,
+				SBOMFormat:           dpbo.sbomFormat,
+				OSArch:               dpbo.osArch,
+				LatestNVersions:      dpbo.latestNVersions,
+				SinceVersion:         dpbo.sinceVersion,
 				ImageProcessor:       carvelhelpers.NewImageOperationsImpl(),
 			}
 			return options.DownloadPluginBundle()
@@ -81,6 +117,10 @@ to an internet-restricted environment. Please also see the "upload-bundle" comma
 
 	// Shell completion for this flag is the default behavior of doing file completion
 	f.StringVarP(&dpbo.tarFile, "to-tar", "", "", "local tar file path to store the plugin images")
+	f.StringVarP(&dpbo.ociLayoutDir, "to-oci-layout", "", "", "local directory to store the plugin images as an OCI image layout usable as a discovery source")
+	utils.PanicOnErr(downloadBundleCmd.RegisterFlagCompletionFunc("to-oci-layout", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return nil, cobra.ShellCompDirectiveFilterDirs
+	}))
 	f.StringSliceVarP(&dpbo.groups, "group", "", []string{}, "only download the plugins specified in the plugin-group version (can specify multiple)")
 	utils.PanicOnErr(downloadBundleCmd.RegisterFlagCompletionFunc("group", completeGroupsAndVersionForBundleDownload))
 
@@ -89,17 +129,50 @@ to an internet-restricted environment. Please also see the "upload-bundle" comma
 
 	f.BoolVarP(&dpbo.refreshConfigOnly, "refresh-configuration-only", "", false, "only refresh the central configuration data")
 
+	// Shell completion for this flag is the default behavior of doing file completion
+	f.StringVarP(&dpbo.sinceBundle, "since-bundle", "", "", "only download the plugins that are not part of the specified previous plugin bundle or its plugin migration manifest")
+	f.StringVarP(&dpbo.excludeExistingIn, "exclude-existing-in", "", "", "only download the plugins that are not yet uploaded to the specified repository")
+	utils.PanicOnErr(downloadBundleCmd.RegisterFlagCompletionFunc("exclude-existing-in", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return cobra.AppendActiveHelp(nil, "Please enter the URI of the repository the plugins were uploaded to"), cobra.ShellCompDirectiveNoFileComp
+	}))
+
+	// Shell completion for this flag is the default behavior of doing file completion
+	f.StringVarP(&dpbo.signingKey, "signing-key", "", "", "cosign private key used to sign the plugin bundle. The key password is read from the COSIGN_PASSWORD environment variable")
+	f.StringVarP(&dpbo.sbomFormat, "sbom-format", "", "", fmt.Sprintf("include a software bill of materials of the plugins in the specified format in the plugin bundle. Allowed values: %s", strings.Join(airgapped.SBOMFormats, ", ")))
+	utils.PanicOnErr(downloadBundleCmd.RegisterFlagCompletionFunc("sbom-format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return airgapped.SBOMFormats, cobra.ShellCompDirectiveNoFileComp
+	}))
+
+	f.StringSliceVarP(&dpbo.osArch, "os-arch", "", []string{}, "only download the plugin binaries for the specified platforms. Format: os/arch (can specify multiple)")
+	utils.PanicOnErr(downloadBundleCmd.RegisterFlagCompletionFunc("os-arch", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"darwin/amd64", "darwin/arm64", "linux/amd64", "linux/arm64", "windows/amd64"}, cobra.ShellCompDirectiveNoFileComp
+	}))
+	f.IntVarP(&dpbo.latestNVersions, "latest-n-versions", "", 0, "only download the specified number of latest versions of each plugin selected without a version. The recommended version is always included")
+	utils.PanicOnErr(downloadBundleCmd.RegisterFlagCompletionFunc("latest-n-versions", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return cobra.AppendActiveHelp(nil, "Please enter the number of latest versions of each plugin to download"), cobra.ShellCompDirectiveNoFileComp
+	}))
+	f.StringVarP(&dpbo.sinceVersion, "since-version", "", "", "only download the versions newer than or equal to the specified version of each plugin selected without a version")
+	utils.PanicOnErr(downloadBundleCmd.RegisterFlagCompletionFunc("since-version", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return cobra.AppendActiveHelp(nil, "Please enter the oldest version of each plugin to download"), cobra.ShellCompDirectiveNoFileComp
+	}))
+
 	f.BoolVarP(&dpbo.dryRun, "dry-run", "", false, "perform a dry run by listing the images to download without actually downloading them")
 	_ = downloadBundleCmd.Flags().MarkHidden("dry-run")
 
 	// TODO(khouzam): Once using Cobra 1.8, we can use MarkFlagsOneRequired.
 	// We can then adjust the shell completion as it will be handled by cobra
-	downloadBundleCmd.MarkFlagsMutuallyExclusive("to-tar", "dry-run")
+	downloadBundleCmd.MarkFlagsMutuallyExclusive("to-tar", "to-oci-layout", "dry-run")
 
 	// The --refresh-configuration-only flag is only needed when the operator does not
 	// want to include any new plugin or plugin group in the bundle.
 	downloadBundleCmd.MarkFlagsMutuallyExclusive("group", "refresh-configuration-only")
 	downloadBundleCmd.MarkFlagsMutuallyExclusive("plugin", "refresh-configuration-only")
+	downloadBundleCmd.MarkFlagsMutuallyExclusive("since-bundle", "exclude-existing-in")
+	downloadBundleCmd.MarkFlagsMutuallyExclusive("to-oci-layout", "signing-key")
+	downloadBundleCmd.MarkFlagsMutuallyExclusive("to-oci-layout", "sbom-format")
+	downloadBundleCmd.MarkFlagsMutuallyExclusive("os-arch", "refresh-configuration-only")
+	downloadBundleCmd.MarkFlagsMutuallyExclusive("latest-n-versions", "refresh-configuration-only")
+	downloadBundleCmd.MarkFlagsMutuallyExclusive("since-version", "refresh-configuration-only")
 
 	return downloadBundleCmd
 }
@@ -107,6 +180,8 @@ to an internet-restricted environment. Please also see the "upload-bundle" comma
 type uploadPluginBundleOptions struct {
 	sourceTar       string
 	destinationRepo string
+	concurrency     int
+	verificationKey string
 }
 
 var upbo uploadPluginBundleOptions
@@ -116,16 +191,27 @@ func newUploadBundlePluginCmd() *cobra.Command {
 		Use:   "upload-bundle",
 		Short: "Upload plugin bundle to a repository",
 		Long: `Upload a plugin bundle to an alternate container registry for use in an internet-restricted
-environment. The plugin bundle is obtained using the "download-bundle" command.`,
+environment. The plugin bundle is obtained using the "download-bundle" command.
+A delta plugin bundle can only be uploaded to a repository that already contains
+the plugins and plugin groups it builds upon.
+Images that already exist in the repository are not uploaded again, and an
+interrupted upload is resumed when the command is run again with the same arguments.
+When a verification key is specified, the signature of the plugin bundle and the
+digests of all its files are verified before anything is uploaded.`,
 		Example: `
     # Upload the plugin bundle to the remote repository
     tanzu plugin upload-bundle --tar /tmp/plugin_bundle_vmware_tkg_default_v1.0.0.tar.gz --to-repo custom.registry.company.com/tanzu-plugins/
-    tanzu plugin upload-bundle --tar /tmp/plugin_bundle_complete.tar.gz --to-repo custom.registry.company.com/tanzu-plugins/`,
+    tanzu plugin upload-bundle --tar /tmp/plugin_bundle_complete.tar.gz --to-repo custom.registry.company.com/tanzu-plugins/
+
+    # Verify the signature of the plugin bundle before uploading it to the remote repository
+    tanzu plugin upload-bundle --tar /tmp/plugin_bundle_signed.tar.gz --to-repo custom.registry.company.com/tanzu-plugins/ --verification-key cosign.pub`,
 		ValidArgsFunction: completeUploadBundle,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			options := airgapped.UploadPluginBundleOptions{
 				Tar:             upbo.sourceTar,
 				DestinationRepo: upbo.destinationRepo,
+				Concurrency:     upbo.concurrency,
+				VerificationKey: upbo.verificationKey,
 				ImageProcessor:  carvelhelpers.NewImageOperationsImpl(),
 			}
 			return options.UploadPluginBundle()
@@ -141,20 +227,221 @@ environment. The plugin bundle is obtained using the "download-bundle" command.`
 		return cobra.AppendActiveHelp(nil, "Please enter the URI of the destination repository for publishing plugins"), cobra.ShellCompDirectiveNoFileComp
 	}))
 
+	f.IntVarP(&upbo.concurrency, "concurrency", "", 4, "number of images to upload in parallel")
+	utils.PanicOnErr(uploadBundleCmd.RegisterFlagCompletionFunc("concurrency", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return cobra.AppendActiveHelp(nil, "Please enter the number of images to upload in parallel"), cobra.ShellCompDirectiveNoFileComp
+	}))
+
+	// Shell completion for this flag is the default behavior of doing file completion
+	f.StringVarP(&upbo.verificationKey, "verification-key", "", "", "cosign public key used to verify the signature of the plugin bundle before uploading it")
+
 	_ = uploadBundleCmd.MarkFlagRequired("tar")
 	_ = uploadBundleCmd.MarkFlagRequired("to-repo")
 
 	return uploadBundleCmd
 }
 
+var (
+	verifyBundleTar string
+	verifyBundleKey string
+)
+
+func newVerifyBundlePluginCmd() *cobra.Command {
+	var verifyBundleCmd = &cobra.Command{
+		Use:   "verify-bundle",
+		Short: "Verify the integrity of a plugin bundle",
+		Long: `Verify that a plugin bundle obtained using the "download-bundle" command is complete
+and has not been corrupted. No registry is accessed, so the verification can be done
+before uploading the plugin bundle in an internet-restricted environment.
+When a verification key is specified, the signature of the plugin bundle is also verified.`,
+		Example: `
+    # Verify the integrity of a plugin bundle
+    tanzu plugin verify-bundle --tar /tmp/plugin_bundle_complete.tar.gz
+
+    # Verify the integrity and the signature of a plugin bundle
+    tanzu plugin verify-bundle --tar /tmp/plugin_bundle_signed.tar.gz --verification-key cosign.pub`,
+		ValidArgsFunction: completeVerifyBundle,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options := airgapped.VerifyPluginBundleOptions{
+				Tar:             verifyBundleTar,
+				VerificationKey: verifyBundleKey,
+			}
+			return options.VerifyPluginBundle()
+		},
+	}
+
+	// Shell completion for this flag is the default behavior of doing file completion
+	verifyBundleCmd.Flags().StringVarP(&verifyBundleTar, "tar", "", "", "tar file of the plugin bundle")
+	verifyBundleCmd.Flags().StringVarP(&verifyBundleKey, "verification-key", "", "", "cosign public key used to verify the signature of the plugin bundle")
+	_ = verifyBundleCmd.MarkFlagRequired("tar")
+
+	return verifyBundleCmd
+}
+
+var inspectBundleTar string
+
+func newBundlePluginCmd() *cobra.Command {
+	var bundleCmd = &cobra.Command{
+		Use:   "bundle",
+		Short: "Inspect plugin bundles",
+		Long:  `Inspect the content of plugin bundles obtained using the "download-bundle" command.`,
+	}
+	bundleCmd.SetUsageFunc(cli.SubCmdUsageFunc)
+
+	bundleCmd.AddCommand(
+		newInspectBundleCmd(),
+		newDiffBundleCmd(),
+	)
+	return bundleCmd
+}
+
+func newInspectBundleCmd() *cobra.Command {
+	var inspectCmd = &cobra.Command{
+		Use:   "inspect",
+		Short: "List the content of a plugin bundle",
+		Long: `List the plugins, plugin groups and central configuration contained in a plugin bundle.
+No registry is accessed.`,
+		Example: `
+    # List the content of a plugin bundle
+    tanzu plugin bundle inspect --tar /tmp/plugin_bundle_complete.tar.gz
+
+    # List the content of a plugin bundle in yaml format
+    tanzu plugin bundle inspect --tar /tmp/plugin_bundle_complete.tar.gz -o yaml`,
+		ValidArgsFunction: completeInspectBundle,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options := airgapped.InspectPluginBundleOptions{
+				Tar:            inspectBundleTar,
+				ImageProcessor: carvelhelpers.NewImageOperationsImpl(),
+			}
+			contents, err := options.InspectPluginBundle()
+			if err != nil {
+				return err
+			}
+			displayPluginBundleContents(contents, cmd.OutOrStdout())
+			return nil
+		},
+	}
+
+	f := inspectCmd.Flags()
+	// Shell completion for this flag is the default behavior of doing file completion
+	f.StringVarP(&inspectBundleTar, "tar", "", "", "tar file of the plugin bundle")
+	f.StringVarP(&outputFormat, "output", "o", "", "output format (yaml|json|table)")
+	utils.PanicOnErr(inspectCmd.RegisterFlagCompletionFunc("output", completionGetOutputFormats))
+	_ = inspectCmd.MarkFlagRequired("tar")
+
+	return inspectCmd
+}
+
+func newDiffBundleCmd() *cobra.Command {
+	var diffCmd = &cobra.Command{
+		Use:   "diff FROM_TAR TO_TAR",
+		Short: "Show the plugins added and removed between two plugin bundles",
+		Long: `Show the plugin versions and plugin-group versions that the second plugin bundle
+contains but not the first one (added), and the ones that the first plugin bundle
+contains but not the second one (removed). No registry is accessed.`,
+		Example: `
+    # Show the plugins added and removed since a previous plugin bundle
+    tanzu plugin bundle diff /tmp/plugin_bundle_v1.0.0.tar.gz /tmp/plugin_bundle_v1.1.0.tar.gz`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeDiffBundle,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			diff, err := airgapped.DiffPluginBundles(args[0], args[1])
+			if err != nil {
+				return err
+			}
+			displayPluginBundleDiff(diff, cmd.OutOrStdout())
+			return nil
+		},
+	}
+
+	diffCmd.Flags().StringVarP(&outputFormat, "output", "o", "", "output format (yaml|json|table)")
+	utils.PanicOnErr(diffCmd.RegisterFlagCompletionFunc("output", completionGetOutputFormats))
+
+	return diffCmd
+}
+
+func displayPluginBundleContents(contents *airgapped.PluginBundleContents, writer io.Writer) {
+	if outputFormat != "" && outputFormat != string(component.TableOutputType) {
+		component.NewObjectWriter(writer, outputFormat, contents).Render()
+		return
+	}
+
+	centralConfig := "none"
+	if contents.CentralConfig != nil {
+		centralConfig = contents.CentralConfig.Digest
+		if contents.CentralConfig.Version != "" {
+			centralConfig = fmt.Sprintf("version %s (%s)", contents.CentralConfig.Version, contents.CentralConfig.Digest)
+		}
+	}
+	fmt.Fprintf(writer, "Plugin inventory image: %s\n", contents.PluginInventoryImage)
+	fmt.Fprintf(writer, "Central configuration: %s\n", centralConfig)
+	fmt.Fprintf(writer, "Signed: %t\n", contents.Signed)
+	if contents.SBOM != "" {
+		fmt.Fprintf(writer, "SBOM: %s\n", contents.SBOM)
+	}
+	if contents.DeltaBase != nil {
+		fmt.Fprintf(writer, "Delta bundle building upon: %d plugins, %d plugin groups\n", len(contents.DeltaBase.Plugins), len(contents.DeltaBase.PluginGroups))
+	}
+	fmt.Fprintf(writer, "Total size: %s\n\n", formatBundleSize(contents.Size))
+
+	groupOutput := component.NewOutputWriterWithOptions(writer, string(component.TableOutputType), []component.OutputWriterOption{}, "group", "plugins")
+	for _, pg := range contents.PluginGroups {
+		groupOutput.AddRow(pg.ID, pg.Plugins)
+	}
+	groupOutput.Render()
+	fmt.Fprintln(writer)
+
+	pluginOutput := component.NewOutputWriterWithOptions(writer, string(component.TableOutputType), []component.OutputWriterOption{}, "name", "target", "version", "os/arch", "size")
+	for _, p := range contents.Plugins {
+		pluginOutput.AddRow(p.Name, p.Target, p.Version, p.OS+"/"+p.Arch, formatBundleSize(p.Size))
+	}
+	pluginOutput.Render()
+}
+
+func displayPluginBundleDiff(diff *airgapped.PluginBundleDiff, writer io.Writer) {
+	if outputFormat != "" && outputFormat != string(component.TableOutputType) {
+		component.NewObjectWriter(writer, outputFormat, diff).Render()
+		return
+	}
+
+	output := component.NewOutputWriterWithOptions(writer, string(component.TableOutputType), []component.OutputWriterOption{}, "change", "kind", "id")
+	for _, id := range diff.AddedPluginGroups {
+		output.AddRow("added", "plugin-group", id)
+	}
+	for _, id := range diff.RemovedPluginGroups {
+		output.AddRow("removed", "plugin-group", id)
+	}
+	for _, id := range diff.AddedPlugins {
+		output.AddRow("added", "plugin", id)
+	}
+	for _, id := range diff.RemovedPlugins {
+		output.AddRow("removed", "plugin", id)
+	}
+	output.Render()
+}
+
+// formatBundleSize returns the size in a human readable format
+func formatBundleSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
 // ====================================
 // Shell completion functions
 // ====================================
 func completeDownloadBundle(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
-	if !dpbo.dryRun && dpbo.tarFile == "" {
+	if !dpbo.dryRun && dpbo.tarFile == "" && dpbo.ociLayoutDir == "" {
 		// The user must provide more info by using flags.
 		// Note that those flags are not marked as mandatory
-		// because only one of the two --to-tar and --dry-run are required
+		// because only one of --to-tar, --to-oci-layout and --dry-run is required
 		comps := []string{"--"}
 		return comps, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
 	}
@@ -163,6 +450,36 @@ func completeDownloadBundle(_ *cobra.Command, _ []string, _ string) ([]string, c
 	return activeHelpNoMoreArgs(nil), cobra.ShellCompDirectiveNoFileComp
 }
 
+func completeVerifyBundle(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if verifyBundleTar == "" {
+		// The flag is required, so completion will be provided for it
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// The user has provided enough information
+	return activeHelpNoMoreArgs(nil), cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeInspectBundle(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if inspectBundleTar == "" {
+		// The flag is required, so completion will be provided for it
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// The user has provided enough information
+	return activeHelpNoMoreArgs(nil), cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeDiffBundle(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) < 2 {
+		// Complete the tar files of the plugin bundles
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+
+	// The user has provided enough information
+	return activeHelpNoMoreArgs(nil), cobra.ShellCompDirectiveNoFileComp
+}
+
 func completeUploadBundle(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 	if upbo.destinationRepo == "" || upbo.sourceTar == "" {
 		// Both flags are required, so completion will be provided for them
@@ -47,6 +47,12 @@ func TestCompletionPluginBundle(t *testing.T) {
 			// ":4" is the value of the ShellCompDirectiveNoFileComp
 			expected: "_activeHelp_ " + compNoMoreArgsMsg + "\n:4\n",
 		},
+		{
+			test: "no completion after the download-bundle command with --to-oci-layout",
+			args: []string{"__complete", "plugin", "download-bundle", "--to-oci-layout", "/tmp/plugins", ""},
+			// ":4" is the value of the ShellCompDirectiveNoFileComp
+			expected: "_activeHelp_ " + compNoMoreArgsMsg + "\n:4\n",
+		},
 		{
 			test: "no completion after the download-bundle command with --dry-run",
 			args: []string{"__complete", "plugin", "download-bundle", "--dry-run", ""},
@@ -72,6 +78,36 @@ func TestCompletionPluginBundle(t *testing.T) {
 			// ":0" is the value of the ShellCompDirectiveDefault
 			expected: ":0\n",
 		},
+		{
+			test: "directory completion for the --to-oci-layout flag value of the download-bundle command",
+			args: []string{"__complete", "plugin", "download-bundle", "--to-oci-layout", ""},
+			// ":16" is the value of the ShellCompDirectiveFilterDirs
+			expected: ":16\n",
+		},
+		{
+			test: "completion for the --sbom-format flag value of the download-bundle command",
+			args: []string{"__complete", "plugin", "download-bundle", "--sbom-format", ""},
+			// ":4" is the value of the ShellCompDirectiveNoFileComp
+			expected: "spdx-json\ncyclonedx-json\n:4\n",
+		},
+		{
+			test: "completion for the --os-arch flag value of the download-bundle command",
+			args: []string{"__complete", "plugin", "download-bundle", "--os-arch", ""},
+			// ":4" is the value of the ShellCompDirectiveNoFileComp
+			expected: "darwin/amd64\ndarwin/arm64\nlinux/amd64\nlinux/arm64\nwindows/amd64\n:4\n",
+		},
+		{
+			test: "no completion for the --latest-n-versions flag value of the download-bundle command",
+			args: []string{"__complete", "plugin", "download-bundle", "--latest-n-versions", ""},
+			// ":4" is the value of the ShellCompDirectiveNoFileComp
+			expected: "_activeHelp_ Please enter the number of latest versions of each plugin to download\n:4\n",
+		},
+		{
+			test: "no completion for the --since-version flag value of the download-bundle command",
+			args: []string{"__complete", "plugin", "download-bundle", "--since-version", ""},
+			// ":4" is the value of the ShellCompDirectiveNoFileComp
+			expected: "_activeHelp_ Please enter the oldest version of each plugin to download\n:4\n",
+		},
 		{
 			test: "completion for the --group flag value for the group name part of the download-bundle command",
 			args: []string{"__complete", "plugin", "download-bundle", "--group", ""},
@@ -197,6 +233,55 @@ func TestCompletionPluginBundle(t *testing.T) {
 			// ":4" is the value of the ShellCompDirectiveNoFileComp
 			expected: "_activeHelp_ " + compNoMoreArgsMsg + "\n:4\n",
 		},
+		// ============================
+		// tanzu plugin verify-bundle
+		// ============================
+		{
+			test: "file completion for the --tar flag value of the verify-bundle command",
+			args: []string{"__complete", "plugin", "verify-bundle", "--tar", ""},
+			// ":0" is the value of the ShellCompDirectiveDefault
+			expected: ":0\n",
+		},
+		{
+			test: "flag completion after the verify-bundle command when no flags are present",
+			args: []string{"__complete", "plugin", "verify-bundle", ""},
+			// ":4" is the value of the ShellCompDirectiveNoFileComp
+			expected: "--tar\ttar file of the plugin bundle\n" +
+				":4\n",
+		},
+		{
+			test: "no completion after the verify-bundle command when all flags are present",
+			args: []string{"__complete", "plugin", "verify-bundle", "--tar", "plugin.tar", ""},
+			// ":4" is the value of the ShellCompDirectiveNoFileComp
+			expected: "_activeHelp_ " + compNoMoreArgsMsg + "\n:4\n",
+		},
+		// ============================
+		// tanzu plugin bundle
+		// ============================
+		{
+			test: "file completion for the --tar flag value of the bundle inspect command",
+			args: []string{"__complete", "plugin", "bundle", "inspect", "--tar", ""},
+			// ":0" is the value of the ShellCompDirectiveDefault
+			expected: ":0\n",
+		},
+		{
+			test: "no completion after the bundle inspect command when all flags are present",
+			args: []string{"__complete", "plugin", "bundle", "inspect", "--tar", "plugin.tar", ""},
+			// ":4" is the value of the ShellCompDirectiveNoFileComp
+			expected: "_activeHelp_ " + compNoMoreArgsMsg + "\n:4\n",
+		},
+		{
+			test: "file completion for the plugin bundles of the bundle diff command",
+			args: []string{"__complete", "plugin", "bundle", "diff", "plugin1.tar", ""},
+			// ":0" is the value of the ShellCompDirectiveDefault
+			expected: ":0\n",
+		},
+		{
+			test: "no completion after the bundle diff command when both plugin bundles are present",
+			args: []string{"__complete", "plugin", "bundle", "diff", "plugin1.tar", "plugin2.tar", ""},
+			// ":4" is the value of the ShellCompDirectiveNoFileComp
+			expected: "_activeHelp_ " + compNoMoreArgsMsg + "\n:4\n",
+		},
 	}
 
 	// Setup a plugin source and a set of installed plugins
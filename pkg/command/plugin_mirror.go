@@ -0,0 +1,113 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/component"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/airgapped"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/carvelhelpers"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/constants"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/utils"
+)
+
+type mirrorPluginsOptions struct {
+	pluginDiscoveryOCIImage string
+	destinationRepo         string
+	groups                  []string
+	concurrency             int
+	dryRun                  bool
+}
+
+var mpo mirrorPluginsOptions
+
+func newMirrorPluginCmd() *cobra.Command {
+	var mirrorCmd = &cobra.Command{
+		Use:   "mirror",
+		Short: "Mirror plugins to a repository",
+		Long: `Mirror the plugins of a plugin discovery image to another repository, copying the
+images directly from registry to registry. This is an alternative to the "download-bundle"
+and "upload-bundle" commands when both the source and the destination repositories are
+reachable. Only the images that are missing from the destination repository are copied,
+and the plugin inventory metadata of the destination repository is merged with the mirrored
+plugins and plugin groups, so the command can be run periodically to keep the destination
+repository up to date.`,
+		Example: `
+    # Mirror all the plugins of the default discovery source to a repository
+    tanzu plugin mirror --to custom.registry.company.com/tanzu-plugins/
+
+    # Mirror the plugins of a specific group version from a custom discovery source
+    tanzu plugin mirror --from custom.registry.vmware.com/tkg/tanzu-plugins/plugin-inventory:latest --to custom.registry.company.com/tanzu-plugins/ --group vmware-tkg/default:v1.0.0
+
+    # Show the plugins, plugin groups and images missing from a repository without copying anything
+    tanzu plugin mirror --to custom.registry.company.com/tanzu-plugins/ --dry-run`,
+		ValidArgsFunction: noMoreCompletions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options := airgapped.MirrorPluginsOptions{
+				PluginInventoryImage: mpo.pluginDiscoveryOCIImage,
+				DestinationRepo:      mpo.destinationRepo,
+				Groups:               mpo.groups,
+				Concurrency:          mpo.concurrency,
+				DryRun:               mpo.dryRun,
+				ImageProcessor:       carvelhelpers.NewImageOperationsImpl(),
+			}
+			diff, err := options.MirrorPlugins()
+			if err != nil {
+				return err
+			}
+			if mpo.dryRun {
+				displayPluginMirrorDiff(diff, cmd.OutOrStdout())
+			}
+			return nil
+		},
+	}
+
+	f := mirrorCmd.Flags()
+	f.StringVarP(&mpo.pluginDiscoveryOCIImage, "from", "", constants.TanzuCLIDefaultCentralPluginDiscoveryImage, "URI of the plugin discovery image providing the plugins to mirror")
+	utils.PanicOnErr(mirrorCmd.RegisterFlagCompletionFunc("from", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return cobra.AppendActiveHelp(nil, "Please enter the URI of the plugin discovery image providing the plugins to mirror"), cobra.ShellCompDirectiveNoFileComp
+	}))
+	f.StringVarP(&mpo.destinationRepo, "to", "", "", "destination repository for publishing the mirrored plugins")
+	utils.PanicOnErr(mirrorCmd.RegisterFlagCompletionFunc("to", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return cobra.AppendActiveHelp(nil, "Please enter the URI of the destination repository for publishing the mirrored plugins"), cobra.ShellCompDirectiveNoFileComp
+	}))
+	f.StringSliceVarP(&mpo.groups, "group", "", []string{}, "only mirror the plugins specified in the plugin-group version (can specify multiple)")
+	utils.PanicOnErr(mirrorCmd.RegisterFlagCompletionFunc("group", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return cobra.AppendActiveHelp(nil, "Please enter the plugin group to mirror, as vendor-publisher/name:version"), cobra.ShellCompDirectiveNoFileComp
+	}))
+	f.IntVarP(&mpo.concurrency, "concurrency", "", 4, "number of images to copy in parallel")
+	utils.PanicOnErr(mirrorCmd.RegisterFlagCompletionFunc("concurrency", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return cobra.AppendActiveHelp(nil, "Please enter the number of images to copy in parallel"), cobra.ShellCompDirectiveNoFileComp
+	}))
+	f.BoolVarP(&mpo.dryRun, "dry-run", "", false, "only show the plugins, plugin groups and images missing from the destination repository")
+	f.StringVarP(&outputFormat, "output", "o", "", "output format of the dry run (yaml|json|table)")
+	utils.PanicOnErr(mirrorCmd.RegisterFlagCompletionFunc("output", completionGetOutputFormats))
+
+	_ = mirrorCmd.MarkFlagRequired("to")
+
+	return mirrorCmd
+}
+
+func displayPluginMirrorDiff(diff *airgapped.PluginMirrorDiff, writer io.Writer) {
+	if outputFormat != "" && outputFormat != string(component.TableOutputType) {
+		component.NewObjectWriter(writer, outputFormat, diff).Render()
+		return
+	}
+
+	output := component.NewOutputWriterWithOptions(writer, string(component.TableOutputType), []component.OutputWriterOption{}, "kind", "missing")
+	for _, id := range diff.PluginGroups {
+		output.AddRow("plugin-group", id)
+	}
+	for _, id := range diff.Plugins {
+		output.AddRow("plugin", id)
+	}
+	for _, image := range diff.Images {
+		output.AddRow("image", image)
+	}
+	output.Render()
+}
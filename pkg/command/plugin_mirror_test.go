@@ -0,0 +1,97 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/airgapped"
+)
+
+func TestCompletionPluginMirror(t *testing.T) {
+	// This is global logic and needs not be tested for each
+	// command.  Let's deactivate it.
+	os.Setenv("TANZU_ACTIVE_HELP", "no_short_help")
+
+	tests := []struct {
+		test     string
+		args     []string
+		expected string
+	}{
+		{
+			test: "completion of the required flag after the mirror command",
+			args: []string{"__complete", "plugin", "mirror", ""},
+			// ":4" is the value of the ShellCompDirectiveNoFileComp
+			expected: "--to\tdestination repository for publishing the mirrored plugins\n" +
+				"_activeHelp_ " + compNoMoreArgsMsg + "\n:4\n",
+		},
+		{
+			test: "no completion for the --from flag value of the mirror command",
+			args: []string{"__complete", "plugin", "mirror", "--from", ""},
+			// ":4" is the value of the ShellCompDirectiveNoFileComp
+			expected: "_activeHelp_ Please enter the URI of the plugin discovery image providing the plugins to mirror\n:4\n",
+		},
+		{
+			test: "no completion for the --to flag value of the mirror command",
+			args: []string{"__complete", "plugin", "mirror", "--to", ""},
+			// ":4" is the value of the ShellCompDirectiveNoFileComp
+			expected: "_activeHelp_ Please enter the URI of the destination repository for publishing the mirrored plugins\n:4\n",
+		},
+		{
+			test: "no completion for the --group flag value of the mirror command",
+			args: []string{"__complete", "plugin", "mirror", "--group", ""},
+			// ":4" is the value of the ShellCompDirectiveNoFileComp
+			expected: "_activeHelp_ Please enter the plugin group to mirror, as vendor-publisher/name:version\n:4\n",
+		},
+	}
+
+	for _, spec := range tests {
+		t.Run(spec.test, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rootCmd, err := NewRootCmdForTest()
+			assert.Nil(err)
+
+			var out bytes.Buffer
+			rootCmd.SetOut(&out)
+			rootCmd.SetArgs(spec.args)
+
+			err = rootCmd.Execute()
+			assert.Nil(err)
+
+			assert.Equal(spec.expected, out.String())
+
+			resetPluginCommandFlags()
+		})
+	}
+
+	os.Unsetenv("TANZU_ACTIVE_HELP")
+}
+
+func TestDisplayPluginMirrorDiff(t *testing.T) {
+	assert := assert.New(t)
+
+	diff := &airgapped.PluginMirrorDiff{
+		Plugins:      []string{"foo@global:v1.0.0"},
+		PluginGroups: []string{"vmware-tkg/default:v1.0.0"},
+		Images:       []string{"registry.example.com/plugins/foo:v1.0.0"},
+	}
+
+	var out bytes.Buffer
+	displayPluginMirrorDiff(diff, &out)
+	assert.Contains(out.String(), "KIND")
+	assert.Regexp(`plugin-group\s+vmware-tkg/default:v1.0.0`, out.String())
+	assert.Regexp(`plugin\s+foo@global:v1.0.0`, out.String())
+	assert.Regexp(`image\s+registry.example.com/plugins/foo:v1.0.0`, out.String())
+
+	outputFormat = "json"
+	defer func() { outputFormat = "" }()
+	out.Reset()
+	displayPluginMirrorDiff(diff, &out)
+	assert.Contains(out.String(), `"pluginGroups": [`)
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/vmware-tanzu/tanzu-cli/pkg/catalog"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/common"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/provenance"
 	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
 	"github.com/vmware-tanzu/tanzu-plugin-runtime/plugin"
 )
@@ -978,4 +979,43 @@ func resetPluginCommandFlags() {
 	groupID = ""
 	showDetails = false
 	pluginName = ""
+	showProvenance = false
+}
+
+func TestDisplayPluginProvenance(t *testing.T) {
+	assert := assert.New(t)
+
+	statement := &provenance.Statement{
+		Type:          provenance.StatementType,
+		Subject:       []provenance.ResourceDescriptor{{Name: "foo", Digest: map[string]string{"sha256": "abcd"}}},
+		PredicateType: provenance.PredicateType,
+		Predicate: provenance.Provenance{
+			BuildDefinition: provenance.BuildDefinition{
+				BuildType:          provenance.BuildType,
+				ExternalParameters: provenance.BuildParameters{Plugin: "foo", Version: "v1.0.0", GoFlags: "-trimpath", Reproducible: true},
+				InternalParameters: provenance.InternalParameters{GoVersion: "go1.22.5"},
+				ResolvedDependencies: []provenance.ResourceDescriptor{
+					{URI: "git+https://github.com/org/repo@1234", Digest: map[string]string{"gitCommit": "1234"}},
+					{URI: "pkg:golang/github.com/pkg/errors@v0.9.1", Digest: map[string]string{"goModuleH1": "xyz"}},
+				},
+			},
+			RunDetails: provenance.RunDetails{Builder: provenance.Builder{ID: provenance.BuilderID}},
+		},
+	}
+
+	outputFormat = "listtable"
+	defer func() { outputFormat = "" }()
+	var out bytes.Buffer
+	displayPluginProvenance(statement, &out)
+	assert.Regexp(`subject:\s+foo@sha256:abcd`, out.String())
+	assert.Regexp(`source:\s+git\+https://github.com/org/repo@1234`, out.String())
+	assert.Regexp(`commit:\s+1234`, out.String())
+	assert.Regexp(`goVersion:\s+go1.22.5`, out.String())
+	assert.Regexp(`reproducible:\s+true`, out.String())
+	assert.Regexp(`dependencies:\s+github.com/pkg/errors@v0.9.1`, out.String())
+
+	outputFormat = "json"
+	out.Reset()
+	displayPluginProvenance(statement, &out)
+	assert.Contains(out.String(), `"predicateType": "https://slsa.dev/provenance/v1"`)
 }
@@ -32,6 +32,7 @@ import (
 	"github.com/vmware-tanzu/tanzu-cli/pkg/discovery"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/globalinit"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/lastversion"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/plugincmdtree"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/pluginmanager"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/pluginsupplier"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/recommendedversion"
@@ -92,12 +93,28 @@ func createRootCmd() (*cobra.Command, error) { //nolint: gocyclo
 	// Configure defined environment variables found in the config file
 	cliconfig.ConfigureEnvVariables()
 
+	// Complete plugin subcommands and flags from the plugin command tree cache
+	// so that plugins only need to be invoked for dynamic completions
+	if disabled, _ := strconv.ParseBool(os.Getenv(constants.DisableCachedPluginCompletion)); !disabled {
+		cli.SetPluginCompletionFunc(plugincmdtree.CompletePluginCommand)
+	} else {
+		cli.SetPluginCompletionFunc(nil)
+	}
+
+	// Warn about plugin versions not supported by the active contexts, and report why
+	// a plugin is unavailable instead of invoking it when the cluster of the active
+	// context does not meet the plugin requirements
+	cli.SetPluginAvailabilityFunc(pluginmanager.VerifyPluginAvailability)
+
 	rootCmd.AddCommand(
 		newVersionCmd(),
 		newPluginCmd(),
 		newLoginCmd(),
 		newInitCmd(),
 		newCompletionCmd(),
+		newDocsCmd(),
+		newDoctorCmd(),
+		newUpdateCmd(),
 		newConfigCmd(),
 		newContextCmd(),
 		newAPITokenCmd(),
@@ -1024,18 +1024,21 @@ func TestCompletionShortHelpInActiveHelp(t *testing.T) {
 			test: "short help as active help at level 1",
 			args: []string{"__complete", "plugin", ""},
 			// ":4" is the value of the ShellCompDirectiveNoFileComp
-			expected: "clean\tClean the plugins\n" +
+			expected: "bundle\tInspect plugin bundles\n" +
+				"clean\tClean the plugins\n" +
 				"describe\tDescribe a plugin\n" +
 				"download-bundle\tDownload plugin bundle to the local system\n" +
 				"group\tManage plugin-groups\n" +
 				"install\tInstall a plugin\n" +
 				"list\tList installed plugins\n" +
+				"mirror\tMirror plugins to a repository\n" +
 				"search\tSearch for available plugins\n" +
 				"source\tManage plugin discovery sources\n" +
 				"sync\tInstalls all plugins recommended by the active contexts\n" +
 				"uninstall\tUninstall a plugin\n" +
 				"upgrade\tUpgrade a plugin\n" +
 				"upload-bundle\tUpload plugin bundle to a repository\n" +
+				"verify-bundle\tVerify the integrity of a plugin bundle\n" +
 				"_activeHelp_ Command help: Manage CLI plugins\n" +
 				":4\n",
 		},
@@ -0,0 +1,70 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/plugin"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/recommendedversion"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/selfupdate"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/utils"
+)
+
+var (
+	updateVersion string
+	updateDryRun  bool
+)
+
+func newUpdateCmd() *cobra.Command {
+	var updateCmd = &cobra.Command{
+		Use:   "update",
+		Short: "Update the Tanzu CLI to a recommended version",
+		Long: "Update the Tanzu CLI to the recommended version listed in the central configuration. " +
+			"The binary for the current OS and architecture is downloaded, its signature and digest are verified, " +
+			"and it then replaces the running binary, which is kept as a backup with the '.bak' extension.",
+		Example: `
+    # Update the Tanzu CLI to the recommended version
+    tanzu update
+
+    # Update the Tanzu CLI to a specific recommended version
+    tanzu update --version v1.5.0
+
+    # Show the update that would be performed without performing it
+    tanzu update --dry-run`,
+		Args:              cobra.NoArgs,
+		ValidArgsFunction: noMoreCompletions,
+		Annotations: map[string]string{
+			"group": string(plugin.SystemCmdGroup),
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return selfupdate.UpdateCLI(cmd.OutOrStdout(), selfupdate.Options{
+				Version: updateVersion,
+				DryRun:  updateDryRun,
+			})
+		},
+	}
+	updateCmd.SetUsageFunc(cli.SubCmdUsageFunc)
+
+	f := updateCmd.Flags()
+	f.StringVar(&updateVersion, "version", "", "the recommended version to update to, instead of the most recent one")
+	f.BoolVar(&updateDryRun, "dry-run", false, "show the update that would be performed without performing it")
+	utils.PanicOnErr(updateCmd.RegisterFlagCompletionFunc("version", completeUpdateVersions))
+
+	return updateCmd
+}
+
+func completeUpdateVersions(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	recommended, err := recommendedversion.GetRecommendedVersions()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var comps []string
+	for i := range recommended {
+		comps = append(comps, recommended[i].Version)
+	}
+	return comps, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
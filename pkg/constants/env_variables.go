@@ -90,6 +90,12 @@ const (
 	// on `tanzu context create` or `tanzu context use`
 	SkipAutoInstallOfContextRecommendedPlugins = "TANZU_CLI_SKIP_CONTEXT_RECOMMENDED_PLUGIN_INSTALLATION"
 
+	// ContextScopedPlugins keeps the plugins recommended by a context installed for that context
+	// instead of installing them as standalone plugins. The CLI then uses the version of a plugin
+	// recommended by the active context, which allows switching between contexts that recommend
+	// different versions of the same plugin without reinstalling it.
+	ContextScopedPlugins = "TANZU_CLI_CONTEXT_SCOPED_PLUGINS"
+
 	// SkipTAPScopesValidationOnTanzuContext skips the TAP scopes validation on the token acquired while creating "tanzu"
 	// context using tanzu login or tanzu context create command
 	SkipTAPScopesValidationOnTanzuContext = "TANZU_CLI_SKIP_TAP_SCOPES_VALIDATION_ON_TANZU_CONTEXT"
@@ -109,6 +115,48 @@ const (
 	// UseTanzuCSP uses the Tanzu CSP while login/context creation
 	UseTanzuCSP = "TANZU_CLI_USE_TANZU_CLOUD_SERVICE_PROVIDER"
 
+	// DisableCachedPluginCompletion disables the completion of plugin subcommands and flags
+	// from the plugin command tree cache and always invokes the plugin to provide completions
+	DisableCachedPluginCompletion = "TANZU_CLI_DISABLE_CACHED_PLUGIN_COMPLETION"
+
+	// DocsPager specifies the pager used by `tanzu docs` to display the documentation
+	// of commands in a terminal, taking precedence over the PAGER environment variable
+	DocsPager = "TANZU_CLI_PAGER"
+
+	// SkipPluginClusterRequirementsCheck skips the verification that the cluster of the active
+	// context provides the capabilities required by a plugin before the plugin is invoked
+	SkipPluginClusterRequirementsCheck = "TANZU_CLI_SKIP_PLUGIN_CLUSTER_REQUIREMENTS_CHECK"
+
+	// ContextEventHookTimeoutSeconds changes the default time allowed for a plugin to process a context event
+	ContextEventHookTimeoutSeconds = "TANZU_CLI_CONTEXT_EVENT_HOOK_TIMEOUT_SECONDS"
+
+	// PluginDiagnosticChecksTimeoutSeconds changes the default time allowed for a plugin to run
+	// its diagnostic checks as part of `tanzu doctor`
+	PluginDiagnosticChecksTimeoutSeconds = "TANZU_CLI_PLUGIN_DIAGNOSTIC_CHECKS_TIMEOUT_SECONDS"
+
+	// AllowInsecureStaticPluginRepository allows the use of static plugin repositories
+	// served over plain HTTP as discovery sources
+	AllowInsecureStaticPluginRepository = "TANZU_CLI_ALLOW_INSECURE_STATIC_PLUGIN_REPOSITORY"
+
+	// PublicKeyPathForCLIBinarySignature specifies a custom public key to verify the signature
+	// of the CLI binary downloaded by `tanzu update` instead of the public keys embedded in the CLI
+	PublicKeyPathForCLIBinarySignature = "TANZU_CLI_BINARY_SIGNATURE_PUBLIC_KEY_PATH"
+
+	// SkipCLIBinarySignatureVerification skips the verification of the signature of the CLI binary
+	// downloaded by `tanzu update`. The digest of the binary is still verified.
+	// Note: THIS SHOULD ONLY BE USED FOR TEST AND NON PRODUCTION ENVIRONMENTS.
+	SkipCLIBinarySignatureVerification = "TANZU_CLI_SKIP_BINARY_SIGNATURE_VERIFICATION"
+
+	// PluginSignatureVerificationPolicy specifies how the signature of the plugin binaries is
+	// verified when installing plugins: "disabled" (the default) does not verify it, "warn" only
+	// warns about plugin binaries that are not signed or whose signature is invalid, and "required"
+	// fails the installation of such plugin binaries.
+	PluginSignatureVerificationPolicy = "TANZU_CLI_PLUGIN_SIGNATURE_VERIFICATION_POLICY"
+
+	// PublicKeyPathForPluginSignature specifies a custom public key to verify the signature
+	// of the plugin binaries instead of the public keys embedded in the CLI
+	PublicKeyPathForPluginSignature = "TANZU_CLI_PLUGIN_SIGNATURE_PUBLIC_KEY_PATH"
+
 	// TPKubernetesOpsEndpoint specifies kubernetes ops endpoint for the Tanzu Platform
 	// This will be used as part of `tanzu login`
 	TPKubernetesOpsEndpoint = "TANZU_CLI_K8S_OPS_ENDPOINT"
@@ -0,0 +1,59 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package cosignhelper
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/env"
+	sigs "github.com/sigstore/cosign/v2/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/options"
+)
+
+// SignBlob signs a blob with the private key referenced by privateKeyRef, which
+// can be any key reference supported by cosign (file, KMS or PKCS11 URI).
+// The password of an encrypted key is read from the COSIGN_PASSWORD environment
+// variable, or prompted for if the CLI runs in a terminal.
+// The returned signature is base64 encoded, as produced by 'cosign sign-blob'.
+func SignBlob(ctx context.Context, privateKeyRef string, blob []byte) ([]byte, error) {
+	signBlob, err := NewBlobSigner(ctx, privateKeyRef)
+	if err != nil {
+		return nil, err
+	}
+	return signBlob(blob)
+}
+
+// NewBlobSigner returns a function signing blobs like SignBlob, loading the private
+// key only once, so that the password of an encrypted key is only read once to sign
+// many blobs. The returned function is safe for concurrent use.
+func NewBlobSigner(ctx context.Context, privateKeyRef string) (func(blob []byte) ([]byte, error), error) {
+	signer, err := sigs.SignerFromKeyRef(ctx, privateKeyRef, getKeyPassword)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading the private key %q", privateKeyRef)
+	}
+	return func(blob []byte) ([]byte, error) {
+		sig, err := signer.SignMessage(bytes.NewReader(blob), options.WithContext(ctx))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed signing the blob")
+		}
+		return []byte(base64.StdEncoding.EncodeToString(sig)), nil
+	}, nil
+}
+
+// getKeyPassword returns the password used to decrypt a cosign private key
+func getKeyPassword(_ bool) ([]byte, error) {
+	if pw, ok := env.LookupEnv(env.VariablePassword); ok {
+		return []byte(pw), nil
+	}
+	if cosign.IsTerminal() {
+		return cosign.GetPassFromTerm(false)
+	}
+	return io.ReadAll(os.Stdin)
+}
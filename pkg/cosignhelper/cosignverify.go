@@ -5,10 +5,12 @@
 package cosignhelper
 
 import (
+	"bytes"
 	"context"
 	"crypto"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/base64"
 	"fmt"
 	"net/http"
 	"os"
@@ -24,6 +26,7 @@ import (
 	sigs "github.com/sigstore/cosign/v2/pkg/signature"
 	"github.com/sigstore/sigstore/pkg/cryptoutils"
 	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/options"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 
 	"github.com/vmware-tanzu/tanzu-cli/pkg/constants"
@@ -59,8 +62,6 @@ func NewCosignVerifier(publicKeyPath string, registryOpts *RegistryOptions) Cosi
 
 // Verify verifies the signature on the images
 func (vo *CosignVerifyOptions) Verify(ctx context.Context, images []string) error {
-	var pubKeys []signature.Verifier
-	var err error
 	httpTrans, err := vo.newHTTPTransport()
 	if err != nil {
 		return errors.Wrapf(err, "creating registry HTTP transport")
@@ -70,33 +71,11 @@ func (vo *CosignVerifyOptions) Verify(ctx context.Context, images []string) erro
 	// Using Rekor Default URL and Rekor public Keys (downloaded from online by default) not be feasible for air-gapped environment
 	ignoreTlog := true
 
-	switch {
-	// If PublicKeyPath is provided(custom public key) use it, else use the embedded public key
-	case vo.PublicKeyPath != "":
-		pubKey, err := sigs.PublicKeyFromKeyRefWithHashAlgo(ctx, vo.PublicKeyPath, crypto.SHA256)
-		if err != nil {
-			return fmt.Errorf("loading custom public key: %w", err)
-		}
-		pubKeys = append(pubKeys, pubKey)
-		pkcs11Key, ok := pubKey.(*pkcs11key.Key)
-		if ok {
-			defer pkcs11Key.Close()
-		}
-
-	default:
-		for _, raw := range [][]byte{tanzuCLIPluginDBImageSignPublicKeyOfficialV2, tanzuCLIPluginDBImageSignPublicKeyOfficial} {
-			// PEM encoded file.
-			key, err := cryptoutils.UnmarshalPEMToPublicKey(raw)
-			if err != nil {
-				return fmt.Errorf("failed unmarshalling PEM encoded default public key: %w", err)
-			}
-			pubKey, err := signature.LoadVerifier(key, crypto.SHA256)
-			if err != nil {
-				return fmt.Errorf("loading default public key: %w", err)
-			}
-			pubKeys = append(pubKeys, pubKey)
-		}
+	pubKeys, err := loadPublicKeys(ctx, vo.PublicKeyPath)
+	if err != nil {
+		return err
 	}
+	defer closePublicKeys(pubKeys)
 
 	var nameOpts []name.Option
 	if vo.RegistryOpts.AllowInsecure {
@@ -143,6 +122,80 @@ func (vo *CosignVerifyOptions) Verify(ctx context.Context, images []string) erro
 	return nil
 }
 
+// VerifyBlobSignature verifies the signature of a blob, as produced by
+// 'cosign sign-blob', using the custom public key if publicKeyPath is provided
+// or else the public keys embedded in the CLI.
+// The signature can either be raw or base64 encoded.
+func VerifyBlobSignature(ctx context.Context, publicKeyPath string, blob, sig []byte) error {
+	pubKeys, err := loadPublicKeys(ctx, publicKeyPath)
+	if err != nil {
+		return err
+	}
+	defer closePublicKeys(pubKeys)
+
+	if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig))); err == nil {
+		sig = decoded
+	}
+
+	var arrErr []error
+	for _, verifier := range pubKeys {
+		err := verifier.VerifySignature(bytes.NewReader(sig), bytes.NewReader(blob), options.WithContext(ctx))
+		if err == nil {
+			return nil
+		}
+		arrErr = append(arrErr, err)
+	}
+	return errors.Wrap(kerrors.NewAggregate(arrErr), "failed validating the signature of the blob")
+}
+
+// ValidatePublicKey checks that the public key used to verify the signature of
+// the plugin discovery images can be loaded. If publicKeyPath is empty, the
+// public keys embedded in the CLI are checked.
+func ValidatePublicKey(ctx context.Context, publicKeyPath string) error {
+	pubKeys, err := loadPublicKeys(ctx, publicKeyPath)
+	closePublicKeys(pubKeys)
+	return err
+}
+
+// loadPublicKeys loads the custom public key if publicKeyPath is provided,
+// or else the public keys embedded in the CLI
+func loadPublicKeys(ctx context.Context, publicKeyPath string) ([]signature.Verifier, error) {
+	var pubKeys []signature.Verifier
+	switch {
+	// If PublicKeyPath is provided(custom public key) use it, else use the embedded public key
+	case publicKeyPath != "":
+		pubKey, err := sigs.PublicKeyFromKeyRefWithHashAlgo(ctx, publicKeyPath, crypto.SHA256)
+		if err != nil {
+			return nil, fmt.Errorf("loading custom public key: %w", err)
+		}
+		pubKeys = append(pubKeys, pubKey)
+
+	default:
+		for _, raw := range [][]byte{tanzuCLIPluginDBImageSignPublicKeyOfficialV2, tanzuCLIPluginDBImageSignPublicKeyOfficial} {
+			// PEM encoded file.
+			key, err := cryptoutils.UnmarshalPEMToPublicKey(raw)
+			if err != nil {
+				return nil, fmt.Errorf("failed unmarshalling PEM encoded default public key: %w", err)
+			}
+			pubKey, err := signature.LoadVerifier(key, crypto.SHA256)
+			if err != nil {
+				return nil, fmt.Errorf("loading default public key: %w", err)
+			}
+			pubKeys = append(pubKeys, pubKey)
+		}
+	}
+	return pubKeys, nil
+}
+
+// closePublicKeys releases the hardware keys among the loaded public keys
+func closePublicKeys(pubKeys []signature.Verifier) {
+	for _, pubKey := range pubKeys {
+		if pkcs11Key, ok := pubKey.(*pkcs11key.Key); ok {
+			pkcs11Key.Close()
+		}
+	}
+}
+
 func (vo *CosignVerifyOptions) newHTTPTransport() (*http.Transport, error) {
 	var pool *x509.CertPool
 
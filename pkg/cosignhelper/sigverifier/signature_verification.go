@@ -20,7 +20,7 @@ import (
 )
 
 func VerifyInventoryImageSignature(image string) error {
-	cosignVerifier, err := getCosignVerifier(image)
+	cosignVerifier, err := getInventoryImageVerifier(image)
 	if err != nil {
 		return errors.Wrapf(err, "failed to initialize the cosign verifier")
 	}
@@ -47,6 +47,50 @@ func VerifyInventoryImageSignature(image string) error {
 	return nil
 }
 
+// VerifyImageSignature verifies the signature of the specified image using the public
+// key at publicKeyPath, or the public keys embedded in the CLI if publicKeyPath is empty.
+// Unlike VerifyInventoryImageSignature, a verification failure is returned to the caller.
+func VerifyImageSignature(image, publicKeyPath string) error {
+	registryOptions, err := getCosignVerifierRegistryOptions(image)
+	if err != nil {
+		return errors.Wrapf(err, "unable to prepare the registry options for cosign verification")
+	}
+	return cosignhelper.NewCosignVerifier(publicKeyPath, registryOptions).Verify(context.Background(), []string{image})
+}
+
+// unsignedImageVerifier fails the verification of the discovery images whose
+// signature cannot be verified, for the specified reason
+type unsignedImageVerifier struct {
+	reason string
+}
+
+func (v unsignedImageVerifier) Verify(_ context.Context, images []string) error {
+	return errors.Errorf("%s: %s", v.reason, strings.Join(images, ", "))
+}
+
+// isStaticRepositoryURL returns true if the discovery image is the URL of the
+// inventory database of a static plugin repository served over HTTP(S)
+func isStaticRepositoryURL(image string) bool {
+	image = strings.TrimSpace(image)
+	return strings.HasPrefix(image, "http://") || strings.HasPrefix(image, "https://")
+}
+
+// getInventoryImageVerifier returns the verifier of the signature of the discovery image
+func getInventoryImageVerifier(image string) (cosignhelper.Cosignhelper, error) {
+	switch {
+	case registry.IsLocalImage(image):
+		// The signature of images stored on the local filesystem is not part of the
+		// OCI image layout, so such images can only be used through the skip list
+		return unsignedImageVerifier{reason: "the signature of images stored on the local filesystem cannot be verified"}, nil
+	case isStaticRepositoryURL(image):
+		// The inventory database of a static plugin repository served over HTTP(S)
+		// is not signed, so such repositories can only be used through the skip list
+		return unsignedImageVerifier{reason: "the inventory database of a static plugin repository is not signed"}, nil
+	default:
+		return getCosignVerifier(image)
+	}
+}
+
 func getCosignVerifier(image string) (cosignhelper.Cosignhelper, error) {
 	// Get the custom public key path and prepare cosign verifier, if empty, cosign verifier would use embedded public key for verification
 	customPublicKeyPath := os.Getenv(constants.PublicKeyPathForPluginDiscoveryImageSignature)
@@ -81,6 +81,42 @@ var _ = Describe("Unit tests for discovery image signature verification", func()
 				Expect(err.Error()).To(ContainSubstring("signature verification fake error"))
 			})
 		})
+		Context("When the image is stored on the local filesystem and is in the signature verification skip list", func() {
+			It("should skip signature verification and return success", func() {
+				os.Setenv(constants.PluginDiscoveryImageSignatureVerificationSkipList, "file:///opt/tanzu-plugins/plugin-inventory:latest")
+				defer os.Unsetenv(constants.PluginDiscoveryImageSignatureVerificationSkipList)
+				err = VerifyInventoryImageSignature("file:///opt/tanzu-plugins/plugin-inventory:latest")
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+		Context("When the image is stored on the local filesystem and is not in the signature verification skip list", func() {
+			It("should fail the signature verification", func() {
+				image := "file:///opt/tanzu-plugins/plugin-inventory:latest"
+				verifier, err := getInventoryImageVerifier(image)
+				Expect(err).ToNot(HaveOccurred())
+				err = verifyInventoryImageSignature(image, verifier)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("the signature of images stored on the local filesystem cannot be verified"))
+			})
+		})
+		Context("When the image is a static plugin repository and is in the signature verification skip list", func() {
+			It("should skip signature verification and return success", func() {
+				os.Setenv(constants.PluginDiscoveryImageSignatureVerificationSkipList, "https://plugins.example.com/repository/plugin_inventory.db")
+				defer os.Unsetenv(constants.PluginDiscoveryImageSignatureVerificationSkipList)
+				err = VerifyInventoryImageSignature("https://plugins.example.com/repository/plugin_inventory.db")
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+		Context("When the image is a static plugin repository and is not in the signature verification skip list", func() {
+			It("should fail the signature verification", func() {
+				image := "https://plugins.example.com/repository/plugin_inventory.db"
+				verifier, err := getInventoryImageVerifier(image)
+				Expect(err).ToNot(HaveOccurred())
+				err = verifyInventoryImageSignature(image, verifier)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("the inventory database of a static plugin repository is not signed"))
+			})
+		})
 	})
 
 	Describe("getCosignVerifier tests", func() {
@@ -7,10 +7,12 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
 
 	cliv1alpha1 "github.com/vmware-tanzu/tanzu-cli/apis/cli/v1alpha1"
+	cliv1alpha2 "github.com/vmware-tanzu/tanzu-cli/apis/cli/v1alpha2"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/cluster"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/common"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/distribution"
@@ -76,8 +78,19 @@ func (k *KubernetesDiscovery) GetDiscoveredPlugins(clusterClient cluster.Client)
 		return nil, nil
 	}
 
+	// Use the v1alpha2 CLIPlugin resources if the cluster serves them.
+	// Otherwise, the v1alpha1 CLIPlugin resources are converted to v1alpha2.
+	v1alpha2Served, _ := clusterClient.VerifyCLIPluginV1alpha2API()
+
 	// Try to get all cliplugins resources available on the cluster
-	cliplugins, errListCLIPlugins := clusterClient.ListCLIPluginResources()
+	var cliplugins []cliv1alpha1.CLIPlugin
+	var cliv1alpha2plugins []cliv1alpha2.CLIPlugin
+	var errListCLIPlugins error
+	if v1alpha2Served {
+		cliv1alpha2plugins, errListCLIPlugins = clusterClient.ListCLIPluginV1alpha2Resources()
+	} else {
+		cliplugins, errListCLIPlugins = clusterClient.ListCLIPluginResources()
+	}
 	if errListCLIPlugins != nil {
 		// If there was an earlier error while verifying CRD, assuming that it was a legitimate
 		// error and will just log a warning and return without error
@@ -93,7 +106,7 @@ func (k *KubernetesDiscovery) GetDiscoveredPlugins(clusterClient cluster.Client)
 		return nil, errListCLIPlugins
 	}
 
-	log.V(4).Infof("found %v CLIPlugin resources.", len(cliplugins))
+	log.V(4).Infof("found %v CLIPlugin resources.", len(cliplugins)+len(cliv1alpha2plugins))
 
 	imageRepositoryOverride, err := clusterClient.GetCLIPluginImageRepositoryOverride()
 	if err != nil {
@@ -107,10 +120,20 @@ func (k *KubernetesDiscovery) GetDiscoveredPlugins(clusterClient cluster.Client)
 			return nil, err
 		}
 		log.V(5).Infof("processing CLIPlugin %q", cliplugins[i].Name)
-		dp.Source = k.name
-		dp.DiscoveryType = k.Type()
 		plugins = append(plugins, dp)
 	}
+	for i := range cliv1alpha2plugins {
+		dp, err := DiscoveredFromK8sV1alpha2(&cliv1alpha2plugins[i])
+		if err != nil {
+			return nil, err
+		}
+		log.V(5).Infof("processing CLIPlugin %q", cliv1alpha2plugins[i].Name)
+		plugins = append(plugins, dp)
+	}
+	for i := range plugins {
+		plugins[i].Source = k.name
+		plugins[i].DiscoveryType = k.Type()
+	}
 
 	return plugins, nil
 }
@@ -125,13 +148,12 @@ func DiscoveredFromK8sV1alpha1WithImageRepositoryOverride(p *cliv1alpha1.CLIPlug
 	// Update artifacts based on image repository override if applicable
 	UpdateArtifactsBasedOnImageRepositoryOverride(p, imageRepoOverride)
 
-	dp := Discovered{
-		Name:               p.Name,
-		Description:        p.Spec.Description,
-		RecommendedVersion: p.Spec.RecommendedVersion,
-		Optional:           p.Spec.Optional,
-		Target:             configtypes.StringToTarget(string(p.Spec.Target)),
+	dp, err := DiscoveredFromK8sV1alpha2(ConvertCLIPluginV1alpha1ToV1alpha2(p))
+	if err != nil {
+		return dp, err
 	}
+
+	// The artifacts of the plugin can only be specified with v1alpha1
 	dp.SupportedVersions = make([]string, 0)
 	for v := range p.Spec.Artifacts {
 		dp.SupportedVersions = append(dp.SupportedVersions, v)
@@ -144,6 +166,50 @@ func DiscoveredFromK8sV1alpha1WithImageRepositoryOverride(p *cliv1alpha1.CLIPlug
 	return dp, nil
 }
 
+// DiscoveredFromK8sV1alpha2 returns discovered plugin object from k8sV1alpha2
+func DiscoveredFromK8sV1alpha2(p *cliv1alpha2.CLIPlugin) (Discovered, error) {
+	dp := Discovered{
+		Name:                 p.Name,
+		Description:          p.Spec.Description,
+		RecommendedVersion:   p.Spec.RecommendedVersion,
+		Optional:             p.Spec.Optional,
+		Target:               configtypes.StringToTarget(string(p.Spec.Target)),
+		MinVersion:           p.Spec.MinVersion,
+		MaxVersion:           p.Spec.MaxVersion,
+		Deprecated:           p.Spec.Deprecated,
+		ReplacedBy:           p.Spec.ReplacedBy,
+		RequiredContextTypes: p.Spec.RequiredContextTypes,
+	}
+	if p.Spec.PluginGroup != nil && p.Spec.PluginGroup.Name != "" {
+		dp.PluginGroupID = p.Spec.PluginGroup.Name
+		if p.Spec.PluginGroup.Version != "" {
+			dp.PluginGroupID += ":" + p.Spec.PluginGroup.Version
+		}
+	}
+	if dp.RecommendedVersion == "" && dp.PluginGroupID == "" {
+		return dp, errors.Errorf("plugin %s must specify a recommended version or a plugin group", p.Name)
+	}
+	return dp, nil
+}
+
+// ConvertCLIPluginV1alpha1ToV1alpha2 converts a v1alpha1 CLIPlugin resource to a v1alpha2 CLIPlugin resource.
+// The artifacts of the v1alpha1 resource have no equivalent in v1alpha2 and are not converted.
+func ConvertCLIPluginV1alpha1ToV1alpha2(p *cliv1alpha1.CLIPlugin) *cliv1alpha2.CLIPlugin {
+	return &cliv1alpha2.CLIPlugin{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: cliv1alpha2.GroupVersion.String(),
+			Kind:       cliv1alpha2.GroupVersionKindCLIPlugin.Kind,
+		},
+		ObjectMeta: *p.ObjectMeta.DeepCopy(),
+		Spec: cliv1alpha2.CLIPluginSpec{
+			Description:        p.Spec.Description,
+			RecommendedVersion: p.Spec.RecommendedVersion,
+			Optional:           p.Spec.Optional,
+			Target:             p.Spec.Target,
+		},
+	}
+}
+
 // UpdateArtifactsBasedOnImageRepositoryOverride updates artifacts based on image repository override
 func UpdateArtifactsBasedOnImageRepositoryOverride(p *cliv1alpha1.CLIPlugin, imageRepoOverride map[string]string) {
 	replaceImageRepository := func(a *cliv1alpha1.Artifact) {
@@ -10,7 +10,12 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+
 	"github.com/vmware-tanzu/tanzu-cli/apis/cli/v1alpha1"
+	"github.com/vmware-tanzu/tanzu-cli/apis/cli/v1alpha2"
 
 	"github.com/vmware-tanzu/tanzu-cli/pkg/discovery"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/fakes"
@@ -172,5 +177,102 @@ var _ = Describe("Unit tests for kubernetes discovery", func() {
 				Expect(artifact.Image).To(Equal("custom.repo.com/tkg/plugin/test-linux-plugin:v1.4.0"))
 			})
 		})
+
+		Context("When the cluster serves the v1alpha2 CLIPlugin API", func() {
+			BeforeEach(func() {
+				cliv1alpha2plugins := []v1alpha2.CLIPlugin{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "plugin1"},
+						Spec: v1alpha2.CLIPluginSpec{
+							Description:        "plugin1 desc",
+							RecommendedVersion: "v1.2.0",
+							MinVersion:         "v1.0.0",
+							MaxVersion:         "v1.3.0",
+						},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "plugin2"},
+						Spec: v1alpha2.CLIPluginSpec{
+							Description:          "plugin2 desc",
+							PluginGroup:          &v1alpha2.PluginGroupReference{Name: "vmware-tkg/default", Version: "v2.1.0"},
+							Deprecated:           true,
+							ReplacedBy:           "plugin3",
+							RequiredContextTypes: []configtypes.ContextType{configtypes.ContextTypeK8s},
+						},
+					},
+				}
+				currentClusterClient.VerifyCLIPluginCRDReturns(true, nil)
+				currentClusterClient.VerifyCLIPluginV1alpha2APIReturns(true, nil)
+				currentClusterClient.ListCLIPluginV1alpha2ResourcesReturns(cliv1alpha2plugins, nil)
+			})
+			It("should return the plugins of the v1alpha2 CLIPlugin resources", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(currentClusterClient.ListCLIPluginResourcesCallCount()).To(Equal(0))
+				Expect(len(plugins)).To(Equal(2))
+				Expect(plugins[0].Name).To(Equal("plugin1"))
+				Expect(plugins[0].RecommendedVersion).To(Equal("v1.2.0"))
+				Expect(plugins[0].MinVersion).To(Equal("v1.0.0"))
+				Expect(plugins[0].MaxVersion).To(Equal("v1.3.0"))
+				Expect(plugins[1].Name).To(Equal("plugin2"))
+				Expect(plugins[1].RecommendedVersion).To(Equal(""))
+				Expect(plugins[1].PluginGroupID).To(Equal("vmware-tkg/default:v2.1.0"))
+				Expect(plugins[1].Deprecated).To(BeTrue())
+				Expect(plugins[1].ReplacedBy).To(Equal("plugin3"))
+				Expect(plugins[1].RequiredContextTypes).To(Equal([]configtypes.ContextType{configtypes.ContextTypeK8s}))
+			})
+		})
+
+		Context("When a v1alpha2 CLIPlugin resource has no recommended version nor plugin group", func() {
+			BeforeEach(func() {
+				cliv1alpha2plugins := []v1alpha2.CLIPlugin{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "plugin1"},
+						Spec:       v1alpha2.CLIPluginSpec{Description: "plugin1 desc"},
+					},
+				}
+				currentClusterClient.VerifyCLIPluginCRDReturns(true, nil)
+				currentClusterClient.VerifyCLIPluginV1alpha2APIReturns(true, nil)
+				currentClusterClient.ListCLIPluginV1alpha2ResourcesReturns(cliv1alpha2plugins, nil)
+			})
+			It("should return an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("plugin plugin1 must specify a recommended version or a plugin group"))
+			})
+		})
+	})
+
+	Describe("When converting a v1alpha1 CLIPlugin resource", func() {
+		It("should keep the fields available in v1alpha2", func() {
+			cliplugin := fakehelper.NewCLIPlugin(fakehelper.TestCLIPluginOption{Name: "plugin1", Description: "plugin1 desc", RecommendedVersion: "v0.0.1"})
+			cliplugin.Spec.Optional = true
+			cliplugin.Spec.Target = configtypes.TargetK8s
+
+			converted := discovery.ConvertCLIPluginV1alpha1ToV1alpha2(&cliplugin)
+			Expect(converted.APIVersion).To(Equal("cli.tanzu.vmware.com/v1alpha2"))
+			Expect(converted.Kind).To(Equal("CLIPlugin"))
+			Expect(converted.Name).To(Equal("plugin1"))
+			Expect(converted.Spec).To(Equal(v1alpha2.CLIPluginSpec{
+				Description:        "plugin1 desc",
+				RecommendedVersion: "v0.0.1",
+				Optional:           true,
+				Target:             configtypes.TargetK8s,
+			}))
+		})
+	})
+
+	Describe("When checking if a version is supported", func() {
+		It("should check the version against the MinVersion and MaxVersion", func() {
+			dp := discovery.Discovered{Name: "plugin1"}
+			Expect(dp.IsVersionSupported("v0.1.0")).To(BeTrue())
+
+			dp.MinVersion = "v1.0.0"
+			Expect(dp.IsVersionSupported("v0.9.0")).To(BeFalse())
+			Expect(dp.IsVersionSupported("v1.0.0")).To(BeTrue())
+			Expect(dp.IsVersionSupported("v2.0.0")).To(BeTrue())
+
+			dp.MaxVersion = "v1.3.0"
+			Expect(dp.IsVersionSupported("v1.3.0")).To(BeTrue())
+			Expect(dp.IsVersionSupported("v1.3.1")).To(BeFalse())
+		})
 	})
 })
@@ -61,6 +61,12 @@ func newDBBackedOCIDiscovery(name, image string) *DBBackedOCIDiscovery {
 	// E.g., if the main image is at project.registry.vmware.com/tanzu-cli/plugins/plugin-inventory:latest
 	// then the image prefix should be project.registry.vmware.com/tanzu-cli/plugins/
 	imagePrefix := path.Dir(image)
+	// For a static plugin repository, the plugin binary paths are relative to the
+	// URL of the directory of the database
+	static := IsStaticRepositoryURI(image)
+	if static {
+		imagePrefix = getStaticRepositoryBaseURL(image)
+	}
 	// The data for the inventory is stored in the cache
 	pluginDataDir := filepath.Join(common.DefaultCacheDir, common.PluginInventoryDirName, name)
 
@@ -68,6 +74,7 @@ func newDBBackedOCIDiscovery(name, image string) *DBBackedOCIDiscovery {
 	return &DBBackedOCIDiscovery{
 		name:          name,
 		image:         image,
+		static:        static,
 		pluginDataDir: pluginDataDir,
 		inventory:     inventory,
 	}
@@ -34,7 +34,11 @@ type DBBackedOCIDiscovery struct {
 	// a valid URI path (MAY contain zero or more ‘/’) and a valid tag
 	// E.g., harbor.my-domain.local/tanzu-cli/plugins/plugins-inventory:latest
 	// This image contains a single SQLite database file.
+	// It can also be the URL of the database of a static plugin repository.
 	image string
+	// static indicates that the image is the URL of the database of a static
+	// plugin repository, where the plugin binaries are files instead of images
+	static bool
 	// pluginCriteria specifies different conditions that a plugin must respect to be discovered.
 	// This allows to filter the list of plugins that will be returned.
 	pluginCriteria *PluginDiscoveryCriteria
@@ -141,13 +145,18 @@ func (od *DBBackedOCIDiscovery) listPluginsFromInventory() ([]Discovered, error)
 			fmt.Fprintf(os.Stderr, "error parsing versions for plugin %s: %v\n", entry.Name, err)
 		}
 
+		artifacts := entry.Artifacts
+		if od.static {
+			artifacts = getStaticRepositoryArtifacts(entry.Artifacts)
+		}
+
 		plugin := Discovered{
 			Name:               entry.Name,
 			Description:        entry.Description,
 			RecommendedVersion: entry.RecommendedVersion,
 			InstalledVersion:   "", // Not set when discovered, but later.
 			SupportedVersions:  versions,
-			Distribution:       entry.Artifacts,
+			Distribution:       artifacts,
 			Optional:           false,
 			Scope:              common.PluginScopeStandalone,
 			Source:             od.name,
@@ -193,6 +202,10 @@ func (od *DBBackedOCIDiscovery) fetchInventoryImage() error {
 		return nil
 	}
 
+	if od.static {
+		return od.fetchStaticInventory()
+	}
+
 	// check the cache to see if downloaded plugin inventory database is up-to-date or not
 	// by comparing the image digests
 	newCacheHashFileForInventoryImage, newCacheHashFileForMetadataImage, err := od.checkImageCache()
@@ -0,0 +1,137 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package discovery
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/artifact"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/constants"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cosignhelper/sigverifier"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/distribution"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/plugininventory"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
+)
+
+// A static plugin repository is a plugin inventory database along with the plugin
+// binaries stored at paths relative to the database, as created by
+// 'tanzu builder inventory init --local-repository'. It is served by any web server,
+// without any registry, by using the URL of the database as discovery source
+// (e.g. https://example.com/plugins/plugin_inventory.db). When stored on the local
+// filesystem, it is used as any other local image through its plugin-inventory
+// image (e.g. file:///opt/tanzu/plugins/plugin-inventory:latest).
+
+// IsStaticRepositoryURI returns true if the discovery source URI is the URL of the
+// inventory database of a static plugin repository
+func IsStaticRepositoryURI(uri string) bool {
+	uri = strings.TrimSpace(uri)
+	if !strings.HasPrefix(uri, "http://") && !strings.HasPrefix(uri, "https://") {
+		return false
+	}
+	return strings.HasSuffix(uri, "/"+plugininventory.SQliteDBFileName)
+}
+
+// getStaticRepositoryBaseURL returns the URL of the directory of the inventory database,
+// which the plugin binary paths stored in the database are relative to
+func getStaticRepositoryBaseURL(uri string) string {
+	return uri[:strings.LastIndex(uri, "/")]
+}
+
+// fetchStaticInventory downloads the inventory database of the static plugin
+// repository and stores it in the cache directory if it has changed.
+func (od *DBBackedOCIDiscovery) fetchStaticInventory() error {
+	// The inventory database is not signed, so it must at least not be tampered with in transit
+	if strings.HasPrefix(strings.TrimSpace(od.image), "http://") {
+		if allow, _ := strconv.ParseBool(os.Getenv(constants.AllowInsecureStaticPluginRepository)); !allow {
+			return errors.Errorf("the static plugin repository %q is served over plain HTTP, which is insecure. To use it anyway, set the environment variable %q to true", od.image, constants.AllowInsecureStaticPluginRepository)
+		}
+	}
+
+	log.Infof("Refreshing plugin inventory cache for %q, this will take a few seconds.", od.image)
+
+	tempDir, err := os.MkdirTemp("", "")
+	if err != nil {
+		return errors.Wrap(err, "unable to create temp directory")
+	}
+	defer os.RemoveAll(tempDir)
+
+	dbContent, err := fetchStaticRepositoryFile(od.image)
+	if err != nil {
+		return errors.Wrapf(err, "plugins discovery database download failed. Please check that the repository URL %q is correct", od.image)
+	}
+	inventoryDBFilePath := filepath.Join(tempDir, plugininventory.SQliteDBFileName)
+	if err := os.WriteFile(inventoryDBFilePath, dbContent, 0644); err != nil {
+		return err
+	}
+
+	// The digest of the database is used in place of the digest of the inventory image
+	// to check whether the cached database is up-to-date
+	newCacheHashFile := od.checkDigestFileExistence(fmt.Sprintf("%x", sha256.Sum256(dbContent)), "")
+	if newCacheHashFile == "" {
+		// The cache can be re-used. We are done.
+		od.resetCacheTTL()
+		return nil
+	}
+
+	// The inventory database of a static plugin repository is not signed, so it can only
+	// be used through the signature verification skip list; the integrity of the plugin
+	// binaries is still verified using their digest in the database.
+	if err := sigverifier.VerifyInventoryImageSignature(od.image); err != nil {
+		return err
+	}
+
+	// The central config file is optional
+	baseURL := getStaticRepositoryBaseURL(od.image)
+	if content, err := fetchStaticRepositoryFile(baseURL + "/" + constants.CentralConfigFileName); err == nil {
+		_ = os.WriteFile(filepath.Join(tempDir, constants.CentralConfigFileName), content, 0644)
+	}
+
+	if err := os.MkdirAll(od.pluginDataDir, 0755); err != nil {
+		return errors.Wrap(err, "unable to create the plugin inventory cache directory")
+	}
+	if err := os.WriteFile(filepath.Join(od.pluginDataDir, plugininventory.SQliteDBFileName), dbContent, 0644); err != nil {
+		return errors.Wrap(err, "unable to cache the plugin inventory database")
+	}
+	od.setupCentralConfig(tempDir)
+
+	if file, err := os.Create(newCacheHashFile); err == nil {
+		_, _ = file.WriteString(od.image)
+		file.Close()
+	}
+	od.resetCacheTTL()
+	return nil
+}
+
+// fetchStaticRepositoryFile returns the content of a file of the static plugin
+// repository, served over HTTP(S) or stored on the local filesystem
+func fetchStaticRepositoryFile(url string) ([]byte, error) {
+	a, err := artifact.NewURIArtifact(url)
+	if err != nil {
+		return nil, err
+	}
+	return a.Fetch()
+}
+
+// getStaticRepositoryArtifacts returns the artifacts of a plugin of the static plugin
+// repository, whose location is the URI of the plugin binary rather than an OCI image
+func getStaticRepositoryArtifacts(artifacts distribution.Artifacts) distribution.Artifacts {
+	staticArtifacts := make(distribution.Artifacts, len(artifacts))
+	for version, artifactList := range artifacts {
+		staticArtifactList := make(distribution.ArtifactList, 0, len(artifactList))
+		for _, a := range artifactList {
+			a.URI = a.Image
+			a.Image = ""
+			staticArtifactList = append(staticArtifactList, a)
+		}
+		staticArtifacts[version] = staticArtifactList
+	}
+	return staticArtifacts
+}
@@ -0,0 +1,177 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package discovery
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/constants"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/distribution"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/plugininventory"
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+)
+
+var _ = Describe("Unit tests for the discovery of static plugin repositories", func() {
+	Describe("Check if a discovery source is a static plugin repository", func() {
+		It("should only match the URL of an inventory database", func() {
+			Expect(IsStaticRepositoryURI("https://example.com/plugins/plugin_inventory.db")).To(BeTrue())
+			Expect(IsStaticRepositoryURI("http://example.com/plugin_inventory.db")).To(BeTrue())
+
+			// A static plugin repository on the local filesystem is used as a local image
+			Expect(IsStaticRepositoryURI("file:///opt/tanzu/plugins/plugin_inventory.db")).To(BeFalse())
+			Expect(IsStaticRepositoryURI("file:///opt/tanzu/plugins/plugin-inventory:latest")).To(BeFalse())
+			Expect(IsStaticRepositoryURI("example.com/plugins/plugin-inventory:latest")).To(BeFalse())
+			Expect(IsStaticRepositoryURI("example.com/plugins/plugin_inventory.db")).To(BeFalse())
+			Expect(IsStaticRepositoryURI("https://example.com/plugins/")).To(BeFalse())
+			Expect(IsStaticRepositoryURI("https://example.com/plugins/other.db")).To(BeFalse())
+		})
+	})
+
+	Describe("List plugins from a static plugin repository", func() {
+		var (
+			err        error
+			tmpDir     string
+			repoDir    string
+			repoServer *httptest.Server
+			configFile *os.File
+		)
+		BeforeEach(func() {
+			tmpDir, err = os.MkdirTemp(os.TempDir(), "")
+			Expect(err).To(BeNil(), "unable to create temporary directory")
+			repoDir, err = os.MkdirTemp(os.TempDir(), "")
+			Expect(err).To(BeNil(), "unable to create temporary directory")
+
+			configFile, err = os.CreateTemp("", "config")
+			Expect(err).To(BeNil())
+			os.Setenv("TANZU_CONFIG", configFile.Name())
+			os.Setenv(constants.SuppressSkipSignatureVerificationWarning, "true")
+
+			// Create the static plugin repository with a single plugin
+			repoInventory := plugininventory.NewSQLiteInventory(filepath.Join(repoDir, plugininventory.SQliteDBFileName), "")
+			Expect(repoInventory.CreateSchema()).To(Succeed())
+			Expect(repoInventory.InsertPlugin(&plugininventory.PluginInventoryEntry{
+				Name:               "foo",
+				Target:             configtypes.TargetK8s,
+				Description:        "Foo plugin",
+				Publisher:          "tkg",
+				Vendor:             "vmware",
+				RecommendedVersion: "v1.0.0",
+				Artifacts: distribution.Artifacts{
+					"v1.0.0": []distribution.Artifact{
+						{
+							Image:  "vmware/tkg/linux/amd64/kubernetes/foo/v1.0.0/tanzu-foo-linux_amd64",
+							Digest: "0000000000",
+							OS:     "linux",
+							Arch:   "amd64",
+						},
+					},
+				},
+			})).To(Succeed())
+			repoServer = httptest.NewServer(http.FileServer(http.Dir(repoDir)))
+		})
+		AfterEach(func() {
+			repoServer.Close()
+			os.Unsetenv("TANZU_CONFIG")
+			os.Unsetenv(constants.SuppressSkipSignatureVerificationWarning)
+			os.Unsetenv(constants.PluginDiscoveryImageSignatureVerificationSkipList)
+			os.Unsetenv(constants.AllowInsecureStaticPluginRepository)
+			os.RemoveAll(configFile.Name())
+			os.RemoveAll(tmpDir)
+			os.RemoveAll(repoDir)
+		})
+
+		It("should return plugins whose artifacts are the URIs of the plugin binaries", func() {
+			repoURI := repoServer.URL + "/" + plugininventory.SQliteDBFileName
+			os.Setenv(constants.AllowInsecureStaticPluginRepository, "true")
+			os.Setenv(constants.PluginDiscoveryImageSignatureVerificationSkipList, repoURI)
+
+			discovery := NewOCIDiscovery("test-discovery", repoURI)
+			dbDiscovery, ok := discovery.(*DBBackedOCIDiscovery)
+			Expect(ok).To(BeTrue(), "oci discovery is not of type DBBackedOCIDiscovery")
+			Expect(dbDiscovery.static).To(BeTrue())
+
+			// Inject the data dir
+			dbDiscovery.pluginDataDir = tmpDir
+			dbDiscovery.inventory = plugininventory.NewSQLiteInventory(filepath.Join(tmpDir, plugininventory.SQliteDBFileName), getStaticRepositoryBaseURL(repoURI))
+
+			plugins, err := dbDiscovery.List()
+			Expect(err).To(BeNil())
+			Expect(len(plugins)).To(Equal(1))
+			Expect(plugins[0].Name).To(Equal("foo"))
+
+			artifacts := plugins[0].Distribution.(distribution.Artifacts)["v1.0.0"]
+			Expect(len(artifacts)).To(Equal(1))
+			Expect(artifacts[0].Image).To(BeEmpty())
+			Expect(artifacts[0].URI).To(Equal(repoServer.URL + "/vmware/tkg/linux/amd64/kubernetes/foo/v1.0.0/tanzu-foo-linux_amd64"))
+			Expect(artifacts[0].Digest).To(Equal("0000000000"))
+
+			// The database and its digest are cached
+			Expect(filepath.Join(tmpDir, plugininventory.SQliteDBFileName)).To(BeAnExistingFile())
+			matches, _ := filepath.Glob(filepath.Join(tmpDir, "digest.*"))
+			Expect(len(matches)).To(Equal(1))
+			Expect(dbDiscovery.cacheTTLExpired()).To(BeFalse())
+		})
+
+		It("should refuse a repository served over plain HTTP unless allowed", func() {
+			repoURI := repoServer.URL + "/" + plugininventory.SQliteDBFileName
+			os.Setenv(constants.PluginDiscoveryImageSignatureVerificationSkipList, repoURI)
+
+			discovery := NewOCIDiscovery("test-discovery", repoURI)
+			dbDiscovery, ok := discovery.(*DBBackedOCIDiscovery)
+			Expect(ok).To(BeTrue(), "oci discovery is not of type DBBackedOCIDiscovery")
+			dbDiscovery.pluginDataDir = tmpDir
+
+			_, err := dbDiscovery.List()
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("is served over plain HTTP, which is insecure"))
+			Expect(err.Error()).To(ContainSubstring(constants.AllowInsecureStaticPluginRepository))
+			Expect(filepath.Join(tmpDir, plugininventory.SQliteDBFileName)).NotTo(BeAnExistingFile())
+		})
+
+		It("should fail if the inventory database does not exist", func() {
+			repoURI := repoServer.URL + "/missing/" + plugininventory.SQliteDBFileName
+			os.Setenv(constants.AllowInsecureStaticPluginRepository, "true")
+
+			discovery := NewOCIDiscovery("test-discovery", repoURI)
+			dbDiscovery, ok := discovery.(*DBBackedOCIDiscovery)
+			Expect(ok).To(BeTrue(), "oci discovery is not of type DBBackedOCIDiscovery")
+			dbDiscovery.pluginDataDir = tmpDir
+
+			_, err := dbDiscovery.List()
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("plugins discovery database download failed"))
+		})
+
+		It("should use the repository stored on the filesystem as a local image", func() {
+			repoImage := "file://" + filepath.ToSlash(repoDir) + "/plugin-inventory:latest"
+			os.Setenv(constants.PluginDiscoveryImageSignatureVerificationSkipList, repoImage)
+
+			discovery := NewOCIDiscovery("test-discovery", repoImage)
+			dbDiscovery, ok := discovery.(*DBBackedOCIDiscovery)
+			Expect(ok).To(BeTrue(), "oci discovery is not of type DBBackedOCIDiscovery")
+			Expect(dbDiscovery.static).To(BeFalse())
+
+			// Inject the data dir
+			dbDiscovery.pluginDataDir = tmpDir
+			dbDiscovery.inventory = plugininventory.NewSQLiteInventory(filepath.Join(tmpDir, plugininventory.SQliteDBFileName), path.Dir(repoImage))
+
+			plugins, err := dbDiscovery.List()
+			Expect(err).To(BeNil())
+			Expect(len(plugins)).To(Equal(1))
+
+			// The plugin binaries are images of the local plugin repository
+			artifacts := plugins[0].Distribution.(distribution.Artifacts)["v1.0.0"]
+			Expect(len(artifacts)).To(Equal(1))
+			Expect(artifacts[0].URI).To(BeEmpty())
+			Expect(artifacts[0].Image).To(Equal(path.Dir(repoImage) + "/vmware/tkg/linux/amd64/kubernetes/foo/v1.0.0/tanzu-foo-linux_amd64"))
+		})
+	})
+})
@@ -57,6 +57,18 @@ func (stub *stubInventory) UpdatePluginActivationState(_ *plugininventory.Plugin
 func (stub *stubInventory) UpdatePluginGroupActivationState(_ *plugininventory.PluginGroup) error {
 	return nil
 }
+func (stub *stubInventory) DeletePlugin(_ *plugininventory.PluginInventoryEntry) error {
+	return nil
+}
+func (stub *stubInventory) UpdatePluginURIPrefix(_, _ string) (int, error) {
+	return 0, nil
+}
+func (stub *stubInventory) InsertPromotion(_ *plugininventory.PromotionEntry) error {
+	return nil
+}
+func (stub *stubInventory) GetPromotions() ([]*plugininventory.PromotionEntry, error) {
+	return nil, nil
+}
 
 var _ = Describe("Unit tests for DB-backed OCI discovery", func() {
 	var (
@@ -7,6 +7,7 @@ import (
 	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
 
 	"github.com/vmware-tanzu/tanzu-cli/pkg/distribution"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/utils"
 )
 
 // Discovered defines discovered plugin resource
@@ -61,6 +62,34 @@ type Discovered struct {
 
 	// Status is the installed/uninstalled status of the plugin.
 	Status string
+
+	// MinVersion is the oldest version of the plugin supported by the
+	// context from where the plugin was discovered.
+	MinVersion string
+
+	// MaxVersion is the most recent version of the plugin supported by the
+	// context from where the plugin was discovered.
+	MaxVersion string
+
+	// PluginGroupID is the ID of the plugin group providing the plugin
+	// in the format vendor-publisher/name:version. E.g., vmware-tkg/default:v2.1.0
+	PluginGroupID string
+
+	// Deprecated specifies whether the plugin should no longer be used.
+	Deprecated bool
+
+	// ReplacedBy is the name of the plugin replacing a deprecated plugin.
+	ReplacedBy string
+
+	// RequiredContextTypes defines the types of context the plugin is
+	// recommended for. If empty, the plugin is recommended for any context.
+	RequiredContextTypes []configtypes.ContextType
+}
+
+// IsVersionSupported returns true if the given version of the plugin is within
+// the MinVersion and MaxVersion range of the discovered plugin
+func (d *Discovered) IsVersionSupported(version string) bool {
+	return utils.IsVersionInRange(version, d.MinVersion, d.MaxVersion)
 }
 
 // DiscoveredSorter sorts discovered objects.
@@ -21,6 +21,9 @@ type Artifact struct {
 
 	// SHA256 hash of the plugin binary.
 	Digest string
+	// Signature is the base64-encoded cosign signature of the plugin binary,
+	// if the plugin binary was signed when published.
+	Signature string
 
 	// OS of the plugin binary in `GOOS` format.
 	OS string
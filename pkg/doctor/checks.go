@@ -0,0 +1,282 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package doctor
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/alexflint/go-filemutex"
+
+	configlib "github.com/vmware-tanzu/tanzu-plugin-runtime/config"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/carvelhelpers"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/catalog"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/constants"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cosignhelper"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/plugincmdtree"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/utils"
+)
+
+// getImageDigest is used to check that the discovery images can be reached.
+// It is a variable so that it can be replaced in tests.
+var getImageDigest = carvelhelpers.GetImageDigest
+
+// pluginFileNameRegex matches the name of plugin binaries as installed by the
+// plugin manager: <version>_<sha256 digest>_<target>[.exe]
+var pluginFileNameRegex = regexp.MustCompile(`_([0-9a-f]{64})_[^_]*$`)
+
+// checkCatalog verifies that the binary of every plugin in the catalog exists.
+// The repair removes the catalog entries of the missing binaries.
+func checkCatalog(fix bool) []Result {
+	plugins, err := catalog.ListPluginInstallations()
+	if err != nil {
+		return []Result{{Status: StatusError, Message: fmt.Sprintf("unable to read the plugin catalog: %v", err)}}
+	}
+
+	var missing []string
+	var results []Result
+	for i := range plugins {
+		if utils.PathExists(plugins[i].InstallationPath) {
+			continue
+		}
+		missing = append(missing, plugins[i].InstallationPath)
+		results = append(results, Result{
+			Status:  StatusError,
+			Message: fmt.Sprintf("the binary of plugin %q (%s) is missing at %s", plugins[i].Name, plugins[i].Target, plugins[i].InstallationPath),
+		})
+	}
+	if len(missing) == 0 {
+		return okResult(fmt.Sprintf("the binaries of all %d plugin installations are present", len(plugins)))
+	}
+
+	if !fix {
+		for i := range results {
+			results[i].Message += "; run 'tanzu doctor --fix' to remove the entry from the catalog, then reinstall the plugin"
+		}
+		return results
+	}
+
+	if err := catalog.DeletePluginInstallations(missing); err != nil {
+		results = append(results, Result{Status: StatusError, Message: fmt.Sprintf("unable to remove the catalog entries: %v", err)})
+		return results
+	}
+	for i := range results {
+		results[i].Status = StatusFixed
+		results[i].Message += "; the entry was removed from the catalog, reinstall the plugin if needed"
+	}
+	return results
+}
+
+// checkPluginDigests verifies that the binary of every installed plugin matches
+// the digest it was installed with
+func checkPluginDigests(_ bool) []Result {
+	plugins, err := catalog.ListPluginInstallations()
+	if err != nil {
+		return []Result{{Status: StatusError, Message: fmt.Sprintf("unable to read the plugin catalog: %v", err)}}
+	}
+
+	var results []Result
+	verified := 0
+	for i := range plugins {
+		expected := expectedPluginDigest(&plugins[i])
+		if expected == "" || !utils.PathExists(plugins[i].InstallationPath) {
+			continue
+		}
+		actual, err := fileDigest(plugins[i].InstallationPath)
+		if err != nil {
+			results = append(results, Result{
+				Status:  StatusError,
+				Message: fmt.Sprintf("unable to compute the digest of plugin %q: %v", plugins[i].Name, err),
+			})
+			continue
+		}
+		if actual != expected {
+			results = append(results, Result{
+				Status: StatusError,
+				Message: fmt.Sprintf("the binary of plugin %q (%s) has been modified since its installation (expected digest %s, actual digest %s); reinstall the plugin",
+					plugins[i].Name, plugins[i].Target, expected, actual),
+			})
+			continue
+		}
+		verified++
+	}
+	if len(results) == 0 {
+		return okResult(fmt.Sprintf("the digests of %d plugin binaries match", verified))
+	}
+	return results
+}
+
+// expectedPluginDigest returns the SHA256 digest the plugin binary is expected
+// to have, either as provided by the plugin or as recorded in the name of the
+// installed binary
+func expectedPluginDigest(plugin *cli.PluginInfo) string {
+	if plugin.Digest != "" {
+		return plugin.Digest
+	}
+	name := strings.TrimSuffix(filepath.Base(plugin.InstallationPath), ".exe")
+	if matches := pluginFileNameRegex.FindStringSubmatch(name); matches != nil {
+		return matches[1]
+	}
+	return ""
+}
+
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// checkCommandTreeCache verifies that the plugin command tree cache does not
+// contain data of plugins that are no longer installed. The repair removes that data.
+func checkCommandTreeCache(fix bool) []Result {
+	plugins, err := catalog.ListPluginInstallations()
+	if err != nil {
+		return []Result{{Status: StatusError, Message: fmt.Sprintf("unable to read the plugin catalog: %v", err)}}
+	}
+	stale, err := plugincmdtree.GetStaleCacheEntries(plugins)
+	if err != nil {
+		return []Result{{Status: StatusWarning, Message: fmt.Sprintf("unable to read the plugin command tree cache: %v; run 'tanzu completion --refresh-cache' to rebuild it", err)}}
+	}
+	if stale.IsEmpty() {
+		return okResult("the plugin command tree cache is up to date")
+	}
+
+	message := fmt.Sprintf("the plugin command tree cache contains %d command trees and %d docs directories of plugins that are no longer installed",
+		len(stale.CommandTrees), len(stale.DocsDirs))
+	if !fix {
+		return []Result{{Status: StatusWarning, Message: message + "; run 'tanzu doctor --fix' to remove them"}}
+	}
+	if err := plugincmdtree.DeleteStaleCacheEntries(stale); err != nil {
+		return []Result{{Status: StatusWarning, Message: fmt.Sprintf("%s; unable to remove them: %v", message, err)}}
+	}
+	return []Result{{Status: StatusFixed, Message: message + "; they were removed"}}
+}
+
+// checkConfigLocks verifies that none of the locks of the configuration files
+// is held by another process. A lock held for a long time indicates a hung
+// process, which would block every CLI command.
+func checkConfigLocks(_ bool) []Result {
+	configPath, err := configlib.ClientConfigPath()
+	if err != nil {
+		return []Result{{Status: StatusError, Message: fmt.Sprintf("unable to locate the configuration directory: %v", err)}}
+	}
+
+	var results []Result
+	for _, lockFile := range []string{configlib.LocalTanzuFileLock, configlib.LocalTanzuConfigNextGenFileLock, configlib.LocalTanzuMetadataFileLock} {
+		lockPath := filepath.Join(filepath.Dir(configPath), lockFile)
+		if !utils.PathExists(lockPath) {
+			continue
+		}
+		if err := tryLock(lockPath); err != nil {
+			results = append(results, Result{
+				Status:  StatusWarning,
+				Message: fmt.Sprintf("the lock %s is held by another process; if no other tanzu command is running, stop the process holding it", lockPath),
+			})
+		}
+	}
+	if len(results) == 0 {
+		return okResult("no configuration file is locked")
+	}
+	return results
+}
+
+// tryLock acquires and immediately releases the specified file lock, returning
+// an error if the lock is held by another process
+func tryLock(lockPath string) error {
+	lock, err := filemutex.New(lockPath)
+	if err != nil {
+		return err
+	}
+	defer lock.Close()
+
+	if err := lock.TryLock(); err != nil {
+		return err
+	}
+	return lock.Unlock()
+}
+
+// checkConfig verifies that the configuration files can be parsed
+func checkConfig(_ bool) []Result {
+	if _, err := configlib.GetClientConfigNoLock(); err != nil {
+		return []Result{{Status: StatusError, Message: fmt.Sprintf("unable to read the configuration files: %v", err)}}
+	}
+	return okResult("the configuration files are valid")
+}
+
+// checkDiscoverySources verifies that the plugin discovery images can be reached
+func checkDiscoverySources(_ bool) []Result {
+	sources, err := configlib.GetCLIDiscoverySources()
+	if err != nil || len(sources) == 0 {
+		return []Result{{Status: StatusWarning, Message: "no plugin discovery source is configured; run 'tanzu plugin source init' to configure the default one"}}
+	}
+
+	var results []Result
+	for _, source := range sources {
+		if source.OCI == nil {
+			continue
+		}
+		if _, _, err := getImageDigest(source.OCI.Image); err != nil {
+			results = append(results, Result{
+				Status:  StatusError,
+				Message: fmt.Sprintf("the discovery source %q cannot be reached at %s: %v", source.OCI.Name, source.OCI.Image, err),
+			})
+			continue
+		}
+		results = append(results, Result{
+			Status:  StatusOK,
+			Message: fmt.Sprintf("the discovery source %q can be reached at %s", source.OCI.Name, source.OCI.Image),
+		})
+	}
+	return results
+}
+
+// checkSignaturePublicKey verifies that the public key used to verify the
+// signature of the discovery images can be loaded
+func checkSignaturePublicKey(_ bool) []Result {
+	publicKeyPath := os.Getenv(constants.PublicKeyPathForPluginDiscoveryImageSignature)
+	if err := cosignhelper.ValidatePublicKey(context.Background(), publicKeyPath); err != nil {
+		return []Result{{Status: StatusError, Message: fmt.Sprintf("the public key used to verify the discovery images is invalid: %v", err)}}
+	}
+	if publicKeyPath != "" {
+		return okResult(fmt.Sprintf("the custom public key %s is valid", publicKeyPath))
+	}
+	return okResult("the embedded public keys are valid")
+}
+
+// checkEULAAndCEIP reports whether the EULA has been accepted and the CEIP
+// participation has been set. Both require a decision of the user and
+// therefore cannot be repaired.
+func checkEULAAndCEIP(_ bool) []Result {
+	var results []Result
+	eulaStatus, _ := configlib.GetEULAStatus()
+	if eulaStatus == configlib.EULAStatusAccepted {
+		results = append(results, Result{Check: "eula", Status: StatusOK, Message: "the EULA has been accepted"})
+	} else {
+		results = append(results, Result{Check: "eula", Status: StatusWarning, Message: "the EULA has not been accepted; run 'tanzu config eula accept'"})
+	}
+
+	ceipOptIn, _ := configlib.GetCEIPOptIn()
+	if ceipOptIn == "" {
+		results = append(results, Result{Check: "ceip", Status: StatusWarning, Message: "the CEIP participation has not been set; run 'tanzu ceip-participation set <true|false>'"})
+	} else {
+		results = append(results, Result{Check: "ceip", Status: StatusOK, Message: fmt.Sprintf("the CEIP participation is set to %s", ceipOptIn)})
+	}
+	return results
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package doctor provides the diagnostic checks of the CLI installation
+package doctor
+
+// Status is the outcome of a diagnostic check
+type Status string
+
+const (
+	// StatusOK means no problem was found
+	StatusOK Status = "ok"
+	// StatusWarning means a problem was found that does not prevent the CLI from working
+	StatusWarning Status = "warning"
+	// StatusError means a problem was found that prevents the CLI or a plugin from working
+	StatusError Status = "error"
+	// StatusFixed means a problem was found and repaired
+	StatusFixed Status = "fixed"
+)
+
+// Result is the outcome of a diagnostic check for one of the items it verifies
+type Result struct {
+	// Check is the name of the check which produced the result
+	Check string `json:"check" yaml:"check"`
+	// Status is the outcome of the check
+	Status Status `json:"status" yaml:"status"`
+	// Message describes the outcome, and how to address any problem found
+	Message string `json:"message" yaml:"message"`
+}
+
+// Check is a diagnostic check of the CLI installation
+type Check struct {
+	// Name identifies the check in the results
+	Name string
+	// Run performs the check. When fix is true, the problems that can safely
+	// be repaired are repaired and reported with the StatusFixed status.
+	Run func(fix bool) []Result
+}
+
+// BuiltinChecks returns the diagnostic checks provided by the CLI itself.
+// The order matters as the repairs of a check can affect the checks that follow.
+func BuiltinChecks() []Check {
+	return []Check{
+		{Name: "catalog", Run: checkCatalog},
+		{Name: "plugin-digests", Run: checkPluginDigests},
+		{Name: "command-tree-cache", Run: checkCommandTreeCache},
+		{Name: "config-locks", Run: checkConfigLocks},
+		{Name: "config", Run: checkConfig},
+		{Name: "discovery-sources", Run: checkDiscoverySources},
+		{Name: "signature-public-key", Run: checkSignaturePublicKey},
+		{Name: "eula-ceip", Run: checkEULAAndCEIP},
+	}
+}
+
+// Run performs the specified checks in order and returns all their results
+func Run(checks []Check, fix bool) []Result {
+	var results []Result
+	for _, check := range checks {
+		for _, r := range check.Run(fix) {
+			if r.Check == "" {
+				r.Check = check.Name
+			}
+			results = append(results, r)
+		}
+	}
+	return results
+}
+
+// HasErrors returns true if any of the results reports an error
+func HasErrors(results []Result) bool {
+	for _, r := range results {
+		if r.Status == StatusError {
+			return true
+		}
+	}
+	return false
+}
+
+func okResult(message string) []Result {
+	return []Result{{Status: StatusOK, Message: message}}
+}
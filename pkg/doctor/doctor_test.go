@@ -0,0 +1,170 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package doctor
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/catalog"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/constants"
+)
+
+// fakeDoctorPlugin outputs the results of its checks, reporting whether
+// the repairs were requested
+const fakeDoctorPlugin = `#!/bin/bash
+if [ "$4" = "--fix" ]; then
+  echo '[{"check": "kubeconfig", "status": "fixed", "message": "repaired"}]'
+else
+  echo '[{"check": "kubeconfig", "status": "error", "message": "broken"}, {"check": "other", "status": "bad", "message": "unknown"}]'
+fi
+`
+
+const failingDoctorPlugin = `#!/bin/bash
+echo "cannot run checks" >&2
+exit 1
+`
+
+func setupTestCatalog(t *testing.T) string {
+	dir := t.TempDir()
+	t.Setenv("TEST_CUSTOM_CATALOG_CACHE_DIR", filepath.Join(dir, "cache"))
+	t.Setenv("TEST_CUSTOM_PLUGIN_COMMAND_TREE_CACHE_DIR", filepath.Join(dir, "cmdtree"))
+	return dir
+}
+
+func installFakePlugin(t *testing.T, plugin *cli.PluginInfo) {
+	cc, err := catalog.NewContextCatalogUpdater("")
+	assert.NoError(t, err)
+	defer cc.Unlock()
+	assert.NoError(t, cc.Upsert(plugin))
+}
+
+func writePluginBinary(t *testing.T, dir, version string, content []byte) string {
+	path := filepath.Join(dir, fmt.Sprintf("%s_%x_global", version, sha256.Sum256(content)))
+	assert.NoError(t, os.WriteFile(path, content, 0755))
+	return path
+}
+
+func TestCheckCatalog(t *testing.T) {
+	dir := setupTestCatalog(t)
+
+	present := &cli.PluginInfo{Name: "present", Target: configtypes.TargetGlobal, InstallationPath: writePluginBinary(t, dir, "v1.0.0", []byte("present"))}
+	missing := &cli.PluginInfo{Name: "missing", Target: configtypes.TargetGlobal, InstallationPath: filepath.Join(dir, "missing")}
+	installFakePlugin(t, present)
+	installFakePlugin(t, missing)
+
+	results := checkCatalog(false)
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, StatusError, results[0].Status)
+	assert.Contains(t, results[0].Message, `the binary of plugin "missing" (global) is missing`)
+
+	results = checkCatalog(true)
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, StatusFixed, results[0].Status)
+
+	results = checkCatalog(false)
+	assert.Equal(t, []Result{{Status: StatusOK, Message: "the binaries of all 1 plugin installations are present"}}, results)
+}
+
+func TestCheckPluginDigests(t *testing.T) {
+	dir := setupTestCatalog(t)
+
+	valid := &cli.PluginInfo{Name: "valid", InstallationPath: writePluginBinary(t, dir, "v1.0.0", []byte("valid"))}
+	modified := &cli.PluginInfo{Name: "modified", InstallationPath: writePluginBinary(t, dir, "v2.0.0", []byte("modified"))}
+	installFakePlugin(t, valid)
+	installFakePlugin(t, modified)
+
+	results := checkPluginDigests(false)
+	assert.Equal(t, StatusOK, results[0].Status)
+	assert.Equal(t, "the digests of 2 plugin binaries match", results[0].Message)
+
+	assert.NoError(t, os.WriteFile(modified.InstallationPath, []byte("tampered"), 0755))
+	results = checkPluginDigests(false)
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, StatusError, results[0].Status)
+	assert.Contains(t, results[0].Message, `the binary of plugin "modified" () has been modified since its installation`)
+}
+
+func TestExpectedPluginDigest(t *testing.T) {
+	digest := fmt.Sprintf("%x", sha256.Sum256([]byte("plugin")))
+
+	assert.Equal(t, digest, expectedPluginDigest(&cli.PluginInfo{InstallationPath: "/plugins/foo/v1.0.0_" + digest + "_kubernetes"}))
+	assert.Equal(t, digest, expectedPluginDigest(&cli.PluginInfo{InstallationPath: "/plugins/foo/v1.0.0_" + digest + "_.exe"}))
+	assert.Equal(t, "abc", expectedPluginDigest(&cli.PluginInfo{Digest: "abc", InstallationPath: "/plugins/foo/v1.0.0_" + digest + "_global"}))
+	// Plugins installed from a local source do not record their digest
+	assert.Empty(t, expectedPluginDigest(&cli.PluginInfo{InstallationPath: "/plugins/foo/v1.0.0_global"}))
+}
+
+func TestCheckSignaturePublicKey(t *testing.T) {
+	results := checkSignaturePublicKey(false)
+	assert.Equal(t, []Result{{Status: StatusOK, Message: "the embedded public keys are valid"}}, results)
+
+	keyPath := filepath.Join(t.TempDir(), "key.pub")
+	assert.NoError(t, os.WriteFile(keyPath, []byte("not a key"), 0644))
+	t.Setenv(constants.PublicKeyPathForPluginDiscoveryImageSignature, keyPath)
+	results = checkSignaturePublicKey(false)
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, StatusError, results[0].Status)
+	assert.Contains(t, results[0].Message, "the public key used to verify the discovery images is invalid")
+}
+
+func TestGetPluginChecksTimeout(t *testing.T) {
+	assert.Equal(t, defaultPluginChecksTimeout, getPluginChecksTimeout())
+
+	t.Setenv(constants.PluginDiagnosticChecksTimeoutSeconds, "5")
+	assert.Equal(t, 5*time.Second, getPluginChecksTimeout())
+
+	t.Setenv(constants.PluginDiagnosticChecksTimeoutSeconds, "invalid")
+	assert.Equal(t, defaultPluginChecksTimeout, getPluginChecksTimeout())
+}
+
+func TestPluginChecks(t *testing.T) {
+	dir := t.TempDir()
+	pluginPath := filepath.Join(dir, "fake")
+	assert.NoError(t, os.WriteFile(pluginPath, []byte(fakeDoctorPlugin), 0755))
+	failingPath := filepath.Join(dir, "failing")
+	assert.NoError(t, os.WriteFile(failingPath, []byte(failingDoctorPlugin), 0755))
+
+	plugins := []cli.PluginInfo{
+		{Name: "fake", InstallationPath: pluginPath, DiagnosticChecks: true},
+		{Name: "failing", InstallationPath: failingPath, DiagnosticChecks: true},
+		{Name: "nochecks", InstallationPath: pluginPath},
+	}
+	checks := PluginChecks(plugins)
+	assert.Equal(t, 2, len(checks))
+	assert.Equal(t, "plugin/fake", checks[0].Name)
+	assert.Equal(t, "plugin/failing", checks[1].Name)
+
+	results := Run(checks[:1], false)
+	assert.Equal(t, []Result{
+		{Check: "plugin/fake/kubeconfig", Status: StatusError, Message: "broken"},
+		{Check: "plugin/fake/other", Status: StatusWarning, Message: `unknown status "bad": unknown`},
+	}, results)
+	assert.True(t, HasErrors(results))
+
+	results = Run(checks[:1], true)
+	assert.Equal(t, []Result{{Check: "plugin/fake/kubeconfig", Status: StatusFixed, Message: "repaired"}}, results)
+	assert.False(t, HasErrors(results))
+
+	results = Run(checks[1:], false)
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, "plugin/failing", results[0].Check)
+	assert.Equal(t, StatusError, results[0].Status)
+	assert.Contains(t, results[0].Message, "cannot run checks")
+
+	// Plugins that do not complete in time are reported
+	hangingPath := filepath.Join(dir, "hanging")
+	assert.NoError(t, os.WriteFile(hangingPath, []byte("#!/bin/bash\nexec sleep 5\n"), 0755))
+	results = runPluginChecks(&cli.PluginInfo{Name: "hanging", InstallationPath: hangingPath}, false, 100*time.Millisecond)
+	assert.Equal(t, []Result{{Status: StatusError, Message: `the checks of plugin "hanging" did not complete within 100ms`}}, results)
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/constants"
+)
+
+const (
+	// pluginDoctorCommand is the plugin command invoked to run the diagnostic checks
+	// of the plugins that provide some. It is invoked as:
+	//   <plugin> doctor --output json [--fix]
+	pluginDoctorCommand = "doctor"
+
+	// defaultPluginChecksTimeout is the time allowed for each plugin to run its checks
+	defaultPluginChecksTimeout = 30 * time.Second
+)
+
+// getPluginChecksTimeout returns the time allowed for each plugin to run its
+// checks, which can be overridden through an environment variable
+func getPluginChecksTimeout() time.Duration {
+	timeout := defaultPluginChecksTimeout
+	if timeoutOverride := os.Getenv(constants.PluginDiagnosticChecksTimeoutSeconds); timeoutOverride != "" {
+		if seconds, err := strconv.Atoi(timeoutOverride); err == nil && seconds > 0 {
+			timeout = time.Duration(seconds) * time.Second
+		}
+	}
+	return timeout
+}
+
+// PluginChecks returns a diagnostic check for each of the specified plugins
+// that provides its own checks
+func PluginChecks(plugins []cli.PluginInfo) []Check {
+	var checks []Check
+	timeout := getPluginChecksTimeout()
+	for i := range plugins {
+		if !plugins[i].DiagnosticChecks {
+			continue
+		}
+		p := plugins[i]
+		checks = append(checks, Check{
+			Name: pluginCheckName(&p, ""),
+			Run: func(fix bool) []Result {
+				return runPluginChecks(&p, fix, timeout)
+			},
+		})
+	}
+	return checks
+}
+
+func pluginCheckName(p *cli.PluginInfo, check string) string {
+	name := fmt.Sprintf("plugin/%s", p.Name)
+	if check != "" {
+		name += "/" + check
+	}
+	return name
+}
+
+func runPluginChecks(p *cli.PluginInfo, fix bool, timeout time.Duration) []Result {
+	args := []string{pluginDoctorCommand, "--output", "json"}
+	if fix {
+		args = append(args, "--fix")
+	}
+
+	checkCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	log.V(6).Infof("running the diagnostic checks of plugin %q", p.Name)
+	runner := cli.NewRunner(p.Name, p.InstallationPath, args)
+	stdout, stderr, err := runner.RunOutput(checkCtx)
+	if checkCtx.Err() == context.DeadlineExceeded {
+		return []Result{{Status: StatusError, Message: fmt.Sprintf("the checks of plugin %q did not complete within %v", p.Name, timeout)}}
+	}
+	if err != nil {
+		message := fmt.Sprintf("the checks of plugin %q failed: %v", p.Name, err)
+		if stderr = strings.TrimSpace(stderr); stderr != "" {
+			message += ": " + stderr
+		}
+		return []Result{{Status: StatusError, Message: message}}
+	}
+
+	var results []Result
+	if err := json.Unmarshal([]byte(stdout), &results); err != nil {
+		return []Result{{Status: StatusError, Message: fmt.Sprintf("unable to parse the results of the checks of plugin %q: %v", p.Name, err)}}
+	}
+	for i := range results {
+		results[i].Check = pluginCheckName(p, results[i].Check)
+		switch results[i].Status {
+		case StatusOK, StatusWarning, StatusError, StatusFixed:
+		default:
+			results[i].Message = fmt.Sprintf("unknown status %q: %s", results[i].Status, results[i].Message)
+			results[i].Status = StatusWarning
+		}
+	}
+	return results
+}
@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"github.com/vmware-tanzu/tanzu-cli/apis/cli/v1alpha1"
+	"github.com/vmware-tanzu/tanzu-cli/apis/cli/v1alpha2"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/capabilities/discovery"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/cluster"
 )
@@ -22,6 +23,18 @@ type ClusterClient struct {
 		result1 *discovery.ClusterQuery
 		result2 error
 	}
+	BuildClusterQueryClientStub        func() (*discovery.ClusterQueryClient, error)
+	buildClusterQueryClientMutex       sync.RWMutex
+	buildClusterQueryClientArgsForCall []struct {
+	}
+	buildClusterQueryClientReturns struct {
+		result1 *discovery.ClusterQueryClient
+		result2 error
+	}
+	buildClusterQueryClientReturnsOnCall map[int]struct {
+		result1 *discovery.ClusterQueryClient
+		result2 error
+	}
 	GetCLIPluginImageRepositoryOverrideStub        func() (map[string]string, error)
 	getCLIPluginImageRepositoryOverrideMutex       sync.RWMutex
 	getCLIPluginImageRepositoryOverrideArgsForCall []struct {
@@ -46,6 +59,18 @@ type ClusterClient struct {
 		result1 []v1alpha1.CLIPlugin
 		result2 error
 	}
+	ListCLIPluginV1alpha2ResourcesStub        func() ([]v1alpha2.CLIPlugin, error)
+	listCLIPluginV1alpha2ResourcesMutex       sync.RWMutex
+	listCLIPluginV1alpha2ResourcesArgsForCall []struct {
+	}
+	listCLIPluginV1alpha2ResourcesReturns struct {
+		result1 []v1alpha2.CLIPlugin
+		result2 error
+	}
+	listCLIPluginV1alpha2ResourcesReturnsOnCall map[int]struct {
+		result1 []v1alpha2.CLIPlugin
+		result2 error
+	}
 	VerifyCLIPluginCRDStub        func() (bool, error)
 	verifyCLIPluginCRDMutex       sync.RWMutex
 	verifyCLIPluginCRDArgsForCall []struct {
@@ -58,6 +83,18 @@ type ClusterClient struct {
 		result1 bool
 		result2 error
 	}
+	VerifyCLIPluginV1alpha2APIStub        func() (bool, error)
+	verifyCLIPluginV1alpha2APIMutex       sync.RWMutex
+	verifyCLIPluginV1alpha2APIArgsForCall []struct {
+	}
+	verifyCLIPluginV1alpha2APIReturns struct {
+		result1 bool
+		result2 error
+	}
+	verifyCLIPluginV1alpha2APIReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -118,6 +155,62 @@ func (fake *ClusterClient) BuildClusterQueryReturnsOnCall(i int, result1 *discov
 	}{result1, result2}
 }
 
+func (fake *ClusterClient) BuildClusterQueryClient() (*discovery.ClusterQueryClient, error) {
+	fake.buildClusterQueryClientMutex.Lock()
+	ret, specificReturn := fake.buildClusterQueryClientReturnsOnCall[len(fake.buildClusterQueryClientArgsForCall)]
+	fake.buildClusterQueryClientArgsForCall = append(fake.buildClusterQueryClientArgsForCall, struct {
+	}{})
+	stub := fake.BuildClusterQueryClientStub
+	fakeReturns := fake.buildClusterQueryClientReturns
+	fake.recordInvocation("BuildClusterQueryClient", []interface{}{})
+	fake.buildClusterQueryClientMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ClusterClient) BuildClusterQueryClientCallCount() int {
+	fake.buildClusterQueryClientMutex.RLock()
+	defer fake.buildClusterQueryClientMutex.RUnlock()
+	return len(fake.buildClusterQueryClientArgsForCall)
+}
+
+func (fake *ClusterClient) BuildClusterQueryClientCalls(stub func() (*discovery.ClusterQueryClient, error)) {
+	fake.buildClusterQueryClientMutex.Lock()
+	defer fake.buildClusterQueryClientMutex.Unlock()
+	fake.BuildClusterQueryClientStub = stub
+}
+
+func (fake *ClusterClient) BuildClusterQueryClientReturns(result1 *discovery.ClusterQueryClient, result2 error) {
+	fake.buildClusterQueryClientMutex.Lock()
+	defer fake.buildClusterQueryClientMutex.Unlock()
+	fake.BuildClusterQueryClientStub = nil
+	fake.buildClusterQueryClientReturns = struct {
+		result1 *discovery.ClusterQueryClient
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ClusterClient) BuildClusterQueryClientReturnsOnCall(i int, result1 *discovery.ClusterQueryClient, result2 error) {
+	fake.buildClusterQueryClientMutex.Lock()
+	defer fake.buildClusterQueryClientMutex.Unlock()
+	fake.BuildClusterQueryClientStub = nil
+	if fake.buildClusterQueryClientReturnsOnCall == nil {
+		fake.buildClusterQueryClientReturnsOnCall = make(map[int]struct {
+			result1 *discovery.ClusterQueryClient
+			result2 error
+		})
+	}
+	fake.buildClusterQueryClientReturnsOnCall[i] = struct {
+		result1 *discovery.ClusterQueryClient
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ClusterClient) GetCLIPluginImageRepositoryOverride() (map[string]string, error) {
 	fake.getCLIPluginImageRepositoryOverrideMutex.Lock()
 	ret, specificReturn := fake.getCLIPluginImageRepositoryOverrideReturnsOnCall[len(fake.getCLIPluginImageRepositoryOverrideArgsForCall)]
@@ -230,6 +323,62 @@ func (fake *ClusterClient) ListCLIPluginResourcesReturnsOnCall(i int, result1 []
 	}{result1, result2}
 }
 
+func (fake *ClusterClient) ListCLIPluginV1alpha2Resources() ([]v1alpha2.CLIPlugin, error) {
+	fake.listCLIPluginV1alpha2ResourcesMutex.Lock()
+	ret, specificReturn := fake.listCLIPluginV1alpha2ResourcesReturnsOnCall[len(fake.listCLIPluginV1alpha2ResourcesArgsForCall)]
+	fake.listCLIPluginV1alpha2ResourcesArgsForCall = append(fake.listCLIPluginV1alpha2ResourcesArgsForCall, struct {
+	}{})
+	stub := fake.ListCLIPluginV1alpha2ResourcesStub
+	fakeReturns := fake.listCLIPluginV1alpha2ResourcesReturns
+	fake.recordInvocation("ListCLIPluginV1alpha2Resources", []interface{}{})
+	fake.listCLIPluginV1alpha2ResourcesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ClusterClient) ListCLIPluginV1alpha2ResourcesCallCount() int {
+	fake.listCLIPluginV1alpha2ResourcesMutex.RLock()
+	defer fake.listCLIPluginV1alpha2ResourcesMutex.RUnlock()
+	return len(fake.listCLIPluginV1alpha2ResourcesArgsForCall)
+}
+
+func (fake *ClusterClient) ListCLIPluginV1alpha2ResourcesCalls(stub func() ([]v1alpha2.CLIPlugin, error)) {
+	fake.listCLIPluginV1alpha2ResourcesMutex.Lock()
+	defer fake.listCLIPluginV1alpha2ResourcesMutex.Unlock()
+	fake.ListCLIPluginV1alpha2ResourcesStub = stub
+}
+
+func (fake *ClusterClient) ListCLIPluginV1alpha2ResourcesReturns(result1 []v1alpha2.CLIPlugin, result2 error) {
+	fake.listCLIPluginV1alpha2ResourcesMutex.Lock()
+	defer fake.listCLIPluginV1alpha2ResourcesMutex.Unlock()
+	fake.ListCLIPluginV1alpha2ResourcesStub = nil
+	fake.listCLIPluginV1alpha2ResourcesReturns = struct {
+		result1 []v1alpha2.CLIPlugin
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ClusterClient) ListCLIPluginV1alpha2ResourcesReturnsOnCall(i int, result1 []v1alpha2.CLIPlugin, result2 error) {
+	fake.listCLIPluginV1alpha2ResourcesMutex.Lock()
+	defer fake.listCLIPluginV1alpha2ResourcesMutex.Unlock()
+	fake.ListCLIPluginV1alpha2ResourcesStub = nil
+	if fake.listCLIPluginV1alpha2ResourcesReturnsOnCall == nil {
+		fake.listCLIPluginV1alpha2ResourcesReturnsOnCall = make(map[int]struct {
+			result1 []v1alpha2.CLIPlugin
+			result2 error
+		})
+	}
+	fake.listCLIPluginV1alpha2ResourcesReturnsOnCall[i] = struct {
+		result1 []v1alpha2.CLIPlugin
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ClusterClient) VerifyCLIPluginCRD() (bool, error) {
 	fake.verifyCLIPluginCRDMutex.Lock()
 	ret, specificReturn := fake.verifyCLIPluginCRDReturnsOnCall[len(fake.verifyCLIPluginCRDArgsForCall)]
@@ -286,17 +435,79 @@ func (fake *ClusterClient) VerifyCLIPluginCRDReturnsOnCall(i int, result1 bool,
 	}{result1, result2}
 }
 
+func (fake *ClusterClient) VerifyCLIPluginV1alpha2API() (bool, error) {
+	fake.verifyCLIPluginV1alpha2APIMutex.Lock()
+	ret, specificReturn := fake.verifyCLIPluginV1alpha2APIReturnsOnCall[len(fake.verifyCLIPluginV1alpha2APIArgsForCall)]
+	fake.verifyCLIPluginV1alpha2APIArgsForCall = append(fake.verifyCLIPluginV1alpha2APIArgsForCall, struct {
+	}{})
+	stub := fake.VerifyCLIPluginV1alpha2APIStub
+	fakeReturns := fake.verifyCLIPluginV1alpha2APIReturns
+	fake.recordInvocation("VerifyCLIPluginV1alpha2API", []interface{}{})
+	fake.verifyCLIPluginV1alpha2APIMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ClusterClient) VerifyCLIPluginV1alpha2APICallCount() int {
+	fake.verifyCLIPluginV1alpha2APIMutex.RLock()
+	defer fake.verifyCLIPluginV1alpha2APIMutex.RUnlock()
+	return len(fake.verifyCLIPluginV1alpha2APIArgsForCall)
+}
+
+func (fake *ClusterClient) VerifyCLIPluginV1alpha2APICalls(stub func() (bool, error)) {
+	fake.verifyCLIPluginV1alpha2APIMutex.Lock()
+	defer fake.verifyCLIPluginV1alpha2APIMutex.Unlock()
+	fake.VerifyCLIPluginV1alpha2APIStub = stub
+}
+
+func (fake *ClusterClient) VerifyCLIPluginV1alpha2APIReturns(result1 bool, result2 error) {
+	fake.verifyCLIPluginV1alpha2APIMutex.Lock()
+	defer fake.verifyCLIPluginV1alpha2APIMutex.Unlock()
+	fake.VerifyCLIPluginV1alpha2APIStub = nil
+	fake.verifyCLIPluginV1alpha2APIReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ClusterClient) VerifyCLIPluginV1alpha2APIReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.verifyCLIPluginV1alpha2APIMutex.Lock()
+	defer fake.verifyCLIPluginV1alpha2APIMutex.Unlock()
+	fake.VerifyCLIPluginV1alpha2APIStub = nil
+	if fake.verifyCLIPluginV1alpha2APIReturnsOnCall == nil {
+		fake.verifyCLIPluginV1alpha2APIReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.verifyCLIPluginV1alpha2APIReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ClusterClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.buildClusterQueryMutex.RLock()
 	defer fake.buildClusterQueryMutex.RUnlock()
+	fake.buildClusterQueryClientMutex.RLock()
+	defer fake.buildClusterQueryClientMutex.RUnlock()
 	fake.getCLIPluginImageRepositoryOverrideMutex.RLock()
 	defer fake.getCLIPluginImageRepositoryOverrideMutex.RUnlock()
 	fake.listCLIPluginResourcesMutex.RLock()
 	defer fake.listCLIPluginResourcesMutex.RUnlock()
+	fake.listCLIPluginV1alpha2ResourcesMutex.RLock()
+	defer fake.listCLIPluginV1alpha2ResourcesMutex.RUnlock()
 	fake.verifyCLIPluginCRDMutex.RLock()
 	defer fake.verifyCLIPluginCRDMutex.RUnlock()
+	fake.verifyCLIPluginV1alpha2APIMutex.RLock()
+	defer fake.verifyCLIPluginV1alpha2APIMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -8,6 +8,18 @@ import (
 )
 
 type ImageOperationsImpl struct {
+	CopyImageStub        func(string, string) error
+	copyImageMutex       sync.RWMutex
+	copyImageArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	copyImageReturns struct {
+		result1 error
+	}
+	copyImageReturnsOnCall map[int]struct {
+		result1 error
+	}
 	CopyImageFromTarStub        func(string, string) error
 	copyImageFromTarMutex       sync.RWMutex
 	copyImageFromTarArgsForCall []struct {
@@ -86,6 +98,20 @@ type ImageOperationsImpl struct {
 		result2 string
 		result3 error
 	}
+	GetReferrersStub        func(string, string) ([][]byte, error)
+	getReferrersMutex       sync.RWMutex
+	getReferrersArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	getReferrersReturns struct {
+		result1 [][]byte
+		result2 error
+	}
+	getReferrersReturnsOnCall map[int]struct {
+		result1 [][]byte
+		result2 error
+	}
 	PushImageStub        func(string, []string) error
 	pushImageMutex       sync.RWMutex
 	pushImageArgsForCall []struct {
@@ -98,6 +124,19 @@ type ImageOperationsImpl struct {
 	pushImageReturnsOnCall map[int]struct {
 		result1 error
 	}
+	PushReferrerStub        func(string, string, []byte) error
+	pushReferrerMutex       sync.RWMutex
+	pushReferrerArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 []byte
+	}
+	pushReferrerReturns struct {
+		result1 error
+	}
+	pushReferrerReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ResolveImageStub        func(string) error
 	resolveImageMutex       sync.RWMutex
 	resolveImageArgsForCall []struct {
@@ -113,6 +152,68 @@ type ImageOperationsImpl struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *ImageOperationsImpl) CopyImage(arg1 string, arg2 string) error {
+	fake.copyImageMutex.Lock()
+	ret, specificReturn := fake.copyImageReturnsOnCall[len(fake.copyImageArgsForCall)]
+	fake.copyImageArgsForCall = append(fake.copyImageArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.CopyImageStub
+	fakeReturns := fake.copyImageReturns
+	fake.recordInvocation("CopyImage", []interface{}{arg1, arg2})
+	fake.copyImageMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ImageOperationsImpl) CopyImageCallCount() int {
+	fake.copyImageMutex.RLock()
+	defer fake.copyImageMutex.RUnlock()
+	return len(fake.copyImageArgsForCall)
+}
+
+func (fake *ImageOperationsImpl) CopyImageCalls(stub func(string, string) error) {
+	fake.copyImageMutex.Lock()
+	defer fake.copyImageMutex.Unlock()
+	fake.CopyImageStub = stub
+}
+
+func (fake *ImageOperationsImpl) CopyImageArgsForCall(i int) (string, string) {
+	fake.copyImageMutex.RLock()
+	defer fake.copyImageMutex.RUnlock()
+	argsForCall := fake.copyImageArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *ImageOperationsImpl) CopyImageReturns(result1 error) {
+	fake.copyImageMutex.Lock()
+	defer fake.copyImageMutex.Unlock()
+	fake.CopyImageStub = nil
+	fake.copyImageReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ImageOperationsImpl) CopyImageReturnsOnCall(i int, result1 error) {
+	fake.copyImageMutex.Lock()
+	defer fake.copyImageMutex.Unlock()
+	fake.CopyImageStub = nil
+	if fake.copyImageReturnsOnCall == nil {
+		fake.copyImageReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.copyImageReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *ImageOperationsImpl) CopyImageFromTar(arg1 string, arg2 string) error {
 	fake.copyImageFromTarMutex.Lock()
 	ret, specificReturn := fake.copyImageFromTarReturnsOnCall[len(fake.copyImageFromTarArgsForCall)]
@@ -495,6 +596,71 @@ func (fake *ImageOperationsImpl) GetImageDigestReturnsOnCall(i int, result1 stri
 	}{result1, result2, result3}
 }
 
+func (fake *ImageOperationsImpl) GetReferrers(arg1 string, arg2 string) ([][]byte, error) {
+	fake.getReferrersMutex.Lock()
+	ret, specificReturn := fake.getReferrersReturnsOnCall[len(fake.getReferrersArgsForCall)]
+	fake.getReferrersArgsForCall = append(fake.getReferrersArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetReferrersStub
+	fakeReturns := fake.getReferrersReturns
+	fake.recordInvocation("GetReferrers", []interface{}{arg1, arg2})
+	fake.getReferrersMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ImageOperationsImpl) GetReferrersCallCount() int {
+	fake.getReferrersMutex.RLock()
+	defer fake.getReferrersMutex.RUnlock()
+	return len(fake.getReferrersArgsForCall)
+}
+
+func (fake *ImageOperationsImpl) GetReferrersCalls(stub func(string, string) ([][]byte, error)) {
+	fake.getReferrersMutex.Lock()
+	defer fake.getReferrersMutex.Unlock()
+	fake.GetReferrersStub = stub
+}
+
+func (fake *ImageOperationsImpl) GetReferrersArgsForCall(i int) (string, string) {
+	fake.getReferrersMutex.RLock()
+	defer fake.getReferrersMutex.RUnlock()
+	argsForCall := fake.getReferrersArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *ImageOperationsImpl) GetReferrersReturns(result1 [][]byte, result2 error) {
+	fake.getReferrersMutex.Lock()
+	defer fake.getReferrersMutex.Unlock()
+	fake.GetReferrersStub = nil
+	fake.getReferrersReturns = struct {
+		result1 [][]byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ImageOperationsImpl) GetReferrersReturnsOnCall(i int, result1 [][]byte, result2 error) {
+	fake.getReferrersMutex.Lock()
+	defer fake.getReferrersMutex.Unlock()
+	fake.GetReferrersStub = nil
+	if fake.getReferrersReturnsOnCall == nil {
+		fake.getReferrersReturnsOnCall = make(map[int]struct {
+			result1 [][]byte
+			result2 error
+		})
+	}
+	fake.getReferrersReturnsOnCall[i] = struct {
+		result1 [][]byte
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ImageOperationsImpl) PushImage(arg1 string, arg2 []string) error {
 	var arg2Copy []string
 	if arg2 != nil {
@@ -562,6 +728,74 @@ func (fake *ImageOperationsImpl) PushImageReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *ImageOperationsImpl) PushReferrer(arg1 string, arg2 string, arg3 []byte) error {
+	var arg3Copy []byte
+	if arg3 != nil {
+		arg3Copy = make([]byte, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.pushReferrerMutex.Lock()
+	ret, specificReturn := fake.pushReferrerReturnsOnCall[len(fake.pushReferrerArgsForCall)]
+	fake.pushReferrerArgsForCall = append(fake.pushReferrerArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 []byte
+	}{arg1, arg2, arg3Copy})
+	stub := fake.PushReferrerStub
+	fakeReturns := fake.pushReferrerReturns
+	fake.recordInvocation("PushReferrer", []interface{}{arg1, arg2, arg3Copy})
+	fake.pushReferrerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ImageOperationsImpl) PushReferrerCallCount() int {
+	fake.pushReferrerMutex.RLock()
+	defer fake.pushReferrerMutex.RUnlock()
+	return len(fake.pushReferrerArgsForCall)
+}
+
+func (fake *ImageOperationsImpl) PushReferrerCalls(stub func(string, string, []byte) error) {
+	fake.pushReferrerMutex.Lock()
+	defer fake.pushReferrerMutex.Unlock()
+	fake.PushReferrerStub = stub
+}
+
+func (fake *ImageOperationsImpl) PushReferrerArgsForCall(i int) (string, string, []byte) {
+	fake.pushReferrerMutex.RLock()
+	defer fake.pushReferrerMutex.RUnlock()
+	argsForCall := fake.pushReferrerArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *ImageOperationsImpl) PushReferrerReturns(result1 error) {
+	fake.pushReferrerMutex.Lock()
+	defer fake.pushReferrerMutex.Unlock()
+	fake.PushReferrerStub = nil
+	fake.pushReferrerReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ImageOperationsImpl) PushReferrerReturnsOnCall(i int, result1 error) {
+	fake.pushReferrerMutex.Lock()
+	defer fake.pushReferrerMutex.Unlock()
+	fake.PushReferrerStub = nil
+	if fake.pushReferrerReturnsOnCall == nil {
+		fake.pushReferrerReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.pushReferrerReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *ImageOperationsImpl) ResolveImage(arg1 string) error {
 	fake.resolveImageMutex.Lock()
 	ret, specificReturn := fake.resolveImageReturnsOnCall[len(fake.resolveImageArgsForCall)]
@@ -626,6 +860,8 @@ func (fake *ImageOperationsImpl) ResolveImageReturnsOnCall(i int, result1 error)
 func (fake *ImageOperationsImpl) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.copyImageMutex.RLock()
+	defer fake.copyImageMutex.RUnlock()
 	fake.copyImageFromTarMutex.RLock()
 	defer fake.copyImageFromTarMutex.RUnlock()
 	fake.copyImageToTarMutex.RLock()
@@ -638,8 +874,12 @@ func (fake *ImageOperationsImpl) Invocations() map[string][][]interface{} {
 	defer fake.getFilesMapFromImageMutex.RUnlock()
 	fake.getImageDigestMutex.RLock()
 	defer fake.getImageDigestMutex.RUnlock()
+	fake.getReferrersMutex.RLock()
+	defer fake.getReferrersMutex.RUnlock()
 	fake.pushImageMutex.RLock()
 	defer fake.pushImageMutex.RUnlock()
+	fake.pushReferrerMutex.RLock()
+	defer fake.pushReferrerMutex.RUnlock()
 	fake.resolveImageMutex.RLock()
 	defer fake.resolveImageMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
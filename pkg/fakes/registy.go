@@ -8,6 +8,18 @@ import (
 )
 
 type Registry struct {
+	CopyImageStub        func(string, string) error
+	copyImageMutex       sync.RWMutex
+	copyImageArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	copyImageReturns struct {
+		result1 error
+	}
+	copyImageReturnsOnCall map[int]struct {
+		result1 error
+	}
 	CopyImageFromTarStub        func(string, string) error
 	copyImageFromTarMutex       sync.RWMutex
 	copyImageFromTarArgsForCall []struct {
@@ -98,6 +110,20 @@ type Registry struct {
 		result2 string
 		result3 error
 	}
+	GetReferrersStub        func(string, string) ([][]byte, error)
+	getReferrersMutex       sync.RWMutex
+	getReferrersArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	getReferrersReturns struct {
+		result1 [][]byte
+		result2 error
+	}
+	getReferrersReturnsOnCall map[int]struct {
+		result1 [][]byte
+		result2 error
+	}
 	ListImageTagsStub        func(string) ([]string, error)
 	listImageTagsMutex       sync.RWMutex
 	listImageTagsArgsForCall []struct {
@@ -123,6 +149,19 @@ type Registry struct {
 	pushImageReturnsOnCall map[int]struct {
 		result1 error
 	}
+	PushReferrerStub        func(string, string, []byte) error
+	pushReferrerMutex       sync.RWMutex
+	pushReferrerArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 []byte
+	}
+	pushReferrerReturns struct {
+		result1 error
+	}
+	pushReferrerReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ResolveImageStub        func(string) error
 	resolveImageMutex       sync.RWMutex
 	resolveImageArgsForCall []struct {
@@ -138,6 +177,68 @@ type Registry struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *Registry) CopyImage(arg1 string, arg2 string) error {
+	fake.copyImageMutex.Lock()
+	ret, specificReturn := fake.copyImageReturnsOnCall[len(fake.copyImageArgsForCall)]
+	fake.copyImageArgsForCall = append(fake.copyImageArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.CopyImageStub
+	fakeReturns := fake.copyImageReturns
+	fake.recordInvocation("CopyImage", []interface{}{arg1, arg2})
+	fake.copyImageMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Registry) CopyImageCallCount() int {
+	fake.copyImageMutex.RLock()
+	defer fake.copyImageMutex.RUnlock()
+	return len(fake.copyImageArgsForCall)
+}
+
+func (fake *Registry) CopyImageCalls(stub func(string, string) error) {
+	fake.copyImageMutex.Lock()
+	defer fake.copyImageMutex.Unlock()
+	fake.CopyImageStub = stub
+}
+
+func (fake *Registry) CopyImageArgsForCall(i int) (string, string) {
+	fake.copyImageMutex.RLock()
+	defer fake.copyImageMutex.RUnlock()
+	argsForCall := fake.copyImageArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Registry) CopyImageReturns(result1 error) {
+	fake.copyImageMutex.Lock()
+	defer fake.copyImageMutex.Unlock()
+	fake.CopyImageStub = nil
+	fake.copyImageReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Registry) CopyImageReturnsOnCall(i int, result1 error) {
+	fake.copyImageMutex.Lock()
+	defer fake.copyImageMutex.Unlock()
+	fake.CopyImageStub = nil
+	if fake.copyImageReturnsOnCall == nil {
+		fake.copyImageReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.copyImageReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *Registry) CopyImageFromTar(arg1 string, arg2 string) error {
 	fake.copyImageFromTarMutex.Lock()
 	ret, specificReturn := fake.copyImageFromTarReturnsOnCall[len(fake.copyImageFromTarArgsForCall)]
@@ -582,6 +683,71 @@ func (fake *Registry) GetImageDigestReturnsOnCall(i int, result1 string, result2
 	}{result1, result2, result3}
 }
 
+func (fake *Registry) GetReferrers(arg1 string, arg2 string) ([][]byte, error) {
+	fake.getReferrersMutex.Lock()
+	ret, specificReturn := fake.getReferrersReturnsOnCall[len(fake.getReferrersArgsForCall)]
+	fake.getReferrersArgsForCall = append(fake.getReferrersArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetReferrersStub
+	fakeReturns := fake.getReferrersReturns
+	fake.recordInvocation("GetReferrers", []interface{}{arg1, arg2})
+	fake.getReferrersMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Registry) GetReferrersCallCount() int {
+	fake.getReferrersMutex.RLock()
+	defer fake.getReferrersMutex.RUnlock()
+	return len(fake.getReferrersArgsForCall)
+}
+
+func (fake *Registry) GetReferrersCalls(stub func(string, string) ([][]byte, error)) {
+	fake.getReferrersMutex.Lock()
+	defer fake.getReferrersMutex.Unlock()
+	fake.GetReferrersStub = stub
+}
+
+func (fake *Registry) GetReferrersArgsForCall(i int) (string, string) {
+	fake.getReferrersMutex.RLock()
+	defer fake.getReferrersMutex.RUnlock()
+	argsForCall := fake.getReferrersArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Registry) GetReferrersReturns(result1 [][]byte, result2 error) {
+	fake.getReferrersMutex.Lock()
+	defer fake.getReferrersMutex.Unlock()
+	fake.GetReferrersStub = nil
+	fake.getReferrersReturns = struct {
+		result1 [][]byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Registry) GetReferrersReturnsOnCall(i int, result1 [][]byte, result2 error) {
+	fake.getReferrersMutex.Lock()
+	defer fake.getReferrersMutex.Unlock()
+	fake.GetReferrersStub = nil
+	if fake.getReferrersReturnsOnCall == nil {
+		fake.getReferrersReturnsOnCall = make(map[int]struct {
+			result1 [][]byte
+			result2 error
+		})
+	}
+	fake.getReferrersReturnsOnCall[i] = struct {
+		result1 [][]byte
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *Registry) ListImageTags(arg1 string) ([]string, error) {
 	fake.listImageTagsMutex.Lock()
 	ret, specificReturn := fake.listImageTagsReturnsOnCall[len(fake.listImageTagsArgsForCall)]
@@ -713,6 +879,74 @@ func (fake *Registry) PushImageReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *Registry) PushReferrer(arg1 string, arg2 string, arg3 []byte) error {
+	var arg3Copy []byte
+	if arg3 != nil {
+		arg3Copy = make([]byte, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.pushReferrerMutex.Lock()
+	ret, specificReturn := fake.pushReferrerReturnsOnCall[len(fake.pushReferrerArgsForCall)]
+	fake.pushReferrerArgsForCall = append(fake.pushReferrerArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 []byte
+	}{arg1, arg2, arg3Copy})
+	stub := fake.PushReferrerStub
+	fakeReturns := fake.pushReferrerReturns
+	fake.recordInvocation("PushReferrer", []interface{}{arg1, arg2, arg3Copy})
+	fake.pushReferrerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Registry) PushReferrerCallCount() int {
+	fake.pushReferrerMutex.RLock()
+	defer fake.pushReferrerMutex.RUnlock()
+	return len(fake.pushReferrerArgsForCall)
+}
+
+func (fake *Registry) PushReferrerCalls(stub func(string, string, []byte) error) {
+	fake.pushReferrerMutex.Lock()
+	defer fake.pushReferrerMutex.Unlock()
+	fake.PushReferrerStub = stub
+}
+
+func (fake *Registry) PushReferrerArgsForCall(i int) (string, string, []byte) {
+	fake.pushReferrerMutex.RLock()
+	defer fake.pushReferrerMutex.RUnlock()
+	argsForCall := fake.pushReferrerArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *Registry) PushReferrerReturns(result1 error) {
+	fake.pushReferrerMutex.Lock()
+	defer fake.pushReferrerMutex.Unlock()
+	fake.PushReferrerStub = nil
+	fake.pushReferrerReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Registry) PushReferrerReturnsOnCall(i int, result1 error) {
+	fake.pushReferrerMutex.Lock()
+	defer fake.pushReferrerMutex.Unlock()
+	fake.PushReferrerStub = nil
+	if fake.pushReferrerReturnsOnCall == nil {
+		fake.pushReferrerReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.pushReferrerReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *Registry) ResolveImage(arg1 string) error {
 	fake.resolveImageMutex.Lock()
 	ret, specificReturn := fake.resolveImageReturnsOnCall[len(fake.resolveImageArgsForCall)]
@@ -777,6 +1011,8 @@ func (fake *Registry) ResolveImageReturnsOnCall(i int, result1 error) {
 func (fake *Registry) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.copyImageMutex.RLock()
+	defer fake.copyImageMutex.RUnlock()
 	fake.copyImageFromTarMutex.RLock()
 	defer fake.copyImageFromTarMutex.RUnlock()
 	fake.copyImageToTarMutex.RLock()
@@ -791,10 +1027,14 @@ func (fake *Registry) Invocations() map[string][][]interface{} {
 	defer fake.getFilesMutex.RUnlock()
 	fake.getImageDigestMutex.RLock()
 	defer fake.getImageDigestMutex.RUnlock()
+	fake.getReferrersMutex.RLock()
+	defer fake.getReferrersMutex.RUnlock()
 	fake.listImageTagsMutex.RLock()
 	defer fake.listImageTagsMutex.RUnlock()
 	fake.pushImageMutex.RLock()
 	defer fake.pushImageMutex.RUnlock()
+	fake.pushReferrerMutex.RLock()
+	defer fake.pushReferrerMutex.RUnlock()
 	fake.resolveImageMutex.RLock()
 	defer fake.resolveImageMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
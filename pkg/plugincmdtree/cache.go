@@ -28,12 +28,23 @@ type Cache interface {
 type CommandNode struct {
 	Subcommands    map[string]*CommandNode `yaml:"subcommands" json:"subcommands"`
 	Aliases        map[string]struct{}     `yaml:"aliases" json:"aliases"`
+	Description    string                  `yaml:"description,omitempty" json:"description,omitempty"`
+	Flags          map[string]*FlagNode    `yaml:"flags,omitempty" json:"flags,omitempty"`
 	AliasProcessed bool                    `yaml:"-" json:"-"`
 }
 
+// FlagNode holds the details of a flag supported by a command
+// which are needed to complete the flag name without invoking the plugin
+type FlagNode struct {
+	Shorthand   string `yaml:"shorthand,omitempty" json:"shorthand,omitempty"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	TakesValue  bool   `yaml:"takesValue,omitempty" json:"takesValue,omitempty"`
+}
+
 func NewCommandNode() *CommandNode {
 	return &CommandNode{
 		Subcommands: make(map[string]*CommandNode),
 		Aliases:     make(map[string]struct{}),
+		Flags:       make(map[string]*FlagNode),
 	}
 }
@@ -0,0 +1,88 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugincmdtree
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+)
+
+// StaleCacheEntries lists the cached data of plugin installations that no longer exist
+type StaleCacheEntries struct {
+	// CommandTrees are the installation paths of the stale command trees
+	CommandTrees []string
+	// DocsDirs are the stale plugin docs directories
+	DocsDirs []string
+}
+
+// IsEmpty returns true if there are no stale entries
+func (s *StaleCacheEntries) IsEmpty() bool {
+	return len(s.CommandTrees) == 0 && len(s.DocsDirs) == 0
+}
+
+// GetStaleCacheEntries returns the cached command trees and docs that do not
+// belong to any of the specified plugin installations.
+func GetStaleCacheEntries(plugins []cli.PluginInfo) (*StaleCacheEntries, error) {
+	installed := make(map[string]struct{}, len(plugins))
+	docsDirs := make(map[string]struct{}, len(plugins))
+	for i := range plugins {
+		installed[plugins[i].InstallationPath] = struct{}{}
+		docsDirs[getPluginDocsCacheDir(&plugins[i])] = struct{}{}
+	}
+
+	stale := &StaleCacheEntries{}
+	pct, err := getPluginCommandTree()
+	if err != nil {
+		return nil, err
+	}
+	for path := range pct.CommandTree {
+		if _, exists := installed[path]; !exists {
+			stale.CommandTrees = append(stale.CommandTrees, path)
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Join(getPluginsCommandTreeCacheDir(), pluginsDocsDir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "failed to read the plugin docs cache")
+	}
+	for _, entry := range entries {
+		dir := filepath.Join(getPluginsCommandTreeCacheDir(), pluginsDocsDir, entry.Name())
+		if _, exists := docsDirs[dir]; !exists {
+			stale.DocsDirs = append(stale.DocsDirs, dir)
+		}
+	}
+
+	sort.Strings(stale.CommandTrees)
+	sort.Strings(stale.DocsDirs)
+	return stale, nil
+}
+
+// DeleteStaleCacheEntries removes the specified stale command trees and docs from the cache
+func DeleteStaleCacheEntries(stale *StaleCacheEntries) error {
+	if len(stale.CommandTrees) > 0 {
+		pct, err := getPluginCommandTree()
+		if err != nil {
+			return err
+		}
+		for _, path := range stale.CommandTrees {
+			delete(pct.CommandTree, path)
+		}
+		c := &cacheImpl{pluginCommands: pct}
+		if err := c.savePluginCommandTree(); err != nil {
+			return err
+		}
+	}
+
+	for _, dir := range stale.DocsDirs {
+		if err := os.RemoveAll(dir); err != nil {
+			return errors.Wrapf(err, "failed to delete the plugin docs directory %q", dir)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugincmdtree
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+)
+
+func TestStaleCacheEntries(t *testing.T) {
+	tmpCacheDir := t.TempDir()
+	t.Setenv("TEST_CUSTOM_PLUGIN_COMMAND_TREE_CACHE_DIR", tmpCacheDir)
+
+	installed := cli.PluginInfo{Name: "installed", InstallationPath: "/path/to/installed"}
+	removed := cli.PluginInfo{Name: "removed", InstallationPath: "/path/to/removed"}
+
+	// Nothing is stale when the cache is empty
+	stale, err := GetStaleCacheEntries([]cli.PluginInfo{installed})
+	assert.NoError(t, err)
+	assert.True(t, stale.IsEmpty())
+
+	cache := &cacheImpl{
+		pluginCommands: &pluginCommandTree{
+			CommandTree: map[string]*CommandNode{
+				installed.InstallationPath: NewCommandNode(),
+				removed.InstallationPath:   NewCommandNode(),
+			},
+		},
+	}
+	assert.NoError(t, cache.savePluginCommandTree())
+	for _, p := range []*cli.PluginInfo{&installed, &removed} {
+		assert.NoError(t, os.MkdirAll(getPluginDocsCacheDir(p), 0755))
+	}
+
+	stale, err = GetStaleCacheEntries([]cli.PluginInfo{installed})
+	assert.NoError(t, err)
+	assert.False(t, stale.IsEmpty())
+	assert.Equal(t, []string{removed.InstallationPath}, stale.CommandTrees)
+	assert.Equal(t, []string{getPluginDocsCacheDir(&removed)}, stale.DocsDirs)
+
+	assert.NoError(t, DeleteStaleCacheEntries(stale))
+
+	stale, err = GetStaleCacheEntries([]cli.PluginInfo{installed})
+	assert.NoError(t, err)
+	assert.True(t, stale.IsEmpty())
+	_, exists := GetCachedPluginTree(&installed)
+	assert.True(t, exists)
+	_, err = os.Stat(getPluginDocsCacheDir(&installed))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,169 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugincmdtree
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+)
+
+const doubleHyphen = "--"
+
+// GetCachedPluginTree returns the plugin command tree only if it is already
+// present in the cache. Unlike Cache.GetPluginTree it never invokes the plugin
+// to construct a missing tree, which makes it suitable for shell completion.
+func GetCachedPluginTree(plugin *cli.PluginInfo) (*CommandNode, bool) {
+	b, err := os.ReadFile(GetPluginsCommandTreeCachePath())
+	if err != nil {
+		return nil, false
+	}
+	var pct pluginCommandTree
+	if err := yaml.Unmarshal(b, &pct); err != nil {
+		return nil, false
+	}
+	tree, exists := pct.CommandTree[plugin.InstallationPath]
+	return tree, exists && tree != nil
+}
+
+// CompletePluginCommand answers the completion request for a plugin command using
+// the cached plugin command tree. The cmdPath is the path of the CLI command
+// representing the plugin (excluding the root command), args are the arguments
+// following that command and toComplete is the word being completed.
+// The last return value is false if the completion cannot be answered from the
+// cache, in which case the plugin should be invoked to provide the completion.
+func CompletePluginCommand(plugin *cli.PluginInfo, cmdPath, args []string, toComplete string) ([]string, cobra.ShellCompDirective, bool) {
+	tree, exists := GetCachedPluginTree(plugin)
+	if !exists {
+		return nil, cobra.ShellCompDirectiveDefault, false
+	}
+
+	current := tree
+	for _, name := range cmdPath {
+		if current = current.findSubcommand(name); current == nil {
+			return nil, cobra.ShellCompDirectiveDefault, false
+		}
+	}
+	return current.Complete(args, toComplete)
+}
+
+// Complete provides the subcommand and flag name completions for the command
+// represented by this node. The last return value is false if the completion
+// requires dynamic values (e.g. arguments or flag values) that only the plugin
+// itself can provide.
+func (n *CommandNode) Complete(args []string, toComplete string) ([]string, cobra.ShellCompDirective, bool) {
+	current := n
+	numPositionalArgs := 0
+	expectFlagValue := false
+	for _, arg := range args {
+		switch {
+		case expectFlagValue:
+			expectFlagValue = false
+		case arg == doubleHyphen:
+			// Everything after "--" is an argument
+			return nil, cobra.ShellCompDirectiveDefault, false
+		case strings.HasPrefix(arg, "-"):
+			if flag := current.findFlag(arg); flag != nil && flag.TakesValue && !strings.Contains(arg, "=") {
+				expectFlagValue = true
+			}
+		default:
+			if subCmd := current.findSubcommand(arg); subCmd != nil && numPositionalArgs == 0 {
+				current = subCmd
+				continue
+			}
+			numPositionalArgs++
+		}
+	}
+
+	// The value of a flag is being completed
+	if expectFlagValue {
+		return nil, cobra.ShellCompDirectiveDefault, false
+	}
+
+	if strings.HasPrefix(toComplete, "-") {
+		// Flag values, or flags that were not recorded in the cache,
+		// can only be completed by the plugin
+		if strings.Contains(toComplete, "=") || len(current.Flags) == 0 {
+			return nil, cobra.ShellCompDirectiveDefault, false
+		}
+		return current.flagCompletions(toComplete), cobra.ShellCompDirectiveNoFileComp, true
+	}
+
+	if numPositionalArgs == 0 && len(current.Subcommands) > 0 {
+		return current.subcommandCompletions(toComplete), cobra.ShellCompDirectiveNoFileComp, true
+	}
+
+	return nil, cobra.ShellCompDirectiveDefault, false
+}
+
+func (n *CommandNode) findSubcommand(arg string) *CommandNode {
+	if subCmd, exists := n.Subcommands[arg]; exists {
+		return subCmd
+	}
+	for _, subCmd := range n.Subcommands {
+		if _, exists := subCmd.Aliases[arg]; exists {
+			return subCmd
+		}
+	}
+	return nil
+}
+
+func (n *CommandNode) findFlag(arg string) *FlagNode {
+	name, _, _ := strings.Cut(arg, "=")
+	if strings.HasPrefix(name, "--") {
+		return n.Flags[strings.TrimPrefix(name, "--")]
+	}
+	// Only consider the last shorthand flag when they are combined (e.g. -vo)
+	name = strings.TrimPrefix(name, "-")
+	if name == "" {
+		return nil
+	}
+	shorthand := name[len(name)-1:]
+	for _, flag := range n.Flags {
+		if flag.Shorthand == shorthand {
+			return flag
+		}
+	}
+	return nil
+}
+
+func (n *CommandNode) subcommandCompletions(toComplete string) []string {
+	var comps []string
+	for name, subCmd := range n.Subcommands {
+		if strings.HasPrefix(name, toComplete) {
+			comps = append(comps, withDescription(name, subCmd.Description))
+		}
+	}
+	sort.Strings(comps)
+	return comps
+}
+
+func (n *CommandNode) flagCompletions(toComplete string) []string {
+	var comps []string
+	for name, flag := range n.Flags {
+		if longFlag := "--" + name; strings.HasPrefix(longFlag, toComplete) {
+			comps = append(comps, withDescription(longFlag, flag.Description))
+		}
+		// Like cobra, only complete shorthand flags when a single "-" was provided
+		if flag.Shorthand != "" && !strings.HasPrefix(toComplete, "--") {
+			if shortFlag := "-" + flag.Shorthand; strings.HasPrefix(shortFlag, toComplete) {
+				comps = append(comps, withDescription(shortFlag, flag.Description))
+			}
+		}
+	}
+	sort.Strings(comps)
+	return comps
+}
+
+func withDescription(comp, description string) string {
+	if description == "" {
+		return comp
+	}
+	return comp + "\t" + description
+}
@@ -0,0 +1,194 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugincmdtree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+)
+
+const sampleCommandDoc = "## tanzu cluster-plugin-global foo1\n\n" +
+	"Foo1 operations\n\n" +
+	"### Synopsis\n\n" +
+	"Foo1 operations on clusters\n\n" +
+	"```\ntanzu cluster-plugin-global foo1 [flags]\n```\n\n" +
+	"### Options\n\n" +
+	"```\n" +
+	"  -h, --help            help for foo1\n" +
+	"  -o, --output string   Output format (yaml|json|table)\n" +
+	"      --wait            Wait for the operation to complete\n" +
+	"```\n\n" +
+	"### Options inherited from parent commands\n\n" +
+	"```\n" +
+	"      --verbose int32   Number for the log level verbosity(0-9)\n" +
+	"```\n\n" +
+	"### SEE ALSO\n\n" +
+	"* [tanzu cluster-plugin-global](tanzu_cluster-plugin-global.md)\t - Cluster operations\n"
+
+func TestParseCommandDoc(t *testing.T) {
+	tmpDir := t.TempDir()
+	docPath := filepath.Join(tmpDir, "tanzu_cluster-plugin-global_foo1.md")
+	err := os.WriteFile(docPath, []byte(sampleCommandDoc), 0644)
+	assert.NoError(t, err)
+
+	description, flags := parseCommandDoc(docPath)
+	assert.Equal(t, "Foo1 operations", description)
+	assert.Equal(t, map[string]*FlagNode{
+		"help":    {Shorthand: "h", Description: "help for foo1"},
+		"output":  {Shorthand: "o", Description: "Output format (yaml|json|table)", TakesValue: true},
+		"wait":    {Description: "Wait for the operation to complete"},
+		"verbose": {Description: "Number for the log level verbosity(0-9)", TakesValue: true},
+	}, flags)
+
+	// A missing docs file should not provide anything
+	description, flags = parseCommandDoc(filepath.Join(tmpDir, "missing.md"))
+	assert.Empty(t, description)
+	assert.Empty(t, flags)
+}
+
+func sampleCompletionTree() *CommandNode {
+	return &CommandNode{
+		Subcommands: map[string]*CommandNode{
+			"cluster-plugin-global": {
+				Description: "Cluster operations",
+				Aliases:     map[string]struct{}{"pg": {}},
+				Flags: map[string]*FlagNode{
+					"help": {Shorthand: "h", Description: "help for cluster-plugin-global"},
+				},
+				Subcommands: map[string]*CommandNode{
+					"foo1": {
+						Description: "Foo1 operations",
+						Aliases:     map[string]struct{}{"f1": {}},
+						Flags: map[string]*FlagNode{
+							"help":   {Shorthand: "h", Description: "help for foo1"},
+							"output": {Shorthand: "o", Description: "Output format", TakesValue: true},
+							"wait":   {Description: "Wait for completion"},
+						},
+						Subcommands: map[string]*CommandNode{},
+					},
+					"bar1": {
+						Description: "Bar1 operations",
+						Subcommands: map[string]*CommandNode{},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCommandNodeComplete(t *testing.T) {
+	tests := []struct {
+		test              string
+		args              []string
+		toComplete        string
+		expectedComps     []string
+		expectedDirective cobra.ShellCompDirective
+		expectedOK        bool
+	}{
+		{
+			test:              "subcommands of the plugin",
+			toComplete:        "",
+			expectedComps:     []string{"bar1\tBar1 operations", "foo1\tFoo1 operations"},
+			expectedDirective: cobra.ShellCompDirectiveNoFileComp,
+			expectedOK:        true,
+		},
+		{
+			test:              "subcommands of the plugin with a prefix",
+			toComplete:        "f",
+			expectedComps:     []string{"foo1\tFoo1 operations"},
+			expectedDirective: cobra.ShellCompDirectiveNoFileComp,
+			expectedOK:        true,
+		},
+		{
+			test:              "flags of a subcommand reached through an alias",
+			args:              []string{"f1"},
+			toComplete:        "-",
+			expectedComps:     []string{"--help\thelp for foo1", "--output\tOutput format", "--wait\tWait for completion", "-h\thelp for foo1", "-o\tOutput format"},
+			expectedDirective: cobra.ShellCompDirectiveNoFileComp,
+			expectedOK:        true,
+		},
+		{
+			test:              "long flags only",
+			args:              []string{"foo1", "--wait"},
+			toComplete:        "--o",
+			expectedComps:     []string{"--output\tOutput format"},
+			expectedDirective: cobra.ShellCompDirectiveNoFileComp,
+			expectedOK:        true,
+		},
+		{
+			test:       "positional arguments are completed by the plugin",
+			args:       []string{"foo1"},
+			toComplete: "",
+			expectedOK: false,
+		},
+		{
+			test:       "flag values are completed by the plugin",
+			args:       []string{"foo1", "-o"},
+			toComplete: "",
+			expectedOK: false,
+		},
+		{
+			test:       "flag values with '=' are completed by the plugin",
+			args:       []string{"foo1"},
+			toComplete: "--output=",
+			expectedOK: false,
+		},
+		{
+			test:       "flags that were not cached are completed by the plugin",
+			args:       []string{"bar1"},
+			toComplete: "--",
+			expectedOK: false,
+		},
+	}
+
+	for _, spec := range tests {
+		t.Run(spec.test, func(t *testing.T) {
+			tree := sampleCompletionTree().Subcommands["cluster-plugin-global"]
+			comps, directive, ok := tree.Complete(spec.args, spec.toComplete)
+			assert.Equal(t, spec.expectedOK, ok)
+			if spec.expectedOK {
+				assert.Equal(t, spec.expectedComps, comps)
+				assert.Equal(t, spec.expectedDirective, directive)
+			}
+		})
+	}
+}
+
+func TestCompletePluginCommand(t *testing.T) {
+	tmpCacheDir := t.TempDir()
+	t.Setenv("TEST_CUSTOM_PLUGIN_COMMAND_TREE_CACHE_DIR", tmpCacheDir)
+
+	plugin := &cli.PluginInfo{
+		Name:             "cluster-plugin-global",
+		InstallationPath: "/path/to/cluster-plugin-global",
+	}
+
+	// Without a cached tree the plugin must provide the completion
+	_, _, ok := CompletePluginCommand(plugin, []string{"cluster-plugin-global"}, nil, "")
+	assert.False(t, ok)
+
+	cache := &cacheImpl{
+		pluginCommands: &pluginCommandTree{
+			CommandTree: map[string]*CommandNode{
+				plugin.InstallationPath: sampleCompletionTree(),
+			},
+		},
+	}
+	assert.NoError(t, cache.savePluginCommandTree())
+
+	comps, directive, ok := CompletePluginCommand(plugin, []string{"pg"}, nil, "b")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"bar1\tBar1 operations"}, comps)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+
+	// An unknown command path cannot be completed from the cache
+	_, _, ok = CompletePluginCommand(plugin, []string{"unknown"}, nil, "")
+	assert.False(t, ok)
+}
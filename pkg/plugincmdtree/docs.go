@@ -0,0 +1,49 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugincmdtree
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+)
+
+const pluginsDocsDir = ".docs"
+
+// getPluginDocsCacheDir returns the directory of the command tree cache in which
+// the markdown docs of the specified plugin are generated. Like the command tree,
+// the docs are specific to the installation of the plugin.
+func getPluginDocsCacheDir(plugin *cli.PluginInfo) string {
+	return filepath.Join(getPluginsDocsCachePath(), fmt.Sprintf("%s_%s", plugin.Name, filepath.Base(plugin.InstallationPath)))
+}
+
+// GetPluginCommandPath returns the path under which a command documented by the plugin
+// is available in the CLI. Like for the command tree, the target of the plugin is
+// inserted after the root command unless the plugin is global or the command is remapped,
+// e.g. "tanzu cluster list" becomes "tanzu mission-control cluster list".
+func GetPluginCommandPath(cmdPath string, plugin *cli.PluginInfo) string {
+	return strings.Join(adjustCmdNamesForPluginTarget(strings.Fields(cmdPath), plugin), " ")
+}
+
+// GetPluginDocsDir returns the directory containing the markdown docs of the plugin.
+// If the docs are not cached yet, they are generated using the 'generate-docs'
+// command of the plugin. The docs are removed from the cache along with the
+// command tree of the plugin.
+func GetPluginDocsDir(plugin *cli.PluginInfo) (string, error) {
+	docsDir := getPluginDocsCacheDir(plugin)
+	if entries, err := os.ReadDir(docsDir); err == nil && len(entries) > 0 {
+		return docsDir, nil
+	}
+
+	if err := generatePluginDocs(plugin); err != nil {
+		_ = os.RemoveAll(docsDir)
+		return "", errors.Wrapf(err, "failed to generate docs for the plugin %q", plugin.Name)
+	}
+	return docsDir, nil
+}
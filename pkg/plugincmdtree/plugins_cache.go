@@ -38,11 +38,11 @@ func getPluginsCommandTreeCacheDir() string {
 	return filepath.Join(common.DefaultCacheDir, pluginsCommandTreeDir)
 }
 func GetPluginsCommandTreeCachePath() string {
-	return filepath.Join(getPluginsCommandTreeCacheDir(), "command_tree_v2.yaml")
+	return filepath.Join(getPluginsCommandTreeCacheDir(), "command_tree_v3.yaml")
 }
 
 func getPluginsDocsCachePath() string {
-	return filepath.Join(getPluginsCommandTreeCacheDir(), ".docs")
+	return filepath.Join(getPluginsCommandTreeCacheDir(), pluginsDocsDir)
 }
 
 type cacheImpl struct {
@@ -106,6 +106,10 @@ func (c *cacheImpl) constructAndAddTree(rootCmd *cobra.Command, plugin *cli.Plug
 }
 
 func (c *cacheImpl) DeletePluginTree(plugin *cli.PluginInfo) error {
+	if err := os.RemoveAll(getPluginDocsCacheDir(plugin)); err != nil {
+		return err
+	}
+
 	_, exists := c.pluginCommands.CommandTree[plugin.InstallationPath]
 	if !exists {
 		return nil
@@ -118,6 +122,9 @@ func (c *cacheImpl) DeletePluginTree(plugin *cli.PluginInfo) error {
 
 func (c *cacheImpl) DeleteTree() error {
 	c.pluginCommands.CommandTree = make(map[string]*CommandNode)
+	if err := os.RemoveAll(getPluginsDocsCachePath()); err != nil {
+		return err
+	}
 	return os.RemoveAll(GetPluginsCommandTreeCachePath())
 }
 
@@ -141,7 +148,7 @@ func (c *cacheImpl) constructPluginCommandTree(rootCmd *cobra.Command, plugin *c
 	// construct the command tree
 	cmdTreeRoot := NewCommandNode()
 
-	docsDir := getPluginsDocsCachePath()
+	docsDir := getPluginDocsCacheDir(plugin)
 	files, err := os.ReadDir(docsDir)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error while reading local plugin command tree directory")
@@ -164,6 +171,10 @@ func (c *cacheImpl) constructPluginCommandTree(rootCmd *cobra.Command, plugin *c
 			continue
 		}
 
+		// Extract the description and flags of the command so that completion
+		// can be provided without having to invoke the plugin
+		cmdDescription, cmdFlags := parseCommandDoc(filepath.Join(docsDir, file.Name()))
+
 		// Loop a second time for k8s targets since they are both at the root
 		// level and under the k8s target
 		for i := 0; i < numTargets; i++ {
@@ -177,12 +188,17 @@ func (c *cacheImpl) constructPluginCommandTree(rootCmd *cobra.Command, plugin *c
 
 			var aliasArgs []string
 			current := cmdTreeRoot
-			for _, cmdName := range cmdNames {
+			for idx, cmdName := range cmdNames {
 				if current.Subcommands[cmdName] == nil {
 					current.Subcommands[cmdName] = NewCommandNode()
 				}
 
 				current = current.Subcommands[cmdName]
+				if idx == len(cmdNames)-1 {
+					// This is the command described by the docs file
+					current.Description = cmdDescription
+					current.Flags = cmdFlags
+				}
 				if cmdName != "tanzu" {
 					// The aliasArgs are used to construct the command we will use to get the help text
 					// so we can extract the aliases of command.
@@ -235,6 +251,61 @@ func (c *cacheImpl) constructPluginCommandTree(rootCmd *cobra.Command, plugin *c
 	return nil, nil
 }
 
+var flagUsageRegex = regexp.MustCompile(`^\s*(?:-(\S),\s+)?--([^\s=\[]+)(\s\S+)?(?:\s{2,}(.*))?$`)
+
+// parseCommandDoc extracts the short description and the flags of a command
+// from the markdown generated by the plugin's 'generate-docs' command.
+// The markdown is expected to follow the format produced by cobra's doc
+// generator, where the short description follows the "## <command>" header
+// and the flags are listed in the "### Options" sections.
+func parseCommandDoc(docPath string) (string, map[string]*FlagNode) {
+	flags := make(map[string]*FlagNode)
+	b, err := os.ReadFile(docPath)
+	if err != nil {
+		return "", flags
+	}
+
+	var description string
+	inHeader, inOptions, inCodeBlock := false, false, false
+	for _, line := range strings.Split(string(b), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "## "):
+			inHeader = description == ""
+			inOptions = false
+			continue
+		case strings.HasPrefix(line, "### "):
+			inHeader = false
+			inOptions = strings.HasPrefix(line, "### Options")
+			continue
+		case strings.HasPrefix(trimmed, "```"):
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+
+		if inHeader && trimmed != "" {
+			description = trimmed
+			inHeader = false
+			continue
+		}
+		if !inOptions || !inCodeBlock {
+			continue
+		}
+
+		matches := flagUsageRegex.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		flags[matches[2]] = &FlagNode{
+			Shorthand:   matches[1],
+			Description: strings.TrimSpace(matches[4]),
+			// Boolean flags are shown without a value type in the usage
+			TakesValue: strings.TrimSpace(matches[3]) != "",
+		}
+	}
+	return description, flags
+}
+
 func getTargetAliases(target types.Target) map[string]struct{} {
 	switch target {
 	case types.TargetK8s:
@@ -318,7 +389,7 @@ func getPluginCommandTree() (*pluginCommandTree, error) {
 }
 
 func generatePluginDocs(plugin *cli.PluginInfo) error {
-	docsDir := getPluginsDocsCachePath()
+	docsDir := getPluginDocsCacheDir(plugin)
 	_ = os.RemoveAll(docsDir)
 	_ = os.MkdirAll(docsDir, 0755)
 
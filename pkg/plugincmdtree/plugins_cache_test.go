@@ -270,15 +270,6 @@ func testConstructAndAddTreeForPlugin(t *testing.T, target configtypes.Target) {
 	assert.NoError(t, err)
 	defer os.RemoveAll(tmpCacheDir)
 
-	tmpCMDDocsDir := filepath.Join(tmpCacheDir, ".docs")
-	err = os.MkdirAll(tmpCMDDocsDir, 0755)
-	assert.NoError(t, err)
-	defer os.RemoveAll(tmpCMDDocsDir)
-
-	// pre-generate the plugin docs for the dummy plugin
-	err = createPluginDocs(tmpCMDDocsDir, target)
-	assert.NoError(t, err, "failed to create command docs for testing")
-
 	os.Setenv("TEST_CUSTOM_PLUGIN_COMMAND_TREE_CACHE_DIR", tmpCacheDir)
 	defer func() {
 		os.Unsetenv("TEST_CUSTOM_PLUGIN_COMMAND_TREE_CACHE_DIR")
@@ -309,6 +300,14 @@ func testConstructAndAddTreeForPlugin(t *testing.T, target configtypes.Target) {
 			},
 		},
 	}
+	tmpCMDDocsDir := getPluginDocsCacheDir(samplePlugin)
+	err = os.MkdirAll(tmpCMDDocsDir, 0755)
+	assert.NoError(t, err)
+
+	// pre-generate the plugin docs for the dummy plugin
+	err = createPluginDocs(tmpCMDDocsDir, target)
+	assert.NoError(t, err, "failed to create command docs for testing")
+
 	// setup the plugin
 	setupDummyPlugin(t, tmpCacheDir, pluginName[target], pluginAlias[target])
 
@@ -381,12 +380,17 @@ func TestCache_DeletePluginTree(t *testing.T) {
 		Version:          "1.0.0",
 	}
 	cache := getCacheWithSamplePluginCommandTree(plugin.Name, plugin.InstallationPath)
+	err = os.MkdirAll(getPluginDocsCacheDir(plugin), 0755)
+	assert.NoError(t, err)
 
 	// Test deleting the command tree
 	err = cache.DeletePluginTree(plugin)
 	assert.NoError(t, err)
 	// Make sure the cache was updated in memory
 	assert.Equal(t, 0, len(cache.pluginCommands.CommandTree))
+	// Make sure the docs of the plugin were removed along with its command tree
+	_, err = os.Stat(getPluginDocsCacheDir(plugin))
+	assert.True(t, os.IsNotExist(err))
 
 	// Test getting the command tree for a non-existing plugin
 	nonExistingPlugin := &cli.PluginInfo{
@@ -12,3 +12,12 @@ CREATE TABLE IF NOT EXISTS "AvailablePluginGroups" (
 		"GroupVersion"       TEXT NOT NULL,
 		PRIMARY KEY("Vendor", "Publisher", "GroupName", "GroupVersion")
 );
+
+CREATE TABLE IF NOT EXISTS "AvailablePluginPlatforms" (
+		"PluginName"         TEXT NOT NULL,
+		"Target"             TEXT NOT NULL,
+		"Version"            TEXT NOT NULL,
+		"OS"                 TEXT NOT NULL,
+		"Architecture"       TEXT NOT NULL,
+		PRIMARY KEY("PluginName", "Target", "Version", "OS", "Architecture")
+);
@@ -10,6 +10,7 @@ package plugininventory
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/vmware-tanzu/tanzu-cli/pkg/distribution"
 	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
@@ -45,6 +46,20 @@ type PluginInventory interface {
 
 	// UpdatePluginGroupActivationState updates plugin-group metadata to activate or deactivate the plugin-group
 	UpdatePluginGroupActivationState(*PluginGroup) error
+
+	// DeletePlugin deletes the versions of the plugin specified in the artifacts of the entry
+	DeletePlugin(*PluginInventoryEntry) error
+
+	// UpdatePluginURIPrefix replaces the prefix of the URIs of the plugin binaries starting
+	// with oldPrefix by newPrefix and returns the number of updated plugin binaries
+	UpdatePluginURIPrefix(oldPrefix, newPrefix string) (int, error)
+
+	// InsertPromotion records the promotion of a plugin or plugin-group version
+	// from another inventory
+	InsertPromotion(*PromotionEntry) error
+
+	// GetPromotions returns the promotions recorded in the inventory
+	GetPromotions() ([]*PromotionEntry, error)
 }
 
 // PluginInventoryEntry represents the inventory information
@@ -146,6 +161,29 @@ type PluginGroup struct {
 	Versions map[string][]*PluginGroupPluginEntry
 }
 
+const (
+	// PromotionKindPlugin is the kind of the promotion of a plugin version
+	PromotionKindPlugin = "plugin"
+	// PromotionKindPluginGroup is the kind of the promotion of a plugin-group version
+	PromotionKindPluginGroup = "plugin-group"
+)
+
+// PromotionEntry records the promotion of a plugin or plugin-group version
+// from another inventory
+type PromotionEntry struct {
+	// Source is the location of the inventory the version was promoted from
+	Source string
+	// Kind is either PromotionKindPlugin or PromotionKindPluginGroup
+	Kind string
+	// ID identifies the plugin, as "name@target", or the plugin-group,
+	// as "vendor-publisher/name"
+	ID string
+	// Version is the promoted version
+	Version string
+	// PromotedAt is the time of the promotion
+	PromotedAt time.Time
+}
+
 func PluginGroupToID(pg *PluginGroup) string {
 	return fmt.Sprintf("%s-%s/%s", pg.Vendor, pg.Publisher, pg.Name)
 }
@@ -19,6 +19,11 @@ type PluginInventoryMetadata interface {
 	// AvailablePluginBinaries table
 	InsertPluginIdentifier(*PluginIdentifier) error
 
+	// InsertPluginPlatform inserts the OS and architecture of a plugin binary
+	// to the AvailablePluginPlatforms table. A plugin version without any
+	// entry in this table is available for all the OS and architectures.
+	InsertPluginPlatform(pi *PluginIdentifier, osName, arch string) error
+
 	// InsertPluginGroupIdentifier inserts the PluginGroupIdentifier entry to the
 	// AvailablePluginGroups table
 	InsertPluginGroupIdentifier(*PluginGroupIdentifier) error
@@ -27,6 +32,14 @@ type PluginInventoryMetadata interface {
 	// merging the content of AvailablePluginBinaries and AvailablePluginGroups tables
 	MergeInventoryMetadataDatabase(additionalMetadataDBFilePath string) error
 
+	// GetPluginIdentifiers returns the PluginIdentifier entries of the
+	// AvailablePluginBinaries table
+	GetPluginIdentifiers() ([]*PluginIdentifier, error)
+
+	// GetPluginGroupIdentifiers returns the PluginGroupIdentifier entries of the
+	// AvailablePluginGroups table
+	GetPluginGroupIdentifiers() ([]*PluginGroupIdentifier, error)
+
 	// UpdatePluginInventoryDatabase updates the plugin inventory database based
 	// on the plugin inventory metadata database by deleting entries that don't
 	// exists in plugin inventory metadata database
@@ -9,6 +9,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	// Import the sqlite3 driver
 	_ "modernc.org/sqlite"
@@ -39,7 +40,15 @@ const (
 	SQliteDBFileName = "plugin_inventory.db"
 
 	// pluginSelectClause is the SELECT section of the SQL query to be used when querying the inventory DB.
-	pluginSelectClause = "SELECT PluginName,Target,RecommendedVersion,Version,Hidden,Description,Publisher,Vendor,OS,Architecture,Digest,URI FROM PluginBinaries"
+	// The %s verb is replaced by the signature column, see pluginSignatureColumn().
+	pluginSelectClause = "SELECT PluginName,Target,RecommendedVersion,Version,Hidden,Description,Publisher,Vendor,OS,Architecture,Digest,URI,%s FROM PluginBinaries"
+
+	// pluginSignatureColumnName is the name of the optional column of the PluginBinaries table
+	// storing the base64-encoded cosign signature of the plugin binaries.
+	// The column is only added to the table when a signed plugin binary is inserted, so that
+	// inventories without any signed plugin binary remain usable by older versions of the
+	// builder, which insert rows without specifying the column names.
+	pluginSignatureColumnName = "Signature"
 
 	// pluginOrderClause is the ORDER section of the SQL query to be used when querying the inventory DB.
 	// It MUST be used, as the order of the results is required by the functions processing the results.
@@ -69,6 +78,7 @@ type pluginDBRow struct {
 	arch               string
 	digest             string
 	uri                string
+	signature          string
 }
 
 // Structure of each row of the PluginGroups table within the SQLite database
@@ -187,7 +197,11 @@ func (b *SQLiteInventory) getPluginsFromDB(filter *PluginInventoryFilter) ([]*Pl
 	// Build the final query with the SELECT, WHERE and ORDER clauses.
 	// The ORDER clause is essential because the parsing algorithm of extractPluginsFromRows()
 	// assumes that ordering.
-	dbQuery := fmt.Sprintf("%s %s %s", pluginSelectClause, whereClause, pluginOrderClause)
+	signatureColumn, err := pluginSignatureColumn(db)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read the columns of the DB at '%s'", b.inventoryFile)
+	}
+	dbQuery := fmt.Sprintf("%s %s %s", fmt.Sprintf(pluginSelectClause, signatureColumn), whereClause, pluginOrderClause)
 	rows, err := db.Query(dbQuery)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to setup DB query for DB at '%s'", b.inventoryFile)
@@ -197,6 +211,30 @@ func (b *SQLiteInventory) getPluginsFromDB(filter *PluginInventoryFilter) ([]*Pl
 	return b.extractPluginsFromRows(rows)
 }
 
+// hasPluginSignatureColumn returns true if the PluginBinaries table of the DB
+// has the column storing the signature of the plugin binaries
+func hasPluginSignatureColumn(db *sql.DB) (bool, error) {
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('PluginBinaries') WHERE name = ?;", pluginSignatureColumnName).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// pluginSignatureColumn returns the expression to select the signature of the
+// plugin binaries, which is empty if the DB does not have the signature column
+func pluginSignatureColumn(db *sql.DB) (string, error) {
+	hasColumn, err := hasPluginSignatureColumn(db)
+	if err != nil {
+		return "", err
+	}
+	if !hasColumn {
+		return fmt.Sprintf("'' AS %s", pluginSignatureColumnName), nil
+	}
+	return pluginSignatureColumnName, nil
+}
+
 // createPluginWhereClause parses the filter and creates the WHERE clause for the DB query.
 //
 //nolint:unparam
@@ -330,11 +368,12 @@ func (b *SQLiteInventory) extractPluginsFromRows(rows *sql.Rows) ([]*PluginInven
 		fullImagePath := fmt.Sprintf("%s/%s", b.uriPrefix, row.uri)
 		// Create the artifact for this row.
 		artifact := distribution.Artifact{
-			Image:  fullImagePath,
-			URI:    "",
-			Digest: row.digest,
-			OS:     row.os,
-			Arch:   row.arch,
+			Image:     fullImagePath,
+			URI:       "",
+			Digest:    row.digest,
+			Signature: row.signature,
+			OS:        row.os,
+			Arch:      row.arch,
 		}
 		artifactList = append(artifactList, artifact)
 	}
@@ -523,6 +562,7 @@ func getPluginNextRow(rows *sql.Rows) (*pluginDBRow, error) {
 		&row.arch,
 		&row.digest,
 		&row.uri,
+		&row.signature,
 	)
 	return &row, err
 }
@@ -614,6 +654,19 @@ func (b *SQLiteInventory) InsertPlugin(pluginInventoryEntry *PluginInventoryEntr
 	}
 	defer db.Close()
 
+	hasSignatureColumn, err := hasPluginSignatureColumn(db)
+	if err != nil {
+		return errors.Wrapf(err, "unable to read the columns of the DB from '%s' file", b.inventoryFile)
+	}
+	if !hasSignatureColumn && hasSignedArtifact(pluginInventoryEntry) {
+		stmt := fmt.Sprintf("ALTER TABLE PluginBinaries ADD COLUMN %s TEXT NOT NULL DEFAULT '';", pluginSignatureColumnName)
+		if _, err = db.Exec(stmt); err != nil {
+			return errors.Wrap(err, "unable to add the signature column to the PluginBinaries table")
+		}
+		writeSQLStatementLogs(stmt + "\n")
+		hasSignatureColumn = true
+	}
+
 	for version, artifacts := range pluginInventoryEntry.Artifacts {
 		for _, a := range artifacts {
 			row := pluginDBRow{
@@ -629,20 +682,41 @@ func (b *SQLiteInventory) InsertPlugin(pluginInventoryEntry *PluginInventoryEntr
 				arch:               a.Arch,
 				digest:             a.Digest,
 				uri:                a.Image,
+				signature:          a.Signature,
 			}
 
-			_, err = db.Exec("INSERT INTO PluginBinaries VALUES(?,?,?,?,?,?,?,?,?,?,?,?);", row.name, row.target, row.recommendedVersion, row.version, row.hidden, row.description, row.publisher, row.vendor, row.os, row.arch, row.digest, row.uri)
+			if hasSignatureColumn {
+				_, err = db.Exec("INSERT INTO PluginBinaries (PluginName,Target,RecommendedVersion,Version,Hidden,Description,Publisher,Vendor,OS,Architecture,Digest,URI,Signature) VALUES(?,?,?,?,?,?,?,?,?,?,?,?,?);", row.name, row.target, row.recommendedVersion, row.version, row.hidden, row.description, row.publisher, row.vendor, row.os, row.arch, row.digest, row.uri, row.signature)
+			} else {
+				_, err = db.Exec("INSERT INTO PluginBinaries VALUES(?,?,?,?,?,?,?,?,?,?,?,?);", row.name, row.target, row.recommendedVersion, row.version, row.hidden, row.description, row.publisher, row.vendor, row.os, row.arch, row.digest, row.uri)
+			}
 			if err != nil {
 				return errors.Wrapf(err, "unable to insert plugin row %v", row)
 			}
 
 			// Write sql statement logs if required
-			writeSQLStatementLogs(fmt.Sprintf("INSERT INTO PluginBinaries VALUES(%v,%v,%v,%v,%v,%v,%v,%v,%v,%v,%v,%v);\n", row.name, row.target, row.recommendedVersion, row.version, row.hidden, row.description, row.publisher, row.vendor, row.os, row.arch, row.digest, row.uri))
+			if hasSignatureColumn {
+				writeSQLStatementLogs(fmt.Sprintf("INSERT INTO PluginBinaries (PluginName,Target,RecommendedVersion,Version,Hidden,Description,Publisher,Vendor,OS,Architecture,Digest,URI,Signature) VALUES(%v,%v,%v,%v,%v,%v,%v,%v,%v,%v,%v,%v,%v);\n", row.name, row.target, row.recommendedVersion, row.version, row.hidden, row.description, row.publisher, row.vendor, row.os, row.arch, row.digest, row.uri, row.signature))
+			} else {
+				writeSQLStatementLogs(fmt.Sprintf("INSERT INTO PluginBinaries VALUES(%v,%v,%v,%v,%v,%v,%v,%v,%v,%v,%v,%v);\n", row.name, row.target, row.recommendedVersion, row.version, row.hidden, row.description, row.publisher, row.vendor, row.os, row.arch, row.digest, row.uri))
+			}
 		}
 	}
 	return nil
 }
 
+// hasSignedArtifact returns true if any of the plugin binaries of the entry is signed
+func hasSignedArtifact(pluginInventoryEntry *PluginInventoryEntry) bool {
+	for _, artifacts := range pluginInventoryEntry.Artifacts {
+		for _, a := range artifacts {
+			if a.Signature != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // InsertPluginGroup inserts plugin-group to the inventory
 // specifying override will delete the existing plugin-group and add new one
 func (b *SQLiteInventory) InsertPluginGroup(pg *PluginGroup, override bool) error { //nolint:gocyclo
@@ -799,6 +873,121 @@ func (b *SQLiteInventory) UpdatePluginGroupActivationState(pg *PluginGroup) erro
 	return nil
 }
 
+// DeletePlugin deletes the versions of the plugin specified in the artifacts of the entry
+func (b *SQLiteInventory) DeletePlugin(pluginInventoryEntry *PluginInventoryEntry) error {
+	db, err := sql.Open("sqlite", b.inventoryFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open the DB from '%s' file", b.inventoryFile)
+	}
+	defer db.Close()
+
+	for version := range pluginInventoryEntry.Artifacts {
+		result, err := db.Exec("DELETE FROM PluginBinaries WHERE PluginName = ? AND Target = ? AND Version = ? ;", pluginInventoryEntry.Name, string(pluginInventoryEntry.Target), version)
+		if err != nil {
+			return errors.Wrapf(err, "unable to delete plugin %v_%v version %v", pluginInventoryEntry.Name, pluginInventoryEntry.Target, version)
+		}
+		rowsAffected, _ := result.RowsAffected()
+		if rowsAffected == 0 {
+			return errors.Errorf("unable to delete plugin %v_%v version %v. This might be possible because the provided plugin version doesn't exists", pluginInventoryEntry.Name, pluginInventoryEntry.Target, version)
+		}
+		// Write sql statement logs if required
+		writeSQLStatementLogs(fmt.Sprintf("DELETE FROM PluginBinaries WHERE PluginName = %v AND Target = %v AND Version = %v ;\n", pluginInventoryEntry.Name, pluginInventoryEntry.Target, version))
+	}
+	return nil
+}
+
+// UpdatePluginURIPrefix replaces the prefix of the URIs of the plugin binaries starting
+// with oldPrefix by newPrefix and returns the number of updated plugin binaries
+func (b *SQLiteInventory) UpdatePluginURIPrefix(oldPrefix, newPrefix string) (int, error) {
+	if oldPrefix == "" {
+		return 0, errors.New("the prefix of the URIs to update cannot be empty")
+	}
+	db, err := sql.Open("sqlite", b.inventoryFile)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to open the DB from '%s' file", b.inventoryFile)
+	}
+	defer db.Close()
+
+	// substr() is used instead of replace() to only replace the prefix of the URIs
+	result, err := db.Exec("UPDATE PluginBinaries SET URI = ? || substr(URI, ?) WHERE substr(URI, 1, ?) = ? ;", newPrefix, len(oldPrefix)+1, len(oldPrefix), oldPrefix)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to update the URIs with prefix %q", oldPrefix)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	// Write sql statement logs if required
+	writeSQLStatementLogs(fmt.Sprintf("UPDATE PluginBinaries SET URI = %v || substr(URI, %v) WHERE substr(URI, 1, %v) = %v ;\n", newPrefix, len(oldPrefix)+1, len(oldPrefix), oldPrefix))
+	return int(rowsAffected), nil
+}
+
+// createPromotionsTable is the statement creating the Promotions table. The table was
+// added after the other tables, so the databases published by older versions of the
+// builder do not have it.
+const createPromotionsTable = `
+	CREATE TABLE IF NOT EXISTS "Promotions" (
+		"Source"     TEXT NOT NULL,
+		"Kind"       TEXT NOT NULL,
+		"ID"         TEXT NOT NULL,
+		"Version"    TEXT NOT NULL,
+		"PromotedAt" TEXT NOT NULL
+	);`
+
+// InsertPromotion records the promotion of a plugin or plugin-group version
+// from another inventory
+func (b *SQLiteInventory) InsertPromotion(promotion *PromotionEntry) error {
+	db, err := sql.Open("sqlite", b.inventoryFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open the DB from '%s' file", b.inventoryFile)
+	}
+	defer db.Close()
+
+	promotedAt := promotion.PromotedAt.UTC().Format(time.RFC3339)
+	_, err = db.Exec(createPromotionsTable+"INSERT INTO Promotions VALUES(?,?,?,?,?);", promotion.Source, promotion.Kind, promotion.ID, promotion.Version, promotedAt)
+	if err != nil {
+		return errors.Wrapf(err, "unable to insert the promotion of %s %s:%s", promotion.Kind, promotion.ID, promotion.Version)
+	}
+	// Write sql statement logs if required
+	writeSQLStatementLogs(fmt.Sprintf("INSERT INTO Promotions VALUES(%v,%v,%v,%v,%v);\n", promotion.Source, promotion.Kind, promotion.ID, promotion.Version, promotedAt))
+	return nil
+}
+
+// GetPromotions returns the promotions recorded in the inventory, from the oldest
+func (b *SQLiteInventory) GetPromotions() ([]*PromotionEntry, error) {
+	db, err := sql.Open("sqlite", b.inventoryFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open the DB at '%s'", b.inventoryFile)
+	}
+	defer db.Close()
+
+	var count int
+	err = db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'Promotions';").Scan(&count)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read the tables of the DB at '%s'", b.inventoryFile)
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	rows, err := db.Query("SELECT Source,Kind,ID,Version,PromotedAt FROM Promotions ORDER BY PromotedAt,rowid;")
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to setup DB query for DB at '%s'", b.inventoryFile)
+	}
+	defer rows.Close()
+
+	var promotions []*PromotionEntry
+	for rows.Next() {
+		var promotedAt string
+		promotion := &PromotionEntry{}
+		if err := rows.Scan(&promotion.Source, &promotion.Kind, &promotion.ID, &promotion.Version, &promotedAt); err != nil {
+			return nil, errors.Wrapf(err, "unable to read the promotions of the DB at '%s'", b.inventoryFile)
+		}
+		if promotion.PromotedAt, err = time.Parse(time.RFC3339, promotedAt); err != nil {
+			return nil, errors.Wrapf(err, "invalid promotion time %q", promotedAt)
+		}
+		promotions = append(promotions, promotion)
+	}
+	return promotions, rows.Err()
+}
+
 func writeSQLStatementLogs(statements string) {
 	logFile := os.Getenv("SQL_STATEMENTS_LOG_FILE")
 	if logFile != "" {
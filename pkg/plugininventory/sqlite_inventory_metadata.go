@@ -5,6 +5,7 @@ package plugininventory
 
 import (
 	"database/sql"
+	"fmt"
 
 	// Import the sqlite3 driver
 	_ "modernc.org/sqlite"
@@ -65,6 +66,22 @@ func (b *SQLiteInventoryMetadata) InsertPluginIdentifier(pi *PluginIdentifier) e
 	return nil
 }
 
+// InsertPluginPlatform inserts the OS and architecture of a plugin binary
+// to the AvailablePluginPlatforms table
+func (b *SQLiteInventoryMetadata) InsertPluginPlatform(pi *PluginIdentifier, osName, arch string) error {
+	db, err := sql.Open("sqlite", b.inventoryMetadataDBFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open the DB from '%s' file", b.inventoryMetadataDBFile)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(createPluginPlatformsTable("main")+"INSERT OR REPLACE INTO AvailablePluginPlatforms VALUES(?,?,?,?,?);", pi.Name, pi.Target, pi.Version, osName, arch)
+	if err != nil {
+		return errors.Wrapf(err, "unable to insert plugin platform %v %s/%s", pi, osName, arch)
+	}
+	return nil
+}
+
 // InsertPluginGroupIdentifier inserts the PluginGroupIdentifier entry to the
 // AvailablePluginGroups table
 func (b *SQLiteInventoryMetadata) InsertPluginGroupIdentifier(pgi *PluginGroupIdentifier) error {
@@ -90,7 +107,12 @@ func (b *SQLiteInventoryMetadata) MergeInventoryMetadataDatabase(additionalMetad
 	}
 	defer db.Close()
 
-	mergeQuery := `ATTACH ? as additionalMetadataDB;
+	// A plugin version available for all the platforms in one of the databases
+	// remains available for all the platforms once the databases are merged
+	mergeQuery := `ATTACH ? as additionalMetadataDB;` +
+		createPluginPlatformsTable("main") + createPluginPlatformsTable("additionalMetadataDB") + `
+	DELETE FROM AvailablePluginPlatforms WHERE EXISTS (SELECT 1 FROM additionalMetadataDB.AvailablePluginBinaries b WHERE b.PluginName = AvailablePluginPlatforms.PluginName AND b.Target = AvailablePluginPlatforms.Target AND b.Version = AvailablePluginPlatforms.Version AND NOT EXISTS (SELECT 1 FROM additionalMetadataDB.AvailablePluginPlatforms p WHERE p.PluginName = b.PluginName AND p.Target = b.Target AND p.Version = b.Version));
+	INSERT OR REPLACE INTO AvailablePluginPlatforms SELECT a.PluginName,a.Target,a.Version,a.OS,a.Architecture FROM additionalMetadataDB.AvailablePluginPlatforms a WHERE NOT EXISTS (SELECT 1 FROM AvailablePluginBinaries b WHERE b.PluginName = a.PluginName AND b.Target = a.Target AND b.Version = a.Version AND NOT EXISTS (SELECT 1 FROM AvailablePluginPlatforms p WHERE p.PluginName = b.PluginName AND p.Target = b.Target AND p.Version = b.Version));
 	INSERT OR REPLACE INTO AvailablePluginGroups SELECT Vendor,Publisher,GroupName,GroupVersion FROM additionalMetadataDB.AvailablePluginGroups;
 	INSERT OR REPLACE INTO AvailablePluginBinaries SELECT PluginName,Target,Version FROM additionalMetadataDB.AvailablePluginBinaries;`
 
@@ -101,6 +123,58 @@ func (b *SQLiteInventoryMetadata) MergeInventoryMetadataDatabase(additionalMetad
 	return nil
 }
 
+// GetPluginIdentifiers returns the PluginIdentifier entries of the
+// AvailablePluginBinaries table
+func (b *SQLiteInventoryMetadata) GetPluginIdentifiers() ([]*PluginIdentifier, error) {
+	db, err := sql.Open("sqlite", b.inventoryMetadataDBFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open the DB from '%s' file", b.inventoryMetadataDBFile)
+	}
+	defer db.Close()
+
+	rows, err := db.Query("SELECT PluginName,Target,Version FROM AvailablePluginBinaries ORDER BY PluginName,Target,Version;")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read plugin identifiers")
+	}
+	defer rows.Close()
+
+	var pis []*PluginIdentifier
+	for rows.Next() {
+		pi := &PluginIdentifier{}
+		if err := rows.Scan(&pi.Name, &pi.Target, &pi.Version); err != nil {
+			return nil, errors.Wrap(err, "unable to read plugin identifiers")
+		}
+		pis = append(pis, pi)
+	}
+	return pis, rows.Err()
+}
+
+// GetPluginGroupIdentifiers returns the PluginGroupIdentifier entries of the
+// AvailablePluginGroups table
+func (b *SQLiteInventoryMetadata) GetPluginGroupIdentifiers() ([]*PluginGroupIdentifier, error) {
+	db, err := sql.Open("sqlite", b.inventoryMetadataDBFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open the DB from '%s' file", b.inventoryMetadataDBFile)
+	}
+	defer db.Close()
+
+	rows, err := db.Query("SELECT Vendor,Publisher,GroupName,GroupVersion FROM AvailablePluginGroups ORDER BY Vendor,Publisher,GroupName,GroupVersion;")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read plugin group identifiers")
+	}
+	defer rows.Close()
+
+	var pgis []*PluginGroupIdentifier
+	for rows.Next() {
+		pgi := &PluginGroupIdentifier{}
+		if err := rows.Scan(&pgi.Vendor, &pgi.Publisher, &pgi.Name, &pgi.Version); err != nil {
+			return nil, errors.Wrap(err, "unable to read plugin group identifiers")
+		}
+		pgis = append(pgis, pgi)
+	}
+	return pgis, rows.Err()
+}
+
 // UpdatePluginInventoryDatabase updates the plugin inventory database based
 // on the plugin inventory metadata database by deleting entries that don't
 // exists in plugin inventory metadata database
@@ -111,9 +185,10 @@ func (b *SQLiteInventoryMetadata) UpdatePluginInventoryDatabase(pluginInventoryD
 	}
 	defer db.Close()
 
-	updateQuery := `ATTACH ? as piDB;
+	updateQuery := `ATTACH ? as piDB;` + createPluginPlatformsTable("main") + `
 	DELETE FROM piDB.PluginGroups WHERE ROWID IN (SELECT a.ROWID FROM piDB.PluginGroups a LEFT JOIN AvailablePluginGroups b ON b.Vendor = a.Vendor AND b.Publisher = a.Publisher AND b.GroupName = a.GroupName AND b.GroupVersion = a.GroupVersion WHERE b.GroupVersion IS null);
-	DELETE FROM piDB.PluginBinaries WHERE ROWID IN (SELECT a.ROWID FROM piDB.PluginBinaries a LEFT JOIN AvailablePluginBinaries b ON b.PluginName = a.PluginName AND b.Target = a.Target AND b.Version = a.Version WHERE b.PluginName IS null);`
+	DELETE FROM piDB.PluginBinaries WHERE ROWID IN (SELECT a.ROWID FROM piDB.PluginBinaries a LEFT JOIN AvailablePluginBinaries b ON b.PluginName = a.PluginName AND b.Target = a.Target AND b.Version = a.Version WHERE b.PluginName IS null);
+	DELETE FROM piDB.PluginBinaries WHERE ROWID IN (SELECT a.ROWID FROM piDB.PluginBinaries a WHERE EXISTS (SELECT 1 FROM AvailablePluginPlatforms p WHERE p.PluginName = a.PluginName AND p.Target = a.Target AND p.Version = a.Version) AND NOT EXISTS (SELECT 1 FROM AvailablePluginPlatforms p WHERE p.PluginName = a.PluginName AND p.Target = a.Target AND p.Version = a.Version AND p.OS = a.OS AND p.Architecture = a.Architecture));`
 
 	_, err = db.Exec(updateQuery, pluginInventoryDBFilePath)
 	if err != nil {
@@ -121,3 +196,18 @@ func (b *SQLiteInventoryMetadata) UpdatePluginInventoryDatabase(pluginInventoryD
 	}
 	return nil
 }
+
+// createPluginPlatformsTable returns the statement creating the AvailablePluginPlatforms
+// table in the specified schema. The table was added after the other tables, so the
+// inventory metadata databases published by older versions of the CLI do not have it.
+func createPluginPlatformsTable(schema string) string {
+	return fmt.Sprintf(`
+	CREATE TABLE IF NOT EXISTS %s."AvailablePluginPlatforms" (
+		"PluginName"   TEXT NOT NULL,
+		"Target"       TEXT NOT NULL,
+		"Version"      TEXT NOT NULL,
+		"OS"           TEXT NOT NULL,
+		"Architecture" TEXT NOT NULL,
+		PRIMARY KEY("PluginName", "Target", "Version", "OS", "Architecture")
+	);`, schema)
+}
@@ -375,6 +375,47 @@ var _ = Describe("Unit tests for plugin inventory metadata", func() {
 		})
 	})
 
+	Describe("Get plugin and plugin group identifiers", func() {
+		Context("With an empty DB file", func() {
+			BeforeEach(func() {
+				metadataInventory, _ = createInventoryMetadataDB(false)
+			})
+			AfterEach(func() {
+				os.RemoveAll(tmpDir1)
+			})
+			It("should return an error", func() {
+				_, err = metadataInventory.GetPluginIdentifiers()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("unable to read plugin identifiers"))
+
+				_, err = metadataInventory.GetPluginGroupIdentifiers()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("unable to read plugin group identifiers"))
+			})
+		})
+
+		Context("With plugin and plugin group identifiers", func() {
+			BeforeEach(func() {
+				metadataInventory, _ = createInventoryMetadataDB(true)
+				Expect(metadataInventory.InsertPluginIdentifier(&pluginIdentifier2)).To(Succeed())
+				Expect(metadataInventory.InsertPluginIdentifier(&pluginIdentifier1)).To(Succeed())
+				Expect(metadataInventory.InsertPluginGroupIdentifier(&pluginGroupIdentifier1)).To(Succeed())
+			})
+			AfterEach(func() {
+				os.RemoveAll(tmpDir1)
+			})
+			It("should return all the identifiers", func() {
+				pis, err := metadataInventory.GetPluginIdentifiers()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pis).To(Equal([]*PluginIdentifier{&pluginIdentifier1, &pluginIdentifier2}))
+
+				pgis, err := metadataInventory.GetPluginGroupIdentifiers()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pgis).To(Equal([]*PluginGroupIdentifier{&pluginGroupIdentifier1}))
+			})
+		})
+	})
+
 	Describe("Update Plugin Inventory Database based on Metadata Database", func() {
 		Context("when plugin inventory database provided is invalid and does not have tables created", func() {
 			BeforeEach(func() {
@@ -495,6 +536,86 @@ var _ = Describe("Unit tests for plugin inventory metadata", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(len(pluginGroupEntries)).To(Equal(2))
 			})
+
+			It("when metadata database has platforms for a plugin version, it should remove the plugin binaries of the other platforms from inventory database", func() {
+				darwinEntry := pluginEntry1
+				darwinEntry.Artifacts = distribution.Artifacts{
+					"v1.0.0": []distribution.Artifact{
+						{
+							OS:     "darwin",
+							Arch:   "amd64",
+							Digest: "1111111111",
+							Image:  "vmware/tkg/darwin/amd64/global/plugin1:v1.0.0",
+						},
+					},
+				}
+				err = pluginInventory.InsertPlugin(&darwinEntry)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = metadataInventory.InsertPluginIdentifier(&pluginIdentifier1)
+				Expect(err).NotTo(HaveOccurred())
+				err = metadataInventory.InsertPluginPlatform(&pluginIdentifier1, "darwin", "amd64")
+				Expect(err).NotTo(HaveOccurred())
+				err = metadataInventory.InsertPluginIdentifier(&pluginIdentifier2)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = metadataInventory.UpdatePluginInventoryDatabase(pluginInventoryFilePath)
+				Expect(err).NotTo(HaveOccurred())
+
+				pluginEntries, err := pluginInventory.GetAllPlugins()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(pluginEntries)).To(Equal(2))
+				for _, pe := range pluginEntries {
+					Expect(len(pe.Artifacts)).To(Equal(1))
+					if pe.Name == pluginEntry1.Name {
+						Expect(pe.Artifacts["v1.0.0"]).To(HaveLen(1))
+						Expect(pe.Artifacts["v1.0.0"][0].OS).To(Equal("darwin"))
+					} else {
+						Expect(pe.Artifacts["v2.0.0"]).To(HaveLen(1))
+						Expect(pe.Artifacts["v2.0.0"][0].OS).To(Equal("linux"))
+					}
+				}
+			})
+
+			It("when merged metadata databases have different platforms for a plugin version, it should keep the plugin binaries of all the platforms", func() {
+				darwinEntry := pluginEntry1
+				darwinEntry.Artifacts = distribution.Artifacts{
+					"v1.0.0": []distribution.Artifact{
+						{
+							OS:     "darwin",
+							Arch:   "amd64",
+							Digest: "1111111111",
+							Image:  "vmware/tkg/darwin/amd64/global/plugin1:v1.0.0",
+						},
+					},
+				}
+				err = pluginInventory.InsertPlugin(&darwinEntry)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = metadataInventory.InsertPluginIdentifier(&pluginIdentifier1)
+				Expect(err).NotTo(HaveOccurred())
+				err = metadataInventory.InsertPluginPlatform(&pluginIdentifier1, "darwin", "amd64")
+				Expect(err).NotTo(HaveOccurred())
+
+				metadataDir := tmpDir1
+				additionalMetadataInventory, additionalMetadataInventoryFilePath := createInventoryMetadataDB(true)
+				defer os.RemoveAll(tmpDir1)
+				tmpDir1 = metadataDir
+				err = additionalMetadataInventory.InsertPluginIdentifier(&pluginIdentifier1)
+				Expect(err).NotTo(HaveOccurred())
+				err = additionalMetadataInventory.InsertPluginPlatform(&pluginIdentifier1, "linux", "amd64")
+				Expect(err).NotTo(HaveOccurred())
+
+				err = metadataInventory.MergeInventoryMetadataDatabase(additionalMetadataInventoryFilePath)
+				Expect(err).NotTo(HaveOccurred())
+				err = metadataInventory.UpdatePluginInventoryDatabase(pluginInventoryFilePath)
+				Expect(err).NotTo(HaveOccurred())
+
+				pluginEntries, err := pluginInventory.GetAllPlugins()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(pluginEntries)).To(Equal(1))
+				Expect(pluginEntries[0].Artifacts["v1.0.0"]).To(HaveLen(2))
+			})
 		})
 	})
 })
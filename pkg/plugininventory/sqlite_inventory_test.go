@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"sort"
 	"testing"
+	"time"
 
 	// Import the sqlite driver
 	_ "modernc.org/sqlite"
@@ -1333,6 +1334,67 @@ var _ = Describe("Unit tests for plugin inventory", func() {
 				Expect(err.Error()).To(ContainSubstring("UNIQUE constraint failed"))
 			})
 		})
+		Context("When inserting signed plugins", func() {
+			It("should add the signature column only when a signed plugin is inserted and return the signatures", func() {
+				// Unsigned plugins are inserted without adding the signature column
+				err = inventory.InsertPlugin(&piEntry1)
+				Expect(err).To(BeNil(), "failed to insert plugin1")
+				db, err := sql.Open("sqlite", dbFile.Name())
+				Expect(err).To(BeNil())
+				defer db.Close()
+				hasColumn, err := hasPluginSignatureColumn(db)
+				Expect(err).To(BeNil())
+				Expect(hasColumn).To(BeFalse())
+
+				plugins, err := inventory.GetPlugins(&PluginInventoryFilter{Name: "management-cluster", Target: types.TargetK8s})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(len(plugins)).To(Equal(1))
+				for _, a := range plugins[0].Artifacts["v0.28.0"] {
+					Expect(a.Signature).To(BeEmpty())
+				}
+
+				signedEntry := PluginInventoryEntry{
+					Name:        "isolated-cluster",
+					Target:      types.TargetGlobal,
+					Description: "Isolated cluster plugin",
+					Publisher:   "otherpublisher",
+					Vendor:      "othervendor",
+					Artifacts: distribution.Artifacts{
+						"v1.2.3": []distribution.Artifact{
+							{
+								Image:     "othervendor/otherpublisher/linux/amd64/global/isolated-cluster:v1.2.3",
+								URI:       "",
+								Digest:    "3333333333",
+								Signature: "c2lnbmF0dXJl",
+								OS:        "linux",
+								Arch:      "amd64",
+							},
+						},
+					},
+				}
+				err = inventory.InsertPlugin(&signedEntry)
+				Expect(err).To(BeNil(), "failed to insert the signed plugin")
+				hasColumn, err = hasPluginSignatureColumn(db)
+				Expect(err).To(BeNil())
+				Expect(hasColumn).To(BeTrue())
+
+				plugins, err = inventory.GetPlugins(&PluginInventoryFilter{Name: "isolated-cluster", Target: types.TargetGlobal})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(len(plugins)).To(Equal(1))
+				a := plugins[0].Artifacts["v1.2.3"]
+				Expect(len(a)).To(Equal(1))
+				Expect(a[0].Digest).To(Equal("3333333333"))
+				Expect(a[0].Signature).To(Equal("c2lnbmF0dXJl"))
+
+				// The plugins inserted before the signature column was added are unsigned
+				plugins, err = inventory.GetPlugins(&PluginInventoryFilter{Name: "management-cluster", Target: types.TargetK8s})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(len(plugins)).To(Equal(1))
+				for _, a := range plugins[0].Artifacts["v0.28.0"] {
+					Expect(a.Signature).To(BeEmpty())
+				}
+			})
+		})
 	})
 
 	Describe("Inserting plugin-groups to inventory and verifying it with GetPluginGroups", func() {
@@ -1747,6 +1809,123 @@ var _ = Describe("Unit tests for plugin inventory", func() {
 			})
 		})
 	})
+
+	Describe("Deleting plugins and updating the URIs of plugins", func() {
+		BeforeEach(func() {
+			tmpDir, err = os.MkdirTemp(os.TempDir(), "")
+			Expect(err).To(BeNil(), "unable to create temporary directory")
+
+			// Create DB file
+			dbFile, err = os.Create(filepath.Join(tmpDir, SQliteDBFileName))
+			Expect(err).To(BeNil())
+
+			inventory = NewSQLiteInventory(dbFile.Name(), tmpDir)
+			err = inventory.CreateSchema()
+			Expect(err).To(BeNil(), "failed to create DB schema for testing")
+
+			err = inventory.InsertPlugin(&piEntry1)
+			Expect(err).To(BeNil(), "failed to insert plugin1")
+			err = inventory.InsertPlugin(&piEntry3)
+			Expect(err).To(BeNil(), "failed to insert plugin3")
+		})
+		AfterEach(func() {
+			os.RemoveAll(tmpDir)
+		})
+		Context("When deleting a plugin version which exists in the database", func() {
+			It("should not return error and GetPlugins should not return the plugin version", func() {
+				err = inventory.DeletePlugin(&PluginInventoryEntry{
+					Name:      piEntry1.Name,
+					Target:    piEntry1.Target,
+					Artifacts: distribution.Artifacts{"v0.28.0": nil},
+				})
+				Expect(err).To(BeNil())
+
+				plugins, err := inventory.GetPlugins(&PluginInventoryFilter{Name: piEntry1.Name, Target: piEntry1.Target, IncludeHidden: true})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(len(plugins)).To(Equal(0))
+
+				// The plugin with the same name for another target is not deleted
+				plugins, err = inventory.GetPlugins(&PluginInventoryFilter{Name: piEntry3.Name, Target: piEntry3.Target})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(len(plugins)).To(Equal(1))
+			})
+		})
+		Context("When deleting a plugin version which does not exist in the database", func() {
+			It("should return error", func() {
+				err = inventory.DeletePlugin(&PluginInventoryEntry{
+					Name:      piEntry1.Name,
+					Target:    piEntry1.Target,
+					Artifacts: distribution.Artifacts{"v9.9.9": nil},
+				})
+				Expect(err).NotTo(BeNil())
+				Expect(err.Error()).To(ContainSubstring("unable to delete plugin management-cluster_kubernetes version v9.9.9"))
+			})
+		})
+		Context("When updating the prefix of the URIs of the plugins", func() {
+			It("should only update the URIs starting with the prefix", func() {
+				count, err := inventory.UpdatePluginURIPrefix("vmware/tkg/", "new-registry.example.com/tkg/")
+				Expect(err).To(BeNil())
+				Expect(count).To(Equal(3))
+
+				plugins, err := inventory.GetPlugins(&PluginInventoryFilter{Name: piEntry1.Name, Target: piEntry1.Target})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(len(plugins)).To(Equal(1))
+				for _, a := range plugins[0].Artifacts["v0.28.0"] {
+					Expect(a.Image).To(Equal(tmpDir + "/new-registry.example.com/tkg/" + a.OS + "/amd64/k8s/management-cluster:v0.28.0"))
+				}
+
+				plugins, err = inventory.GetPlugins(&PluginInventoryFilter{Name: piEntry3.Name, Target: piEntry3.Target})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(len(plugins)).To(Equal(1))
+				Expect(plugins[0].Artifacts["v0.0.1"][0].Image).To(Equal(tmpDir + "/vmware/tmc/linux/amd64/tmc/management-cluster:v0.0.1"))
+
+				// No URI matches the old prefix anymore
+				count, err = inventory.UpdatePluginURIPrefix("vmware/tkg/", "other/")
+				Expect(err).To(BeNil())
+				Expect(count).To(Equal(0))
+			})
+			It("should return error when the prefix is empty", func() {
+				_, err := inventory.UpdatePluginURIPrefix("", "new-registry.example.com/")
+				Expect(err).NotTo(BeNil())
+			})
+		})
+	})
+	Describe("Recording promotions", func() {
+		BeforeEach(func() {
+			tmpDir, err = os.MkdirTemp(os.TempDir(), "")
+			Expect(err).To(BeNil(), "unable to create temporary directory")
+
+			inventory = NewSQLiteInventory(filepath.Join(tmpDir, SQliteDBFileName), tmpDir)
+			err = inventory.CreateSchema()
+			Expect(err).To(BeNil(), "failed to create DB schema for testing")
+		})
+		AfterEach(func() {
+			os.RemoveAll(tmpDir)
+		})
+		Context("When the database does not have any promotion", func() {
+			It("should not return any promotion", func() {
+				promotions, err := inventory.GetPromotions()
+				Expect(err).To(BeNil())
+				Expect(promotions).To(BeEmpty())
+			})
+		})
+		Context("When inserting promotions", func() {
+			It("should return the promotions from the oldest", func() {
+				promotedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+				promotions := []*PromotionEntry{
+					{Source: "staging.example.com/plugins/plugin-inventory:latest", Kind: PromotionKindPluginGroup, ID: "vmware-tkg/default", Version: "v1.0.0", PromotedAt: promotedAt.Add(time.Minute)},
+					{Source: "staging.example.com/plugins/plugin-inventory:latest", Kind: PromotionKindPlugin, ID: "cluster@kubernetes", Version: "v0.28.0", PromotedAt: promotedAt},
+				}
+				for _, promotion := range promotions {
+					Expect(inventory.InsertPromotion(promotion)).To(Succeed())
+				}
+
+				recorded, err := inventory.GetPromotions()
+				Expect(err).To(BeNil())
+				Expect(recorded).To(Equal([]*PromotionEntry{promotions[1], promotions[0]}))
+			})
+		})
+	})
 })
 
 type pluginGroupSorter []*PluginGroup
@@ -0,0 +1,276 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginmanager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	configlib "github.com/vmware-tanzu/tanzu-plugin-runtime/config"
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
+
+	capdiscovery "github.com/vmware-tanzu/tanzu-cli/pkg/capabilities/discovery"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cluster"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/common"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/constants"
+)
+
+const (
+	// clusterRequirementsCheckTimeout is the time allowed for each request
+	// made to the cluster when verifying the cluster requirements of plugins
+	clusterRequirementsCheckTimeout = 5 * time.Second
+
+	// clusterRequirementsCacheFileName is the name of the file caching the results
+	// of the cluster requirements checks, in the cache directory of the CLI
+	clusterRequirementsCacheFileName = "cluster_requirements.yaml"
+
+	// clusterRequirementsCacheTTL is the time during which the result of a cluster
+	// requirements check is reused before invoking the plugin
+	clusterRequirementsCacheTTL = 24 * time.Hour
+
+	kubernetesVersionQueryName = "kubernetesVersion"
+)
+
+// clusterRequirementsCacheEntry is the result of the cluster requirements check of
+// a plugin version against the cluster of a context
+type clusterRequirementsCacheEntry struct {
+	// Reasons describe the requirements the cluster does not meet, if any
+	Reasons []string `yaml:"reasons,omitempty"`
+	// CheckedAt is the time of the check
+	CheckedAt time.Time `yaml:"checkedAt"`
+}
+
+// newClusterQueryClient returns a ClusterQueryClient for the cluster of the context.
+// It is a variable so that unit tests can query a fake cluster.
+var newClusterQueryClient = func(ctx *configtypes.Context) (*capdiscovery.ClusterQueryClient, error) {
+	clusterClient, err := cluster.NewClient(ctx.ClusterOpts.Path, ctx.ClusterOpts.Context, nil, cluster.Options{RequestTimeout: clusterRequirementsCheckTimeout})
+	if err != nil {
+		return nil, err
+	}
+	return clusterClient.BuildClusterQueryClient()
+}
+
+// PluginUnavailableError describes why a plugin is unavailable for the cluster of a context
+type PluginUnavailableError struct {
+	PluginName  string
+	ContextName string
+	Reasons     []string
+}
+
+func (e *PluginUnavailableError) Error() string {
+	return fmt.Sprintf("plugin '%s' is unavailable for the cluster of context '%s': %s", e.PluginName, e.ContextName, strings.Join(e.Reasons, "; "))
+}
+
+// CheckClusterRequirements evaluates the cluster requirements of the plugins against the
+// cluster of the context and returns a PluginUnavailableError for each plugin whose
+// requirements are not met. Plugins without cluster requirements are ignored, as are
+// all plugins if the context does not reference a cluster.
+func CheckClusterRequirements(plugins []cli.PluginInfo, ctx *configtypes.Context) ([]*PluginUnavailableError, error) {
+	if ctx == nil || ctx.ClusterOpts == nil {
+		return nil, nil
+	}
+
+	var cqc *capdiscovery.ClusterQueryClient
+	var unavailable []*PluginUnavailableError
+	results := make(map[string]clusterRequirementsCacheEntry)
+	defer func() { saveClusterRequirementsResults(results) }()
+	for i := range plugins {
+		if plugins[i].ClusterRequirements == nil {
+			continue
+		}
+		if cqc == nil {
+			var err error
+			if cqc, err = newClusterQueryClient(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		reasons, err := unmetClusterRequirements(plugins[i].ClusterRequirements, cqc)
+		if err != nil {
+			return nil, fmt.Errorf("unable to verify the cluster requirements of plugin '%s': %w", plugins[i].Name, err)
+		}
+		results[clusterRequirementsCacheKey(ctx, &plugins[i])] = clusterRequirementsCacheEntry{Reasons: reasons, CheckedAt: time.Now()}
+		if len(reasons) > 0 {
+			unavailable = append(unavailable, &PluginUnavailableError{
+				PluginName:  plugins[i].Name,
+				ContextName: ctx.Name,
+				Reasons:     reasons,
+			})
+		}
+	}
+	return unavailable, nil
+}
+
+// VerifyPluginClusterRequirements returns an error describing why the plugin is unavailable
+// if the cluster of the active context does not meet the cluster requirements of the plugin.
+// Failing to query the cluster does not prevent the plugin from being used, as the plugin
+// is in a better position to report the problem.
+// The result of the check is cached per context and plugin version, and is refreshed
+// when the plugins of the context are synced, or once clusterRequirementsCacheTTL elapsed.
+func VerifyPluginClusterRequirements(p *cli.PluginInfo) error {
+	if p == nil || p.ClusterRequirements == nil {
+		return nil
+	}
+	if skipCheck, _ := strconv.ParseBool(os.Getenv(constants.SkipPluginClusterRequirementsCheck)); skipCheck {
+		return nil
+	}
+
+	ctx := getActiveClusterContext()
+	if ctx == nil {
+		return nil
+	}
+	if result, found := getClusterRequirementsCache()[clusterRequirementsCacheKey(ctx, p)]; found && time.Since(result.CheckedAt) < clusterRequirementsCacheTTL {
+		if len(result.Reasons) == 0 {
+			return nil
+		}
+		return &PluginUnavailableError{PluginName: p.Name, ContextName: ctx.Name, Reasons: result.Reasons}
+	}
+	unavailable, err := CheckClusterRequirements([]cli.PluginInfo{*p}, ctx)
+	if err != nil {
+		log.V(6).Infof("skipping the cluster requirements check of plugin '%s': %v", p.Name, err)
+		return nil
+	}
+	if len(unavailable) > 0 {
+		return unavailable[0]
+	}
+	return nil
+}
+
+// clusterRequirementsCacheKey returns the key of the result of the cluster requirements
+// check of the plugin version against the cluster of the context
+func clusterRequirementsCacheKey(ctx *configtypes.Context, p *cli.PluginInfo) string {
+	return fmt.Sprintf("%s/%s/%s@%s:%s", ctx.Name, ctx.ClusterOpts.Context, p.Name, p.Target, p.Version)
+}
+
+func getClusterRequirementsCachePath() string {
+	return filepath.Join(common.DefaultCacheDir, clusterRequirementsCacheFileName)
+}
+
+// getClusterRequirementsCache returns the cached results of the cluster requirements checks
+func getClusterRequirementsCache() map[string]clusterRequirementsCacheEntry {
+	cache := make(map[string]clusterRequirementsCacheEntry)
+	b, err := os.ReadFile(getClusterRequirementsCachePath())
+	if err != nil {
+		return cache
+	}
+	if err := yaml.Unmarshal(b, &cache); err != nil {
+		log.V(6).Infof("ignoring the invalid cluster requirements cache: %v", err)
+		return make(map[string]clusterRequirementsCacheEntry)
+	}
+	return cache
+}
+
+// saveClusterRequirementsResults adds the results of cluster requirements checks to
+// the cache, dropping the expired results. Failing to save the cache is not an error
+// as the requirements are then verified again.
+func saveClusterRequirementsResults(results map[string]clusterRequirementsCacheEntry) {
+	if len(results) == 0 {
+		return
+	}
+	cache := getClusterRequirementsCache()
+	for key, result := range cache {
+		if time.Since(result.CheckedAt) >= clusterRequirementsCacheTTL {
+			delete(cache, key)
+		}
+	}
+	for key, result := range results {
+		cache[key] = result
+	}
+
+	b, err := yaml.Marshal(cache)
+	if err == nil {
+		err = os.MkdirAll(common.DefaultCacheDir, 0755)
+	}
+	if err == nil {
+		err = os.WriteFile(getClusterRequirementsCachePath(), b, 0644)
+	}
+	if err != nil {
+		log.V(6).Infof("unable to save the cluster requirements cache: %v", err)
+	}
+}
+
+// getActiveClusterContext returns the active context referencing a cluster, giving
+// precedence to the kubernetes context over the tanzu context
+func getActiveClusterContext() *configtypes.Context {
+	activeContexts, err := configlib.GetAllActiveContextsMap()
+	if err != nil {
+		return nil
+	}
+	for _, contextType := range []configtypes.ContextType{configtypes.ContextTypeK8s, configtypes.ContextTypeTanzu} {
+		if ctx := activeContexts[contextType]; ctx != nil && ctx.ClusterOpts != nil {
+			return ctx
+		}
+	}
+	return nil
+}
+
+// unmetClusterRequirements queries the cluster for the requirements and returns
+// a description of each requirement the cluster does not meet
+func unmetClusterRequirements(requirements *cli.ClusterRequirements, cqc *capdiscovery.ClusterQueryClient) ([]string, error) {
+	var versionQuery *capdiscovery.QueryKubernetesVersion
+	var targets []capdiscovery.QueryTarget
+	if requirements.MinKubernetesVersion != "" {
+		versionQuery = capdiscovery.KubernetesVersion(kubernetesVersionQueryName).AtLeast(requirements.MinKubernetesVersion)
+		targets = append(targets, versionQuery)
+	}
+	for i := range requirements.APIs {
+		apiQuery := capdiscovery.Group(apiQueryName(i), requirements.APIs[i].Group)
+		if len(requirements.APIs[i].Versions) > 0 {
+			apiQuery.WithVersions(requirements.APIs[i].Versions...)
+		}
+		if requirements.APIs[i].Resource != "" {
+			apiQuery.WithResource(requirements.APIs[i].Resource)
+		}
+		targets = append(targets, apiQuery)
+	}
+	if len(targets) == 0 {
+		return nil, nil
+	}
+
+	query := cqc.Query(targets...)
+	found, err := query.Execute()
+	if err != nil || found {
+		return nil, err
+	}
+
+	var reasons []string
+	results := query.Results()
+	if versionQuery != nil && !results.ForQuery(kubernetesVersionQueryName).Found {
+		reasons = append(reasons, fmt.Sprintf("the cluster runs Kubernetes version %s but version %s or later is required",
+			versionQuery.ServerVersion(), requirements.MinKubernetesVersion))
+	}
+	for i := range requirements.APIs {
+		if !results.ForQuery(apiQueryName(i)).Found {
+			reasons = append(reasons, fmt.Sprintf("the cluster does not serve the required API (%s)", describeAPIRequirement(&requirements.APIs[i])))
+		}
+	}
+	return reasons, nil
+}
+
+func apiQueryName(index int) string {
+	return fmt.Sprintf("api-%d", index)
+}
+
+func describeAPIRequirement(api *cli.ClusterAPIRequirement) string {
+	group := api.Group
+	if group == "" {
+		group = "core"
+	}
+	description := []string{fmt.Sprintf("group '%s'", group)}
+	if len(api.Versions) > 0 {
+		description = append(description, fmt.Sprintf("versions '%s'", strings.Join(api.Versions, ",")))
+	}
+	if api.Resource != "" {
+		description = append(description, fmt.Sprintf("resource '%s'", api.Resource))
+	}
+	return strings.Join(description, ", ")
+}
@@ -0,0 +1,178 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginmanager
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+
+	capdiscovery "github.com/vmware-tanzu/tanzu-cli/pkg/capabilities/discovery"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/constants"
+)
+
+// fakeClusterQueryClient replaces the ClusterQueryClient used to verify the cluster
+// requirements of plugins with one querying a fake cluster serving the specified
+// Kubernetes version and the apps/v1 deployments and statefulsets resources
+func fakeClusterQueryClient(t *testing.T, serverVersion string) *int {
+	fakeDiscovery := &fakediscovery.FakeDiscovery{
+		Fake: &k8stesting.Fake{
+			Resources: []*metav1.APIResourceList{
+				{
+					GroupVersion: "apps/v1",
+					APIResources: []metav1.APIResource{{Name: "deployments"}, {Name: "statefulsets"}},
+				},
+			},
+		},
+		FakedServerVersion: &version.Info{GitVersion: serverVersion},
+	}
+
+	calls := 0
+	originalNewClusterQueryClient := newClusterQueryClient
+	newClusterQueryClient = func(ctx *configtypes.Context) (*capdiscovery.ClusterQueryClient, error) {
+		calls++
+		return capdiscovery.NewClusterQueryClient(nil, fakeDiscovery)
+	}
+	t.Cleanup(func() { newClusterQueryClient = originalNewClusterQueryClient })
+	return &calls
+}
+
+func Test_CheckClusterRequirements(t *testing.T) {
+	assertions := assert.New(t)
+
+	defer setupLocalDistroForTesting()()
+	calls := fakeClusterQueryClient(t, "v1.28.3+vmware.1")
+
+	ctx := &configtypes.Context{
+		Name:        "mgmt",
+		ContextType: configtypes.ContextTypeK8s,
+		ClusterOpts: &configtypes.ClusterServer{Path: "config", Context: "mgmt-admin@mgmt"},
+	}
+	plugins := []cli.PluginInfo{
+		{
+			Name: "no-requirements",
+		},
+		{
+			Name: "supported",
+			ClusterRequirements: &cli.ClusterRequirements{
+				MinKubernetesVersion: "v1.26.0",
+				APIs: []cli.ClusterAPIRequirement{
+					{Group: "apps", Versions: []string{"v1"}, Resource: "deployments"},
+					{Group: "apps"},
+				},
+			},
+		},
+		{
+			Name: "unsupported",
+			ClusterRequirements: &cli.ClusterRequirements{
+				MinKubernetesVersion: "1.30",
+				APIs: []cli.ClusterAPIRequirement{
+					{Group: "apps", Versions: []string{"v1"}, Resource: "statefulsets"},
+					{Group: "example.com", Versions: []string{"v1alpha1"}, Resource: "widgets"},
+					{Group: "apps", Versions: []string{"v1", "v2"}},
+				},
+			},
+		},
+	}
+
+	// The cluster is not queried when no plugin specifies requirements
+	unavailable, err := CheckClusterRequirements(plugins[:1], ctx)
+	assertions.Nil(err)
+	assertions.Empty(unavailable)
+	assertions.Equal(0, *calls)
+
+	// The requirements are ignored for contexts without a cluster
+	unavailable, err = CheckClusterRequirements(plugins, &configtypes.Context{Name: "tmc", ContextType: configtypes.ContextTypeTMC})
+	assertions.Nil(err)
+	assertions.Empty(unavailable)
+	assertions.Equal(0, *calls)
+
+	unavailable, err = CheckClusterRequirements(plugins, ctx)
+	assertions.Nil(err)
+	assertions.Equal(1, *calls)
+	assertions.Len(unavailable, 1)
+	assertions.Equal("unsupported", unavailable[0].PluginName)
+	assertions.Equal("mgmt", unavailable[0].ContextName)
+	assertions.Equal([]string{
+		"the cluster runs Kubernetes version v1.28.3+vmware.1 but version 1.30 or later is required",
+		"the cluster does not serve the required API (group 'example.com', versions 'v1alpha1', resource 'widgets')",
+		"the cluster does not serve the required API (group 'apps', versions 'v1,v2')",
+	}, unavailable[0].Reasons)
+	assertions.Contains(unavailable[0].Error(), "plugin 'unsupported' is unavailable for the cluster of context 'mgmt': the cluster runs Kubernetes version")
+
+	// An invalid requirement is reported as an error
+	plugins[1].ClusterRequirements.MinKubernetesVersion = "latest"
+	_, err = CheckClusterRequirements(plugins, ctx)
+	assertions.NotNil(err)
+	assertions.Contains(err.Error(), "unable to verify the cluster requirements of plugin 'supported'")
+}
+
+func Test_VerifyPluginClusterRequirements(t *testing.T) {
+	assertions := assert.New(t)
+
+	defer setupLocalDistroForTesting()()
+	calls := fakeClusterQueryClient(t, "v1.25.0")
+
+	p := &cli.PluginInfo{
+		Name: "foo",
+		ClusterRequirements: &cli.ClusterRequirements{
+			MinKubernetesVersion: "v1.26.0",
+		},
+	}
+
+	// The cluster of the active kubernetes context does not meet the requirements
+	err := VerifyPluginClusterRequirements(p)
+	assertions.NotNil(err)
+	assertions.Equal("plugin 'foo' is unavailable for the cluster of context 'mgmt': the cluster runs Kubernetes version v1.25.0 but version v1.26.0 or later is required", err.Error())
+	assertions.Equal(1, *calls)
+
+	// The result of the check is cached for the plugin version
+	err = VerifyPluginClusterRequirements(p)
+	assertions.NotNil(err)
+	assertions.Equal("plugin 'foo' is unavailable for the cluster of context 'mgmt': the cluster runs Kubernetes version v1.25.0 but version v1.26.0 or later is required", err.Error())
+	assertions.Equal(1, *calls)
+
+	// Other versions of the plugin are verified, and the cluster is verified again once the cached result expired
+	p.Version = "v1.1.0"
+	err = VerifyPluginClusterRequirements(p)
+	assertions.NotNil(err)
+	assertions.Equal(2, *calls)
+	cache := getClusterRequirementsCache()
+	key := clusterRequirementsCacheKey(&configtypes.Context{Name: "mgmt", ClusterOpts: &configtypes.ClusterServer{Context: "mgmt-admin@mgmt"}}, p)
+	assertions.Contains(cache, key)
+	saveClusterRequirementsResults(map[string]clusterRequirementsCacheEntry{key: {CheckedAt: time.Now().Add(-clusterRequirementsCacheTTL)}})
+	err = VerifyPluginClusterRequirements(p)
+	assertions.NotNil(err)
+	assertions.Equal(3, *calls)
+
+	// The check can be skipped
+	os.Setenv(constants.SkipPluginClusterRequirementsCheck, "true")
+	err = VerifyPluginClusterRequirements(p)
+	os.Unsetenv(constants.SkipPluginClusterRequirementsCheck)
+	assertions.Nil(err)
+	assertions.Equal(3, *calls)
+
+	// Plugins without requirements are always available
+	err = VerifyPluginClusterRequirements(&cli.PluginInfo{Name: "bar"})
+	assertions.Nil(err)
+	assertions.Equal(3, *calls)
+
+	// Failing to reach the cluster does not prevent the plugin from being used
+	newClusterQueryClient = func(ctx *configtypes.Context) (*capdiscovery.ClusterQueryClient, error) {
+		return nil, errors.New("cluster unreachable")
+	}
+	p.Version = "v1.2.0"
+	err = VerifyPluginClusterRequirements(p)
+	assertions.Nil(err)
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginmanager
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/constants"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/pluginsupplier"
+)
+
+const (
+	// contextHookCommand is the plugin command invoked for the context events
+	// the plugin registered for. It is invoked as:
+	//   <plugin> context-hook <event> --context <context name> --type <context type>
+	contextHookCommand = "context-hook"
+
+	// defaultContextEventHookTimeout is the time allowed for each plugin to process a context event
+	defaultContextEventHookTimeout = 30 * time.Second
+)
+
+// getContextEventHookTimeout returns the time allowed for each plugin to process
+// a context event, which can be overridden through an environment variable
+func getContextEventHookTimeout() time.Duration {
+	timeout := defaultContextEventHookTimeout
+	if timeoutOverride := os.Getenv(constants.ContextEventHookTimeoutSeconds); timeoutOverride != "" {
+		if seconds, err := strconv.Atoi(timeoutOverride); err == nil && seconds > 0 {
+			timeout = time.Duration(seconds) * time.Second
+		}
+	}
+	return timeout
+}
+
+// RunContextEventHooks invokes the 'context-hook' command of every installed plugin
+// that registered for the specified context event. The plugins are invoked in parallel,
+// each with a timeout, and any failures are aggregated in the returned error.
+func RunContextEventHooks(event cli.ContextEvent, ctx *configtypes.Context) error {
+	if ctx == nil {
+		return nil
+	}
+	plugins, err := pluginsupplier.GetInstalledPlugins()
+	if err != nil {
+		return err
+	}
+	return runContextEventHooks(plugins, event, ctx, getContextEventHookTimeout())
+}
+
+func runContextEventHooks(plugins []cli.PluginInfo, event cli.ContextEvent, ctx *configtypes.Context, timeout time.Duration) error {
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	var errorList []error
+	for i := range plugins {
+		if !slices.Contains(plugins[i].ContextEventHooks, event) {
+			continue
+		}
+
+		wg.Add(1)
+		go func(p *cli.PluginInfo) {
+			defer wg.Done()
+			if err := runContextEventHook(p, event, ctx, timeout); err != nil {
+				mutex.Lock()
+				errorList = append(errorList, err)
+				mutex.Unlock()
+			}
+		}(&plugins[i])
+	}
+	wg.Wait()
+
+	return kerrors.NewAggregate(errorList)
+}
+
+func runContextEventHook(p *cli.PluginInfo, event cli.ContextEvent, ctx *configtypes.Context, timeout time.Duration) error {
+	args := []string{contextHookCommand, string(event), "--context", ctx.Name, "--type", string(ctx.ContextType)}
+
+	hookCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	log.V(6).Infof("running the %q hook of plugin %q", event, p.Name)
+	runner := cli.NewRunner(p.Name, p.InstallationPath, args)
+	_, stderr, err := runner.RunOutput(hookCtx)
+	if hookCtx.Err() == context.DeadlineExceeded {
+		return errors.Errorf("the %q hook of plugin %q did not complete within %v", event, p.Name, timeout)
+	}
+	if err != nil {
+		if stderr = strings.TrimSpace(stderr); stderr != "" {
+			return errors.Wrapf(err, "the %q hook of plugin %q failed: %s", event, p.Name, stderr)
+		}
+		return errors.Wrapf(err, "the %q hook of plugin %q failed", event, p.Name)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginmanager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/constants"
+)
+
+// fakeHookPlugin records the arguments it is invoked with in the specified file,
+// and fails or hangs depending on the event it receives
+const fakeHookPlugin = `#!/bin/bash
+echo "$@" >> %s
+if [ "$2" = "context-deleted" ]; then
+  echo "cannot clean up" >&2
+  exit 1
+fi
+if [ "$2" = "post-login" ]; then
+  exec sleep 5
+fi
+`
+
+func setupFakeHookPlugin(t *testing.T, dir, name string) (cli.PluginInfo, string) {
+	outputFile := filepath.Join(dir, name+".out")
+	pluginPath := filepath.Join(dir, name)
+	err := os.WriteFile(pluginPath, []byte(fmt.Sprintf(fakeHookPlugin, outputFile)), 0755)
+	assert.NoError(t, err)
+
+	return cli.PluginInfo{
+		Name:             name,
+		InstallationPath: pluginPath,
+		ContextEventHooks: []cli.ContextEvent{
+			cli.ContextEventActivated,
+			cli.ContextEventDeleted,
+			cli.ContextEventPostLogin,
+		},
+	}, outputFile
+}
+
+func TestRunContextEventHooks(t *testing.T) {
+	dir := t.TempDir()
+	plugin1, output1 := setupFakeHookPlugin(t, dir, "plugin1")
+	plugin2, output2 := setupFakeHookPlugin(t, dir, "plugin2")
+	// plugin2 does not register for the activated event
+	plugin2.ContextEventHooks = []cli.ContextEvent{cli.ContextEventDeleted}
+	plugins := []cli.PluginInfo{plugin1, plugin2}
+
+	ctx := &configtypes.Context{Name: "my-ctx", ContextType: configtypes.ContextTypeK8s}
+
+	// Only the plugins that registered for the event are invoked
+	err := runContextEventHooks(plugins, cli.ContextEventActivated, ctx, 5*time.Second)
+	assert.NoError(t, err)
+	b, err := os.ReadFile(output1)
+	assert.NoError(t, err)
+	assert.Equal(t, "context-hook context-activated --context my-ctx --type kubernetes\n", string(b))
+	_, err = os.Stat(output2)
+	assert.True(t, os.IsNotExist(err))
+
+	// Errors of all plugins are aggregated
+	err = runContextEventHooks(plugins, cli.ContextEventDeleted, ctx, 5*time.Second)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `the "context-deleted" hook of plugin "plugin1" failed: cannot clean up`)
+	assert.Contains(t, err.Error(), `the "context-deleted" hook of plugin "plugin2" failed: cannot clean up`)
+
+	// Plugins that do not complete in time are reported
+	err = runContextEventHooks(plugins, cli.ContextEventPostLogin, ctx, 100*time.Millisecond)
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), `the "post-login" hook of plugin "plugin1" did not complete within 100ms`), err.Error())
+
+	// No plugin registered for the event
+	err = runContextEventHooks(plugins, cli.ContextEventCreated, ctx, 5*time.Second)
+	assert.NoError(t, err)
+}
+
+func TestGetContextEventHookTimeout(t *testing.T) {
+	assert.Equal(t, defaultContextEventHookTimeout, getContextEventHookTimeout())
+
+	t.Setenv(constants.ContextEventHookTimeoutSeconds, "5")
+	assert.Equal(t, 5*time.Second, getContextEventHookTimeout())
+
+	t.Setenv(constants.ContextEventHookTimeoutSeconds, "invalid")
+	assert.Equal(t, defaultContextEventHookTimeout, getContextEventHookTimeout())
+}
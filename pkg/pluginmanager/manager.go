@@ -5,12 +5,14 @@
 package pluginmanager
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/Masterminds/semver"
@@ -29,6 +31,8 @@ import (
 	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/common"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/config"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/constants"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cosignhelper"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/discovery"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/distribution"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/plugincmdtree"
@@ -54,6 +58,11 @@ const (
 	errorNoDiscoverySourcesFound = "there are no plugin discovery sources available. Please run 'tanzu plugin source init'"
 
 	errorNoActiveContexForGivenContextType = "there is no active context for the given context type `%v`"
+
+	// Values of the plugin signature verification policy
+	pluginSignaturePolicyDisabled = "disabled"
+	pluginSignaturePolicyWarn     = "warn"
+	pluginSignaturePolicyRequired = "required"
 )
 
 var execCommand = exec.Command
@@ -195,6 +204,8 @@ func discoverServerPluginsForGivenContexts(contexts []*configtypes.Context) ([]d
 		if err != nil {
 			errList = append(errList, err)
 		}
+		discoveredPlugins = filterPluginsByRequiredContextType(discoveredPlugins, context.ContextType)
+		pluginGroups := make(map[string]*plugininventory.PluginGroup)
 		for i := range discoveredPlugins {
 			discoveredPlugins[i].Scope = common.PluginScopeContext
 			discoveredPlugins[i].Status = common.PluginStatusNotInstalled
@@ -215,12 +226,34 @@ func discoverServerPluginsForGivenContexts(contexts []*configtypes.Context) ([]d
 				}
 			}
 
+			// The context can recommend the version of the plugin included in a plugin group
+			if discoveredPlugins[i].RecommendedVersion == "" && discoveredPlugins[i].PluginGroupID != "" {
+				version, err := getPluginVersionFromGroup(&discoveredPlugins[i], pluginGroups)
+				if err != nil {
+					errList = append(errList, err)
+					continue
+				}
+				discoveredPlugins[i].RecommendedVersion = version
+			}
+
 			// It is possible that server recommends shortened plugin version of format vMAJOR or vMAJOR.MINOR
 			// in that case, try to find the latest available version of the plugin that matches with the given recommended version
 			matchedRecommendedVersion := getMatchingRecommendedVersionOfPlugin(discoveredPlugins[i].Name, discoveredPlugins[i].Target, discoveredPlugins[i].RecommendedVersion)
 			if matchedRecommendedVersion != "" {
 				discoveredPlugins[i].RecommendedVersion = matchedRecommendedVersion
 			}
+
+			// The recommended version, possibly resolved from a plugin group, must be supported by the context
+			if err := verifyRecommendedVersionSupported(&discoveredPlugins[i]); err != nil {
+				errList = append(errList, err)
+				discoveredPlugins[i].RecommendedVersion = ""
+			}
+		}
+		discoveredPlugins = slices.DeleteFunc(discoveredPlugins, func(p discovery.Discovered) bool {
+			return p.RecommendedVersion == ""
+		})
+		if err == nil {
+			saveContextPluginVersionRanges(context.Name, discoveredPlugins)
 		}
 		// Remove older plugins from the discoveredPlugins list when there are duplicates
 		// this can be possible if a same plugin gets discovered from different kubernetes namespaces
@@ -230,6 +263,90 @@ func discoverServerPluginsForGivenContexts(contexts []*configtypes.Context) ([]d
 	return plugins, kerrors.NewAggregate(errList)
 }
 
+// verifyRecommendedVersionSupported returns an error if the recommended version of the
+// discovered plugin is outside of the range of versions supported by its context
+func verifyRecommendedVersionSupported(p *discovery.Discovered) error {
+	if p.IsVersionSupported(p.RecommendedVersion) {
+		return nil
+	}
+	return errors.Errorf("recommended version '%s' of plugin '%s' is not supported by context '%s' (supported versions: %s)",
+		p.RecommendedVersion, p.Name, p.ContextName, utils.DescribeVersionRange(p.MinVersion, p.MaxVersion))
+}
+
+// saveContextPluginVersionRanges records the range of versions supported by the context
+// for the discovered plugins, for the plugins to be verified when they are invoked
+func saveContextPluginVersionRanges(contextName string, plugins []discovery.Discovered) {
+	ranges := make(map[string]catalog.PluginVersionRange)
+	for i := range plugins {
+		if plugins[i].MinVersion != "" || plugins[i].MaxVersion != "" {
+			ranges[catalog.PluginNameTarget(plugins[i].Name, plugins[i].Target)] = catalog.PluginVersionRange{
+				MinVersion: plugins[i].MinVersion,
+				MaxVersion: plugins[i].MaxVersion,
+			}
+		}
+	}
+	if err := catalog.SetContextPluginVersionRanges(contextName, ranges); err != nil {
+		log.V(6).Infof("unable to save the plugin versions supported by context '%s': %v", contextName, err)
+	}
+}
+
+// VerifyPluginAvailability verifies the plugin before it is invoked. It warns if the version
+// of the plugin is not supported by an active context, and returns an error if the cluster
+// of the active context does not meet the cluster requirements of the plugin.
+func VerifyPluginAvailability(p *cli.PluginInfo) error {
+	warnUnsupportedPluginVersion(p)
+	return VerifyPluginClusterRequirements(p)
+}
+
+// warnUnsupportedPluginVersion warns if the version of the plugin is not supported by
+// one of the active contexts, according to the range of versions of the plugin the
+// context specified when its plugins were last discovered
+func warnUnsupportedPluginVersion(p *cli.PluginInfo) {
+	if p == nil {
+		return
+	}
+	activeContexts, err := configlib.GetAllActiveContextsList()
+	if err != nil {
+		return
+	}
+	for _, ctxName := range activeContexts {
+		versionRange, exists, err := catalog.GetContextPluginVersionRange(ctxName, p.Name, p.Target)
+		if err != nil || !exists || utils.IsVersionInRange(p.Version, versionRange.MinVersion, versionRange.MaxVersion) {
+			continue
+		}
+		log.Warningf("version '%s' of plugin '%s' is not supported by context '%s' (supported versions: %s), run 'tanzu plugin sync' to install a supported version",
+			p.Version, p.Name, ctxName, utils.DescribeVersionRange(versionRange.MinVersion, versionRange.MaxVersion))
+	}
+}
+
+// filterPluginsByRequiredContextType removes the plugins that are not recommended
+// for the given context type
+func filterPluginsByRequiredContextType(plugins []discovery.Discovered, contextType configtypes.ContextType) []discovery.Discovered {
+	return slices.DeleteFunc(plugins, func(p discovery.Discovered) bool {
+		return len(p.RequiredContextTypes) != 0 && !slices.Contains(p.RequiredContextTypes, contextType)
+	})
+}
+
+// getPluginVersionFromGroup returns the version of the plugin included in the plugin group
+// referenced by the discovered plugin. The plugin groups already fetched are kept in pluginGroups.
+func getPluginVersionFromGroup(p *discovery.Discovered, pluginGroups map[string]*plugininventory.PluginGroup) (string, error) {
+	pg, exists := pluginGroups[p.PluginGroupID]
+	if !exists {
+		var err error
+		pg, err = GetPluginGroup(p.PluginGroupID, DisableLogs())
+		if err != nil {
+			return "", errors.Wrapf(err, "unable to find the version of plugin '%s' from plugin group '%s'", p.Name, p.PluginGroupID)
+		}
+		pluginGroups[p.PluginGroupID] = pg
+	}
+	for _, plugin := range pg.Versions[pg.RecommendedVersion] {
+		if plugin.Name == p.Name && (p.Target == configtypes.TargetUnknown || plugin.Target == p.Target) {
+			return plugin.Version, nil
+		}
+	}
+	return "", errors.Errorf("plugin '%s' is not part of the plugin group '%s'", p.Name, p.PluginGroupID)
+}
+
 func getMatchingRecommendedVersionOfPlugin(pluginName string, pluginTarget configtypes.Target, version string) string {
 	criteria := &discovery.PluginDiscoveryCriteria{
 		Name:    pluginName,
@@ -413,7 +530,9 @@ func setAvailablePluginsStatus(availablePlugins []discovery.Discovered, installe
 		for j := range availablePlugins {
 			if installedPlugins[i].Name == availablePlugins[j].Name && installedPlugins[i].Target == availablePlugins[j].Target {
 				// Match found, Check for update available and update status
-				if installedPlugins[i].DiscoveredRecommendedVersion == availablePlugins[j].RecommendedVersion {
+				// An installed version outside of the range supported by the context needs to be updated
+				if installedPlugins[i].DiscoveredRecommendedVersion == availablePlugins[j].RecommendedVersion &&
+					availablePlugins[j].IsVersionSupported(installedPlugins[i].Version) {
 					availablePlugins[j].Status = common.PluginStatusInstalled
 				} else {
 					availablePlugins[j].Status = common.PluginStatusUpdateAvailable
@@ -481,6 +600,12 @@ func InstallStandalonePlugin(pluginName, version string, target configtypes.Targ
 	return installPlugin(pluginName, version, target, "")
 }
 
+// InstallContextPlugin installs a plugin by name, version and target as a plugin
+// scoped to the given context.
+func InstallContextPlugin(pluginName, version string, target configtypes.Target, contextName string) error {
+	return installPlugin(pluginName, version, target, contextName)
+}
+
 // installs a plugin by name, version and target.
 // If the contextName is not empty, it implies the plugin is a context-scope plugin, otherwise
 // we are installing a standalone plugin.
@@ -830,6 +955,10 @@ func fetchAndVerifyPlugin(p *discovery.Discovered, version string) ([]byte, erro
 	if err != nil {
 		return nil, errors.Wrapf(err, "%q plugin post-download verification failed", p.Name)
 	}
+	err = verifyPluginSignature(p, version, b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%q plugin signature verification failed", p.Name)
+	}
 	return b, nil
 }
 
@@ -1082,6 +1211,14 @@ func UpdatePluginsInstallationStatus(plugins []discovery.Discovered) {
 	}
 }
 
+// UpdateContextPluginsInstallationStatus updates the installation status of the given
+// plugins based on the plugins installed for the given context
+func UpdateContextPluginsInstallationStatus(plugins []discovery.Discovered, contextName string) {
+	if c, err := catalog.NewContextCatalog(contextName); err == nil {
+		setAvailablePluginsStatus(plugins, c.List())
+	}
+}
+
 // InstallPluginsFromLocalSource installs plugin from local source directory
 //
 //nolint:gocyclo
@@ -1440,6 +1577,37 @@ func verifyPluginPostDownload(p *discovery.Discovered, srcDigest string, b []byt
 	return nil
 }
 
+// verifyPluginSignature verifies the signature of the downloaded binary according
+// to the plugin signature verification policy. Unlike the digest, which comes from
+// the same plugin inventory as the location of the binary, the signature can only
+// be produced by the publisher of the plugin, so that a compromised plugin inventory
+// cannot be used to install malicious plugin binaries.
+func verifyPluginSignature(p *discovery.Discovered, version string, b []byte) error {
+	policy := strings.ToLower(strings.TrimSpace(os.Getenv(constants.PluginSignatureVerificationPolicy)))
+	switch policy {
+	case "", pluginSignaturePolicyDisabled:
+		return nil
+	case pluginSignaturePolicyWarn, pluginSignaturePolicyRequired:
+	default:
+		return errors.Errorf("invalid value %q for %s, it should be one of %q, %q or %q", policy, constants.PluginSignatureVerificationPolicy, pluginSignaturePolicyDisabled, pluginSignaturePolicyWarn, pluginSignaturePolicyRequired)
+	}
+
+	artifactInfo, err := p.Distribution.DescribeArtifact(version, cli.GOOS, cli.GOARCH)
+	if err != nil {
+		return err
+	}
+	if artifactInfo.Signature == "" {
+		err = errors.Errorf("plugin %q version %q is not signed", p.Name, version)
+	} else {
+		err = cosignhelper.VerifyBlobSignature(context.Background(), os.Getenv(constants.PublicKeyPathForPluginSignature), b, []byte(artifactInfo.Signature))
+	}
+	if err != nil && policy == pluginSignaturePolicyWarn {
+		log.Warningf("Installing plugin %q version %q despite its signature verification failure: %v", p.Name, version, err)
+		return nil
+	}
+	return err
+}
+
 // getPluginDiscoveries returns the plugin discoveries found in the configuration file.
 //
 //nolint:unparam
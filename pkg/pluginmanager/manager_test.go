@@ -4,6 +4,12 @@
 package pluginmanager
 
 import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
 	"fmt"
 	"os"
 	"os/exec"
@@ -13,10 +19,14 @@ import (
 	"testing"
 
 	"github.com/pkg/errors"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
 	"github.com/stretchr/testify/assert"
 
 	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
 
+	"github.com/vmware-tanzu/tanzu-cli/pkg/catalog"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/common"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/config"
@@ -452,6 +462,52 @@ func Test_InstallStandalonePlugin(t *testing.T) {
 	}
 }
 
+func Test_InstallContextPlugin(t *testing.T) {
+	assertions := assert.New(t)
+
+	defer setupPluginSourceForTesting()()
+	execCommand = fakeInfoExecCommand
+	defer func() { execCommand = exec.Command }()
+
+	os.Setenv(constants.ContextScopedPlugins, "true")
+	defer os.Unsetenv(constants.ContextScopedPlugins)
+
+	err := InstallStandalonePlugin("login", "v0.2.0", configtypes.TargetUnknown)
+	assertions.Nil(err)
+
+	// Install a different version of the plugin for the active context
+	err = InstallContextPlugin("login", "v0.20.0", configtypes.TargetUnknown, "mgmt")
+	assertions.Nil(err)
+
+	// The version installed for the active context is used
+	installedPlugins, err := pluginsupplier.GetInstalledPlugins()
+	assertions.Nil(err)
+	assertions.Equal(1, len(installedPlugins))
+	assertions.Equal("login", installedPlugins[0].Name)
+	assertions.Equal("v0.20.0", installedPlugins[0].Version)
+
+	// The standalone plugin is kept
+	standaloneCatalog, err := catalog.NewContextCatalog("")
+	assertions.Nil(err)
+	assertions.Equal(1, len(standaloneCatalog.List()))
+	assertions.Equal("v0.2.0", standaloneCatalog.List()[0].Version)
+
+	// The installation status is based on the plugins of the context
+	for _, tc := range []struct {
+		contextName string
+		status      string
+	}{
+		{"mgmt", common.PluginStatusInstalled},
+		{"tmc-fake", common.PluginStatusNotInstalled},
+	} {
+		plugins := []discovery.Discovered{
+			{Name: "login", Target: configtypes.TargetGlobal, RecommendedVersion: "v0.20.0", Status: common.PluginStatusNotInstalled},
+		}
+		UpdateContextPluginsInstallationStatus(plugins, tc.contextName)
+		assertions.Equal(tc.status, plugins[0].Status)
+	}
+}
+
 func Test_InstallPluginsFromGroup(t *testing.T) {
 	assertions := assert.New(t)
 
@@ -846,6 +902,111 @@ func Test_setAvailablePluginsStatus(t *testing.T) {
 	assertions.Equal("v3.0.0", availablePlugins[0].RecommendedVersion)
 	assertions.Equal("v1.0.0", availablePlugins[0].InstalledVersion)
 	assertions.Equal(common.PluginStatusUpdateAvailable, availablePlugins[0].Status)
+
+	// If the installed version is outside of the range supported by the context
+	// then available plugin status should show 'update available'
+	availablePlugins = []discovery.Discovered{{Name: "fake1", DiscoveryType: "kubernetes", RecommendedVersion: "v1.2.0", MinVersion: "v1.1.0", Status: common.PluginStatusNotInstalled}}
+	installedPlugin = []cli.PluginInfo{{Name: "fake1", Version: "v1.0.0", Discovery: "local", DiscoveredRecommendedVersion: "v1.2.0"}}
+	setAvailablePluginsStatus(availablePlugins, installedPlugin)
+	assertions.Equal("v1.0.0", availablePlugins[0].InstalledVersion)
+	assertions.Equal(common.PluginStatusUpdateAvailable, availablePlugins[0].Status)
+
+	installedPlugin[0].Version = "v1.1.0"
+	setAvailablePluginsStatus(availablePlugins, installedPlugin)
+	assertions.Equal(common.PluginStatusInstalled, availablePlugins[0].Status)
+}
+
+func Test_getPluginVersionFromGroup(t *testing.T) {
+	assertions := assert.New(t)
+
+	defer setupPluginSourceForTesting()()
+
+	pluginGroups := make(map[string]*plugininventory.PluginGroup)
+	p := &discovery.Discovered{Name: "management-cluster", Target: configtypes.TargetK8s, PluginGroupID: testGroupName + ":" + testGroupVersion}
+	version, err := getPluginVersionFromGroup(p, pluginGroups)
+	assertions.Nil(err)
+	assertions.Equal("v1.6.0", version)
+	assertions.Contains(pluginGroups, testGroupName+":"+testGroupVersion)
+
+	// The latest version of the plugin group is used when no version is specified
+	p = &discovery.Discovered{Name: "isolated-cluster", Target: configtypes.TargetGlobal, PluginGroupID: testGroupName}
+	version, err = getPluginVersionFromGroup(p, pluginGroups)
+	assertions.Nil(err)
+	assertions.Equal("v1", version)
+
+	p = &discovery.Discovered{Name: "not-exists", Target: configtypes.TargetK8s, PluginGroupID: testGroupName}
+	_, err = getPluginVersionFromGroup(p, pluginGroups)
+	assertions.NotNil(err)
+	assertions.Contains(err.Error(), "plugin 'not-exists' is not part of the plugin group")
+
+	p = &discovery.Discovered{Name: "management-cluster", Target: configtypes.TargetK8s, PluginGroupID: "vmware-test/not-exists"}
+	_, err = getPluginVersionFromGroup(p, pluginGroups)
+	assertions.NotNil(err)
+	assertions.Contains(err.Error(), "unable to find the version of plugin 'management-cluster' from plugin group 'vmware-test/not-exists'")
+}
+
+func Test_filterPluginsByRequiredContextType(t *testing.T) {
+	assertions := assert.New(t)
+
+	plugins := []discovery.Discovered{
+		{Name: "fake1"},
+		{Name: "fake2", RequiredContextTypes: []configtypes.ContextType{configtypes.ContextTypeK8s}},
+		{Name: "fake3", RequiredContextTypes: []configtypes.ContextType{configtypes.ContextTypeTanzu}},
+		{Name: "fake4", RequiredContextTypes: []configtypes.ContextType{configtypes.ContextTypeTMC, configtypes.ContextTypeK8s}},
+	}
+	plugins = filterPluginsByRequiredContextType(plugins, configtypes.ContextTypeK8s)
+	assertions.Equal(3, len(plugins))
+	assertions.Equal("fake1", plugins[0].Name)
+	assertions.Equal("fake2", plugins[1].Name)
+	assertions.Equal("fake4", plugins[2].Name)
+}
+
+func Test_verifyRecommendedVersionSupported(t *testing.T) {
+	assertions := assert.New(t)
+
+	p := &discovery.Discovered{Name: "fake1", ContextName: "mgmt", RecommendedVersion: "v1.1.0", MinVersion: "v1.0.0", MaxVersion: "v1.2.0"}
+	assertions.Nil(verifyRecommendedVersionSupported(p))
+
+	// A version resolved from a plugin group can be outside of the supported range
+	p.RecommendedVersion = "v1.3.0"
+	err := verifyRecommendedVersionSupported(p)
+	assertions.NotNil(err)
+	assertions.Equal("recommended version 'v1.3.0' of plugin 'fake1' is not supported by context 'mgmt' (supported versions: >= v1.0.0, <= v1.2.0)", err.Error())
+}
+
+func Test_warnUnsupportedPluginVersion(t *testing.T) {
+	assertions := assert.New(t)
+
+	defer setupLocalDistroForTesting()()
+	var buf bytes.Buffer
+	log.SetStderr(&buf)
+	defer log.SetStderr(os.Stderr)
+
+	saveContextPluginVersionRanges("mgmt", []discovery.Discovered{
+		{Name: "fake1", Target: configtypes.TargetK8s, RecommendedVersion: "v1.1.0", MinVersion: "v1.1.0", MaxVersion: "v1.2.0"},
+		{Name: "fake2", Target: configtypes.TargetK8s, RecommendedVersion: "v1.0.0"},
+	})
+	versionRange, exists, err := catalog.GetContextPluginVersionRange("mgmt", "fake1", configtypes.TargetK8s)
+	assertions.Nil(err)
+	assertions.True(exists)
+	assertions.Equal(catalog.PluginVersionRange{MinVersion: "v1.1.0", MaxVersion: "v1.2.0"}, versionRange)
+	_, exists, err = catalog.GetContextPluginVersionRange("mgmt", "fake2", configtypes.TargetK8s)
+	assertions.Nil(err)
+	assertions.False(exists)
+
+	// A supported version is invoked without warning
+	assertions.Nil(VerifyPluginAvailability(&cli.PluginInfo{Name: "fake1", Target: configtypes.TargetK8s, Version: "v1.2.0"}))
+	assertions.Empty(buf.String())
+
+	// A version outside of the range supported by the active context is invoked with a warning
+	assertions.Nil(VerifyPluginAvailability(&cli.PluginInfo{Name: "fake1", Target: configtypes.TargetK8s, Version: "v1.0.0"}))
+	assertions.Contains(buf.String(), "version 'v1.0.0' of plugin 'fake1' is not supported by context 'mgmt' (supported versions: >= v1.1.0, <= v1.2.0)")
+
+	// The ranges are no longer verified once the context stops specifying them
+	buf.Reset()
+	saveContextPluginVersionRanges("mgmt", nil)
+	assertions.Nil(VerifyPluginAvailability(&cli.PluginInfo{Name: "fake1", Target: configtypes.TargetK8s, Version: "v1.0.0"}))
+	assertions.Empty(buf.String())
 }
 
 func Test_DiscoverPluginsFromLocalSourceBasedOnManifestFile(t *testing.T) {
@@ -1052,6 +1213,93 @@ func TestVerifyPluginPostDownload(t *testing.T) {
 	}
 }
 
+func TestVerifyPluginSignature(t *testing.T) {
+	binary := []byte("plugin binary")
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	signer, err := signature.LoadECDSASignerVerifier(privateKey, crypto.SHA256)
+	assert.NoError(t, err)
+	sig, err := signer.SignMessage(bytes.NewReader(binary))
+	assert.NoError(t, err)
+	publicKey, err := cryptoutils.MarshalPublicKeyToPEM(&privateKey.PublicKey)
+	assert.NoError(t, err)
+	publicKeyPath := filepath.Join(t.TempDir(), "cosign.pub")
+	assert.NoError(t, os.WriteFile(publicKeyPath, publicKey, 0644))
+	t.Setenv(constants.PublicKeyPathForPluginSignature, publicKeyPath)
+
+	discoveredPlugin := func(signature string) *discovery.Discovered {
+		return &discovery.Discovered{
+			Name: "foo",
+			Distribution: distribution.Artifacts{
+				"v1.0.0": []distribution.Artifact{{OS: cli.GOOS, Arch: cli.GOARCH, Image: "example.com/foo:v1.0.0", Signature: signature}},
+			},
+		}
+	}
+	validSignature := base64.StdEncoding.EncodeToString(sig)
+	invalidSignature := base64.StdEncoding.EncodeToString([]byte("invalid"))
+
+	tcs := []struct {
+		name      string
+		policy    string
+		signature string
+		err       string
+	}{
+		{
+			name:   "success - verification disabled by default",
+			policy: "",
+		},
+		{
+			name:      "success - verification disabled with an invalid signature",
+			policy:    "disabled",
+			signature: invalidSignature,
+		},
+		{
+			name:      "success - valid signature",
+			policy:    "required",
+			signature: validSignature,
+		},
+		{
+			name:   "success - unsigned plugin with the warn policy",
+			policy: "warn",
+		},
+		{
+			name:      "success - invalid signature with the warn policy",
+			policy:    "warn",
+			signature: invalidSignature,
+		},
+		{
+			name:   "failure - unsigned plugin with the required policy",
+			policy: "required",
+			err:    `plugin "foo" version "v1.0.0" is not signed`,
+		},
+		{
+			name:      "failure - invalid signature with the required policy",
+			policy:    "required",
+			signature: invalidSignature,
+			err:       "failed validating the signature of the blob",
+		},
+		{
+			name:   "failure - invalid policy",
+			policy: "strict",
+			err:    `invalid value "strict" for TANZU_CLI_PLUGIN_SIGNATURE_VERIFICATION_POLICY`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(constants.PluginSignatureVerificationPolicy, tc.policy)
+
+			err := verifyPluginSignature(discoveredPlugin(tc.signature), "v1.0.0", binary)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestHelperProcess(_ *testing.T) {
 	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
 		return
@@ -0,0 +1,72 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginmanager
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/helpers"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/carvelhelpers"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/discovery"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/provenance"
+)
+
+// GetPluginProvenance returns the provenance statement of the installed plugin,
+// published as an OCI referrer of the plugin image in the discovery source of the
+// plugin. The provenance statement is only returned if it is about the installed
+// plugin binary.
+func GetPluginProvenance(pd *cli.PluginInfo) (*provenance.Statement, error) {
+	discoveries, err := getPluginDiscoveries()
+	if err != nil {
+		return nil, err
+	}
+	if len(discoveries) == 0 {
+		return nil, errors.New(errorNoDiscoverySourcesFound)
+	}
+	criteria := &discovery.PluginDiscoveryCriteria{
+		Name:    pd.Name,
+		Target:  pd.Target,
+		Version: pd.Version,
+		OS:      cli.GOOS,
+		Arch:    cli.GOARCH,
+	}
+	plugins, err := discoverSpecificPlugins(discoveries, discovery.WithPluginDiscoveryCriteria(criteria))
+	if err != nil || len(plugins) == 0 {
+		return nil, errors.Errorf("unable to find plugin '%v' version '%v' in the discovery sources", pd.Name, pd.Version)
+	}
+	artifact, err := plugins[0].Distribution.DescribeArtifact(pd.Version, cli.GOOS, cli.GOARCH)
+	if err != nil {
+		return nil, err
+	}
+	if artifact.Image == "" {
+		return nil, errors.Errorf("the provenance of plugin '%v' is only available for plugins published as OCI images", pd.Name)
+	}
+
+	contents, err := carvelhelpers.NewImageOperationsImpl().GetReferrers(artifact.Image, provenance.ArtifactType)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get the provenance of plugin '%v'", pd.Name)
+	}
+	binaryDigest, err := helpers.GetDigest(pd.InstallationPath)
+	if err != nil {
+		return nil, err
+	}
+	statement := findProvenance(contents, binaryDigest)
+	if statement == nil {
+		return nil, errors.Errorf("no provenance found for plugin '%v' version '%v' at '%v'", pd.Name, pd.Version, artifact.Image)
+	}
+	return statement, nil
+}
+
+// findProvenance returns the first valid provenance statement about the
+// file with the specified sha256 digest
+func findProvenance(contents [][]byte, sha256Digest string) *provenance.Statement {
+	for _, content := range contents {
+		statement, err := provenance.Parse(content)
+		if err == nil && statement.HasSubject(sha256Digest) {
+			return statement
+		}
+	}
+	return nil
+}
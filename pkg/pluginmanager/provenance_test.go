@@ -0,0 +1,31 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginmanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindProvenance(t *testing.T) {
+	assert := assert.New(t)
+
+	statement := func(digest string) []byte {
+		return []byte(`{"_type": "https://in-toto.io/Statement/v1", "predicateType": "https://slsa.dev/provenance/v1",
+			"subject": [{"name": "foo", "digest": {"sha256": "` + digest + `"}}]}`)
+	}
+	contents := [][]byte{
+		[]byte("invalid"),
+		statement("1111"),
+		statement("2222"),
+	}
+
+	s := findProvenance(contents, "2222")
+	assert.NotNil(s)
+	assert.True(s.HasSubject("2222"))
+
+	assert.Nil(findProvenance(contents, "3333"))
+	assert.Nil(findProvenance(nil, "1111"))
+}
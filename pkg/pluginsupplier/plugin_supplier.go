@@ -5,16 +5,28 @@
 package pluginsupplier
 
 import (
+	"os"
 	"slices"
+	"sort"
+	"strconv"
 
 	"github.com/vmware-tanzu/tanzu-cli/pkg/catalog"
 	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/constants"
 	configlib "github.com/vmware-tanzu/tanzu-plugin-runtime/config"
 	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
 )
 
 // GetInstalledPlugins return the installed plugins
+//
+// When context-scoped plugins are enabled, the plugins installed for the
+// active contexts are returned instead of the standalone plugins of the
+// same name and target.
 func GetInstalledPlugins() ([]cli.PluginInfo, error) {
+	if IsContextScopedPluginsEnabled() {
+		return getInstalledPluginsWithContextScopedPlugins()
+	}
+
 	// Migrate context-scoped plugins as standalone plugin if required
 	// TODO(anujc): Think on how to invoke this function just once after the newer version
 	// of the CLI gets installed as we just need to do this migration once
@@ -28,6 +40,37 @@ func GetInstalledPlugins() ([]cli.PluginInfo, error) {
 	return standAloneCatalog.List(), nil
 }
 
+// IsContextScopedPluginsEnabled returns true if the plugins recommended by a
+// context are installed for that context instead of as standalone plugins
+func IsContextScopedPluginsEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(constants.ContextScopedPlugins))
+	return enabled
+}
+
+func getInstalledPluginsWithContextScopedPlugins() ([]cli.PluginInfo, error) {
+	activeContexts, _ := configlib.GetAllActiveContextsList()
+	// Sort the contexts so that the same plugin is used every time if
+	// more than one active context installed it
+	sort.Strings(activeContexts)
+
+	var installedPlugins []cli.PluginInfo
+	pluginKeys := make(map[string]bool)
+	for _, ctxName := range append(activeContexts, "") {
+		c, err := catalog.NewContextCatalog(ctxName)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range c.List() { //nolint:gocritic
+			key := catalog.PluginNameTarget(p.Name, p.Target)
+			if !pluginKeys[key] {
+				pluginKeys[key] = true
+				installedPlugins = append(installedPlugins, p)
+			}
+		}
+	}
+	return installedPlugins, nil
+}
+
 // FilterPluginsByActiveContextType will exclude any plugin with an explicit
 // setting of supportedContextType that does not match the type of any active CLI context
 // Separating this conditional check so GetInstalledPlugins can
@@ -242,6 +242,52 @@ var _ = Describe("GetInstalledPlugins (standalone plugins)", func() {
 		})
 	})
 
+	Context("when context-scoped plugins are enabled", func() {
+		BeforeEach(func() {
+			os.Setenv(constants.ContextScopedPlugins, "true")
+
+			pd1, err = fakeInstallPlugin("", "fake-plugin1", types.TargetK8s, "v1.0.0")
+			Expect(err).ToNot(HaveOccurred())
+			pd2, err = fakeInstallPlugin(k8sContextName, "fake-plugin1", types.TargetK8s, "v2.0.0")
+			Expect(err).ToNot(HaveOccurred())
+			pd3, err = fakeInstallPlugin("test-use-context", "fake-plugin1", types.TargetK8s, "v3.0.0")
+			Expect(err).ToNot(HaveOccurred())
+			pd4, err = fakeInstallPlugin(tmcContextName, "fake-plugin2", types.TargetTMC, "v1.0.0")
+			Expect(err).ToNot(HaveOccurred())
+			pd5, err = fakeInstallPlugin("", "fake-plugin3", types.TargetGlobal, "v1.0.0")
+			Expect(err).ToNot(HaveOccurred())
+		})
+		AfterEach(func() {
+			os.Unsetenv(constants.ContextScopedPlugins)
+		})
+
+		It("should return the plugins of the active contexts over the standalone plugins", func() {
+			Expect(IsContextScopedPluginsEnabled()).To(BeTrue())
+
+			installedPlugins, err := GetInstalledPlugins()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(installedPlugins)).To(Equal(3))
+			Expect(installedPlugins).ShouldNot(ContainElement(*pd1))
+			Expect(installedPlugins).Should(ContainElement(*pd2))
+			Expect(installedPlugins).ShouldNot(ContainElement(*pd3))
+			Expect(installedPlugins).Should(ContainElement(*pd4))
+			Expect(installedPlugins).Should(ContainElement(*pd5))
+		})
+
+		It("should not migrate the plugins of the active contexts as standalone plugins", func() {
+			_, err := GetInstalledPlugins()
+			Expect(err).ToNot(HaveOccurred())
+
+			standaloneCatalog, err := catalog.NewContextCatalog("")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(standaloneCatalog.List()).To(ConsistOf(*pd1, *pd5))
+
+			contextCatalog, err := catalog.NewContextCatalog(k8sContextName)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contextCatalog.List()).To(ConsistOf(*pd2))
+		})
+	})
+
 	Context("with a catalog cache from an older CLI version", func() {
 		BeforeEach(func() {
 			cdir, err = os.MkdirTemp("", "test-catalog-cache")
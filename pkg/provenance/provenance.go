@@ -0,0 +1,224 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package provenance implements the in-toto provenance statements recording how
+// plugin binaries are built, following the SLSA provenance v1 predicate.
+package provenance
+
+import (
+	"debug/buildinfo"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/helpers"
+	cliBuildInfo "github.com/vmware-tanzu/tanzu-cli/pkg/buildinfo"
+)
+
+const (
+	// ArtifactType is the OCI artifact type of the provenance statements
+	// published as referrers of the plugin images
+	ArtifactType = "application/vnd.in-toto+json"
+	// FileSuffix is the suffix of the provenance statement file saved next
+	// to the plugin binary
+	FileSuffix = ".intoto.json"
+	// StatementType is the in-toto statement type
+	StatementType = "https://in-toto.io/Statement/v1"
+	// PredicateType is the SLSA provenance predicate type
+	PredicateType = "https://slsa.dev/provenance/v1"
+	// BuildType is the type of the builds performed by `tanzu builder plugin build`
+	BuildType = "https://github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/plugin-build@v1"
+	// BuilderID identifies the tanzu builder plugin
+	BuilderID = "https://github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder"
+)
+
+// Statement is an in-toto statement with a SLSA provenance predicate
+type Statement struct {
+	Type          string               `json:"_type" yaml:"_type"`
+	Subject       []ResourceDescriptor `json:"subject" yaml:"subject"`
+	PredicateType string               `json:"predicateType" yaml:"predicateType"`
+	Predicate     Provenance           `json:"predicate" yaml:"predicate"`
+}
+
+// ResourceDescriptor describes an artifact or a dependency of the build
+type ResourceDescriptor struct {
+	Name   string            `json:"name,omitempty" yaml:"name,omitempty"`
+	URI    string            `json:"uri,omitempty" yaml:"uri,omitempty"`
+	Digest map[string]string `json:"digest,omitempty" yaml:"digest,omitempty"`
+}
+
+// Provenance is the SLSA provenance v1 predicate
+type Provenance struct {
+	BuildDefinition BuildDefinition `json:"buildDefinition" yaml:"buildDefinition"`
+	RunDetails      RunDetails      `json:"runDetails" yaml:"runDetails"`
+}
+
+// BuildDefinition describes the inputs of the build
+type BuildDefinition struct {
+	BuildType            string               `json:"buildType" yaml:"buildType"`
+	ExternalParameters   BuildParameters      `json:"externalParameters" yaml:"externalParameters"`
+	InternalParameters   InternalParameters   `json:"internalParameters" yaml:"internalParameters"`
+	ResolvedDependencies []ResourceDescriptor `json:"resolvedDependencies,omitempty" yaml:"resolvedDependencies,omitempty"`
+}
+
+// BuildParameters are the parameters of the plugin build
+type BuildParameters struct {
+	Plugin       string `json:"plugin" yaml:"plugin"`
+	Target       string `json:"target,omitempty" yaml:"target,omitempty"`
+	Version      string `json:"version" yaml:"version"`
+	OS           string `json:"os" yaml:"os"`
+	Arch         string `json:"arch" yaml:"arch"`
+	LDFlags      string `json:"ldflags,omitempty" yaml:"ldflags,omitempty"`
+	Tags         string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	GoFlags      string `json:"goflags,omitempty" yaml:"goflags,omitempty"`
+	Reproducible bool   `json:"reproducible" yaml:"reproducible"`
+}
+
+// InternalParameters are the parameters of the build set by the Go toolchain
+type InternalParameters struct {
+	GoVersion string `json:"goVersion" yaml:"goVersion"`
+	// Settings are the build settings recorded by the Go toolchain in the binary
+	Settings map[string]string `json:"settings,omitempty" yaml:"settings,omitempty"`
+}
+
+// RunDetails describes the builder and the build invocation
+type RunDetails struct {
+	Builder  Builder       `json:"builder" yaml:"builder"`
+	Metadata BuildMetadata `json:"metadata" yaml:"metadata"`
+}
+
+// Builder identifies the builder of the artifact
+type Builder struct {
+	ID      string            `json:"id" yaml:"id"`
+	Version map[string]string `json:"version,omitempty" yaml:"version,omitempty"`
+}
+
+// BuildMetadata is the metadata of the build invocation
+type BuildMetadata struct {
+	FinishedOn time.Time `json:"finishedOn" yaml:"finishedOn"`
+}
+
+// NewStatement returns the provenance statement of the plugin binary, built from
+// the sources in sourceDir with the specified parameters. The Go version, build
+// settings, source commit and dependencies are read from the build information
+// recorded by the Go toolchain in the binary.
+func NewStatement(binaryPath, sourceDir string, params *BuildParameters, finishedOn time.Time) (*Statement, error) {
+	digest, err := helpers.GetDigest(binaryPath)
+	if err != nil {
+		return nil, err
+	}
+	info, err := buildinfo.ReadFile(binaryPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read the build information of %q", binaryPath)
+	}
+
+	settings := map[string]string{}
+	for _, s := range info.Settings {
+		settings[s.Key] = s.Value
+	}
+
+	var dependencies []ResourceDescriptor
+	if revision := settings["vcs.revision"]; revision != "" {
+		dependencies = append(dependencies, ResourceDescriptor{
+			Name:   info.Main.Path,
+			URI:    fmt.Sprintf("git+%s@%s", sourceRepositoryURL(sourceDir, info.Main.Path), revision),
+			Digest: map[string]string{"gitCommit": revision},
+		})
+	}
+	for _, dep := range info.Deps {
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		rd := ResourceDescriptor{URI: fmt.Sprintf("pkg:golang/%s@%s", dep.Path, dep.Version)}
+		// Modules replaced by a local directory have no checksum
+		if dep.Sum != "" {
+			rd.Digest = map[string]string{"goModuleH1": strings.TrimPrefix(dep.Sum, "h1:")}
+		}
+		dependencies = append(dependencies, rd)
+	}
+
+	return &Statement{
+		Type: StatementType,
+		Subject: []ResourceDescriptor{
+			{Name: params.Plugin, Digest: map[string]string{"sha256": digest}},
+		},
+		PredicateType: PredicateType,
+		Predicate: Provenance{
+			BuildDefinition: BuildDefinition{
+				BuildType:          BuildType,
+				ExternalParameters: *params,
+				InternalParameters: InternalParameters{
+					GoVersion: info.GoVersion,
+					Settings:  settings,
+				},
+				ResolvedDependencies: dependencies,
+			},
+			RunDetails: RunDetails{
+				Builder: Builder{
+					ID:      BuilderID,
+					Version: map[string]string{"builder": cliBuildInfo.Version},
+				},
+				Metadata: BuildMetadata{FinishedOn: finishedOn.UTC()},
+			},
+		},
+	}, nil
+}
+
+// Parse parses the content of a provenance statement
+func Parse(content []byte) (*Statement, error) {
+	s := &Statement{}
+	if err := json.Unmarshal(content, s); err != nil {
+		return nil, errors.Wrap(err, "unable to parse the provenance statement")
+	}
+	if s.Type != StatementType || s.PredicateType != PredicateType {
+		return nil, errors.Errorf("unsupported provenance statement of type %q with predicate type %q", s.Type, s.PredicateType)
+	}
+	return s, nil
+}
+
+// Marshal returns the JSON encoding of the provenance statement
+func (s *Statement) Marshal() ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// HasSubject returns true if the provenance statement is about the file with
+// the specified sha256 digest
+func (s *Statement) HasSubject(sha256Digest string) bool {
+	for _, subject := range s.Subject {
+		if subject.Digest["sha256"] == sha256Digest {
+			return true
+		}
+	}
+	return false
+}
+
+// Source returns the source repository and commit of the build, if known
+func (s *Statement) Source() *ResourceDescriptor {
+	for i := range s.Predicate.BuildDefinition.ResolvedDependencies {
+		if _, ok := s.Predicate.BuildDefinition.ResolvedDependencies[i].Digest["gitCommit"]; ok {
+			return &s.Predicate.BuildDefinition.ResolvedDependencies[i]
+		}
+	}
+	return nil
+}
+
+// FileName returns the name of the provenance statement file of the plugin binary
+func FileName(binaryFileName string) string {
+	return binaryFileName + FileSuffix
+}
+
+// sourceRepositoryURL returns the URL of the origin git remote of the sources,
+// or the main module path if it cannot be found
+func sourceRepositoryURL(sourceDir, mainModulePath string) string {
+	cmd := exec.Command("git", "config", "--get", "remote.origin.url")
+	cmd.Dir = sourceDir
+	out, err := cmd.Output()
+	if err != nil || strings.TrimSpace(string(out)) == "" {
+		return "https://" + mainModulePath
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package provenance
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware-tanzu/tanzu-cli/cmd/plugin/builder/helpers"
+)
+
+func TestNewStatement(t *testing.T) {
+	assert := assert.New(t)
+
+	// The test binary is a Go binary with build information
+	binary, err := os.Executable()
+	assert.Nil(err)
+	digest, err := helpers.GetDigest(binary)
+	assert.Nil(err)
+
+	finishedOn := time.Unix(1700000000, 0)
+	params := &BuildParameters{Plugin: "foo", Target: "global", Version: "v1.0.0", OS: "linux", Arch: "amd64", Reproducible: true}
+	statement, err := NewStatement(binary, ".", params, finishedOn)
+	assert.Nil(err)
+	assert.Equal(StatementType, statement.Type)
+	assert.Equal(PredicateType, statement.PredicateType)
+	assert.True(statement.HasSubject(digest))
+	assert.False(statement.HasSubject("0123"))
+	assert.Equal(*params, statement.Predicate.BuildDefinition.ExternalParameters)
+	assert.NotEmpty(statement.Predicate.BuildDefinition.InternalParameters.GoVersion)
+	assert.Equal(finishedOn.UTC(), statement.Predicate.RunDetails.Metadata.FinishedOn)
+
+	// The dependencies of the test binary are recorded with their checksum
+	found := false
+	for _, d := range statement.Predicate.BuildDefinition.ResolvedDependencies {
+		if d.URI != "" && d.Digest["goModuleH1"] != "" {
+			found = true
+		}
+	}
+	assert.True(found)
+
+	b, err := statement.Marshal()
+	assert.Nil(err)
+	parsed, err := Parse(b)
+	assert.Nil(err)
+	assert.Equal(statement, parsed)
+}
+
+func TestParse(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := Parse([]byte("not json"))
+	assert.ErrorContains(err, "unable to parse the provenance statement")
+
+	_, err = Parse([]byte(`{"_type": "https://in-toto.io/Statement/v1", "predicateType": "https://spdx.dev/Document"}`))
+	assert.ErrorContains(err, `unsupported provenance statement of type "https://in-toto.io/Statement/v1" with predicate type "https://spdx.dev/Document"`)
+
+	s, err := Parse([]byte(`{"_type": "https://in-toto.io/Statement/v1", "predicateType": "https://slsa.dev/provenance/v1",
+		"subject": [{"name": "foo", "digest": {"sha256": "abcd"}}],
+		"predicate": {"buildDefinition": {"resolvedDependencies": [
+			{"uri": "pkg:golang/github.com/pkg/errors@v0.9.1"},
+			{"uri": "git+https://github.com/org/repo@1234", "digest": {"gitCommit": "1234"}}]}}}`))
+	assert.Nil(err)
+	assert.True(s.HasSubject("abcd"))
+	assert.Equal("git+https://github.com/org/repo@1234", s.Source().URI)
+	assert.Equal("foo.intoto.json", FileName("foo"))
+}
@@ -30,6 +30,37 @@ import (
 // in the central configuration and read back.
 type RecommendedVersion struct {
 	Version string `yaml:"version" json:"version"`
+	// Artifacts lists where the CLI binary of this version can be downloaded
+	// for the different OS/architectures. It is used by 'tanzu update'.
+	Artifacts []CLIArtifact `yaml:"artifacts,omitempty" json:"artifacts,omitempty"`
+}
+
+// CLIArtifact describes the location of the CLI binary for an OS/architecture.
+// Either Image or URI must be specified.
+type CLIArtifact struct {
+	// OS is the operating system of the binary
+	OS string `yaml:"os" json:"os"`
+	// Arch is the architecture of the binary
+	Arch string `yaml:"arch" json:"arch"`
+	// Image is an OCI image containing the binary, and signed with cosign
+	Image string `yaml:"image,omitempty" json:"image,omitempty"`
+	// URI is an HTTP(S) URL from which the binary can be downloaded
+	URI string `yaml:"uri,omitempty" json:"uri,omitempty"`
+	// SignatureURI is an HTTP(S) URL from which the cosign signature of the
+	// binary downloaded from URI can be obtained
+	SignatureURI string `yaml:"signatureUri,omitempty" json:"signatureUri,omitempty"`
+	// Digest is the SHA256 digest of the binary. An artifact without a digest cannot be installed.
+	Digest string `yaml:"digest,omitempty" json:"digest,omitempty"`
+}
+
+// GetArtifact returns the artifact of the specified OS/architecture
+func (rv *RecommendedVersion) GetArtifact(osName, arch string) (*CLIArtifact, bool) {
+	for i := range rv.Artifacts {
+		if rv.Artifacts[i].OS == osName && rv.Artifacts[i].Arch == arch {
+			return &rv.Artifacts[i], true
+		}
+	}
+	return nil, false
 }
 
 // dataStoreLastVersionCheckKey is the data store key used to store the last
@@ -49,8 +80,7 @@ func CheckRecommendedCLIVersion(cmd *cobra.Command) {
 	}
 
 	// Get the recommended versions from the default central configuration
-	var versionStruct []RecommendedVersion
-	err := centralconfig.DefaultCentralConfigReader.GetCentralConfigEntry(centralConfigRecommendedVersionsKey, &versionStruct)
+	versionStruct, err := GetRecommendedVersions()
 	if err != nil {
 		log.V(7).Error(err, "error reading recommended versions from central config")
 		return
@@ -76,6 +106,30 @@ func CheckRecommendedCLIVersion(cmd *cobra.Command) {
 	printVersionRecommendations(cmd.ErrOrStderr(), currentVersion, major, minor, patch)
 }
 
+// GetRecommendedVersions returns the recommended versions of the Tanzu CLI
+// from the default central configuration
+func GetRecommendedVersions() ([]RecommendedVersion, error) {
+	var versionStruct []RecommendedVersion
+	err := centralconfig.DefaultCentralConfigReader.GetCentralConfigEntry(centralConfigRecommendedVersionsKey, &versionStruct)
+	return versionStruct, err
+}
+
+// FindRecommendedUpdateVersion returns the version the current version should be
+// updated to: the recommended minor version if there is one, or else the recommended
+// patch version. Major versions are never returned as they contain breaking changes.
+// An empty string is returned if the current version is already the best recommended version.
+func FindRecommendedUpdateVersion(recommendedVersions []string, currentVersion string) (string, error) {
+	recommendedVersions, err := sortRecommendedVersionsDescending(recommendedVersions)
+	if err != nil {
+		return "", err
+	}
+	includePreReleases := utils.IsPreRelease(currentVersion)
+	if minor := findRecommendedMinorVersion(recommendedVersions, currentVersion, includePreReleases); minor != "" {
+		return minor, nil
+	}
+	return findRecommendedPatchVersion(recommendedVersions, currentVersion, includePreReleases), nil
+}
+
 // findRecommendedMajorVersion will return the recommended major version from the list of
 // recommended versions. If the current version is already at the most recent major version,
 // it will return an empty string.
@@ -254,7 +308,7 @@ func printVersionRecommendations(writer io.Writer, currentVersion, _, minor, pat
 		}
 	}
 
-	fmt.Fprintf(writer, "\nPlease refer to these instructions for upgrading: https://github.com/vmware-tanzu/tanzu-cli/blob/main/docs/quickstart/install.md.\n")
+	fmt.Fprintf(writer, "\nRun 'tanzu update' to update to the recommended version, or refer to these instructions for upgrading: https://github.com/vmware-tanzu/tanzu-cli/blob/main/docs/quickstart/install.md.\n")
 
 	delay := getRecommendationDelayInSeconds()
 	var delayStr string
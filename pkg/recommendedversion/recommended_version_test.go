@@ -209,6 +209,70 @@ func TestFindRecommendedPatchVersion(t *testing.T) {
 	}
 }
 
+func TestFindRecommendedUpdateVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		recommended []string
+		current     string
+		expected    string
+	}{
+		{
+			name:        "Newer minor",
+			recommended: strings.Split("v1.3.3,v2.1.0-alpha.2,v1.4.4,v2.0.2,v1.5.0-beta.0", ","),
+			current:     "v1.3.0",
+			expected:    "v1.4.4",
+		},
+		{
+			name:        "Newer patch only",
+			recommended: strings.Split("v1.3.3,v2.1.0-alpha.2,v1.4.4,v2.0.2,v1.5.0-beta.0", ","),
+			current:     "v1.4.1",
+			expected:    "v1.4.4",
+		},
+		{
+			name:        "Newer major is not recommended",
+			recommended: strings.Split("v1.3.3,v2.1.0-alpha.2,v1.4.4,v2.0.2,v1.5.0-beta.0", ","),
+			current:     "v1.4.4",
+			expected:    "",
+		},
+		{
+			name:        "Newer pre-release",
+			recommended: strings.Split("v1.3.3,v2.1.0-alpha.2,v1.4.4,v2.0.2,v1.5.0-beta.0", ","),
+			current:     "v1.5.0-alpha.1",
+			expected:    "v1.5.0-beta.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindRecommendedUpdateVersion(tt.recommended, tt.current)
+			if err != nil {
+				t.Errorf("FindRecommendedUpdateVersion() returned an unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("FindRecommendedUpdateVersion() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetArtifact(t *testing.T) {
+	rv := RecommendedVersion{
+		Version: "v1.4.4",
+		Artifacts: []CLIArtifact{
+			{OS: "linux", Arch: "amd64", Image: "example.com/tanzu-cli-linux-amd64:v1.4.4"},
+			{OS: "darwin", Arch: "arm64", URI: "https://example.com/tanzu-cli-darwin-arm64"},
+		},
+	}
+
+	artifact, found := rv.GetArtifact("darwin", "arm64")
+	if !found || artifact.URI != "https://example.com/tanzu-cli-darwin-arm64" {
+		t.Errorf("GetArtifact() = %v, %v, want the darwin/arm64 artifact", artifact, found)
+	}
+	if _, found = rv.GetArtifact("windows", "amd64"); found {
+		t.Errorf("GetArtifact() found an artifact for windows/amd64")
+	}
+}
+
 func TestSortRecommendedVersionsDescending(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -7,10 +7,17 @@ import (
 	"archive/tar"
 	"bytes"
 	"io"
+	"net/http"
+	"strings"
 
 	"github.com/cppforlife/go-cli-ui/ui"
 	regname "github.com/google/go-containerregistry/pkg/name"
 	regv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
 	"github.com/pkg/errors"
 
 	"github.com/vmware-tanzu/carvel-imgpkg/pkg/imgpkg/bundle"
@@ -228,6 +235,35 @@ func (r *registry) CopyImageFromTar(sourceTarFile, destImageRepo string) error {
 	return nil
 }
 
+// CopyImage copies the image to the destination repository, directly from registry to registry
+// This is equivalent to `imgpkg copy --image <image> --to-repo <dest-repo>` command
+func (r *registry) CopyImage(sourceImageName, destImageRepo string) error {
+	// Creating a dummy writer to capture the logs
+	writerUI := ui.NewWriterUI(&writer{}, &writer{}, nil)
+
+	copyOptions := cmd.NewCopyOptions(ui.NewWrappingConfUI(writerUI, nil))
+	copyOptions.Concurrency = 3
+	copyOptions.SignatureFlags = cmd.SignatureFlags{CopyCosignSignatures: true}
+	isBundle, _ := bundle.NewBundle(sourceImageName, r.registry).IsBundle()
+	if isBundle {
+		copyOptions.BundleFlags = cmd.BundleFlags{Bundle: sourceImageName}
+	} else {
+		copyOptions.ImageFlags = cmd.ImageFlags{Image: sourceImageName}
+	}
+	copyOptions.RepoDst = destImageRepo
+
+	if r.opts != nil {
+		copyOptions.RegistryFlags = cmd.RegistryFlags{
+			CACertPaths: r.opts.CACertPaths,
+			VerifyCerts: r.opts.VerifyCerts,
+			Insecure:    r.opts.Insecure,
+			Anon:        r.opts.Anon,
+		}
+	}
+
+	return copyOptions.Run()
+}
+
 func (r *registry) downloadBundleOrImage(imageName, outputDir string, isBundle bool) error {
 	// Creating a dummy writer to capture the logs
 	// currently this logs are not displayed or used directly
@@ -308,3 +344,127 @@ func (r *registry) ResolveImage(imageWithTag string) error {
 
 	return resolveOptions.Run()
 }
+
+// PushReferrer publishes the content as an OCI artifact of the given artifact
+// type referring to the specified image, similarly to `oras attach`.
+// The artifact is also added to the referrers tag schema index of the image
+// (`<alg>-<digest>` tag), for the referrers to be found on any registry,
+// whether it supports the OCI referrers API or not.
+func (r *registry) PushReferrer(imageWithTag, artifactType string, content []byte) error {
+	ref, err := regname.ParseReference(imageWithTag, regname.WeakValidation)
+	if err != nil {
+		return err
+	}
+	subject, err := r.registry.Get(ref)
+	if err != nil {
+		return errors.Wrapf(err, "unable to find image %q", imageWithTag)
+	}
+
+	artifact := mutate.MediaType(empty.Image, types.OCIManifestSchema1)
+	artifact = mutate.ConfigMediaType(artifact, types.MediaType(artifactType))
+	artifact, err = mutate.AppendLayers(artifact, static.NewLayer(content, types.MediaType(artifactType)))
+	if err != nil {
+		return err
+	}
+	artifact, ok := mutate.Subject(artifact, subject.Descriptor).(regv1.Image)
+	if !ok {
+		return errors.New("unable to set the subject of the referrer artifact")
+	}
+	artifactDigest, err := artifact.Digest()
+	if err != nil {
+		return err
+	}
+	if err := r.registry.WriteImage(ref.Context().Digest(artifactDigest.String()), artifact, nil); err != nil {
+		return errors.Wrapf(err, "unable to publish the referrer of image %q", imageWithTag)
+	}
+
+	referrersTag := referrersTagForDigest(ref.Context(), subject.Digest)
+	referrers, err := r.getReferrersIndex(referrersTag)
+	if err != nil {
+		return err
+	}
+	manifest, err := referrers.IndexManifest()
+	if err != nil {
+		return err
+	}
+	for i := range manifest.Manifests {
+		if manifest.Manifests[i].Digest == artifactDigest {
+			return nil
+		}
+	}
+	referrers = mutate.AppendManifests(referrers, mutate.IndexAddendum{Add: artifact})
+	if err := r.registry.WriteIndex(referrersTag, referrers); err != nil {
+		return errors.Wrapf(err, "unable to update the referrers of image %q", imageWithTag)
+	}
+	return nil
+}
+
+// GetReferrers gets the content of the OCI artifacts of the given artifact
+// type referring to the specified image
+func (r *registry) GetReferrers(imageWithTag, artifactType string) ([][]byte, error) {
+	ref, err := regname.ParseReference(imageWithTag, regname.WeakValidation)
+	if err != nil {
+		return nil, err
+	}
+	subjectDigest, err := r.registry.Digest(ref)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to find image %q", imageWithTag)
+	}
+	referrers, err := r.getReferrersIndex(referrersTagForDigest(ref.Context(), subjectDigest))
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := referrers.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	var contents [][]byte
+	for i := range manifest.Manifests {
+		if manifest.Manifests[i].ArtifactType != artifactType {
+			continue
+		}
+		artifact, err := r.registry.Image(ref.Context().Digest(manifest.Manifests[i].Digest.String()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to get the referrer %s of image %q", manifest.Manifests[i].Digest, imageWithTag)
+		}
+		layers, err := artifact.Layers()
+		if err != nil {
+			return nil, err
+		}
+		for _, layer := range layers {
+			// The content of the artifact layers is not compressed
+			rc, err := layer.Compressed()
+			if err != nil {
+				return nil, err
+			}
+			content, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return nil, err
+			}
+			contents = append(contents, content)
+		}
+	}
+	return contents, nil
+}
+
+// getReferrersIndex returns the referrers tag schema index at the given tag,
+// or an empty index if the tag does not exist
+func (r *registry) getReferrersIndex(tag regname.Tag) (regv1.ImageIndex, error) {
+	idx, err := r.registry.Index(tag)
+	if err == nil {
+		return idx, nil
+	}
+	var terr *transport.Error
+	if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
+		return mutate.IndexMediaType(empty.Index, types.OCIImageIndex), nil
+	}
+	return nil, errors.Wrapf(err, "unable to get the referrers index %q", tag.String())
+}
+
+// referrersTagForDigest returns the tag of the referrers tag schema index of
+// the image with the given digest, as defined by the OCI distribution specification
+func referrersTagForDigest(repo regname.Repository, digest regv1.Hash) regname.Tag {
+	return repo.Tag(strings.Replace(digest.String(), ":", "-", 1))
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	ctlimg "github.com/vmware-tanzu/carvel-imgpkg/pkg/imgpkg/registry"
+)
+
+var _ = Describe("Registry referrers", func() {
+	const artifactType = "application/vnd.in-toto+json"
+	var (
+		tempDir      string
+		reg          Registry
+		image        string
+		stopRegistry func()
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "")
+		Expect(err).ToNot(HaveOccurred())
+
+		var port string
+		port, stopRegistry, err = ServeLocalRegistry("")
+		Expect(err).ToNot(HaveOccurred())
+		reg, err = New(&ctlimg.Opts{Anon: true, Insecure: true})
+		Expect(err).ToNot(HaveOccurred())
+
+		file := filepath.Join(tempDir, "foo")
+		Expect(os.WriteFile(file, []byte("foo binary"), 0644)).To(Succeed())
+		image = fmt.Sprintf("localhost:%s/plugins/foo:v1.0.0", port)
+		Expect(reg.PushImage(image, []string{file})).To(Succeed())
+	})
+	AfterEach(func() {
+		stopRegistry()
+		os.RemoveAll(tempDir)
+	})
+
+	It("returns no referrers when none is published", func() {
+		referrers, err := reg.GetReferrers(image, artifactType)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(referrers).To(BeEmpty())
+	})
+
+	It("returns the published referrers of the artifact type", func() {
+		Expect(reg.PushReferrer(image, artifactType, []byte("statement 1"))).To(Succeed())
+		Expect(reg.PushReferrer(image, "application/vnd.other", []byte("other"))).To(Succeed())
+		Expect(reg.PushReferrer(image, artifactType, []byte("statement 2"))).To(Succeed())
+		// Publishing the same referrer again does not duplicate it
+		Expect(reg.PushReferrer(image, artifactType, []byte("statement 1"))).To(Succeed())
+
+		referrers, err := reg.GetReferrers(image, artifactType)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(referrers).To(ConsistOf([]byte("statement 1"), []byte("statement 2")))
+	})
+
+	It("fails for a missing image", func() {
+		err := reg.PushReferrer(image+"-missing", artifactType, []byte("statement"))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("unable to find image"))
+	})
+})
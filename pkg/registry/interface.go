@@ -27,9 +27,18 @@ type Registry interface {
 	// CopyImageFromTar publishes the image to destination repository from specified tar file
 	// This is equivalent to `imgpkg copy --tar <file> --to-repo <dest-repo>` command
 	CopyImageFromTar(sourceTarFile, destImageRepo string) error
+	// CopyImage copies the image to the destination repository, directly from registry to registry
+	// This is equivalent to `imgpkg copy --image <image> --to-repo <dest-repo>` command
+	CopyImage(sourceImageName, destImageRepo string) error
 	// PushImage publishes the image to the specified location
 	// This is equivalent to `imgpkg push -i <image> -f <filepath>`
 	PushImage(imageWithTag string, filePaths []string) error
 	// ResolveImage invokes `imgpkg tag resolve -i <image>` command
 	ResolveImage(imageWithTag string) error
+	// PushReferrer publishes the content as an OCI artifact of the given artifact
+	// type referring to the specified image, similarly to `oras attach`
+	PushReferrer(imageWithTag, artifactType string, content []byte) error
+	// GetReferrers gets the content of the OCI artifacts of the given artifact
+	// type referring to the specified image
+	GetReferrers(imageWithTag, artifactType string) ([][]byte, error)
 }
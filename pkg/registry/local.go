@@ -0,0 +1,585 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	regv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/match"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/vmware-tanzu/carvel-imgpkg/pkg/imgpkg/imagetar"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/constants"
+)
+
+const (
+	// localImageScheme is the URI scheme of the images stored on the local filesystem
+	localImageScheme = "file:"
+	// ociLayoutFile marks the root directory of an OCI image layout
+	ociLayoutFile = "oci-layout"
+	// pluginMigrationManifestFile marks the directory of an extracted plugin bundle.
+	// It must match airgapped.PluginMigrationManifestFile which cannot be imported here.
+	pluginMigrationManifestFile = "plugin_migration_manifest.yaml"
+	// staticRepositoryDBFile marks the root directory of a static plugin repository.
+	// It must match plugininventory.SQliteDBFileName which cannot be imported here.
+	staticRepositoryDBFile = "plugin_inventory.db"
+	// staticRepositoryInventoryRepo is the repository of the plugin inventory image
+	// served from the inventory database of a static plugin repository
+	staticRepositoryInventoryRepo = "plugin-inventory"
+	// annotationRefName is the annotation of the OCI image layout index holding
+	// the reference of an image, relative to the root of the layout
+	annotationRefName = "org.opencontainers.image.ref.name"
+)
+
+// localImageLayoutMutex serializes the accesses to the index of OCI image layouts
+// as multiple images can be published in parallel
+var localImageLayoutMutex sync.Mutex
+
+var windowsDrivePath = regexp.MustCompile(`^/[a-zA-Z]:`)
+
+// IsLocalImage returns true if the image is a file URI referencing an image stored
+// on the local filesystem (e.g. file:///opt/tanzu/plugins/plugin-inventory:latest)
+// The image is read from the OCI image layout, the extracted plugin bundle or the
+// static plugin repository containing the path.
+func IsLocalImage(image string) bool {
+	return strings.HasPrefix(strings.TrimSpace(image), localImageScheme)
+}
+
+// InitLocalImageRepository creates an empty OCI image layout at the location of
+// the specified local repository (e.g. file:///opt/tanzu/plugins) so that images
+// can be published to it, unless the location is already part of a layout.
+func InitLocalImageRepository(repo string) error {
+	ref, err := parseLocalImage(repo)
+	if err != nil {
+		return err
+	}
+	if _, _, err := findLocalImageStore(filepath.Join(ref.path, "image")); err == nil {
+		return nil
+	}
+	if _, err := layout.Write(ref.path, empty.Index); err != nil {
+		return errors.Wrapf(err, "unable to create an OCI image layout at %q", ref.path)
+	}
+	return nil
+}
+
+// localImageRef is a parsed reference to an image stored on the local filesystem
+type localImageRef struct {
+	// path is the filesystem path of the image repository
+	path   string
+	tag    string
+	digest string
+}
+
+func parseLocalImage(image string) (*localImageRef, error) {
+	image = strings.TrimSpace(image)
+	if !IsLocalImage(image) {
+		return nil, errors.Errorf("%q is not a local image", image)
+	}
+	// The image can have any of the file:///dir, file:/dir or file://dir forms,
+	// the second one being produced by path.Dir("file:///dir/image")
+	p := strings.TrimPrefix(strings.TrimPrefix(image, localImageScheme), "//")
+	if windowsDrivePath.MatchString(p) {
+		p = p[1:]
+	}
+
+	ref := &localImageRef{}
+	if idx := strings.LastIndex(p, "@"); idx != -1 {
+		p, ref.digest = p[:idx], p[idx+1:]
+	} else if idx := strings.LastIndex(p, ":"); idx > strings.LastIndex(p, "/") {
+		p, ref.tag = p[:idx], p[idx+1:]
+	}
+	if p == "" {
+		return nil, errors.Errorf("invalid local image %q", image)
+	}
+	ref.path = filepath.Clean(filepath.FromSlash(p))
+	return ref, nil
+}
+
+// localImageStore is a set of images stored on the local filesystem
+type localImageStore interface {
+	// image returns the image of the specified repository, relative to the root of
+	// the store, matching the tag or the digest
+	image(repo, tag, digest string) (regv1.Image, error)
+	// tags lists the tags of the specified repository
+	tags(repo string) ([]string, error)
+	// write publishes the image to the specified repository with the specified tag
+	write(repo, tag string, img regv1.Image) error
+}
+
+// findLocalImageStore walks up the parents of the repository path to find the OCI
+// image layout, the extracted plugin bundle or the static plugin repository
+// containing it. It returns the store and the path of the repository relative to
+// the root of the store.
+func findLocalImageStore(repoPath string) (localImageStore, string, error) {
+	for dir := filepath.Dir(repoPath); ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, ociLayoutFile)); err == nil {
+			p, err := layout.FromPath(dir)
+			if err != nil {
+				return nil, "", errors.Wrapf(err, "invalid OCI image layout %q", dir)
+			}
+			return &ociLayoutStore{path: p}, relativeRepository(dir, repoPath), nil
+		}
+		if _, err := os.Stat(filepath.Join(dir, pluginMigrationManifestFile)); err == nil {
+			store, err := newPluginBundleStore(dir)
+			if err != nil {
+				return nil, "", err
+			}
+			return store, relativeRepository(dir, repoPath), nil
+		}
+		if _, err := os.Stat(filepath.Join(dir, staticRepositoryDBFile)); err == nil && isStaticRepositoryImage(dir, repoPath) {
+			return &staticRepositoryStore{dir: dir}, relativeRepository(dir, repoPath), nil
+		}
+		if dir == filepath.Dir(dir) {
+			return nil, "", errors.Errorf("no OCI image layout, extracted plugin bundle or static plugin repository contains %q", repoPath)
+		}
+	}
+}
+
+// isStaticRepositoryImage returns true if the repository path is the plugin inventory
+// or a plugin binary of the static plugin repository at dir. Unlike the other stores,
+// the inventory database alone does not identify a static plugin repository, as such
+// a database can be found in any directory.
+func isStaticRepositoryImage(dir, repoPath string) bool {
+	if relativeRepository(dir, repoPath) == staticRepositoryInventoryRepo {
+		return true
+	}
+	info, err := os.Stat(repoPath)
+	return err == nil && info.Mode().IsRegular()
+}
+
+func relativeRepository(root, repoPath string) string {
+	rel, _ := filepath.Rel(root, repoPath)
+	return filepath.ToSlash(rel)
+}
+
+// splitRefName splits the reference of an image relative to the root of a
+// local store into its repository and tag or digest
+func splitRefName(refName string) (repo, tag, digest string) {
+	if idx := strings.LastIndex(refName, "@"); idx != -1 {
+		return refName[:idx], "", refName[idx+1:]
+	}
+	if idx := strings.LastIndex(refName, ":"); idx > strings.LastIndex(refName, "/") {
+		return refName[:idx], refName[idx+1:], ""
+	}
+	return refName, "", ""
+}
+
+// ociLayoutStore stores images in an OCI image layout. Each image is referenced
+// by its path, relative to the root of the layout, and its tag.
+type ociLayoutStore struct {
+	path layout.Path
+}
+
+func (s *ociLayoutStore) image(repo, tag, digest string) (regv1.Image, error) {
+	localImageLayoutMutex.Lock()
+	defer localImageLayoutMutex.Unlock()
+
+	index, err := s.path.ImageIndex()
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+	for i := range manifest.Manifests {
+		desc := manifest.Manifests[i]
+		descRepo, descTag, _ := splitRefName(desc.Annotations[annotationRefName])
+		if descRepo != repo {
+			continue
+		}
+		if (digest != "" && desc.Digest.String() == digest) || (digest == "" && descTag == tag) {
+			return index.Image(desc.Digest)
+		}
+	}
+	return nil, errors.Errorf("image %q not found in the OCI image layout %q", repo, string(s.path))
+}
+
+func (s *ociLayoutStore) tags(repo string) ([]string, error) {
+	localImageLayoutMutex.Lock()
+	defer localImageLayoutMutex.Unlock()
+
+	index, err := s.path.ImageIndex()
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	for i := range manifest.Manifests {
+		descRepo, descTag, _ := splitRefName(manifest.Manifests[i].Annotations[annotationRefName])
+		if descRepo == repo && descTag != "" {
+			tags = append(tags, descTag)
+		}
+	}
+	return tags, nil
+}
+
+func (s *ociLayoutStore) write(repo, tag string, img regv1.Image) error {
+	refName := repo + ":" + tag
+	if tag == "" {
+		digest, err := img.Digest()
+		if err != nil {
+			return err
+		}
+		refName = repo + "@" + digest.String()
+	}
+
+	localImageLayoutMutex.Lock()
+	defer localImageLayoutMutex.Unlock()
+	return s.path.ReplaceImage(img, match.Name(refName), layout.WithAnnotations(map[string]string{annotationRefName: refName}))
+}
+
+// pluginBundleStore serves the images of an extracted plugin bundle, as created
+// by the `tanzu plugin download-bundle` command
+type pluginBundleStore struct {
+	dir      string
+	manifest pluginBundleManifest
+}
+
+// pluginBundleManifest is the subset of airgapped.PluginMigrationManifest
+// needed to locate the images of an extracted plugin bundle
+type pluginBundleManifest struct {
+	InventoryMetadataImage *struct {
+		SourceFilePath           string `yaml:"sourceFilePath"`
+		RelativeImagePathWithTag string `yaml:"relativeImagePathWithTag"`
+	} `yaml:"inventoryMetadataImage"`
+	ImagesToCopy []struct {
+		SourceTarFilePath string `yaml:"sourceTarFilePath"`
+		RelativeImagePath string `yaml:"relativeImagePath"`
+	} `yaml:"imagesToCopy"`
+}
+
+func newPluginBundleStore(dir string) (*pluginBundleStore, error) {
+	bytes, err := os.ReadFile(filepath.Join(dir, pluginMigrationManifestFile))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read the plugin bundle %q", dir)
+	}
+	store := &pluginBundleStore{dir: dir}
+	if err := yaml.Unmarshal(bytes, &store.manifest); err != nil {
+		return nil, errors.Wrapf(err, "invalid plugin migration manifest in %q", dir)
+	}
+	return store, nil
+}
+
+func (s *pluginBundleStore) image(repo, tag, digest string) (regv1.Image, error) {
+	// The plugin inventory metadata is bundled as a database file rather than as an image
+	if mi := s.manifest.InventoryMetadataImage; mi != nil {
+		metadataRepo, metadataTag, _ := splitRefName(strings.TrimPrefix(mi.RelativeImagePathWithTag, "/"))
+		if metadataRepo == repo && (digest != "" || metadataTag == tag) {
+			img, err := s.fileImage(mi.SourceFilePath)
+			if err != nil || digest == "" {
+				return img, err
+			}
+			if d, err := img.Digest(); err == nil && d.String() == digest {
+				return img, nil
+			}
+		}
+	}
+
+	for _, ic := range s.manifest.ImagesToCopy {
+		if strings.TrimPrefix(ic.RelativeImagePath, "/") != repo {
+			continue
+		}
+		images, err := imagetar.NewTarReader(filepath.Join(s.dir, ic.SourceTarFilePath)).Read()
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read %q of the plugin bundle", ic.SourceTarFilePath)
+		}
+		for _, i := range images {
+			if i.Image == nil {
+				continue
+			}
+			img := *i.Image
+			if digest == "" && img.Tag() == tag {
+				return img, nil
+			}
+			if d, err := img.Digest(); err == nil && digest != "" && d.String() == digest {
+				return img, nil
+			}
+		}
+	}
+	return nil, errors.Errorf("image %q not found in the plugin bundle %q", repo, s.dir)
+}
+
+// fileImage returns an image made of the specified file of the plugin bundle
+func (s *pluginBundleStore) fileImage(file string) (regv1.Image, error) {
+	bytes, err := os.ReadFile(filepath.Join(s.dir, file))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read %q of the plugin bundle", file)
+	}
+	return crane.Image(map[string][]byte{filepath.Base(file): bytes})
+}
+
+func (s *pluginBundleStore) tags(repo string) ([]string, error) {
+	var tags []string
+	if mi := s.manifest.InventoryMetadataImage; mi != nil {
+		if metadataRepo, metadataTag, _ := splitRefName(strings.TrimPrefix(mi.RelativeImagePathWithTag, "/")); metadataRepo == repo {
+			tags = append(tags, metadataTag)
+		}
+	}
+	for _, ic := range s.manifest.ImagesToCopy {
+		if strings.TrimPrefix(ic.RelativeImagePath, "/") != repo {
+			continue
+		}
+		images, err := imagetar.NewTarReader(filepath.Join(s.dir, ic.SourceTarFilePath)).Read()
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read %q of the plugin bundle", ic.SourceTarFilePath)
+		}
+		for _, i := range images {
+			if i.Image != nil && (*i.Image).Tag() != "" {
+				tags = append(tags, (*i.Image).Tag())
+			}
+		}
+	}
+	return tags, nil
+}
+
+func (s *pluginBundleStore) write(_, _ string, _ regv1.Image) error {
+	return errors.Errorf("images cannot be published to the extracted plugin bundle %q", s.dir)
+}
+
+// staticRepositoryStore serves the images of a static plugin repository, as created
+// by `tanzu builder inventory init --local-repository`, which is a directory holding
+// the plugin inventory database and the plugin binaries at paths relative to it.
+// The plugin-inventory repository is the image made of the database along with the
+// optional central configuration, and each plugin binary is an image made of the
+// binary, whatever the tag.
+type staticRepositoryStore struct {
+	dir string
+}
+
+func (s *staticRepositoryStore) image(repo, _, digest string) (regv1.Image, error) {
+	files := map[string][]byte{}
+	if repo == staticRepositoryInventoryRepo {
+		for _, file := range []string{staticRepositoryDBFile, constants.CentralConfigFileName} {
+			bytes, err := os.ReadFile(filepath.Join(s.dir, file))
+			if err != nil {
+				// The central configuration is optional
+				if file != staticRepositoryDBFile && os.IsNotExist(err) {
+					continue
+				}
+				return nil, errors.Wrapf(err, "unable to read %q of the static plugin repository", file)
+			}
+			files[file] = bytes
+		}
+	} else {
+		file := filepath.Join(s.dir, filepath.FromSlash(repo))
+		if info, err := os.Stat(file); err != nil || !info.Mode().IsRegular() {
+			return nil, errors.Errorf("image %q not found in the static plugin repository %q", repo, s.dir)
+		}
+		bytes, err := os.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read %q of the static plugin repository", repo)
+		}
+		files[filepath.Base(file)] = bytes
+	}
+
+	img, err := crane.Image(files)
+	if err != nil || digest == "" {
+		return img, err
+	}
+	if d, err := img.Digest(); err != nil || d.String() != digest {
+		return nil, errors.Errorf("image %q with digest %q not found in the static plugin repository %q", repo, digest, s.dir)
+	}
+	return img, nil
+}
+
+func (s *staticRepositoryStore) tags(repo string) ([]string, error) {
+	if repo == staticRepositoryInventoryRepo {
+		return []string{"latest"}, nil
+	}
+	return nil, nil
+}
+
+func (s *staticRepositoryStore) write(_, _ string, _ regv1.Image) error {
+	return errors.Errorf("images cannot be published to the static plugin repository %q", s.dir)
+}
+
+// localRegistry implements the Registry interface for the images stored on the
+// local filesystem, in an OCI image layout, in an extracted plugin bundle or in a
+// static plugin repository
+type localRegistry struct{}
+
+// NewLocal instantiates a new Registry for the images stored on the local filesystem
+func NewLocal() Registry {
+	return &localRegistry{}
+}
+
+// getImage returns the image referenced by imageWithTag
+func (r *localRegistry) getImage(imageWithTag string) (regv1.Image, error) {
+	ref, err := parseLocalImage(imageWithTag)
+	if err != nil {
+		return nil, err
+	}
+	store, repo, err := findLocalImageStore(ref.path)
+	if err != nil {
+		return nil, err
+	}
+	tag := ref.tag
+	if tag == "" && ref.digest == "" {
+		tag = "latest"
+	}
+	return store.image(repo, tag, ref.digest)
+}
+
+// ListImageTags lists all tags of the given image.
+func (r *localRegistry) ListImageTags(imageName string) ([]string, error) {
+	ref, err := parseLocalImage(imageName)
+	if err != nil {
+		return nil, err
+	}
+	store, repo, err := findLocalImageStore(ref.path)
+	if err != nil {
+		return nil, err
+	}
+	return store.tags(repo)
+}
+
+// GetFile gets the file content bundled in the given image:tag.
+// If filename is empty, it will get the first file.
+func (r *localRegistry) GetFile(imageWithTag, filename string) ([]byte, error) {
+	img, err := r.getImage(imageWithTag)
+	if err != nil {
+		return nil, err
+	}
+	return getFileContentFromImage(img, filename)
+}
+
+// GetFiles get all the files content bundled in the given image:tag.
+func (r *localRegistry) GetFiles(imageWithTag string) (map[string][]byte, error) {
+	img, err := r.getImage(imageWithTag)
+	if err != nil {
+		return nil, err
+	}
+	return getAllFilesContentFromImage(img)
+}
+
+// DownloadBundle saves the files of the given image to outputDir
+func (r *localRegistry) DownloadBundle(imageName, outputDir string) error {
+	return r.DownloadImage(imageName, outputDir)
+}
+
+// DownloadImage saves the files of the given image to outputDir
+func (r *localRegistry) DownloadImage(imageName, outputDir string) error {
+	files, err := r.GetFiles(imageName)
+	if err != nil {
+		return err
+	}
+	for name, content := range files {
+		file := filepath.Join(outputDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(file, content, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetImageDigest gets the digest of the given image
+func (r *localRegistry) GetImageDigest(imageWithTag string) (string, string, error) {
+	img, err := r.getImage(imageWithTag)
+	if err != nil {
+		return "", "", err
+	}
+	hash, err := img.Digest()
+	if err != nil {
+		return "", "", err
+	}
+	return hash.Algorithm, hash.Hex, nil
+}
+
+// CopyImageToTar is not supported for local images
+func (r *localRegistry) CopyImageToTar(sourceImageName, _ string) error {
+	return errors.Errorf("copying the local image %q to a tar file is not supported", sourceImageName)
+}
+
+// CopyImageFromTar publishes the images of the specified tar file, as created by
+// `imgpkg copy --to-tar`, to the destination repository of an OCI image layout
+func (r *localRegistry) CopyImageFromTar(sourceTarFile, destImageRepo string) error {
+	ref, err := parseLocalImage(destImageRepo)
+	if err != nil {
+		return err
+	}
+	store, repo, err := findLocalImageStore(ref.path)
+	if err != nil {
+		return err
+	}
+	images, err := imagetar.NewTarReader(sourceTarFile).Read()
+	if err != nil {
+		return errors.Wrapf(err, "unable to read %q", sourceTarFile)
+	}
+	for _, i := range images {
+		if i.Image == nil {
+			return errors.Errorf("%q contains an image index which is not supported for local images", sourceTarFile)
+		}
+		if err := store.write(repo, (*i.Image).Tag(), *i.Image); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CopyImage is not supported for local images, which are copied through a tar file
+func (r *localRegistry) CopyImage(sourceImageName, _ string) error {
+	return errors.Errorf("copying the local image %q to a repository is not supported", sourceImageName)
+}
+
+// PushImage publishes an image made of the specified files to an OCI image layout
+func (r *localRegistry) PushImage(imageWithTag string, filePaths []string) error {
+	ref, err := parseLocalImage(imageWithTag)
+	if err != nil {
+		return err
+	}
+	store, repo, err := findLocalImageStore(ref.path)
+	if err != nil {
+		return err
+	}
+	files := map[string][]byte{}
+	for _, file := range filePaths {
+		bytes, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		files[filepath.Base(file)] = bytes
+	}
+	img, err := crane.Image(files)
+	if err != nil {
+		return err
+	}
+	tag := ref.tag
+	if tag == "" {
+		tag = "latest"
+	}
+	return store.write(repo, tag, img)
+}
+
+// ResolveImage checks that the given image exists
+func (r *localRegistry) ResolveImage(imageWithTag string) error {
+	_, err := r.getImage(imageWithTag)
+	return err
+}
+
+// PushReferrer is not supported for local images
+func (r *localRegistry) PushReferrer(imageWithTag, _ string, _ []byte) error {
+	return errors.Errorf("publishing a referrer of the local image %q is not supported", imageWithTag)
+}
+
+// GetReferrers is not supported for local images
+func (r *localRegistry) GetReferrers(imageWithTag, _ string) ([][]byte, error) {
+	return nil, errors.Errorf("getting the referrers of the local image %q is not supported", imageWithTag)
+}
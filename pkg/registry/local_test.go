@@ -0,0 +1,231 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	ctlimg "github.com/vmware-tanzu/carvel-imgpkg/pkg/imgpkg/registry"
+)
+
+var _ = Describe("Local registry", func() {
+	var (
+		tempDir  string
+		localReg Registry
+		dbFile   string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "")
+		Expect(err).ToNot(HaveOccurred())
+		localReg = NewLocal()
+
+		dbFile = filepath.Join(tempDir, "files", "plugin_inventory.db")
+		Expect(os.MkdirAll(filepath.Dir(dbFile), 0755)).To(Succeed())
+		Expect(os.WriteFile(dbFile, []byte("fake db"), 0644)).To(Succeed())
+	})
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	// createImageTar publishes an image to an in-memory registry and saves
+	// it as a tar file the same way download-bundle does
+	createImageTar := func(relativeImage, fileName, content, tarFile string) {
+		port, stopRegistry, err := ServeLocalRegistry("")
+		Expect(err).ToNot(HaveOccurred())
+		defer stopRegistry()
+
+		remoteReg, err := New(&ctlimg.Opts{Anon: true, Insecure: true})
+		Expect(err).ToNot(HaveOccurred())
+		file := filepath.Join(tempDir, fileName)
+		Expect(os.WriteFile(file, []byte(content), 0644)).To(Succeed())
+		image := fmt.Sprintf("localhost:%s/plugins/%s", port, relativeImage)
+		Expect(remoteReg.PushImage(image, []string{file})).To(Succeed())
+		Expect(remoteReg.CopyImageToTar(image, tarFile)).To(Succeed())
+	}
+
+	It("detects local images", func() {
+		Expect(IsLocalImage("file:///opt/plugins/plugin-inventory:latest")).To(BeTrue())
+		Expect(IsLocalImage("file:/opt/plugins/plugin-inventory:latest")).To(BeTrue())
+		Expect(IsLocalImage("example.com/plugins/plugin-inventory:latest")).To(BeFalse())
+	})
+
+	Context("with an OCI image layout", func() {
+		var layoutRepo string
+
+		BeforeEach(func() {
+			layoutRepo = "file://" + filepath.ToSlash(filepath.Join(tempDir, "layout"))
+			Expect(InitLocalImageRepository(layoutRepo)).To(Succeed())
+		})
+
+		It("publishes and reads images made of files", func() {
+			image := layoutRepo + "/plugin-inventory:latest"
+			Expect(localReg.PushImage(image, []string{dbFile})).To(Succeed())
+
+			files, err := localReg.GetFiles(image)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(files).To(Equal(map[string][]byte{"plugin_inventory.db": []byte("fake db")}))
+
+			outputDir := filepath.Join(tempDir, "output")
+			Expect(localReg.DownloadImage(image, outputDir)).To(Succeed())
+			Expect(os.ReadFile(filepath.Join(outputDir, "plugin_inventory.db"))).To(Equal([]byte("fake db")))
+
+			alg, hex, err := localReg.GetImageDigest(image)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(alg).To(Equal("sha256"))
+			Expect(localReg.ResolveImage(fmt.Sprintf("%s/plugin-inventory@%s:%s", layoutRepo, alg, hex))).To(Succeed())
+
+			tags, err := localReg.ListImageTags(layoutRepo + "/plugin-inventory")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tags).To(ConsistOf("latest"))
+
+			// Publishing again with the same tag replaces the image
+			Expect(os.WriteFile(dbFile, []byte("updated db"), 0644)).To(Succeed())
+			Expect(localReg.PushImage(image, []string{dbFile})).To(Succeed())
+			content, err := localReg.GetFile(image, "plugin_inventory.db")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(content).To(Equal([]byte("updated db")))
+			tags, err = localReg.ListImageTags(layoutRepo + "/plugin-inventory")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tags).To(ConsistOf("latest"))
+		})
+
+		It("publishes images from tar files", func() {
+			tarFile := filepath.Join(tempDir, "plugin.tar.gz")
+			createImageTar("vmware/tkg/linux/amd64/global/foo:v1.0.0", "foo", "fake plugin binary", tarFile)
+
+			Expect(localReg.CopyImageFromTar(tarFile, layoutRepo+"/vmware/tkg/linux/amd64/global/foo")).To(Succeed())
+
+			// The image prefix of the discovery is computed with path.Dir() which turns file:/// into file:/
+			image := path.Dir(layoutRepo+"/plugin-inventory:latest") + "/vmware/tkg/linux/amd64/global/foo:v1.0.0"
+			files, err := localReg.GetFiles(image)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(files).To(Equal(map[string][]byte{"foo": []byte("fake plugin binary")}))
+
+			_, err = localReg.GetFiles(layoutRepo + "/vmware/tkg/linux/amd64/global/foo:v2.0.0")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("not found in the OCI image layout"))
+		})
+
+		It("refuses to copy a local image to a tar file", func() {
+			err := localReg.CopyImageToTar(layoutRepo+"/plugin-inventory:latest", filepath.Join(tempDir, "image.tar"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("not supported"))
+		})
+	})
+
+	Context("with an extracted plugin bundle", func() {
+		var bundleRepo string
+
+		BeforeEach(func() {
+			bundleDir := filepath.Join(tempDir, "plugin_bundle")
+			Expect(os.MkdirAll(bundleDir, 0755)).To(Succeed())
+			bundleRepo = "file://" + filepath.ToSlash(bundleDir)
+
+			createImageTar("plugin-inventory:latest", "plugin_inventory.db", "fake inventory db", filepath.Join(bundleDir, "plugin-inventory-image.tar.gz"))
+			createImageTar("vmware/tkg/linux/amd64/global/foo:v1.0.0", "foo", "fake plugin binary", filepath.Join(bundleDir, "foo.tar.gz"))
+			Expect(os.WriteFile(filepath.Join(bundleDir, "plugin_inventory_metadata.db"), []byte("fake metadata db"), 0644)).To(Succeed())
+			manifest := `relativeInventoryImagePathWithTag: /plugin-inventory:latest
+inventoryMetadataImage:
+    sourceFilePath: plugin_inventory_metadata.db
+    relativeImagePathWithTag: /plugin-inventory-metadata:latest
+imagesToCopy:
+    - sourceTarFilePath: plugin-inventory-image.tar.gz
+      relativeImagePath: /plugin-inventory
+    - sourceTarFilePath: foo.tar.gz
+      relativeImagePath: /vmware/tkg/linux/amd64/global/foo
+`
+			Expect(os.WriteFile(filepath.Join(bundleDir, pluginMigrationManifestFile), []byte(manifest), 0644)).To(Succeed())
+		})
+
+		It("reads the images of the plugin bundle", func() {
+			files, err := localReg.GetFiles(bundleRepo + "/plugin-inventory:latest")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(files).To(Equal(map[string][]byte{"plugin_inventory.db": []byte("fake inventory db")}))
+
+			files, err = localReg.GetFiles(bundleRepo + "/vmware/tkg/linux/amd64/global/foo:v1.0.0")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(files).To(Equal(map[string][]byte{"foo": []byte("fake plugin binary")}))
+
+			tags, err := localReg.ListImageTags(bundleRepo + "/vmware/tkg/linux/amd64/global/foo")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tags).To(ConsistOf("v1.0.0"))
+		})
+
+		It("serves the plugin inventory metadata database as an image", func() {
+			image := bundleRepo + "/plugin-inventory-metadata:latest"
+			files, err := localReg.GetFiles(image)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(files).To(Equal(map[string][]byte{"plugin_inventory_metadata.db": []byte("fake metadata db")}))
+
+			alg, hex, err := localReg.GetImageDigest(image)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(localReg.ResolveImage(fmt.Sprintf("%s/plugin-inventory-metadata@%s:%s", bundleRepo, alg, hex))).To(Succeed())
+		})
+
+		It("refuses to publish images to the plugin bundle", func() {
+			err := localReg.PushImage(bundleRepo+"/plugin-inventory:latest", []string{dbFile})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("images cannot be published to the extracted plugin bundle"))
+		})
+	})
+
+	Context("with a static plugin repository", func() {
+		var staticRepo string
+
+		BeforeEach(func() {
+			repoDir := filepath.Join(tempDir, "repository")
+			binaryDir := filepath.Join(repoDir, "vmware", "tkg", "linux", "amd64", "global", "foo", "v1.0.0")
+			Expect(os.MkdirAll(binaryDir, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(repoDir, "plugin_inventory.db"), []byte("fake inventory db"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(binaryDir, "tanzu-foo-linux_amd64"), []byte("fake plugin binary"), 0755)).To(Succeed())
+			staticRepo = "file://" + filepath.ToSlash(repoDir)
+		})
+
+		It("serves the inventory database and the plugin binaries as images", func() {
+			files, err := localReg.GetFiles(staticRepo + "/plugin-inventory:latest")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(files).To(Equal(map[string][]byte{"plugin_inventory.db": []byte("fake inventory db")}))
+
+			// The central configuration is part of the inventory image when present
+			Expect(os.WriteFile(filepath.Join(tempDir, "repository", "central_config.yaml"), []byte("key: value"), 0644)).To(Succeed())
+			files, err = localReg.GetFiles(staticRepo + "/plugin-inventory:latest")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(files).To(HaveLen(2))
+			Expect(files).To(HaveKeyWithValue("central_config.yaml", []byte("key: value")))
+
+			// The image prefix of the discovery is computed with path.Dir() which turns file:/// into file:/
+			image := path.Dir(staticRepo+"/plugin-inventory:latest") + "/vmware/tkg/linux/amd64/global/foo/v1.0.0/tanzu-foo-linux_amd64"
+			files, err = localReg.GetFiles(image)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(files).To(Equal(map[string][]byte{"tanzu-foo-linux_amd64": []byte("fake plugin binary")}))
+
+			alg, hex, err := localReg.GetImageDigest(image)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(localReg.ResolveImage(fmt.Sprintf("%s@%s:%s", image, alg, hex))).To(Succeed())
+
+			_, err = localReg.GetFiles(staticRepo + "/vmware/tkg/linux/amd64/global/foo/v2.0.0/tanzu-foo-linux_amd64")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("refuses to publish images to the static plugin repository", func() {
+			err := localReg.PushImage(staticRepo+"/plugin-inventory:latest", []string{dbFile})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("images cannot be published to the static plugin repository"))
+		})
+	})
+
+	It("fails for images outside of any OCI image layout, plugin bundle or static plugin repository", func() {
+		_, err := localReg.GetFiles("file://" + filepath.ToSlash(tempDir) + "/plugin-inventory:latest")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("no OCI image layout, extracted plugin bundle or static plugin repository contains"))
+	})
+})
@@ -0,0 +1,259 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package selfupdate implements the update of the CLI binary to a recommended version
+package selfupdate
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/artifact"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/buildinfo"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/carvelhelpers"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/constants"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cosignhelper"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cosignhelper/sigverifier"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/recommendedversion"
+)
+
+// backupSuffix is appended to the path of the CLI binary to back up the
+// binary being replaced
+const backupSuffix = ".bak"
+
+// Options are the options of a CLI update
+type Options struct {
+	// Version is the version to update to. If empty, the recommended version is used.
+	Version string
+	// DryRun only reports the update that would be performed
+	DryRun bool
+}
+
+// These variables can be replaced in tests
+var (
+	// getRecommendedVersions returns the recommended versions of the CLI
+	getRecommendedVersions = recommendedversion.GetRecommendedVersions
+	// getExecutablePath returns the path of the running CLI binary
+	getExecutablePath = func() (string, error) {
+		path, err := os.Executable()
+		if err != nil {
+			return "", err
+		}
+		return filepath.EvalSymlinks(path)
+	}
+	// verifyImageSignature verifies the cosign signature of an OCI image
+	verifyImageSignature = sigverifier.VerifyImageSignature
+	// getImageDigest returns the hash algorithm and the digest of an OCI image
+	getImageDigest = carvelhelpers.GetImageDigest
+	// fetchImage fetches the binary stored in an OCI image
+	fetchImage = func(image string) ([]byte, error) {
+		return artifact.NewOCIArtifact(image).Fetch()
+	}
+)
+
+// UpdateCLI updates the running CLI binary to the specified version, or to the
+// recommended version if none is specified. The binary is downloaded from the
+// location listed in the central configuration for the current OS/architecture,
+// its signature and digest are verified, and it then atomically replaces the
+// running binary, which is kept as a backup.
+func UpdateCLI(w io.Writer, opts Options) error {
+	currentVersion := buildinfo.Version
+	target, err := resolveTargetVersion(currentVersion, opts.Version)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		fmt.Fprintf(w, "The Tanzu CLI is already at the recommended version %s\n", currentVersion)
+		return nil
+	}
+
+	cliArtifact, found := target.GetArtifact(cli.GOOS, cli.GOARCH)
+	if !found {
+		return errors.Errorf("no binary of version %s of the Tanzu CLI is available for %s/%s", target.Version, cli.GOOS, cli.GOARCH)
+	}
+	source := cliArtifact.Image
+	if source == "" {
+		source = cliArtifact.URI
+	}
+
+	exePath, err := getExecutablePath()
+	if err != nil {
+		return errors.Wrap(err, "unable to locate the running CLI binary")
+	}
+
+	if opts.DryRun {
+		fmt.Fprintf(w, "The Tanzu CLI at %s would be updated from version %s to version %s using %s\n", exePath, currentVersion, target.Version, source)
+		return nil
+	}
+
+	log.Infof("Downloading version %s of the Tanzu CLI from %s", target.Version, source)
+	binary, err := fetchAndVerifyBinary(cliArtifact)
+	if err != nil {
+		return errors.Wrapf(err, "unable to download version %s of the Tanzu CLI", target.Version)
+	}
+
+	backupPath, err := replaceBinary(exePath, binary)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "The Tanzu CLI has been updated from version %s to version %s. The previous binary was saved to %s\n", currentVersion, target.Version, backupPath)
+	return nil
+}
+
+// resolveTargetVersion returns the recommended version to update to, or nil if
+// the current version is already the recommended one. If a version is requested,
+// it must be one of the recommended versions.
+func resolveTargetVersion(currentVersion, requestedVersion string) (*recommendedversion.RecommendedVersion, error) {
+	recommended, err := getRecommendedVersions()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the recommended versions of the Tanzu CLI from the central configuration")
+	}
+
+	if requestedVersion == "" {
+		var versions []string
+		for i := range recommended {
+			versions = append(versions, recommended[i].Version)
+		}
+		if requestedVersion, err = recommendedversion.FindRecommendedUpdateVersion(versions, currentVersion); err != nil {
+			return nil, err
+		}
+		if requestedVersion == "" {
+			return nil, nil
+		}
+	}
+
+	for i := range recommended {
+		if recommended[i].Version == requestedVersion {
+			return &recommended[i], nil
+		}
+	}
+	return nil, errors.Errorf("version %s is not a recommended version of the Tanzu CLI", requestedVersion)
+}
+
+// fetchAndVerifyBinary downloads the CLI binary and verifies its signature and digest
+func fetchAndVerifyBinary(cliArtifact *recommendedversion.CLIArtifact) ([]byte, error) {
+	skipSignature, _ := strconv.ParseBool(os.Getenv(constants.SkipCLIBinarySignatureVerification))
+	if skipSignature {
+		log.Warningf("Skipping the signature verification of the Tanzu CLI binary")
+	}
+	publicKeyPath := os.Getenv(constants.PublicKeyPathForCLIBinarySignature)
+
+	// The digest is required even when the signature is verified, as it is
+	// the only check left when the signature verification is skipped
+	if cliArtifact.Digest == "" {
+		return nil, errors.New("no digest is listed for the binary")
+	}
+
+	var binary []byte
+	var err error
+	switch {
+	case cliArtifact.Image != "":
+		// Verify and fetch the image by digest so that it cannot change in between
+		image, err := resolveImageDigest(cliArtifact.Image)
+		if err != nil {
+			return nil, err
+		}
+		if !skipSignature {
+			if err := verifyImageSignature(image, publicKeyPath); err != nil {
+				return nil, errors.Wrapf(err, "signature verification of %s failed", image)
+			}
+		}
+		if binary, err = fetchImage(image); err != nil {
+			return nil, err
+		}
+
+	case cliArtifact.URI != "":
+		if binary, err = artifact.NewHTTPArtifact(cliArtifact.URI).Fetch(); err != nil {
+			return nil, err
+		}
+		if !skipSignature {
+			if cliArtifact.SignatureURI == "" {
+				return nil, errors.Errorf("no signature is available for %s", cliArtifact.URI)
+			}
+			sig, err := artifact.NewHTTPArtifact(cliArtifact.SignatureURI).Fetch()
+			if err != nil {
+				return nil, errors.Wrap(err, "unable to download the signature of the binary")
+			}
+			if err := cosignhelper.VerifyBlobSignature(context.Background(), publicKeyPath, binary, sig); err != nil {
+				return nil, errors.Wrapf(err, "signature verification of %s failed", cliArtifact.URI)
+			}
+		}
+
+	default:
+		return nil, errors.New("the location of the binary is not specified")
+	}
+
+	if actual := fmt.Sprintf("%x", sha256.Sum256(binary)); actual != cliArtifact.Digest {
+		return nil, errors.Errorf("the binary has been corrupted during download. source digest: %s, actual digest: %s", cliArtifact.Digest, actual)
+	}
+	return binary, nil
+}
+
+// resolveImageDigest returns the reference by digest of the image currently
+// pointed to by the specified image reference
+func resolveImageDigest(image string) (string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid image reference %s", image)
+	}
+	hashAlgorithm, hashHexVal, err := getImageDigest(image)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to resolve the digest of %s", image)
+	}
+	return fmt.Sprintf("%s@%s:%s", ref.Context().Name(), hashAlgorithm, hashHexVal), nil
+}
+
+// replaceBinary atomically replaces the binary at exePath with the specified
+// content, keeping the previous binary as a backup whose path is returned.
+// The new binary is first written next to the existing one so that the final
+// rename does not cross file systems.
+func replaceBinary(exePath string, binary []byte) (string, error) {
+	info, err := os.Stat(exePath)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to access the running CLI binary")
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(exePath), "."+filepath.Base(exePath)+"-update-*")
+	if err != nil {
+		return "", errors.Wrap(err, "unable to write the new CLI binary")
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath)
+
+	_, err = tmpFile.Write(binary)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpPath, info.Mode().Perm())
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "unable to write the new CLI binary")
+	}
+
+	// Renaming the running binary is allowed on all platforms, including Windows
+	backupPath := exePath + backupSuffix
+	_ = os.Remove(backupPath)
+	if err := os.Rename(exePath, backupPath); err != nil {
+		return "", errors.Wrap(err, "unable to back up the running CLI binary")
+	}
+	if err := os.Rename(tmpPath, exePath); err != nil {
+		// Restore the previous binary
+		if restoreErr := os.Rename(backupPath, exePath); restoreErr != nil {
+			return "", errors.Wrapf(err, "unable to install the new CLI binary, and unable to restore the previous binary from %s", backupPath)
+		}
+		return "", errors.Wrap(err, "unable to install the new CLI binary")
+	}
+	return backupPath, nil
+}
@@ -0,0 +1,208 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package selfupdate
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware-tanzu/tanzu-cli/pkg/buildinfo"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/cli"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/constants"
+	"github.com/vmware-tanzu/tanzu-cli/pkg/recommendedversion"
+)
+
+var newBinary = []byte("new tanzu binary")
+
+// setupUpdateTest serves the new binary and its signature, configures the public key
+// to verify the signature and installs a fake CLI binary. It returns the path
+// of the fake CLI binary and the URL of the test server.
+func setupUpdateTest(t *testing.T) (string, string) {
+	dir := t.TempDir()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	signer, err := signature.LoadECDSASignerVerifier(privateKey, crypto.SHA256)
+	assert.NoError(t, err)
+	sig, err := signer.SignMessage(bytes.NewReader(newBinary))
+	assert.NoError(t, err)
+	publicKey, err := cryptoutils.MarshalPublicKeyToPEM(&privateKey.PublicKey)
+	assert.NoError(t, err)
+	publicKeyPath := filepath.Join(dir, "cosign.pub")
+	assert.NoError(t, os.WriteFile(publicKeyPath, publicKey, 0644))
+	t.Setenv(constants.PublicKeyPathForCLIBinarySignature, publicKeyPath)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tanzu", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(newBinary)
+	})
+	mux.HandleFunc("/tanzu.sig", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(base64.StdEncoding.EncodeToString(sig)))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	exePath := filepath.Join(dir, "tanzu")
+	assert.NoError(t, os.WriteFile(exePath, []byte("old tanzu binary"), 0755))
+
+	origGetExecutablePath, origGetRecommendedVersions, origVersion := getExecutablePath, getRecommendedVersions, buildinfo.Version
+	t.Cleanup(func() {
+		getExecutablePath, getRecommendedVersions, buildinfo.Version = origGetExecutablePath, origGetRecommendedVersions, origVersion
+	})
+	getExecutablePath = func() (string, error) { return exePath, nil }
+	buildinfo.Version = "v1.3.0"
+
+	return exePath, server.URL
+}
+
+func recommendedVersions(serverURL, digest string) []recommendedversion.RecommendedVersion {
+	return []recommendedversion.RecommendedVersion{
+		{Version: "v2.0.0"},
+		{
+			Version: "v1.4.1",
+			Artifacts: []recommendedversion.CLIArtifact{
+				{OS: cli.GOOS, Arch: cli.GOARCH, URI: serverURL + "/tanzu", SignatureURI: serverURL + "/tanzu.sig", Digest: digest},
+			},
+		},
+		{Version: "v1.3.0"},
+	}
+}
+
+func TestUpdateCLI(t *testing.T) {
+	exePath, serverURL := setupUpdateTest(t)
+	digest := fmt.Sprintf("%x", sha256.Sum256(newBinary))
+	getRecommendedVersions = func() ([]recommendedversion.RecommendedVersion, error) {
+		return recommendedVersions(serverURL, digest), nil
+	}
+
+	// A dry-run does not change the binary
+	var out bytes.Buffer
+	err := UpdateCLI(&out, Options{DryRun: true})
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "would be updated from version v1.3.0 to version v1.4.1")
+	b, _ := os.ReadFile(exePath)
+	assert.Equal(t, "old tanzu binary", string(b))
+
+	out.Reset()
+	err = UpdateCLI(&out, Options{})
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "updated from version v1.3.0 to version v1.4.1")
+
+	b, _ = os.ReadFile(exePath)
+	assert.Equal(t, newBinary, b)
+	info, err := os.Stat(exePath)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+	b, _ = os.ReadFile(exePath + backupSuffix)
+	assert.Equal(t, "old tanzu binary", string(b))
+
+	// No temporary file is left behind
+	files, _ := os.ReadDir(filepath.Dir(exePath))
+	assert.Equal(t, 3, len(files))
+}
+
+func TestUpdateCLIVerificationFailures(t *testing.T) {
+	exePath, serverURL := setupUpdateTest(t)
+
+	// Digest mismatch
+	getRecommendedVersions = func() ([]recommendedversion.RecommendedVersion, error) {
+		return recommendedVersions(serverURL, "0123"), nil
+	}
+	err := UpdateCLI(&bytes.Buffer{}, Options{})
+	assert.ErrorContains(t, err, "the binary has been corrupted during download")
+
+	// No digest listed for the binary
+	getRecommendedVersions = func() ([]recommendedversion.RecommendedVersion, error) {
+		return recommendedVersions(serverURL, ""), nil
+	}
+	err = UpdateCLI(&bytes.Buffer{}, Options{})
+	assert.ErrorContains(t, err, "no digest is listed for the binary")
+
+	// Invalid signature
+	rv := recommendedVersions(serverURL, fmt.Sprintf("%x", sha256.Sum256(newBinary)))
+	rv[1].Artifacts[0].SignatureURI = serverURL + "/tanzu"
+	getRecommendedVersions = func() ([]recommendedversion.RecommendedVersion, error) { return rv, nil }
+	err = UpdateCLI(&bytes.Buffer{}, Options{})
+	assert.ErrorContains(t, err, "signature verification of "+serverURL+"/tanzu failed")
+
+	// The signature verification can be skipped, but the digest is still verified
+	t.Setenv(constants.SkipCLIBinarySignatureVerification, "true")
+	rv[1].Artifacts[0].Digest = "0123"
+	err = UpdateCLI(&bytes.Buffer{}, Options{})
+	assert.ErrorContains(t, err, "the binary has been corrupted during download")
+
+	b, _ := os.ReadFile(exePath)
+	assert.Equal(t, "old tanzu binary", string(b))
+}
+
+func TestFetchAndVerifyImageByDigest(t *testing.T) {
+	origVerifyImageSignature, origGetImageDigest, origFetchImage := verifyImageSignature, getImageDigest, fetchImage
+	t.Cleanup(func() {
+		verifyImageSignature, getImageDigest, fetchImage = origVerifyImageSignature, origGetImageDigest, origFetchImage
+	})
+
+	var verifiedImage, fetchedImage string
+	getImageDigest = func(image string) (string, string, error) {
+		return "sha256", "abcdef", nil
+	}
+	verifyImageSignature = func(image, _ string) error {
+		verifiedImage = image
+		return nil
+	}
+	fetchImage = func(image string) ([]byte, error) {
+		fetchedImage = image
+		return newBinary, nil
+	}
+
+	binary, err := fetchAndVerifyBinary(&recommendedversion.CLIArtifact{
+		Image:  "fake.repo.com/tanzu-cli/tanzu:v1.4.1",
+		Digest: fmt.Sprintf("%x", sha256.Sum256(newBinary)),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, newBinary, binary)
+	// The image is verified and fetched using the same digest
+	assert.Equal(t, "fake.repo.com/tanzu-cli/tanzu@sha256:abcdef", verifiedImage)
+	assert.Equal(t, verifiedImage, fetchedImage)
+}
+
+func TestResolveTargetVersion(t *testing.T) {
+	_, serverURL := setupUpdateTest(t)
+	getRecommendedVersions = func() ([]recommendedversion.RecommendedVersion, error) {
+		return recommendedVersions(serverURL, ""), nil
+	}
+
+	target, err := resolveTargetVersion("v1.3.0", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.4.1", target.Version)
+
+	// The major version is only used when explicitly requested
+	target, err = resolveTargetVersion("v1.4.1", "")
+	assert.NoError(t, err)
+	assert.Nil(t, target)
+	target, err = resolveTargetVersion("v1.4.1", "v2.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "v2.0.0", target.Version)
+
+	_, err = resolveTargetVersion("v1.3.0", "v1.9.9")
+	assert.ErrorContains(t, err, "version v1.9.9 is not a recommended version of the Tanzu CLI")
+
+	// The requested version must provide a binary for the current OS/arch
+	err = UpdateCLI(&bytes.Buffer{}, Options{Version: "v2.0.0"})
+	assert.ErrorContains(t, err, "no binary of version v2.0.0 of the Tanzu CLI is available")
+}
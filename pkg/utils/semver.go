@@ -6,6 +6,7 @@ package utils
 
 import (
 	"sort"
+	"strings"
 
 	"github.com/Masterminds/semver"
 )
@@ -44,6 +45,31 @@ func IsNewVersion(incomingVersionStr, existingVersionStr string) bool {
 	return incomingVersion.Compare(existingVersion) > 0 // Return true if new version is available
 }
 
+// IsVersionInRange returns true if the version is within the minVersion and maxVersion
+// range. An empty minVersion or maxVersion leaves the range unbounded on that side.
+func IsVersionInRange(version, minVersion, maxVersion string) bool {
+	if minVersion != "" && IsNewVersion(minVersion, version) {
+		return false
+	}
+	if maxVersion != "" && IsNewVersion(version, maxVersion) {
+		return false
+	}
+	return true
+}
+
+// DescribeVersionRange returns a description of the minVersion and maxVersion range.
+// E.g., ">= v1.0.0, <= v1.2.0"
+func DescribeVersionRange(minVersion, maxVersion string) string {
+	var constraints []string
+	if minVersion != "" {
+		constraints = append(constraints, ">= "+minVersion)
+	}
+	if maxVersion != "" {
+		constraints = append(constraints, "<= "+maxVersion)
+	}
+	return strings.Join(constraints, ", ")
+}
+
 // IsPreRelease checks if the version is a pre-release version.
 func IsPreRelease(versionStr string) bool {
 	version, err := semver.NewVersion(versionStr)
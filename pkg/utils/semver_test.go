@@ -360,3 +360,25 @@ func TestIsSameMinor(t *testing.T) {
 		})
 	}
 }
+
+func TestIsVersionInRange(t *testing.T) {
+	tests := []struct {
+		version    string
+		minVersion string
+		maxVersion string
+		want       bool
+	}{
+		{version: "v1.1.0", want: true},
+		{version: "v1.1.0", minVersion: "v1.0.0", maxVersion: "v1.2.0", want: true},
+		{version: "v1.0.0", minVersion: "v1.0.0", maxVersion: "v1.2.0", want: true},
+		{version: "v1.2.0", minVersion: "v1.0.0", maxVersion: "v1.2.0", want: true},
+		{version: "v0.9.0", minVersion: "v1.0.0", want: false},
+		{version: "v1.2.1", maxVersion: "v1.2.0", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version+" in ["+DescribeVersionRange(tt.minVersion, tt.maxVersion)+"]", func(t *testing.T) {
+			assert.Equal(t, tt.want, IsVersionInRange(tt.version, tt.minVersion, tt.maxVersion))
+		})
+	}
+	assert.Equal(t, ">= v1.0.0, <= v1.2.0", DescribeVersionRange("v1.0.0", "v1.2.0"))
+}
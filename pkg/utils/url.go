@@ -28,7 +28,7 @@ func JoinURL(baseURL, relativeURL string) (string, error) {
 	var schemaNotPresent bool
 
 	// Check if the URL has a schema
-	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
+	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") && !strings.HasPrefix(baseURL, "file://") {
 		schemaNotPresent = true
 		// If not, prepend "https://"
 		baseURL = "https://" + baseURL
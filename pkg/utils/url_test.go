@@ -100,6 +100,11 @@ func TestJoinUrl(t *testing.T) {
 		{"https://127.0.0.1:5001/", "", "https://127.0.0.1:5001/", nil},
 		{"https://127.0.0.1:5001/", "/", "https://127.0.0.1:5001/", nil},
 
+		// local file tests
+
+		{"file:///opt/tanzu-plugins", "/plugin-inventory:latest", "file:///opt/tanzu-plugins/plugin-inventory:latest", nil},
+		{"file:///opt/tanzu-plugins/", "test/path:v1.0.0", "file:///opt/tanzu-plugins/test/path:v1.0.0", nil},
+
 		// ip address tests without port
 
 		{"127.0.0.1/tanzu-plugins/", "/test/path/", "127.0.0.1/tanzu-plugins/test/path/", nil},
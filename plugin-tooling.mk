@@ -122,6 +122,12 @@ plugin-build-%:
 		--plugin-scope-association-file $(PLUGIN_SCOPE_ASSOCIATION_FILE) \
 		--debug-symbols=$(PLUGIN_DEBUG)
 
+.PHONY: plugin-verify
+plugin-verify: ## Verify the plugin binaries built for the local platform against the plugin contract
+	$(BUILDER_PLUGIN) plugin verify \
+		--binary-artifacts $(PLUGIN_BINARY_ARTIFACTS_DIR) \
+		--match "$(PLUGIN_NAME)"
+
 .PHONY: plugin-build-packages
 plugin-build-packages:  ## Build plugin packages
 	$(BUILDER_PLUGIN) plugin build-package \